@@ -110,6 +110,9 @@ const (
 	// Allowed to create zip files.
 	PREPARE_RESULTS
 
+	// Allowed to approve hunts requested by other users.
+	APPROVE_HUNT
+
 	// When adding new permission - update CheckAccess,
 	// GetRolePermissions and acl.proto
 )
@@ -150,6 +153,8 @@ func (self ACL_PERMISSION) String() string {
 		return "MACHINE_STATE"
 	case PREPARE_RESULTS:
 		return "PREPARE_RESULTS"
+	case APPROVE_HUNT:
+		return "APPROVE_HUNT"
 
 	}
 	return fmt.Sprintf("%d", self)
@@ -192,6 +197,8 @@ func GetPermission(name string) ACL_PERMISSION {
 		return MACHINE_STATE
 	case "PREPARE_RESULTS":
 		return PREPARE_RESULTS
+	case "APPROVE_HUNT":
+		return APPROVE_HUNT
 
 	}
 	return NO_PERMISSIONS
@@ -345,6 +352,9 @@ func CheckAccessWithToken(
 	case PREPARE_RESULTS:
 		return token.PrepareResults, nil
 
+	case APPROVE_HUNT:
+		return token.ApproveHunt, nil
+
 	}
 
 	return false, nil
@@ -40,6 +40,7 @@ func GetRolePermissions(
 			result.FilesystemWrite = true
 			result.MachineState = true
 			result.PrepareResults = true
+			result.ApproveHunt = true
 
 			// Readers can view results but not edit or
 			// modify anything.
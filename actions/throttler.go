@@ -0,0 +1,154 @@
+package actions
+
+import (
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/process"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	// How often the process' resource use is sampled.
+	resource_sample_period = time.Second
+
+	// How long an op waits before checking the limits again.
+	resource_backoff = 100 * time.Millisecond
+)
+
+// Samples the resources used by the process so far: the total CPU
+// time in seconds and the number of IO operations.
+type resourceSampler func() (cpu_seconds float64, io_ops uint64, err error)
+
+func sampleProcessResources() (float64, uint64, error) {
+	proc, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	times, err := proc.Times()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Not all platforms report IO counters.
+	io_ops := uint64(0)
+	counters, err := proc.IOCounters()
+	if err == nil {
+		io_ops = counters.ReadCount + counters.WriteCount
+	}
+
+	return times.User + times.System, io_ops, nil
+}
+
+// A throttler which keeps the process' CPU and IO use under the
+// query's limits. Each op is first rate limited by the ops per second
+// throttler, then delayed for as long as the last sample was over
+// one of the limits.
+//
+// The usage is sampled for the whole process so any other queries
+// running at the same time count towards the limits too.
+type resourceThrottler struct {
+	ops vfilter.Throttler
+
+	// Percent of the total CPU across all cores.
+	cpu_limit  float64
+	iops_limit float64
+	sample     resourceSampler
+
+	mu         sync.Mutex
+	over_limit bool
+	closed     bool
+	done       chan bool
+}
+
+func newResourceThrottler(
+	ops_per_second, cpu_limit, iops_limit float64,
+	sample resourceSampler) vfilter.Throttler {
+	ops := vfilter.NewTimeThrottler(ops_per_second)
+	if cpu_limit <= 0 && iops_limit <= 0 {
+		return ops
+	}
+
+	result := &resourceThrottler{
+		ops:        ops,
+		cpu_limit:  cpu_limit,
+		iops_limit: iops_limit,
+		sample:     sample,
+		done:       make(chan bool),
+	}
+
+	go result.sampleLoop()
+
+	return result
+}
+
+func (self *resourceThrottler) sampleLoop() {
+	last_time := time.Now()
+	last_cpu, last_io, err := self.sample()
+	if err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-self.done:
+			return
+		case <-time.After(resource_sample_period):
+		}
+
+		now := time.Now()
+		cpu, io, err := self.sample()
+		if err != nil {
+			continue
+		}
+
+		elapsed := now.Sub(last_time).Seconds()
+		if elapsed <= 0 {
+			continue
+		}
+
+		cpu_percent := (cpu - last_cpu) * 100 /
+			(elapsed * float64(runtime.NumCPU()))
+		iops := float64(io-last_io) / elapsed
+
+		self.mu.Lock()
+		self.over_limit = (self.cpu_limit > 0 && cpu_percent > self.cpu_limit) ||
+			(self.iops_limit > 0 && iops > self.iops_limit)
+		self.mu.Unlock()
+
+		last_time, last_cpu, last_io = now, cpu, io
+	}
+}
+
+func (self *resourceThrottler) isOverLimit() bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.over_limit
+}
+
+func (self *resourceThrottler) ChargeOp() {
+	self.ops.ChargeOp()
+
+	for self.isOverLimit() {
+		select {
+		case <-self.done:
+			return
+		case <-time.After(resource_backoff):
+		}
+	}
+}
+
+func (self *resourceThrottler) Close() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if !self.closed {
+		self.closed = true
+		close(self.done)
+		self.ops.Close()
+	}
+}
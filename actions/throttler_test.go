@@ -0,0 +1,71 @@
+package actions
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert"
+)
+
+func TestResourceThrottler(t *testing.T) {
+	old_period := resource_sample_period
+	old_backoff := resource_backoff
+	resource_sample_period = 10 * time.Millisecond
+	resource_backoff = 5 * time.Millisecond
+	defer func() {
+		resource_sample_period = old_period
+		resource_backoff = old_backoff
+	}()
+
+	// Each sample while busy uses far more CPU than the limit.
+	var mu sync.Mutex
+	busy := true
+	cpu := float64(0)
+	sample := func() (float64, uint64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if busy {
+			cpu += 1000
+		}
+		return cpu, 0, nil
+	}
+
+	set_busy := func(value bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		busy = value
+	}
+
+	throttler := newResourceThrottler(0, 50, 0, sample)
+	defer throttler.Close()
+
+	// Wait for the first samples to be taken.
+	time.Sleep(50 * time.Millisecond)
+
+	charged := make(chan bool)
+	go func() {
+		throttler.ChargeOp()
+		close(charged)
+	}()
+
+	// Ops are held while the process is over the limit.
+	select {
+	case <-charged:
+		t.Fatalf("Op was not throttled")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	set_busy(false)
+
+	select {
+	case <-charged:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Op was not released")
+	}
+
+	// Without limits only the ops rate applies.
+	_, ok := newResourceThrottler(0, 0, 0, sample).(*resourceThrottler)
+	assert.False(t, ok)
+}
@@ -149,7 +149,9 @@ func (self VQLClientAction) StartQuery(
 
 	scope.Log("Starting query execution.")
 
-	vfilter.InstallThrottler(scope, vfilter.NewTimeThrottler(float64(rate)))
+	vfilter.InstallThrottler(scope, newResourceThrottler(
+		float64(rate), float64(arg.CpuLimit), float64(arg.IopsLimit),
+		sampleProcessResources))
 
 	start := time.Now()
 
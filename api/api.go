@@ -327,7 +327,7 @@ func (self *ApiServer) ListHunts(
 
 	result, err := flows.ListHunts(self.config, in)
 	if err != nil {
-		return nil, err
+		return nil, huntDispatcherStatus(err)
 	}
 
 	return result, nil
@@ -352,12 +352,31 @@ func (self *ApiServer) GetHunt(
 
 	result, err := flows.GetHunt(self.config, in)
 	if err != nil {
-		return nil, err
+		return nil, huntDispatcherStatus(err)
+	}
+
+	// The result is a copy so it is safe to fill in this user's
+	// permissions.
+	if !in.StatsOnly {
+		result.Permissions, err = flows.GetHuntPermissions(self.config,
+			vql_subsystem.NewServerACLManager(self.config, user_name), result)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return result, nil
 }
 
+// Let API clients tell a server which is still starting (and may be
+// retried) apart from other failures.
+func huntDispatcherStatus(err error) error {
+	if errors.Is(err, services.ErrHuntDispatcherNotReady) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return err
+}
+
 func (self *ApiServer) GetHuntResults(
 	ctx context.Context,
 	in *api_proto.GetHuntResultsRequest) (*api_proto.GetTableResponse, error) {
@@ -1142,21 +1161,28 @@ func (self *ApiServer) CreateDownloadFile(ctx context.Context,
 	env := ordereddict.NewDict()
 	if in.FlowId != "" && in.ClientId != "" {
 		query = `SELECT create_flow_download(
-      client_id=ClientId, flow_id=FlowId, type=DownloadType) AS VFSPath
+      client_id=ClientId, flow_id=FlowId, type=DownloadType,
+      compression=Compression) AS VFSPath
       FROM scope()`
 
 		env.Set("ClientId", in.ClientId).
 			Set("FlowId", in.FlowId).
-			Set("DownloadType", in.DownloadType)
+			Set("DownloadType", in.DownloadType).
+			Set("Compression", in.Compression)
 
 	} else if in.HuntId != "" {
 		query = `SELECT create_hunt_download(
-      hunt_id=HuntId, only_combined=OnlyCombined, format=Format) AS VFSPath
+      hunt_id=HuntId, only_combined=OnlyCombined, format=Format,
+      since=Since, since_last_download=SinceLast,
+      compression=Compression) AS VFSPath
       FROM scope()`
 
 		env.Set("HuntId", in.HuntId).
 			Set("Format", format).
-			Set("OnlyCombined", in.OnlyCombinedHunt)
+			Set("OnlyCombined", in.OnlyCombinedHunt).
+			Set("Since", in.Since).
+			Set("SinceLast", in.SinceLastDownload).
+			Set("Compression", in.Compression)
 
 	}
 
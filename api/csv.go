@@ -123,6 +123,9 @@ func getPathManager(
 	} else if in.HuntId != "" && in.Type == "hunt_status" {
 		return paths.NewHuntPathManager(in.HuntId).ClientErrors()
 
+	} else if in.HuntId != "" && in.Type == "client_snapshot" {
+		return paths.NewHuntPathManager(in.HuntId).ClientSnapshot()
+
 	} else if in.NotebookId != "" && in.CellId != "" {
 		return reporting.NewNotebookPathManager(in.NotebookId).Cell(
 			in.CellId).QueryStorage(in.TableId)
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/sirupsen/logrus"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/flows"
+	"www.velocidex.com/golang/velociraptor/logging"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+func (self *ApiServer) ListHuntTemplates(
+	ctx context.Context,
+	in *empty.Empty) (*api_proto.ListHuntTemplatesResponse, error) {
+
+	defer Instrument("ListHuntTemplates")()
+
+	user_name := GetGRPCUserInfo(self.config, ctx).Name
+	permissions := acls.READ_RESULTS
+	perm, err := acls.CheckAccess(self.config, user_name, permissions)
+	if !perm || err != nil {
+		return nil, status.Error(codes.PermissionDenied,
+			"User is not allowed to view hunt templates.")
+	}
+
+	return flows.ListHuntTemplates(self.config)
+}
+
+func (self *ApiServer) GetHuntTemplate(
+	ctx context.Context,
+	in *api_proto.HuntTemplateRequest) (*api_proto.HuntTemplate, error) {
+
+	defer Instrument("GetHuntTemplate")()
+
+	user_name := GetGRPCUserInfo(self.config, ctx).Name
+	permissions := acls.READ_RESULTS
+	perm, err := acls.CheckAccess(self.config, user_name, permissions)
+	if !perm || err != nil {
+		return nil, status.Error(codes.PermissionDenied,
+			"User is not allowed to view hunt templates.")
+	}
+
+	return flows.GetHuntTemplate(self.config, in.Name)
+}
+
+func (self *ApiServer) SetHuntTemplate(
+	ctx context.Context,
+	in *api_proto.HuntTemplate) (*api_proto.HuntTemplate, error) {
+
+	defer Instrument("SetHuntTemplate")()
+
+	// Log this event as an Audit event.
+	in.Creator = GetGRPCUserInfo(self.config, ctx).Name
+
+	permissions := acls.COLLECT_CLIENT
+	perm, err := acls.CheckAccess(self.config, in.Creator, permissions)
+	if !perm || err != nil {
+		return nil, status.Error(codes.PermissionDenied,
+			"User is not allowed to modify hunt templates.")
+	}
+
+	logging.GetLogger(self.config, &logging.Audit).
+		WithFields(logrus.Fields{
+			"user":     in.Creator,
+			"template": in.Name,
+			"details":  fmt.Sprintf("%v", in),
+		}).Info("SetHuntTemplate")
+
+	err = flows.SaveHuntTemplate(self.config, in)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return flows.GetHuntTemplate(self.config, in.Name)
+}
+
+func (self *ApiServer) DeleteHuntTemplate(
+	ctx context.Context,
+	in *api_proto.HuntTemplateRequest) (*empty.Empty, error) {
+
+	defer Instrument("DeleteHuntTemplate")()
+
+	user_name := GetGRPCUserInfo(self.config, ctx).Name
+	permissions := acls.COLLECT_CLIENT
+	perm, err := acls.CheckAccess(self.config, user_name, permissions)
+	if !perm || err != nil {
+		return nil, status.Error(codes.PermissionDenied,
+			"User is not allowed to modify hunt templates.")
+	}
+
+	logging.GetLogger(self.config, &logging.Audit).
+		WithFields(logrus.Fields{
+			"user":     user_name,
+			"template": in.Name,
+		}).Info("DeleteHuntTemplate")
+
+	err = flows.DeleteHuntTemplate(self.config, in.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (self *ApiServer) CreateHuntFromTemplate(
+	ctx context.Context,
+	in *api_proto.CreateHuntFromTemplateRequest) (
+	*api_proto.StartFlowResponse, error) {
+
+	defer Instrument("CreateHuntFromTemplate")()
+
+	overrides := in.Overrides
+	if overrides == nil {
+		overrides = &api_proto.Hunt{}
+	}
+
+	// Log this event as an Audit event.
+	overrides.Creator = GetGRPCUserInfo(self.config, ctx).Name
+	overrides.HuntId = flows.GetNewHuntId()
+
+	permissions := acls.COLLECT_CLIENT
+	perm, err := acls.CheckAccess(self.config, overrides.Creator, permissions)
+	if !perm || err != nil {
+		return nil, status.Error(codes.PermissionDenied,
+			"User is not allowed to launch hunts.")
+	}
+
+	logging.GetLogger(self.config, &logging.Audit).
+		WithFields(logrus.Fields{
+			"user":     overrides.Creator,
+			"hunt_id":  overrides.HuntId,
+			"template": in.Name,
+			"details":  fmt.Sprintf("%v", overrides),
+		}).Info("CreateHuntFromTemplate")
+
+	acl_manager := vql_subsystem.NewServerACLManager(
+		self.config, overrides.Creator)
+	hunt_id, err := flows.CreateHuntFromTemplate(
+		ctx, self.config, acl_manager, in.Name, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api_proto.StartFlowResponse{FlowId: hunt_id}, nil
+}
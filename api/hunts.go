@@ -1,6 +1,7 @@
 package api
 
 import (
+	"github.com/sirupsen/logrus"
 	context "golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -10,8 +11,10 @@ import (
 	"www.velocidex.com/golang/velociraptor/file_store/csv"
 	"www.velocidex.com/golang/velociraptor/file_store/result_sets"
 	"www.velocidex.com/golang/velociraptor/flows"
+	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_manager"
 	"www.velocidex.com/golang/velociraptor/utils"
 )
 
@@ -75,3 +78,28 @@ func (self *ApiServer) GetHuntFlows(
 	}
 	return result, nil
 }
+
+func (self *ApiServer) RetryFailedClients(
+	ctx context.Context,
+	in *api_proto.RetryFailedClientsRequest) (*api_proto.RetryFailedClientsResponse, error) {
+
+	defer Instrument("RetryFailedClients")()
+
+	// Same permission required to modify hunts.
+	user_name := GetGRPCUserInfo(self.config, ctx).Name
+	permissions := acls.COLLECT_CLIENT
+	perm, err := acls.CheckAccess(self.config, user_name, permissions)
+	if !perm || err != nil {
+		return nil, status.Error(codes.PermissionDenied,
+			"User is not allowed to retry hunt clients.")
+	}
+
+	// Log this event as an Audit event.
+	logging.GetLogger(self.config, &logging.Audit).
+		WithFields(logrus.Fields{
+			"user":    user_name,
+			"hunt_id": in.HuntId,
+		}).Info("RetryFailedClients")
+
+	return hunt_manager.RetryFailedClients(ctx, self.config, in.HuntId)
+}
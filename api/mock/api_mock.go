@@ -159,6 +159,26 @@ func (mr *MockAPIClientMockRecorder) CreateHunt(arg0, arg1 interface{}, arg2 ...
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHunt", reflect.TypeOf((*MockAPIClient)(nil).CreateHunt), varargs...)
 }
 
+// CreateHuntFromTemplate mocks base method
+func (m *MockAPIClient) CreateHuntFromTemplate(arg0 context.Context, arg1 *proto0.CreateHuntFromTemplateRequest, arg2 ...grpc.CallOption) (*proto0.StartFlowResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateHuntFromTemplate", varargs...)
+	ret0, _ := ret[0].(*proto0.StartFlowResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHuntFromTemplate indicates an expected call of CreateHuntFromTemplate
+func (mr *MockAPIClientMockRecorder) CreateHuntFromTemplate(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHuntFromTemplate", reflect.TypeOf((*MockAPIClient)(nil).CreateHuntFromTemplate), varargs...)
+}
+
 // CreateNotebookDownloadFile mocks base method
 func (m *MockAPIClient) CreateNotebookDownloadFile(arg0 context.Context, arg1 *proto0.NotebookExportRequest, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
 	m.ctrl.T.Helper()
@@ -179,6 +199,26 @@ func (mr *MockAPIClientMockRecorder) CreateNotebookDownloadFile(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotebookDownloadFile", reflect.TypeOf((*MockAPIClient)(nil).CreateNotebookDownloadFile), varargs...)
 }
 
+// DeleteHuntTemplate mocks base method
+func (m *MockAPIClient) DeleteHuntTemplate(arg0 context.Context, arg1 *proto0.HuntTemplateRequest, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteHuntTemplate", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteHuntTemplate indicates an expected call of DeleteHuntTemplate
+func (mr *MockAPIClientMockRecorder) DeleteHuntTemplate(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHuntTemplate", reflect.TypeOf((*MockAPIClient)(nil).DeleteHuntTemplate), varargs...)
+}
+
 // ExportNotebook mocks base method
 func (m *MockAPIClient) ExportNotebook(arg0 context.Context, arg1 *proto0.NotebookExportRequest, arg2 ...grpc.CallOption) (*emptypb.Empty, error) {
 	m.ctrl.T.Helper()
@@ -419,6 +459,26 @@ func (mr *MockAPIClientMockRecorder) GetHuntResults(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHuntResults", reflect.TypeOf((*MockAPIClient)(nil).GetHuntResults), varargs...)
 }
 
+// GetHuntTemplate mocks base method
+func (m *MockAPIClient) GetHuntTemplate(arg0 context.Context, arg1 *proto0.HuntTemplateRequest, arg2 ...grpc.CallOption) (*proto0.HuntTemplate, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetHuntTemplate", varargs...)
+	ret0, _ := ret[0].(*proto0.HuntTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHuntTemplate indicates an expected call of GetHuntTemplate
+func (mr *MockAPIClientMockRecorder) GetHuntTemplate(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHuntTemplate", reflect.TypeOf((*MockAPIClient)(nil).GetHuntTemplate), varargs...)
+}
+
 // GetKeywordCompletions mocks base method
 func (m *MockAPIClient) GetKeywordCompletions(arg0 context.Context, arg1 *emptypb.Empty, arg2 ...grpc.CallOption) (*proto0.KeywordCompletions, error) {
 	m.ctrl.T.Helper()
@@ -699,6 +759,26 @@ func (mr *MockAPIClientMockRecorder) ListClients(arg0, arg1 interface{}, arg2 ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClients", reflect.TypeOf((*MockAPIClient)(nil).ListClients), varargs...)
 }
 
+// ListHuntTemplates mocks base method
+func (m *MockAPIClient) ListHuntTemplates(arg0 context.Context, arg1 *emptypb.Empty, arg2 ...grpc.CallOption) (*proto0.ListHuntTemplatesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListHuntTemplates", varargs...)
+	ret0, _ := ret[0].(*proto0.ListHuntTemplatesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHuntTemplates indicates an expected call of ListHuntTemplates
+func (mr *MockAPIClientMockRecorder) ListHuntTemplates(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHuntTemplates", reflect.TypeOf((*MockAPIClient)(nil).ListHuntTemplates), varargs...)
+}
+
 // ListHunts mocks base method
 func (m *MockAPIClient) ListHunts(arg0 context.Context, arg1 *proto0.ListHuntsRequest, arg2 ...grpc.CallOption) (*proto0.ListHuntsResponse, error) {
 	m.ctrl.T.Helper()
@@ -839,6 +919,26 @@ func (mr *MockAPIClientMockRecorder) Query(arg0, arg1 interface{}, arg2 ...inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockAPIClient)(nil).Query), varargs...)
 }
 
+// RetryFailedClients mocks base method
+func (m *MockAPIClient) RetryFailedClients(arg0 context.Context, arg1 *proto0.RetryFailedClientsRequest, arg2 ...grpc.CallOption) (*proto0.RetryFailedClientsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RetryFailedClients", varargs...)
+	ret0, _ := ret[0].(*proto0.RetryFailedClientsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryFailedClients indicates an expected call of RetryFailedClients
+func (mr *MockAPIClientMockRecorder) RetryFailedClients(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryFailedClients", reflect.TypeOf((*MockAPIClient)(nil).RetryFailedClients), varargs...)
+}
+
 // SetArtifactFile mocks base method
 func (m *MockAPIClient) SetArtifactFile(arg0 context.Context, arg1 *proto0.SetArtifactRequest, arg2 ...grpc.CallOption) (*proto0.APIResponse, error) {
 	m.ctrl.T.Helper()
@@ -919,6 +1019,26 @@ func (mr *MockAPIClientMockRecorder) SetGUIOptions(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGUIOptions", reflect.TypeOf((*MockAPIClient)(nil).SetGUIOptions), varargs...)
 }
 
+// SetHuntTemplate mocks base method
+func (m *MockAPIClient) SetHuntTemplate(arg0 context.Context, arg1 *proto0.HuntTemplate, arg2 ...grpc.CallOption) (*proto0.HuntTemplate, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetHuntTemplate", varargs...)
+	ret0, _ := ret[0].(*proto0.HuntTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetHuntTemplate indicates an expected call of SetHuntTemplate
+func (mr *MockAPIClientMockRecorder) SetHuntTemplate(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHuntTemplate", reflect.TypeOf((*MockAPIClient)(nil).SetHuntTemplate), varargs...)
+}
+
 // SetServerMonitoringState mocks base method
 func (m *MockAPIClient) SetServerMonitoringState(arg0 context.Context, arg1 *proto2.ArtifactCollectorArgs, arg2 ...grpc.CallOption) (*proto2.ArtifactCollectorArgs, error) {
 	m.ctrl.T.Helper()
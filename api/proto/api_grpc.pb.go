@@ -28,6 +28,17 @@ type APIClient interface {
 	ModifyHunt(ctx context.Context, in *Hunt, opts ...grpc.CallOption) (*empty.Empty, error)
 	GetHuntFlows(ctx context.Context, in *GetTableRequest, opts ...grpc.CallOption) (*GetTableResponse, error)
 	GetHuntResults(ctx context.Context, in *GetHuntResultsRequest, opts ...grpc.CallOption) (*GetTableResponse, error)
+	// Schedule the hunt again on the clients whose collection
+	// failed.
+	RetryFailedClients(ctx context.Context, in *RetryFailedClientsRequest, opts ...grpc.CallOption) (*RetryFailedClientsResponse, error)
+	// Hunt templates.
+	ListHuntTemplates(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListHuntTemplatesResponse, error)
+	GetHuntTemplate(ctx context.Context, in *HuntTemplateRequest, opts ...grpc.CallOption) (*HuntTemplate, error)
+	// Creates the template or replaces an existing template with
+	// the same name.
+	SetHuntTemplate(ctx context.Context, in *HuntTemplate, opts ...grpc.CallOption) (*HuntTemplate, error)
+	DeleteHuntTemplate(ctx context.Context, in *HuntTemplateRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	CreateHuntFromTemplate(ctx context.Context, in *CreateHuntFromTemplateRequest, opts ...grpc.CallOption) (*StartFlowResponse, error)
 	// Clients.
 	NotifyClients(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	LabelClients(ctx context.Context, in *LabelClientsRequest, opts ...grpc.CallOption) (*APIResponse, error)
@@ -162,6 +173,60 @@ func (c *aPIClient) GetHuntResults(ctx context.Context, in *GetHuntResultsReques
 	return out, nil
 }
 
+func (c *aPIClient) RetryFailedClients(ctx context.Context, in *RetryFailedClientsRequest, opts ...grpc.CallOption) (*RetryFailedClientsResponse, error) {
+	out := new(RetryFailedClientsResponse)
+	err := c.cc.Invoke(ctx, "/proto.API/RetryFailedClients", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListHuntTemplates(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListHuntTemplatesResponse, error) {
+	out := new(ListHuntTemplatesResponse)
+	err := c.cc.Invoke(ctx, "/proto.API/ListHuntTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetHuntTemplate(ctx context.Context, in *HuntTemplateRequest, opts ...grpc.CallOption) (*HuntTemplate, error) {
+	out := new(HuntTemplate)
+	err := c.cc.Invoke(ctx, "/proto.API/GetHuntTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) SetHuntTemplate(ctx context.Context, in *HuntTemplate, opts ...grpc.CallOption) (*HuntTemplate, error) {
+	out := new(HuntTemplate)
+	err := c.cc.Invoke(ctx, "/proto.API/SetHuntTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) DeleteHuntTemplate(ctx context.Context, in *HuntTemplateRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/proto.API/DeleteHuntTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) CreateHuntFromTemplate(ctx context.Context, in *CreateHuntFromTemplateRequest, opts ...grpc.CallOption) (*StartFlowResponse, error) {
+	out := new(StartFlowResponse)
+	err := c.cc.Invoke(ctx, "/proto.API/CreateHuntFromTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *aPIClient) NotifyClients(ctx context.Context, in *NotificationRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
 	err := c.cc.Invoke(ctx, "/proto.API/NotifyClients", in, out, opts...)
@@ -637,6 +702,17 @@ type APIServer interface {
 	ModifyHunt(context.Context, *Hunt) (*empty.Empty, error)
 	GetHuntFlows(context.Context, *GetTableRequest) (*GetTableResponse, error)
 	GetHuntResults(context.Context, *GetHuntResultsRequest) (*GetTableResponse, error)
+	// Schedule the hunt again on the clients whose collection
+	// failed.
+	RetryFailedClients(context.Context, *RetryFailedClientsRequest) (*RetryFailedClientsResponse, error)
+	// Hunt templates.
+	ListHuntTemplates(context.Context, *empty.Empty) (*ListHuntTemplatesResponse, error)
+	GetHuntTemplate(context.Context, *HuntTemplateRequest) (*HuntTemplate, error)
+	// Creates the template or replaces an existing template with
+	// the same name.
+	SetHuntTemplate(context.Context, *HuntTemplate) (*HuntTemplate, error)
+	DeleteHuntTemplate(context.Context, *HuntTemplateRequest) (*empty.Empty, error)
+	CreateHuntFromTemplate(context.Context, *CreateHuntFromTemplateRequest) (*StartFlowResponse, error)
 	// Clients.
 	NotifyClients(context.Context, *NotificationRequest) (*empty.Empty, error)
 	LabelClients(context.Context, *LabelClientsRequest) (*APIResponse, error)
@@ -732,6 +808,24 @@ func (UnimplementedAPIServer) GetHuntFlows(context.Context, *GetTableRequest) (*
 func (UnimplementedAPIServer) GetHuntResults(context.Context, *GetHuntResultsRequest) (*GetTableResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetHuntResults not implemented")
 }
+func (UnimplementedAPIServer) RetryFailedClients(context.Context, *RetryFailedClientsRequest) (*RetryFailedClientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryFailedClients not implemented")
+}
+func (UnimplementedAPIServer) ListHuntTemplates(context.Context, *empty.Empty) (*ListHuntTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHuntTemplates not implemented")
+}
+func (UnimplementedAPIServer) GetHuntTemplate(context.Context, *HuntTemplateRequest) (*HuntTemplate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHuntTemplate not implemented")
+}
+func (UnimplementedAPIServer) SetHuntTemplate(context.Context, *HuntTemplate) (*HuntTemplate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetHuntTemplate not implemented")
+}
+func (UnimplementedAPIServer) DeleteHuntTemplate(context.Context, *HuntTemplateRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteHuntTemplate not implemented")
+}
+func (UnimplementedAPIServer) CreateHuntFromTemplate(context.Context, *CreateHuntFromTemplateRequest) (*StartFlowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateHuntFromTemplate not implemented")
+}
 func (UnimplementedAPIServer) NotifyClients(context.Context, *NotificationRequest) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NotifyClients not implemented")
 }
@@ -1000,6 +1094,114 @@ func _API_GetHuntResults_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_RetryFailedClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryFailedClientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).RetryFailedClients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/RetryFailedClients",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).RetryFailedClients(ctx, req.(*RetryFailedClientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_ListHuntTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).ListHuntTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/ListHuntTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ListHuntTemplates(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_GetHuntTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HuntTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetHuntTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/GetHuntTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetHuntTemplate(ctx, req.(*HuntTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_SetHuntTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HuntTemplate)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).SetHuntTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/SetHuntTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).SetHuntTemplate(ctx, req.(*HuntTemplate))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_DeleteHuntTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HuntTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).DeleteHuntTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/DeleteHuntTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).DeleteHuntTemplate(ctx, req.(*HuntTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_CreateHuntFromTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateHuntFromTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).CreateHuntFromTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.API/CreateHuntFromTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).CreateHuntFromTemplate(ctx, req.(*CreateHuntFromTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_NotifyClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NotificationRequest)
 	if err := dec(in); err != nil {
@@ -1913,6 +2115,30 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetHuntResults",
 			Handler:    _API_GetHuntResults_Handler,
 		},
+		{
+			MethodName: "RetryFailedClients",
+			Handler:    _API_RetryFailedClients_Handler,
+		},
+		{
+			MethodName: "ListHuntTemplates",
+			Handler:    _API_ListHuntTemplates_Handler,
+		},
+		{
+			MethodName: "GetHuntTemplate",
+			Handler:    _API_GetHuntTemplate_Handler,
+		},
+		{
+			MethodName: "SetHuntTemplate",
+			Handler:    _API_SetHuntTemplate_Handler,
+		},
+		{
+			MethodName: "DeleteHuntTemplate",
+			Handler:    _API_DeleteHuntTemplate_Handler,
+		},
+		{
+			MethodName: "CreateHuntFromTemplate",
+			Handler:    _API_CreateHuntFromTemplate_Handler,
+		},
 		{
 			MethodName: "NotifyClients",
 			Handler:    _API_NotifyClients_Handler,
@@ -30,6 +30,7 @@ const (
 	FLOW_PREFIX             = "F."
 	FOREMAN_WELL_KNOWN_FLOW = "E.Foreman"
 	HUNT_PREFIX             = "H."
+	CAMPAIGN_PREFIX         = "CMP."
 
 	// The GUI uses this as the client index.
 	CLIENT_INDEX_URN = "/client_index/"
@@ -49,6 +50,10 @@ const (
 	MAX_MEMORY    = 5 * 1024 * 1024
 	MAX_POST_SIZE = 5 * 1024 * 1024
 
+	// Default limits on hunt creation requests.
+	MAX_HUNT_ARTIFACTS       = 1000
+	MAX_HUNT_PARAMETER_BYTES = 10 * 1024 * 1024
+
 	// Filestore paths for artifacts must begin with this prefix.
 	ARTIFACT_DEFINITION_PREFIX = "/artifact_definitions/"
 
@@ -94,4 +99,6 @@ type key int
 var (
 	HuntIdRegex    = regexp.MustCompile(`^H\.[^.]+$`)
 	STOP_ITERATION = errors.New("Stop Iteration")
+
+	CampaignIdRegex = regexp.MustCompile(`^CMP\.[^./]+$`)
 )
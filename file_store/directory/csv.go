@@ -75,7 +75,11 @@ func ReadRowsCSV(
 					return
 				}
 
-				output <- dict
+				select {
+				case <-ctx.Done():
+					return
+				case output <- dict:
+				}
 			}
 		}
 	}()
@@ -66,7 +66,11 @@ func ReadRowsJSON(
 					}
 				}
 
-				output <- item
+				select {
+				case <-ctx.Done():
+					return
+				case output <- item:
+				}
 			}
 		}
 	}()
@@ -23,7 +23,10 @@ func GetTimeRange(
 		defer cancel()
 
 		for prop := range path_manager.GeneratePaths(sub_ctx) {
-			if start_time > 0 && prop.EndTime < start_time {
+			// Files without a time range (e.g. a
+			// non-event artifact) are filtered by row.
+			if start_time > 0 && prop.EndTime > 0 &&
+				prop.EndTime < start_time {
 				continue
 			}
 
@@ -39,7 +42,11 @@ func GetTimeRange(
 			}
 
 			for item := range row_chan {
-				output <- item
+				select {
+				case <-sub_ctx.Done():
+					return
+				case output <- item:
+				}
 			}
 		}
 
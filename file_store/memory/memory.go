@@ -148,9 +148,11 @@ func (self *MemoryFileStore) ListDirectory(dirname string) ([]os.FileInfo, error
 	}
 	result := []os.FileInfo{}
 	for _, file := range files {
+		full_path := path.Join(dirname, file)
 		result = append(result, &vtesting.MockFileInfo{
 			Name_:     file,
-			FullPath_: path.Join(dirname, file),
+			FullPath_: full_path,
+			Size_:     int64(len(self.Data[full_path])),
 		})
 	}
 
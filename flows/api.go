@@ -18,7 +18,10 @@
 package flows
 
 import (
+	"archive/zip"
 	"context"
+	"io"
+	"io/ioutil"
 	"path"
 	"sort"
 	"strings"
@@ -32,10 +35,13 @@ import (
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
 )
 
 var (
@@ -197,22 +203,176 @@ func getAvailableDownloadFiles(config_obj *config_proto.Config,
 
 	for _, item := range files {
 		if strings.HasSuffix(item.Name(), ".lock") ||
+			strings.HasSuffix(item.Name(), ".sha256") ||
+			strings.HasSuffix(item.Name(), DOWNLOAD_INFO_EXT) ||
 			!item.Mode().IsRegular() {
 			continue
 		}
 
-		result.Files = append(result.Files, &api_proto.AvailableDownloadFile{
+		download_file := &api_proto.AvailableDownloadFile{
 			Name:     item.Name(),
 			Path:     path.Join(download_path, item.Name()),
 			Size:     uint64(item.Size()),
 			Date:     item.ModTime().UTC().Format(time.RFC3339),
 			Complete: is_complete(item.Name()),
-		})
+		}
+
+		// Files still being written can not be opened yet.
+		if !download_file.Complete {
+			progress, err := GetDownloadProgress(
+				file_store_factory, download_file.Path)
+			if err == nil {
+				if progress.Started > 0 {
+					download_file.PreparationStarted = time.Unix(
+						progress.Started, 0).UTC().Format(time.RFC3339)
+				}
+				download_file.PreparedMembers = progress.Members
+				download_file.PreparedBytes = progress.Bytes
+				download_file.PreparedClients = progress.Clients
+				download_file.TotalClients = progress.TotalClients
+			}
+		} else {
+			inspectCompleteDownload(file_store_factory, download_file, item)
+		}
+
+		result.Files = append(result.Files, download_file)
 	}
 
 	return result, nil
 }
 
+// Zip compression methods prepared downloads may use, by the name
+// used in download requests. XZ (method 95) produces much smaller
+// files than deflate for text heavy results but is not supported by
+// all unzip tools - 7-Zip can open it.
+var download_compression_methods = map[string]uint16{
+	"deflate": zip.Deflate,
+	"store":   zip.Store,
+	"xz":      95,
+}
+
+// The zip method for the named download compression. Deflate is the
+// default.
+func GetDownloadCompressionMethod(name string) (uint16, error) {
+	if name == "" {
+		return zip.Deflate, nil
+	}
+
+	method, pres := download_compression_methods[strings.ToLower(name)]
+	if !pres {
+		return 0, errors.Errorf("Unknown download compression %v", name)
+	}
+	return method, nil
+}
+
+// Written to a download's lock file while the download is being
+// prepared. Times are in seconds since the epoch.
+type DownloadProgress struct {
+	Started int64
+	Updated int64
+
+	// The number of zip members written so far and their size
+	// before compression.
+	Members uint64
+	Bytes   uint64
+
+	// For hunt downloads, how many of the hunt's clients were
+	// exported so far out of the total.
+	Clients      uint64
+	TotalClients uint64
+}
+
+// The progress of a download which is being prepared, or an error if
+// it is not. Lock files without progress only tell us when they were
+// written.
+func GetDownloadProgress(file_store_factory api.FileStore,
+	filename string) (*DownloadProgress, error) {
+	fd, err := file_store_factory.ReadFile(filename + ".lock")
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	data, err := ioutil.ReadAll(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DownloadProgress{}
+	err = json.Unmarshal(data, result)
+	if err != nil || result.Started == 0 {
+		result = &DownloadProgress{}
+		stat, err := fd.Stat()
+		if err == nil && !stat.ModTime().IsZero() {
+			result.Started = stat.ModTime().Unix()
+			result.Updated = result.Started
+		}
+	}
+
+	return result, nil
+}
+
+// The hash of the download recorded when it was prepared. Hashing
+// the download here would mean reading all of it again.
+func readDownloadHash(file_store_factory api.FileStore,
+	filename string) string {
+	fd, err := file_store_factory.ReadFile(filename + ".sha256")
+	if err != nil {
+		return ""
+	}
+	defer fd.Close()
+
+	// A hex encoded SHA-256 hash is 64 characters.
+	buf := make([]byte, 64)
+	n, _ := io.ReadFull(fd, buf)
+	return string(buf[:n])
+}
+
+// Check if any of the zip file's members are encrypted and which
+// compression the members use.
+func inspectDownloadZip(file_store_factory api.FileStore,
+	filename string) (password_protected bool, compression string) {
+	if !strings.HasSuffix(filename, ".zip") {
+		return false, ""
+	}
+
+	fd, err := file_store_factory.ReadFile(filename)
+	if err != nil {
+		return false, ""
+	}
+	defer fd.Close()
+
+	stat, err := fd.Stat()
+	if err != nil {
+		return false, ""
+	}
+
+	zip_reader, err := zip.NewReader(utils.ReaderAtter{Reader: fd}, stat.Size())
+	if err != nil {
+		return false, ""
+	}
+
+	for _, member := range zip_reader.File {
+		// Bit 0 of the general purpose flags marks
+		// encryption. The method of encrypted members is not
+		// the real compression method.
+		if member.Flags&0x1 != 0 {
+			password_protected = true
+			continue
+		}
+
+		if compression == "" {
+			for name, method := range download_compression_methods {
+				if method == member.Method {
+					compression = name
+				}
+			}
+		}
+	}
+
+	return password_protected, compression
+}
+
 func CancelFlow(
 	ctx context.Context,
 	config_obj *config_proto.Config,
@@ -0,0 +1,243 @@
+package flows
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+var (
+	// Getting an artifact from the repository compiles and copies
+	// it, which is expensive when every GetHunt() and ListHunts()
+	// does it for each artifact. We only need the source names and
+	// the hash of the definition so we keep a bounded cache of
+	// those.
+	artifact_sources_cache = newArtifactSourcesCache(1000)
+
+	artifactSourcesLookupCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "artifact_sources_repository_lookups",
+		Help: "Total number of artifact source lookups not served from the cache.",
+	})
+)
+
+// What we need to know about an artifact for its hunts.
+type artifactSummary struct {
+	sources []string
+
+	// The hash of the artifact's definition (see
+	// hashArtifactDefinition).
+	hash string
+}
+
+type artifactSourcesEntry struct {
+	name string
+
+	// The repository the summary was read from. If the global
+	// repository is replaced the entry no longer applies.
+	repository services.Repository
+	summary    artifactSummary
+}
+
+// An LRU cache of artifact name -> summary. Entries are
+// invalidated when the repository manager reports an artifact was
+// modified, so the cache is only used while something is watching
+// for those modifications (see StartArtifactSourcesCache).
+type artifactSourcesCache struct {
+	mu sync.Mutex
+
+	size     int
+	lru      *list.List
+	entries  map[string]*list.Element
+	watchers int
+
+	// Incremented on each invalidation so a lookup racing with a
+	// modification does not store the old sources.
+	generation uint64
+}
+
+func newArtifactSourcesCache(size int) *artifactSourcesCache {
+	return &artifactSourcesCache{
+		size:    size,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Returns the cached summary and the current generation to pass to
+// Set() on a miss.
+func (self *artifactSourcesCache) Get(
+	repository services.Repository, name string) (artifactSummary, uint64, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.watchers == 0 {
+		return artifactSummary{}, self.generation, false
+	}
+
+	element, pres := self.entries[name]
+	if !pres {
+		return artifactSummary{}, self.generation, false
+	}
+
+	entry := element.Value.(*artifactSourcesEntry)
+	if entry.repository != repository {
+		return artifactSummary{}, self.generation, false
+	}
+
+	self.lru.MoveToFront(element)
+	return artifactSummary{
+		sources: append([]string{}, entry.summary.sources...),
+		hash:    entry.summary.hash,
+	}, self.generation, true
+}
+
+func (self *artifactSourcesCache) Set(
+	repository services.Repository, name string,
+	summary artifactSummary, generation uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.watchers == 0 || generation != self.generation {
+		return
+	}
+
+	entry := &artifactSourcesEntry{
+		name:       name,
+		repository: repository,
+		summary: artifactSummary{
+			sources: append([]string{}, summary.sources...),
+			hash:    summary.hash,
+		},
+	}
+
+	element, pres := self.entries[name]
+	if pres {
+		element.Value = entry
+		self.lru.MoveToFront(element)
+		return
+	}
+
+	self.entries[name] = self.lru.PushFront(entry)
+	for self.lru.Len() > self.size {
+		oldest := self.lru.Back()
+		self.lru.Remove(oldest)
+		delete(self.entries, oldest.Value.(*artifactSourcesEntry).name)
+	}
+}
+
+// Drop the named artifact from the cache.
+func (self *artifactSourcesCache) Invalidate(name string) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.generation++
+	element, pres := self.entries[name]
+	if pres {
+		self.lru.Remove(element)
+		delete(self.entries, name)
+	}
+}
+
+func (self *artifactSourcesCache) Len() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.lru.Len()
+}
+
+func (self *artifactSourcesCache) addWatcher() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.watchers++
+}
+
+// Once nothing is watching for modifications the cached entries
+// can not be trusted any more.
+func (self *artifactSourcesCache) removeWatcher() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.watchers--
+	if self.watchers == 0 {
+		self.generation++
+		self.lru.Init()
+		self.entries = make(map[string]*list.Element)
+	}
+}
+
+// Summarize the named artifact from the repository, using the cache
+// when possible. Unknown artifacts are not cached since they may be
+// added to the repository later.
+func getArtifactSummary(
+	config_obj *config_proto.Config,
+	repository services.Repository, name string) (artifactSummary, bool) {
+	cached, generation, pres := artifact_sources_cache.Get(repository, name)
+	if pres {
+		return cached, true
+	}
+
+	artifactSourcesLookupCounter.Inc()
+	artifact_obj, pres := repository.Get(config_obj, name)
+	if !pres {
+		return artifactSummary{}, false
+	}
+
+	summary := artifactSummary{hash: hashArtifactDefinition(artifact_obj)}
+	for _, source := range artifact_obj.Sources {
+		summary.sources = append(summary.sources, source.Name)
+	}
+
+	artifact_sources_cache.Set(repository, name, summary, generation)
+	return summary, true
+}
+
+// Enable the artifact sources cache and invalidate its entries as
+// artifacts are modified through the repository manager.
+func StartArtifactSourcesCache(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	config_obj *config_proto.Config) error {
+
+	journal, err := services.GetJournal()
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("<green>Starting</> the artifact sources cache.")
+
+	events, cancel := journal.Watch("Server.Internal.ArtifactModification")
+	artifact_sources_cache.addWatcher()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		defer artifact_sources_cache.removeWatcher()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				name, pres := event.GetString("artifact")
+				if pres && name != "" {
+					artifact_sources_cache.Invalidate(name)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
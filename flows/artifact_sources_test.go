@@ -0,0 +1,210 @@
+package flows
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/services/launcher"
+	"www.velocidex.com/golang/velociraptor/services/notifications"
+	"www.velocidex.com/golang/velociraptor/services/repository"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/velociraptor/vtesting"
+)
+
+func (self *HuntTestSuite) TestArtifactSourcesCache() {
+	require.NoError(self.T(), self.sm.Start(StartArtifactSourcesCache))
+
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Cached
+sources:
+- name: First
+  query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Test.Artifact.Cached"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	get_sources := func() []string {
+		hunt_obj, err := GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: hunt_id})
+		assert.NoError(self.T(), err)
+		return hunt_obj.ArtifactSources
+	}
+
+	assert.Equal(self.T(), []string{"Test.Artifact.Cached/First"},
+		get_sources())
+
+	// Subsequent calls, including checking the hunt's artifact
+	// definitions, do not go to the repository.
+	lookups := testutil.ToFloat64(artifactSourcesLookupCounter)
+	for i := 0; i < 10; i++ {
+		assert.Equal(self.T(), []string{"Test.Artifact.Cached/First"},
+			get_sources())
+	}
+	assert.Equal(self.T(), lookups,
+		testutil.ToFloat64(artifactSourcesLookupCounter))
+
+	// Editing the artifact's sources invalidates the cache.
+	_, err = manager.SetArtifactFile(self.config_obj, "admin", `
+name: Test.Artifact.Cached
+sources:
+- name: First
+  query: SELECT * FROM info()
+- name: Second
+  query: SELECT * FROM info()
+`, "")
+	assert.NoError(self.T(), err)
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		return len(get_sources()) == 2
+	})
+	assert.Equal(self.T(), []string{
+		"Test.Artifact.Cached/First", "Test.Artifact.Cached/Second"},
+		get_sources())
+
+	// The changed definition is noticed too.
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), hunt_obj.StaleDefinition)
+	assert.Equal(self.T(), []string{"Test.Artifact.Cached"},
+		hunt_obj.ChangedArtifacts)
+
+	// Replacing the repository also invalidates the cache.
+	new_repository := manager.NewRepository()
+	_, err = new_repository.LoadYaml(`
+name: Test.Artifact.Cached
+sources:
+- name: Third
+  query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+	manager.SetGlobalRepositoryForTests(self.config_obj, new_repository)
+
+	assert.Equal(self.T(), []string{"Test.Artifact.Cached/Third"},
+		get_sources())
+}
+
+func TestArtifactSourcesCacheEviction(t *testing.T) {
+	cache := newArtifactSourcesCache(2)
+	cache.addWatcher()
+
+	global_repository := &repository.Repository{}
+	for _, name := range []string{"A", "B", "C"} {
+		_, generation, _ := cache.Get(global_repository, name)
+		cache.Set(global_repository, name,
+			artifactSummary{sources: []string{name}, hash: name}, generation)
+	}
+	assert.Equal(t, 2, cache.Len())
+
+	// The least recently used entry is evicted.
+	_, _, pres := cache.Get(global_repository, "A")
+	assert.False(t, pres)
+
+	summary, _, pres := cache.Get(global_repository, "C")
+	assert.True(t, pres)
+	assert.Equal(t, []string{"C"}, summary.sources)
+	assert.Equal(t, "C", summary.hash)
+
+	// A lookup racing with an invalidation is not stored.
+	_, generation, _ := cache.Get(global_repository, "D")
+	cache.Invalidate("D")
+	cache.Set(global_repository, "D",
+		artifactSummary{sources: []string{"D"}}, generation)
+	_, _, pres = cache.Get(global_repository, "D")
+	assert.False(t, pres)
+
+	// Without a watcher nothing is cached.
+	cache.removeWatcher()
+	assert.Equal(t, 0, cache.Len())
+	_, _, pres = cache.Get(global_repository, "C")
+	assert.False(t, pres)
+}
+
+/*
+Repeated GetHunt() calls on a hunt collecting 5 artifacts:
+
+BenchmarkGetHuntArtifactSources/Uncached   41673   25630 ns/op   5.000 lookups/op
+BenchmarkGetHuntArtifactSources/Cached     85868   13257 ns/op   0 lookups/op
+*/
+func BenchmarkGetHuntArtifactSources(b *testing.B) {
+	config_obj, err := new(config.Loader).WithFileLoader(
+		"../http_comms/test_data/server.config.yaml").
+		WithRequiredFrontend().WithWriteback().
+		LoadAndValidate()
+	require.NoError(b, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sm := services.NewServiceManager(ctx, config_obj)
+	defer sm.Close()
+
+	require.NoError(b, sm.Start(journal.StartJournalService))
+	require.NoError(b, sm.Start(notifications.StartNotificationService))
+	require.NoError(b, sm.Start(launcher.StartLauncherService))
+	require.NoError(b, sm.Start(hunt_dispatcher.StartHuntDispatcher))
+	require.NoError(b, sm.Start(repository.StartRepositoryManager))
+
+	hunt_id, err := CreateHunt(ctx, config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{
+					"Generic.Client.Info",
+					"Linux.Sys.Users",
+					"Windows.Sys.Users",
+					"Windows.System.Pslist",
+					"Generic.Client.Stats",
+				},
+			},
+		})
+	require.NoError(b, err)
+
+	get_hunt := func(b *testing.B) {
+		start := testutil.ToFloat64(artifactSourcesLookupCounter)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := GetHunt(config_obj,
+				&api_proto.GetHuntRequest{HuntId: hunt_id})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric((testutil.ToFloat64(artifactSourcesLookupCounter)-start)/float64(b.N),
+			"lookups/op")
+	}
+
+	b.Run("Uncached", get_hunt)
+
+	cache_ctx, cache_cancel := context.WithCancel(ctx)
+	wg := &sync.WaitGroup{}
+	require.NoError(b, StartArtifactSourcesCache(cache_ctx, wg, config_obj))
+
+	b.Run("Cached", get_hunt)
+
+	cache_cancel()
+	wg.Wait()
+}
@@ -420,11 +420,17 @@ func IsRequestComplete(
 
 		// Update any hunts if needed.
 		if constants.HuntIdRegex.MatchString(collection_context.Request.Creator) {
-			err := services.GetHuntDispatcher().ModifyHunt(
+			err := services.GetHuntDispatcher().UpdateHuntStats(
 				collection_context.Request.Creator,
 				func(hunt *api_proto.Hunt) error {
 					if hunt != nil && hunt.Stats != nil {
 						hunt.Stats.TotalClientsWithResults++
+
+						// Only the last request completes
+						// the collection.
+						if collection_context.OutstandingRequests <= 1 {
+							countHuntFlowRetrySuccess(hunt, collection_context)
+						}
 					}
 					return nil
 				})
@@ -477,17 +483,26 @@ func FailIfError(
 
 	// Update the hunt stats if this is a hunt.
 	if constants.HuntIdRegex.MatchString(collection_context.Request.Creator) {
-		err := services.GetHuntDispatcher().ModifyHunt(
+		var retry *api_proto.HuntRetryRecord
+		err := services.GetHuntDispatcher().UpdateHuntStats(
 			collection_context.Request.Creator,
 			func(hunt *api_proto.Hunt) error {
 				if hunt != nil && hunt.Stats != nil {
-					hunt.Stats.TotalClientsWithErrors++
+					retry = countHuntFlowFailure(
+						hunt, collection_context, time.Now())
 				}
 				return nil
 			})
 		if err != nil {
 			return err
 		}
+
+		if retry != nil {
+			err = recordHuntRetry(config_obj, retry)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return errors.New(message.Status.ErrorMessage)
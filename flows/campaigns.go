@@ -0,0 +1,180 @@
+package flows
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"path"
+	"sort"
+	"strings"
+
+	errors "github.com/pkg/errors"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+func GetNewCampaignId() string {
+	result := make([]byte, 8)
+	buf := make([]byte, 4)
+
+	_, _ = rand.Read(buf)
+	hex.Encode(result, buf)
+
+	return constants.CAMPAIGN_PREFIX + string(result)
+}
+
+// Create a new campaign. Hunts join the campaign by setting its id
+// when they are created.
+func CreateCampaign(
+	config_obj *config_proto.Config,
+	campaign *api_proto.Campaign) (string, error) {
+
+	if strings.TrimSpace(campaign.Name) == "" {
+		return "", errors.New("Campaigns must have a name.")
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	campaign.CampaignId = GetNewCampaignId()
+	campaign.CreateTime = HuntTimeNow()
+
+	// The aggregated fields are never stored.
+	campaign.HuntIds = nil
+	campaign.RunningHunts = 0
+	campaign.Stats = nil
+
+	err = db.SetSubject(config_obj,
+		paths.CampaignPath(campaign.CampaignId), campaign)
+	if err != nil {
+		return "", err
+	}
+
+	return campaign.CampaignId, nil
+}
+
+// Get a campaign with the stats of its hunts.
+func GetCampaign(
+	config_obj *config_proto.Config,
+	campaign_id string) (*api_proto.Campaign, error) {
+
+	campaign, err := loadCampaign(config_obj, campaign_id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = aggregateCampaignStats(
+		map[string]*api_proto.Campaign{campaign_id: campaign})
+	return campaign, err
+}
+
+// List all the campaigns with the stats of their hunts, most recent
+// first.
+func ListCampaigns(
+	config_obj *config_proto.Config) (*api_proto.ListCampaignsResponse, error) {
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	urns, err := db.ListChildren(config_obj, paths.CampaignDirectory(), 0, 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api_proto.ListCampaignsResponse{}
+	campaigns := make(map[string]*api_proto.Campaign)
+	for _, urn := range urns {
+		campaign, err := loadCampaign(config_obj, path.Base(urn))
+		if err != nil {
+			continue
+		}
+		campaigns[campaign.CampaignId] = campaign
+		result.Items = append(result.Items, campaign)
+	}
+
+	err = aggregateCampaignStats(campaigns)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result.Items, func(i, j int) bool {
+		return result.Items[i].CreateTime > result.Items[j].CreateTime
+	})
+
+	return result, nil
+}
+
+func loadCampaign(
+	config_obj *config_proto.Config,
+	campaign_id string) (*api_proto.Campaign, error) {
+
+	if !constants.CampaignIdRegex.MatchString(campaign_id) {
+		return nil, errors.Errorf("Invalid campaign id %v", campaign_id)
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := &api_proto.Campaign{}
+	err = db.GetSubject(config_obj, paths.CampaignPath(campaign_id), campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.CampaignId == "" {
+		return nil, errors.Errorf("Unknown campaign %v", campaign_id)
+	}
+
+	return campaign, nil
+}
+
+// Add up the stats of the hunts in each campaign.
+func aggregateCampaignStats(campaigns map[string]*api_proto.Campaign) error {
+	for _, campaign := range campaigns {
+		campaign.Stats = &api_proto.HuntStats{}
+	}
+
+	dispatcher, err := services.RequireHuntDispatcher()
+	if err != nil {
+		return err
+	}
+
+	err = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		campaign, pres := campaigns[hunt.CampaignId]
+		if !pres {
+			return nil
+		}
+
+		campaign.HuntIds = append(campaign.HuntIds, hunt.HuntId)
+		if hunt.State == api_proto.Hunt_RUNNING {
+			campaign.RunningHunts++
+		}
+
+		if hunt.Stats != nil {
+			campaign.Stats.TotalClientsScheduled += hunt.Stats.TotalClientsScheduled
+			campaign.Stats.TotalClientsWithResults += hunt.Stats.TotalClientsWithResults
+			campaign.Stats.TotalClientsWithoutResults += hunt.Stats.TotalClientsWithoutResults
+			campaign.Stats.TotalClientsWithErrors += hunt.Stats.TotalClientsWithErrors
+			campaign.Stats.TotalClientsRetrying += hunt.Stats.TotalClientsRetrying
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, campaign := range campaigns {
+		sort.Strings(campaign.HuntIds)
+	}
+
+	return nil
+}
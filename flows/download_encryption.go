@@ -0,0 +1,111 @@
+package flows
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	errors "github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/packet"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+
+	// Some keys still prefer RIPEMD160 and openpgp refuses to
+	// encrypt to them unless it is available.
+	_ "golang.org/x/crypto/ripemd160"
+)
+
+// Downloads may be encrypted to the public keys of the people who
+// need them rather than only password protected. The server only
+// ever sees the public keys so it can not decrypt the downloads
+// itself. Encrypted downloads carry this extension after the usual
+// .zip.
+const (
+	DOWNLOAD_ENCRYPTION_PGP = "pgp"
+	PGP_DOWNLOAD_EXT        = ".gpg"
+)
+
+// Parse the ASCII armored PGP public keys a download is encrypted
+// to. Private keys are refused - they must never be sent to the
+// server.
+func ParseDownloadPublicKeys(armored string) (openpgp.EntityList, error) {
+	if strings.Contains(armored, "PRIVATE KEY BLOCK") {
+		return nil, errors.New(
+			"Only public keys may be used to encrypt downloads, " +
+				"never upload private keys to the server.")
+	}
+
+	recipients, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid PGP public key")
+	}
+
+	for _, recipient := range recipients {
+		if recipient.PrivateKey != nil {
+			return nil, errors.New(
+				"Only public keys may be used to encrypt downloads, " +
+					"never upload private keys to the server.")
+		}
+	}
+
+	if len(recipients) == 0 {
+		return nil, errors.New("No PGP public keys given")
+	}
+
+	return recipients, nil
+}
+
+// Wrap the writer so everything written to it is encrypted to the
+// recipients. The returned writer must be closed to finish the
+// encrypted message.
+func EncryptDownload(fd io.Writer, filename string,
+	recipients openpgp.EntityList) (io.WriteCloser, error) {
+	return openpgp.Encrypt(fd, recipients, nil, &openpgp.FileHints{
+		IsBinary: true,
+		FileName: strings.TrimSuffix(path.Base(filename), PGP_DOWNLOAD_EXT),
+	}, nil)
+}
+
+// Fill in how an encrypted download may be decrypted. The key ids
+// of the recipients are read from the start of the message so we do
+// not need to remember them separately.
+func inspectDownloadEncryption(file_store_factory api.FileStore,
+	download_file *api_proto.AvailableDownloadFile) {
+	if !strings.HasSuffix(download_file.Name, PGP_DOWNLOAD_EXT) {
+		return
+	}
+
+	download_file.Encryption = DOWNLOAD_ENCRYPTION_PGP
+	download_file.DecryptionInstructions = fmt.Sprintf(
+		"This download is encrypted with PGP. Decrypt it with the "+
+			"private key matching one of the listed key ids, e.g. "+
+			"gpg --output %v --decrypt %v",
+		strings.TrimSuffix(download_file.Name, PGP_DOWNLOAD_EXT),
+		download_file.Name)
+
+	fd, err := file_store_factory.ReadFile(download_file.Path)
+	if err != nil {
+		return
+	}
+	defer fd.Close()
+
+	// The message starts with one encrypted session key packet per
+	// recipient.
+	packets := packet.NewReader(fd)
+	for {
+		p, err := packets.Next()
+		if err != nil {
+			return
+		}
+
+		key, ok := p.(*packet.EncryptedKey)
+		if !ok {
+			return
+		}
+
+		download_file.EncryptionKeyIds = append(download_file.EncryptionKeyIds,
+			fmt.Sprintf("%016X", key.KeyId))
+	}
+}
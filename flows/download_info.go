@@ -0,0 +1,117 @@
+package flows
+
+import (
+	"io/ioutil"
+	"os"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+// Inspecting a complete download means opening it, so the GUI
+// polling the available downloads would open every download over
+// and over. Instead the result is cached next to the download in a
+// file with this extension.
+const DOWNLOAD_INFO_EXT = ".info"
+
+// What we learned about a complete download by inspecting it.
+type downloadInfo struct {
+	// The download's size and modification time (in nanoseconds)
+	// when it was inspected. The info is stale once the download
+	// is prepared again.
+	Size    int64
+	ModTime int64
+
+	PasswordProtected      bool
+	Compression            string
+	Sha256                 string
+	Encryption             string
+	EncryptionKeyIds       []string
+	DecryptionInstructions string
+}
+
+// Fill in the details of a complete download, from the cached info
+// while it is current.
+func inspectCompleteDownload(file_store_factory api.FileStore,
+	download_file *api_proto.AvailableDownloadFile, stat os.FileInfo) {
+	info, err := readDownloadInfo(file_store_factory, download_file.Path)
+	if err == nil && info.Size == stat.Size() &&
+		info.ModTime == stat.ModTime().UnixNano() {
+		download_file.PasswordProtected = info.PasswordProtected
+		download_file.Compression = info.Compression
+		download_file.Sha256 = info.Sha256
+		download_file.Encryption = info.Encryption
+		download_file.EncryptionKeyIds = info.EncryptionKeyIds
+		download_file.DecryptionInstructions = info.DecryptionInstructions
+		return
+	}
+
+	download_file.PasswordProtected,
+		download_file.Compression = inspectDownloadZip(
+		file_store_factory, download_file.Path)
+	download_file.Sha256 = readDownloadHash(
+		file_store_factory, download_file.Path)
+	inspectDownloadEncryption(file_store_factory, download_file)
+
+	// The info is only a cache - we can always inspect the
+	// download again.
+	_ = writeDownloadInfo(file_store_factory, download_file.Path,
+		&downloadInfo{
+			Size:                   stat.Size(),
+			ModTime:                stat.ModTime().UnixNano(),
+			PasswordProtected:      download_file.PasswordProtected,
+			Compression:            download_file.Compression,
+			Sha256:                 download_file.Sha256,
+			Encryption:             download_file.Encryption,
+			EncryptionKeyIds:       download_file.EncryptionKeyIds,
+			DecryptionInstructions: download_file.DecryptionInstructions,
+		})
+}
+
+// Forget what we learned about the download, e.g. because it is
+// about to be prepared again.
+func ForgetDownloadInfo(file_store_factory api.FileStore,
+	filename string) error {
+	return file_store_factory.Delete(filename + DOWNLOAD_INFO_EXT)
+}
+
+func readDownloadInfo(file_store_factory api.FileStore,
+	filename string) (*downloadInfo, error) {
+	fd, err := file_store_factory.ReadFile(filename + DOWNLOAD_INFO_EXT)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	data, err := ioutil.ReadAll(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &downloadInfo{}
+	err = json.Unmarshal(data, result)
+	return result, err
+}
+
+func writeDownloadInfo(file_store_factory api.FileStore,
+	filename string, info *downloadInfo) error {
+	serialized, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	fd, err := file_store_factory.WriteFile(filename + DOWNLOAD_INFO_EXT)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	err = fd.Truncate()
+	if err != nil {
+		return err
+	}
+
+	_, err = fd.Write(serialized)
+	return err
+}
@@ -37,17 +37,37 @@ package flows
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"github.com/Velocidex/ordereddict"
 	errors "github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	constants "www.velocidex.com/golang/velociraptor/constants"
 	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/services"
 )
 
+var (
+	huntMatchEvaluationsCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "hunt_dispatcher_match_evaluations",
+		Help: "Total number of hunts evaluated against polling clients.",
+	})
+
+	huntMatchHistorgram = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "hunt_dispatcher_match_latency",
+			Help:    "Time in seconds to match the hunts against a polling client.",
+			Buckets: prometheus.ExponentialBuckets(0.0001, 4, 8),
+		},
+	)
+)
+
 // ForemanProcessMessage processes a ForemanCheckin message from the
 // client.
 func ForemanProcessMessage(
@@ -93,47 +113,163 @@ func ForemanProcessMessage(
 	}
 
 	// Nop - we need to lock and examine the hunts more carefully.
-	return dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+	hunts, err := huntsNewerThan(dispatcher, client_last_timestamp)
+	if err != nil || len(hunts) == 0 {
+		return err
+	}
+
+	// The hunt manager schedules the client's flows in the order
+	// of the participation rows, so the highest priority hunts
+	// go first, then the oldest.
+	sort.SliceStable(hunts, func(i, j int) bool {
+		if hunts[i].Priority != hunts[j].Priority {
+			return hunts[i].Priority > hunts[j].Priority
+		}
+		return hunts[i].CreateTime < hunts[j].CreateTime
+	})
+
+	// Do not overwhelm the client with too many hunts at once.
+	hunts, last_timestamp := limitClientHunts(
+		config_obj, client_id, hunts)
+
+	rows := []*ordereddict.Dict{}
+	for _, hunt := range hunts {
+		rows = append(rows, ordereddict.NewDict().
+			Set("HuntId", hunt.HuntId).
+			Set("ClientId", client_id).
+			Set("Participate", true))
+	}
+
+	// Notify the hunt manager that we need to hunt this client.
+	if len(rows) > 0 {
+		journal, err := services.GetJournal()
+		if err != nil {
+			return err
+		}
+
+		err = journal.PushRowsToArtifact(config_obj, rows,
+			"System.Hunt.Participation", client_id, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Let the client know it needs to update its foreman state.
+	err = QueueMessageForClient(
+		config_obj, client_id,
+		&crypto_proto.GrrMessage{
+			SessionId: constants.MONITORING_WELL_KNOWN_FLOW,
+			RequestId: constants.IgnoreResponseState,
+			UpdateForeman: &actions_proto.ForemanCheckin{
+				LastHuntTimestamp: last_timestamp,
+			},
+		})
+	if err != nil {
+		return err
+	}
+
+	return services.GetNotifier().NotifyListener(config_obj, client_id)
+}
+
+// Find the running hunts started after the client's last hunt
+// timestamp. Each poll is recorded once in the match metrics, rather
+// than for each hunt, to keep the dispatcher lock hold time down.
+func huntsNewerThan(
+	dispatcher services.IHuntDispatcher,
+	client_last_timestamp uint64) ([]*api_proto.Hunt, error) {
+	start := time.Now()
+	evaluations := 0
+	defer func() {
+		huntMatchEvaluationsCounter.Add(float64(evaluations))
+		huntMatchHistorgram.Observe(time.Since(start).Seconds())
+	}()
+
+	hunts := []*api_proto.Hunt{}
+	err := dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		evaluations++
+
 		// Hunt is stopped we dont care about it.
 		if hunt.State != api_proto.Hunt_RUNNING {
 			return nil
 		}
 
+		// Scheduled hunts only run through their clones.
+		if hunt.Schedule != "" {
+			return nil
+		}
+
 		// This hunt is not relevant to this client.
 		if hunt.StartTime <= client_last_timestamp {
 			return nil
 		}
 
-		journal, err := services.GetJournal()
-		if err != nil {
-			return err
+		hunts = append(hunts, &api_proto.Hunt{
+			HuntId:     hunt.HuntId,
+			CreateTime: hunt.CreateTime,
+			StartTime:  hunt.StartTime,
+			Priority:   hunt.Priority,
+		})
+		return nil
+	})
+
+	return hunts, err
+}
+
+func maxHuntsPerClientPoll(config_obj *config_proto.Config) int {
+	if config_obj.Defaults != nil &&
+		config_obj.Defaults.MaxHuntsPerClientPoll > 0 {
+		return int(config_obj.Defaults.MaxHuntsPerClientPoll)
+	}
+	return 10
+}
+
+// Pick the hunts to schedule on the client in this poll from the
+// hunts in priority order, and the hunt timestamp the client should
+// report next time. At most maxHuntsPerClientPoll hunts are
+// scheduled - the client's timestamp is then kept before the
+// deferred hunts so they are offered again on the next poll. Since
+// the scheduled hunts may be offered again too, hunts the client was
+// already scheduled on are skipped.
+func limitClientHunts(
+	config_obj *config_proto.Config,
+	client_id string,
+	hunts []*api_proto.Hunt) ([]*api_proto.Hunt, uint64) {
+
+	last_timestamp := uint64(0)
+	for _, hunt := range hunts {
+		if hunt.StartTime > last_timestamp {
+			last_timestamp = hunt.StartTime
 		}
+	}
+
+	max_hunts := maxHuntsPerClientPoll(config_obj)
+	if len(hunts) <= max_hunts {
+		return hunts, last_timestamp
+	}
 
-		// Notify the hunt manager that we need to hunt this client.
-		err = journal.PushRowsToArtifact(config_obj,
-			[]*ordereddict.Dict{ordereddict.NewDict().
-				Set("HuntId", hunt.HuntId).
-				Set("ClientId", client_id).
-				Set("Participate", true),
-			}, "System.Hunt.Participation", client_id, "")
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return hunts, last_timestamp
+	}
+
+	pending := make([]*api_proto.Hunt, 0, len(hunts))
+	for _, hunt := range hunts {
+		err := db.CheckIndex(config_obj, constants.HUNT_INDEX,
+			client_id, []string{hunt.HuntId})
 		if err != nil {
-			return err
+			pending = append(pending, hunt)
 		}
+	}
 
-		// Let the client know it needs to update its foreman state.
-		err = QueueMessageForClient(
-			config_obj, client_id,
-			&crypto_proto.GrrMessage{
-				SessionId: constants.MONITORING_WELL_KNOWN_FLOW,
-				RequestId: constants.IgnoreResponseState,
-				UpdateForeman: &actions_proto.ForemanCheckin{
-					LastHuntTimestamp: hunt.StartTime,
-				},
-			})
-		if err != nil {
-			return err
+	if len(pending) <= max_hunts {
+		return pending, last_timestamp
+	}
+
+	for _, hunt := range pending[max_hunts:] {
+		if hunt.StartTime-1 < last_timestamp {
+			last_timestamp = hunt.StartTime - 1
 		}
+	}
 
-		return services.GetNotifier().NotifyListener(config_obj, client_id)
-	})
+	return pending[:max_hunts], last_timestamp
 }
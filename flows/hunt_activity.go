@@ -0,0 +1,190 @@
+package flows
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Merge everything that happened to a hunt into a single timeline,
+// oldest first: its creation and notes (from the hunt object), its
+// state changes (from the System.Hunt.StateChange journal) and when
+// it was recompiled (from the System.Hunt.Recompile journal). The
+// feed is returned a page at a time.
+//
+// The journals are shared by all hunts so each page reads them from
+// the hunt's creation on, at most max_hunt_activity_journal_rows
+// rows of each. Hunts change state rarely so this only cuts short
+// the activity of hunts on servers with a very busy history.
+func GetHuntActivity(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string, offset, count uint64) (*api_proto.GetHuntActivityResponse, error) {
+
+	hunt_obj, err := GetHunt(config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*api_proto.HuntActivity{{
+		Timestamp:   hunt_obj.CreateTime,
+		Type:        "Created",
+		User:        hunt_obj.Creator,
+		Description: hunt_obj.HuntDescription,
+	}}
+
+	for _, note := range hunt_obj.Notes {
+		items = append(items, &api_proto.HuntActivity{
+			Timestamp:   note.Timestamp,
+			Type:        "Note",
+			User:        note.User,
+			Description: note.Text,
+		})
+	}
+
+	// Nothing is journaled for the hunt before it was created.
+	start_time := HuntTimeToTime(hunt_obj.CreateTime).Unix()
+
+	archived := false
+	for _, row := range readHuntJournal(ctx, config_obj,
+		"System.Hunt.StateChange", hunt_id, start_time) {
+		old_state, _ := row.GetString("OldState")
+		new_state, _ := row.GetString("NewState")
+		if new_state == api_proto.Hunt_ARCHIVED.String() {
+			archived = true
+		}
+
+		description := fmt.Sprintf("%v -> %v", old_state, new_state)
+		user, _ := row.GetString("User")
+		reason, _ := row.GetString("Reason")
+		if reason != "" {
+			description += " (" + reason + ")"
+		}
+
+		items = append(items, &api_proto.HuntActivity{
+			Timestamp:   journalTimestamp(row),
+			Type:        "StateChange",
+			User:        user,
+			Description: description,
+		})
+	}
+
+	for _, row := range readHuntJournal(ctx, config_obj,
+		"System.Hunt.Recompile", hunt_id, start_time) {
+		user, _ := row.GetString("User")
+		warning, _ := row.GetString("Warning")
+		items = append(items, &api_proto.HuntActivity{
+			Timestamp:   journalTimestamp(row),
+			Type:        "Recompiled",
+			User:        user,
+			Description: warning,
+		})
+	}
+
+	// Archiving a hunt also records a state change, so the archive
+	// journal is only needed for hunts archived before state
+	// changes were recorded.
+	if !archived {
+		for _, row := range readHuntJournal(ctx, config_obj,
+			"System.Hunt.Archive", hunt_id, start_time) {
+			user, _ := row.GetString("User")
+			items = append(items, &api_proto.HuntActivity{
+				Timestamp: journalTimestamp(row),
+				Type:      "Archived",
+				User:      user,
+			})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp < items[j].Timestamp
+	})
+
+	result := &api_proto.GetHuntActivityResponse{
+		Total: uint64(len(items)),
+	}
+
+	if offset < result.Total {
+		end := offset + count
+		if end > result.Total {
+			end = result.Total
+		}
+		result.Items = items[offset:end]
+	}
+
+	return result, nil
+}
+
+// Each journal is read for at most this many rows.
+var max_hunt_activity_journal_rows = 100000
+
+// The hunt's rows in one of the System.Hunt.* journals written since
+// start_time (in seconds). The journal may not exist (e.g. nothing
+// was ever written to it, or the artifact is not known) in which
+// case there are no rows.
+func readHuntJournal(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	artifact, hunt_id string, start_time int64) []*ordereddict.Dict {
+
+	path_manager := artifacts.NewArtifactPathManager(
+		config_obj, "server", hunt_id, artifact)
+
+	// Stops the reader if we stop early.
+	sub_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	row_chan, err := file_store.GetTimeRange(sub_ctx, config_obj,
+		path_manager, start_time, 0)
+	if err != nil {
+		return nil
+	}
+
+	result := []*ordereddict.Dict{}
+	read := 0
+	for row := range row_chan {
+		read++
+		if read > max_hunt_activity_journal_rows {
+			logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+			logger.Info("Hunt %v: activity only includes the first %v rows of %v",
+				hunt_id, max_hunt_activity_journal_rows, artifact)
+			break
+		}
+
+		// Rows are written with the hunt id so make sure they are
+		// for this hunt.
+		row_hunt_id, pres := row.GetString("HuntId")
+		if pres && row_hunt_id != hunt_id {
+			continue
+		}
+		result = append(result, row)
+	}
+
+	return result
+}
+
+// Rows written before hunt timestamps were in microseconds have
+// their timestamp in seconds. Timestamps in seconds stay below this
+// until the year 33658 while microseconds passed it in 1970.
+const max_journal_timestamp_seconds = 1000000000000
+
+// The row's timestamp in microseconds, so old and new rows sort
+// together.
+func journalTimestamp(row *ordereddict.Dict) uint64 {
+	value, _ := row.Get("Timestamp")
+	timestamp, _ := utils.ToInt64(value)
+	if timestamp > 0 && timestamp < max_journal_timestamp_seconds {
+		return HuntTimeFromTime(time.Unix(timestamp, 0))
+	}
+	return uint64(timestamp)
+}
@@ -0,0 +1,116 @@
+package flows
+
+import (
+	"github.com/Velocidex/ordereddict"
+	errors "github.com/pkg/errors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// When Defaults.hunt_approval_required is set no single user can
+// start a hunt. Starting a hunt (with CreateHunt or ModifyHunt)
+// instead moves it to PENDING_APPROVAL and records the requester. A
+// different user with the APPROVE_HUNT permission then approves it
+// by starting it with ModifyHunt, or denies it by pausing or
+// stopping it, which returns it to PAUSED. The requester may
+// withdraw their own request the same way.
+//
+// Each step is journaled to System.Hunt.Approval.
+func HuntApprovalRequired(config_obj *config_proto.Config) bool {
+	return config_obj.Defaults != nil && config_obj.Defaults.HuntApprovalRequired
+}
+
+// Ask for the hunt to be started.
+func requestHuntApproval(hunt *api_proto.Hunt, user string) error {
+	// The hunt has been expired.
+	if hunt.Stats != nil && hunt.Stats.Stopped {
+		return errors.New("Can not start a stopped hunt.")
+	}
+
+	hunt.State = api_proto.Hunt_PENDING_APPROVAL
+	hunt.RequestedBy = user
+	hunt.ApprovedBy = ""
+
+	return nil
+}
+
+// Check the user may approve (or deny) the pending hunt.
+func checkHuntApprover(
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt, user string, approving bool) error {
+	if user == "" {
+		return errors.New("Hunts must be approved by a user.")
+	}
+
+	if user == hunt.RequestedBy {
+		if approving {
+			return errors.New(
+				"Hunts must be approved by a different user than requested them.")
+		}
+
+		// Requesters may always withdraw their own request.
+		return nil
+	}
+
+	ok, err := acls.CheckAccess(config_obj, user, acls.APPROVE_HUNT)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.Errorf("User %v is not allowed to approve hunts.", user)
+	}
+
+	return nil
+}
+
+// The approval step made by a change from old_state, if any.
+func huntApprovalAction(
+	old_state api_proto.Hunt_State, hunt *api_proto.Hunt, user string) string {
+	switch {
+	case old_state != api_proto.Hunt_PENDING_APPROVAL &&
+		hunt.State == api_proto.Hunt_PENDING_APPROVAL:
+		return "Requested"
+
+	case old_state != api_proto.Hunt_PENDING_APPROVAL ||
+		hunt.State == api_proto.Hunt_PENDING_APPROVAL:
+		return ""
+
+	case hunt.State == api_proto.Hunt_RUNNING:
+		return "Approved"
+
+	case user == hunt.RequestedBy:
+		return "Withdrawn"
+	}
+
+	return "Denied"
+}
+
+// Emit a row to System.Hunt.Approval for each approval step. Like
+// EmitHuntStateChange the change has already been made so failing
+// to emit the row is only logged.
+func emitHuntApproval(
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt, action, user string) {
+
+	row := ordereddict.NewDict().
+		Set("Timestamp", HuntTimeNow()).
+		Set("HuntId", hunt.HuntId).
+		Set("Action", action).
+		Set("RequestedBy", hunt.RequestedBy).
+		Set("User", user)
+
+	journal, err := services.GetJournal()
+	if err == nil {
+		err = journal.PushRowsToArtifact(config_obj,
+			[]*ordereddict.Dict{row}, "System.Hunt.Approval",
+			"server", hunt.HuntId)
+	}
+
+	if err != nil {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("emitHuntApproval %v: %v", hunt.HuntId, err)
+	}
+}
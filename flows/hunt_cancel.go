@@ -0,0 +1,99 @@
+package flows
+
+import (
+	"context"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// The outcome of cancelling a hunt's flows.
+type HuntFlowsCancellation struct {
+	// How many flows were cancelled.
+	Cancelled uint64
+
+	// How many of the cancelled flows are on clients which are not
+	// connected. They receive the cancellation when they next poll.
+	Offline uint64
+}
+
+// Cancel all the hunt's flows which are still running on clients,
+// just like CancelFlow does for a single flow. Stopping a hunt only
+// stops new clients from being scheduled, so this is needed to stop
+// long running collections which were already scheduled.
+func CancelHuntFlows(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id, username string) (*HuntFlowsCancellation, error) {
+	return cancelHuntFlows(ctx, config_obj, hunt_id, username, nil)
+}
+
+// Cancel the hunt's running flows on only these clients.
+func CancelHuntClientFlows(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id, username string,
+	client_ids []string) (*HuntFlowsCancellation, error) {
+	if len(client_ids) == 0 {
+		return &HuntFlowsCancellation{}, nil
+	}
+	return cancelHuntFlows(ctx, config_obj, hunt_id, username, client_ids)
+}
+
+// Cancel the running flows on the clients, or on all clients if
+// client_ids is nil.
+func cancelHuntFlows(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id, username string,
+	client_ids []string) (*HuntFlowsCancellation, error) {
+
+	row_chan, err := file_store.GetTimeRange(ctx, config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	notifier := services.GetNotifier()
+	result := &HuntFlowsCancellation{}
+	seen := make(map[string]bool)
+
+	for row := range row_chan {
+		client_id, _ := row.GetString("ClientId")
+		flow_id, _ := row.GetString("FlowId")
+		if client_id == "" || flow_id == "" || seen[client_id+flow_id] ||
+			(client_ids != nil && !utils.InString(client_ids, client_id)) {
+			continue
+		}
+		seen[client_id+flow_id] = true
+
+		collection_context, err := LoadCollectionContext(
+			config_obj, client_id, flow_id)
+		if err != nil ||
+			collection_context.State != flows_proto.ArtifactCollectorContext_RUNNING {
+			continue
+		}
+
+		// The flow may complete while we get to it so failures
+		// are only logged.
+		_, err = CancelFlow(ctx, config_obj, client_id, flow_id, username)
+		if err != nil {
+			logger.Info("CancelHuntFlows %v: %v/%v: %v",
+				hunt_id, client_id, flow_id, err)
+			continue
+		}
+
+		result.Cancelled++
+		if notifier == nil || !notifier.IsClientConnected(client_id) {
+			result.Offline++
+		}
+	}
+
+	return result, nil
+}
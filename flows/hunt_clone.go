@@ -0,0 +1,127 @@
+package flows
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	errors "github.com/pkg/errors"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+// Create a new paused hunt collecting the same artifacts with the
+// same parameters as an existing hunt. If condition is set it
+// replaces the original hunt's condition, so the same collection
+// can be run against a different set of clients (an empty condition
+// targets all clients).
+//
+// The clone is created just like any other hunt: it is compiled
+// with the caller's permissions and gets a fresh expiry. Schedules,
+// notes and stats are not copied.
+func CloneHunt(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	hunt_id string,
+	condition *api_proto.HuntCondition) (string, error) {
+
+	hunt_obj, err := GetHunt(config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	if err != nil {
+		return "", err
+	}
+
+	if hunt_obj.StartRequest == nil {
+		return "", errors.Errorf("Hunt %v has no artifacts to collect", hunt_id)
+	}
+
+	start_request := proto.Clone(
+		hunt_obj.StartRequest).(*flows_proto.ArtifactCollectorArgs)
+	start_request.CompiledCollectorArgs = nil
+
+	clone := &api_proto.Hunt{
+		HuntDescription:          hunt_obj.HuntDescription,
+		StartRequest:             start_request,
+		Condition:                hunt_obj.Condition,
+		ClientLimit:              hunt_obj.ClientLimit,
+		Priority:                 hunt_obj.Priority,
+		RetentionDays:            hunt_obj.RetentionDays,
+		RetentionIncludesResults: hunt_obj.RetentionIncludesResults,
+		CampaignId:               hunt_obj.CampaignId,
+		Tags:                     hunt_obj.Tags,
+		LabelParameters:          hunt_obj.LabelParameters,
+		AutoStopErrorRate:        hunt_obj.AutoStopErrorRate,
+		ClientsPerMinute:         hunt_obj.ClientsPerMinute,
+		DisableObfuscation:       hunt_obj.DisableObfuscation,
+	}
+
+	// CreateHunt validates the new condition.
+	if condition != nil {
+		clone.Condition = condition
+	}
+
+	return CreateHunt(ctx, config_obj, acl_manager, clone)
+}
+
+// A label condition must name at least one label, otherwise it
+// would silently match no clients at all.
+func validateHuntCondition(condition *api_proto.HuntCondition) error {
+	if labels := condition.GetLabels(); labels != nil {
+		if len(labels.Label) == 0 {
+			return errors.New("Hunt label condition has no labels")
+		}
+		for _, label := range labels.Label {
+			if strings.TrimSpace(label) == "" {
+				return errors.New("Hunt label condition has an empty label")
+			}
+		}
+	}
+
+	if condition.ExcludedLabels != nil {
+		for _, label := range condition.ExcludedLabels.Label {
+			if strings.TrimSpace(label) == "" {
+				return errors.New("Hunt excluded labels has an empty label")
+			}
+		}
+	}
+
+	if os := condition.GetOs(); os != nil {
+		_, pres := api_proto.HuntOsCondition_OS_name[int32(os.Os)]
+		if !pres {
+			return errors.Errorf("Unknown hunt OS condition %v", os.Os)
+		}
+	}
+
+	if condition.MinClientVersion != "" &&
+		!utils.IsValidVersion(condition.MinClientVersion) {
+		return errors.Errorf("Invalid minimum client version %v",
+			condition.MinClientVersion)
+	}
+
+	if condition.MaxClientVersion != "" &&
+		!utils.IsValidVersion(condition.MaxClientVersion) {
+		return errors.Errorf("Invalid maximum client version %v",
+			condition.MaxClientVersion)
+	}
+
+	if condition.MinClientVersion != "" && condition.MaxClientVersion != "" &&
+		utils.CompareVersions(condition.MinClientVersion,
+			condition.MaxClientVersion) > 0 {
+		return errors.Errorf(
+			"Minimum client version %v is above the maximum client version %v",
+			condition.MinClientVersion, condition.MaxClientVersion)
+	}
+
+	if condition.ConditionVql != "" {
+		_, err := hunt_condition_vql_cache.Get(condition.ConditionVql)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
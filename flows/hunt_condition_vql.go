@@ -0,0 +1,211 @@
+package flows
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	errors "github.com/pkg/errors"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	// The condition is evaluated for each client which
+	// participates in the hunt, so it must be quick. Clients for
+	// which it takes longer are not scheduled.
+	hunt_condition_vql_timeout = time.Second
+
+	// Estimating a hunt evaluates the condition on every known
+	// client so the whole estimate is bounded too.
+	hunt_estimate_timeout = 30 * time.Second
+
+	// Evaluations which ignore the context keep running after we
+	// give up on them. Cap how many may run at once so they can
+	// not pile up.
+	hunt_condition_vql_slots = make(chan bool, max_hunt_condition_vql_evaluations)
+
+	hunt_condition_vql_cache = &huntConditionVQLCache{
+		compiled: make(map[string]*vfilter.Lambda),
+	}
+)
+
+// Only a handful of hunts run at once so we keep the compiled
+// conditions until there are too many of them.
+const max_hunt_condition_vql_cache = 1000
+
+const max_hunt_condition_vql_evaluations = 10
+
+type huntConditionVQLCache struct {
+	mu       sync.Mutex
+	compiled map[string]*vfilter.Lambda
+}
+
+// Compile the hunt's condition VQL. The expression is compiled as
+// the body of a lambda taking the Client.
+func (self *huntConditionVQLCache) Get(expression string) (*vfilter.Lambda, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	lambda, pres := self.compiled[expression]
+	if pres {
+		return lambda, nil
+	}
+
+	lambda, err := vfilter.ParseLambda("Client => " + expression)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid hunt condition VQL")
+	}
+
+	if len(self.compiled) >= max_hunt_condition_vql_cache {
+		self.compiled = make(map[string]*vfilter.Lambda)
+	}
+	self.compiled[expression] = lambda
+
+	return lambda, nil
+}
+
+// Does the hunt's condition VQL match the client? Hunts without a
+// condition VQL match all clients. The condition fails closed: a
+// client is not matched if its info can not be read, or the
+// expression fails or times out.
+func ClientMatchesConditionVQL(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt, client_id string) bool {
+	matched, err := EvaluateConditionVQL(ctx, config_obj, hunt, client_id)
+	if err != nil {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("Hunt %v: %v", hunt.HuntId, err)
+		return false
+	}
+	return matched
+}
+
+// Like ClientMatchesConditionVQL but reports why the condition
+// could not be evaluated, so callers can tell a client which does
+// not match from one which may match when tried again.
+func EvaluateConditionVQL(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt, client_id string) (bool, error) {
+	if hunt.Condition == nil || hunt.Condition.ConditionVql == "" {
+		return true, nil
+	}
+
+	lambda, err := hunt_condition_vql_cache.Get(hunt.Condition.ConditionVql)
+	if err != nil {
+		return false, err
+	}
+
+	client, err := getConditionClient(config_obj, client_id)
+	if err != nil {
+		return false, errors.Wrapf(err, "condition VQL for %v", client_id)
+	}
+
+	manager, err := services.GetRepositoryManager()
+	if err != nil {
+		return false, err
+	}
+
+	sub_ctx, cancel := context.WithTimeout(ctx, hunt_condition_vql_timeout)
+	defer cancel()
+
+	// The expression runs with the permissions of the hunt's
+	// creator.
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     config_obj,
+		ACLManager: vql_subsystem.NewServerACLManager(config_obj, hunt.Creator),
+		Logger:     logging.NewPlainLogger(config_obj, &logging.FrontendComponent),
+	})
+
+	// Wait for one of the abandoned evaluations to finish before
+	// starting another one.
+	select {
+	case hunt_condition_vql_slots <- true:
+	case <-sub_ctx.Done():
+		scope.Close()
+		return false, errors.Errorf("condition VQL for %v did not start in %v",
+			client_id, hunt_condition_vql_timeout)
+	}
+
+	// Evaluate in the background so a slow expression which does
+	// not honor the context can not hold us up. The context is
+	// cancelled when we return so the expression stops as soon as
+	// it checks it.
+	result_chan := make(chan bool, 1)
+	go func() {
+		defer func() { <-hunt_condition_vql_slots }()
+		defer scope.Close()
+
+		result := lambda.Reduce(sub_ctx, scope, []vfilter.Any{client})
+		result_chan <- scope.Bool(result)
+	}()
+
+	select {
+	case result := <-result_chan:
+		// Functions cut short by the timeout may return
+		// anything so we can only trust results from before
+		// it.
+		if sub_ctx.Err() == nil {
+			return result, nil
+		}
+
+	case <-sub_ctx.Done():
+	}
+
+	return false, errors.Errorf("condition VQL for %v did not complete in %v",
+		client_id, hunt_condition_vql_timeout)
+}
+
+// The Client the condition VQL sees: the client's info from its last
+// interrogation and its metadata.
+func getConditionClient(
+	config_obj *config_proto.Config, client_id string) (*ordereddict.Dict, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	client_path_manager := paths.NewClientPathManager(client_id)
+	client_info := &actions_proto.ClientInfo{}
+	err = db.GetSubject(config_obj, client_path_manager.Path(), client_info)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clients may not have any metadata.
+	client_metadata := &api_proto.ClientMetadata{}
+	err = db.GetSubject(config_obj,
+		client_path_manager.Metadata(), client_metadata)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	metadata := ordereddict.NewDict()
+	for _, item := range client_metadata.Items {
+		metadata.Set(item.Key, item.Value)
+	}
+
+	return ordereddict.NewDict().
+		Set("ClientId", client_id).
+		Set("Hostname", client_info.Hostname).
+		Set("Fqdn", client_info.Fqdn).
+		Set("OS", client_info.System).
+		Set("Release", client_info.Release).
+		Set("Architecture", client_info.Architecture).
+		Set("ClientVersion", client_info.ClientVersion).
+		Set("ClientName", client_info.ClientName).
+		Set("Labels", client_info.Labels).
+		Set("LastInterrogateFlowId", client_info.LastInterrogateFlowId).
+		Set("Metadata", metadata), nil
+}
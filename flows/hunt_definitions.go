@@ -0,0 +1,95 @@
+package flows
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// Hash the current definition of each artifact the request collects,
+// resolving custom overrides the same way the launcher does when
+// compiling the request. Unknown artifacts are left out. The hashes
+// come from the artifact sources cache so checking every GetHunt()
+// is cheap.
+func huntArtifactHashes(
+	config_obj *config_proto.Config,
+	repository services.Repository,
+	request *flows_proto.ArtifactCollectorArgs) map[string]string {
+
+	names := append([]string{}, request.Artifacts...)
+	for _, spec := range request.Specs {
+		names = append(names, spec.Artifact)
+	}
+
+	result := make(map[string]string)
+	for _, name := range names {
+		var summary artifactSummary
+		pres := false
+		if request.AllowCustomOverrides {
+			summary, pres = getArtifactSummary(
+				config_obj, repository, "Custom."+name)
+		}
+
+		if !pres {
+			summary, pres = getArtifactSummary(config_obj, repository, name)
+		}
+
+		if pres {
+			result[name] = summary.hash
+		}
+	}
+
+	return result
+}
+
+func hashArtifactDefinition(artifact *artifacts_proto.Artifact) string {
+	data := []byte(artifact.Raw)
+
+	// Artifacts not loaded from YAML have no raw definition.
+	if len(data) == 0 {
+		data, _ = proto.Marshal(artifact)
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// Compare the artifact definitions the hunt was created with against
+// the repository and flag the hunt if any have changed since. Hunts
+// created before the hashes were recorded are never flagged, and
+// neither are hunts when the repository is not available.
+func checkHuntDefinitions(
+	config_obj *config_proto.Config, hunt *api_proto.Hunt) {
+	if len(hunt.ArtifactHashes) == 0 || hunt.StartRequest == nil {
+		return
+	}
+
+	manager, err := services.GetRepositoryManager()
+	if err != nil {
+		return
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return
+	}
+
+	current := huntArtifactHashes(config_obj, repository, hunt.StartRequest)
+
+	hunt.ChangedArtifacts = nil
+	for name, hash := range hunt.ArtifactHashes {
+		// A deleted artifact has changed too.
+		if current[name] != hash {
+			hunt.ChangedArtifacts = append(hunt.ChangedArtifacts, name)
+		}
+	}
+	sort.Strings(hunt.ChangedArtifacts)
+	hunt.StaleDefinition = len(hunt.ChangedArtifacts) > 0
+}
@@ -0,0 +1,219 @@
+package flows
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	errors "github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+var diff_identifier_regex = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+// Compare the definitions of two hunts, e.g. to find out why two
+// similar hunts behave differently. Fields which change as the hunt
+// runs (its stats, state, start time etc) are ignored. Artifact
+// parameters are compared per artifact and parameter rather than as
+// a list.
+func DiffHunts(
+	config_obj *config_proto.Config,
+	hunt_id_a, hunt_id_b string) (*api_proto.HuntDiff, error) {
+
+	dispatcher, err := services.RequireHuntDispatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	var hunt_a, hunt_b *api_proto.Hunt
+	err = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		switch hunt.HuntId {
+		case hunt_id_a:
+			hunt_a = proto.Clone(hunt).(*api_proto.Hunt)
+		case hunt_id_b:
+			hunt_b = proto.Clone(hunt).(*api_proto.Hunt)
+		}
+
+		if hunt_a != nil && hunt_b != nil {
+			return services.StopHuntIteration
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if hunt_a == nil {
+		return nil, errors.Errorf("Unknown hunt %v", hunt_id_a)
+	}
+
+	if hunt_b == nil {
+		return nil, errors.Errorf("Unknown hunt %v", hunt_id_b)
+	}
+
+	definition_a, err := huntDefinition(hunt_a)
+	if err != nil {
+		return nil, err
+	}
+
+	definition_b, err := huntDefinition(hunt_b)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api_proto.HuntDiff{
+		HuntIdA: hunt_id_a,
+		HuntIdB: hunt_id_b,
+	}
+	diffValues("", definition_a, definition_b, result)
+
+	return result, nil
+}
+
+// The hunt's definition as a generic JSON object.
+func huntDefinition(hunt *api_proto.Hunt) (map[string]interface{}, error) {
+	// These are runtime state or only apply to a single request.
+	hunt.HuntId = ""
+	hunt.CreateTime = 0
+	hunt.StartTime = 0
+	hunt.State = api_proto.Hunt_UNSET
+	hunt.Stats = nil
+	hunt.LastModifiedBy = ""
+	hunt.Force = false
+	hunt.CancelFlows = false
+	hunt.ClearTags = false
+	hunt.ExcludedClients = nil
+	hunt.IncludedClients = nil
+	hunt.StartWarning = ""
+	hunt.NextScheduledRun = 0
+	hunt.LastScheduledHuntId = ""
+	hunt.ScheduledBy = ""
+	hunt.RequestedBy = ""
+	hunt.ApprovedBy = ""
+	hunt.Notes = nil
+	hunt.IdempotencyKey = ""
+	hunt.Permissions = nil
+	hunt.StaleDefinition = false
+	hunt.ChangedArtifacts = nil
+	hunt.SnapshotClientCount = 0
+
+	// These are derived from the start request and the artifacts it
+	// was compiled with.
+	hunt.Artifacts = nil
+	hunt.ArtifactSources = nil
+	hunt.ArtifactHashes = nil
+
+	// These are only filled in by GetHunt.
+	hunt.CreateTimeString = ""
+	hunt.StartTimeString = ""
+	hunt.ExpiresString = ""
+
+	// This is only filled in by ListHunts.
+	hunt.ScheduledRuns = nil
+
+	// Parameters are keyed by artifact and name so the order they
+	// were given in does not matter.
+	parameters := make(map[string]interface{})
+	if hunt.StartRequest != nil {
+		hunt.StartRequest.Creator = ""
+		hunt.StartRequest.ClientId = ""
+		hunt.StartRequest.CompiledCollectorArgs = nil
+
+		for _, spec := range hunt.StartRequest.Specs {
+			artifact_parameters := make(map[string]interface{})
+			if spec.Parameters != nil {
+				for _, env := range spec.Parameters.Env {
+					artifact_parameters[env.Key] = env.Value
+				}
+			}
+			parameters[spec.Artifact] = artifact_parameters
+		}
+		hunt.StartRequest.Specs = nil
+	}
+
+	serialized, err := protojson.MarshalOptions{
+		UseProtoNames: true,
+	}.Marshal(hunt)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{})
+	err = json.Unmarshal(serialized, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parameters) > 0 {
+		result["parameters"] = parameters
+	}
+
+	return result, nil
+}
+
+// Add a difference for each leaf value which is not the same in a
+// and b. Objects are compared key by key, anything else is compared
+// as a whole.
+func diffValues(field string, a, b interface{}, result *api_proto.HuntDiff) {
+	map_a, a_is_map := a.(map[string]interface{})
+	map_b, b_is_map := b.(map[string]interface{})
+
+	if a_is_map && b_is_map {
+		keys := make(map[string]bool)
+		for k := range map_a {
+			keys[k] = true
+		}
+		for k := range map_b {
+			keys[k] = true
+		}
+
+		sorted_keys := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted_keys = append(sorted_keys, k)
+		}
+		sort.Strings(sorted_keys)
+
+		for _, k := range sorted_keys {
+			diffValues(diffFieldPath(field, k), map_a[k], map_b[k], result)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return
+	}
+
+	result.Differences = append(result.Differences, &api_proto.HuntFieldDiff{
+		Field: field,
+		A:     diffJSON(a),
+		B:     diffJSON(b),
+	})
+}
+
+func diffFieldPath(parent, key string) string {
+	if !diff_identifier_regex.MatchString(key) {
+		return parent + "[" + key + "]"
+	}
+
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func diffJSON(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(serialized)
+}
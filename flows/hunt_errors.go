@@ -0,0 +1,72 @@
+package flows
+
+import (
+	"context"
+	"sort"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+)
+
+// Collect the error messages of all the hunt's failed flows. The
+// failed flows are returned a page at a time, while the groups of
+// identical errors cover all the failed flows so it is easy to see
+// what went wrong across the whole hunt.
+func GetHuntErrors(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string, offset, count uint64) (*api_proto.GetHuntErrorsResponse, error) {
+
+	row_chan, err := file_store.GetTimeRange(ctx, config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api_proto.GetHuntErrorsResponse{}
+	groups := make(map[string]*api_proto.HuntErrorGroup)
+	seen := make(map[string]bool)
+
+	for row := range row_chan {
+		client_id, _ := row.GetString("ClientId")
+		flow_id, _ := row.GetString("FlowId")
+		if client_id == "" || flow_id == "" || seen[client_id+flow_id] {
+			continue
+		}
+		seen[client_id+flow_id] = true
+
+		collection_context, err := LoadCollectionContext(
+			config_obj, client_id, flow_id)
+		if err != nil ||
+			collection_context.State != flows_proto.ArtifactCollectorContext_ERROR {
+			continue
+		}
+
+		message := collection_context.Status
+		group, pres := groups[message]
+		if !pres {
+			group = &api_proto.HuntErrorGroup{Error: message}
+			groups[message] = group
+			result.Groups = append(result.Groups, group)
+		}
+		group.Count++
+
+		if result.Total >= offset && result.Total < offset+count {
+			result.Items = append(result.Items, &api_proto.HuntError{
+				ClientId: client_id,
+				FlowId:   flow_id,
+				Error:    message,
+			})
+		}
+		result.Total++
+	}
+
+	sort.SliceStable(result.Groups, func(i, j int) bool {
+		return result.Groups[i].Count > result.Groups[j].Count
+	})
+
+	return result, nil
+}
@@ -0,0 +1,32 @@
+package flows
+
+import (
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/utils"
+)
+
+// Is the client excluded from the hunt? Excluded clients are not
+// scheduled, retried or taken off the hunt's rollout queue, but the
+// hunt keeps running on all other clients.
+func HuntExcludesClient(hunt *api_proto.Hunt, client_id string) bool {
+	return utils.InString(hunt.ExcludedClients, client_id)
+}
+
+// Add the excluded clients and remove the included ones. An excluded
+// client is not scheduled when it checks in, so including it again
+// lets it be scheduled the next time it checks in - unless it was
+// already scheduled before it was excluded.
+func updateExcludedClients(
+	current, excluded, included []string) []string {
+	var result []string
+	for _, clients := range [][]string{current, excluded} {
+		for _, client_id := range clients {
+			if client_id != "" &&
+				!utils.InString(included, client_id) &&
+				!utils.InString(result, client_id) {
+				result = append(result, client_id)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+package flows
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	errors "github.com/pkg/errors"
+	proto_v2 "google.golang.org/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+var (
+	// How long an idempotency key refers to the hunt created
+	// with it. After this the key may be used again.
+	hunt_idempotency_window = 24 * time.Hour
+
+	// Serializes the check and creation of hunts with an
+	// idempotency key so concurrent retries only create one hunt.
+	hunt_idempotency_mu sync.Mutex
+)
+
+// Store a new hunt unless a hunt was already created with the same
+// idempotency key within the window, in which case the earlier
+// hunt's id is returned and created is false. The keys are stored in
+// the datastore so retries are recognized across server restarts.
+//
+// A key may only be reused for the same request - retrying with a
+// different hunt is an error rather than silently returning the
+// unrelated earlier hunt.
+func storeNewHuntOnce(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	hunt *api_proto.Hunt,
+	approved_by string) (hunt_id string, created bool, err error) {
+
+	if hunt.IdempotencyKey == "" {
+		hunt_id, err := storeNewHunt(ctx, config_obj, acl_manager, hunt, approved_by)
+		return hunt_id, err == nil, err
+	}
+
+	// Digest the request before storeNewHunt fills it in.
+	digest, err := huntRequestDigest(hunt)
+	if err != nil {
+		return "", false, err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return "", false, err
+	}
+
+	hunt_idempotency_mu.Lock()
+	defer hunt_idempotency_mu.Unlock()
+
+	key_path := paths.HuntIdempotencyKeyPath(hunt.IdempotencyKey)
+	record := &api_proto.HuntIdempotencyRecord{}
+	err = db.GetSubject(config_obj, key_path, record)
+	if err == nil && record.HuntId != "" &&
+		time.Since(HuntTimeToTime(record.Timestamp)) < hunt_idempotency_window {
+		if record.RequestDigest != digest {
+			return "", false, errors.Errorf(
+				"Idempotency key %q was already used to create hunt %v "+
+					"with a different request.",
+				hunt.IdempotencyKey, record.HuntId)
+		}
+		return record.HuntId, false, nil
+	}
+
+	hunt_id, err = storeNewHunt(ctx, config_obj, acl_manager, hunt, approved_by)
+	if err != nil {
+		return "", false, err
+	}
+
+	err = db.SetSubject(config_obj, key_path,
+		&api_proto.HuntIdempotencyRecord{
+			HuntId:        hunt_id,
+			Timestamp:     HuntTimeNow(),
+			RequestDigest: digest,
+		})
+	return hunt_id, true, err
+}
+
+// Remove the idempotency records which are older than the window,
+// since their keys no longer refer to their hunts.
+func SweepHuntIdempotencyKeys(
+	config_obj *config_proto.Config, now time.Time) error {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	// Collect all the records before deleting any so the pages do
+	// not shift under us.
+	urns := []string{}
+	page_size := uint64(1000)
+	for offset := uint64(0); ; offset += page_size {
+		page, err := db.ListChildren(config_obj,
+			paths.HuntIdempotencyDirectory(), offset, page_size)
+		if err != nil {
+			return err
+		}
+		urns = append(urns, page...)
+
+		if uint64(len(page)) < page_size {
+			break
+		}
+	}
+
+	hunt_idempotency_mu.Lock()
+	defer hunt_idempotency_mu.Unlock()
+
+	for _, urn := range urns {
+		record := &api_proto.HuntIdempotencyRecord{}
+		err = db.GetSubject(config_obj, urn, record)
+		if err == nil &&
+			now.Sub(HuntTimeToTime(record.Timestamp)) < hunt_idempotency_window {
+			continue
+		}
+
+		err = db.DeleteSubject(config_obj, urn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The API assigns each request a new hunt id so it is not part of
+// the digest.
+func huntRequestDigest(hunt *api_proto.Hunt) (string, error) {
+	request := proto.Clone(hunt).(*api_proto.Hunt)
+	request.HuntId = ""
+
+	serialized, err := proto_v2.MarshalOptions{Deterministic: true}.Marshal(
+		proto.MessageV2(request))
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(serialized)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,157 @@
+package flows
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	errors "github.com/pkg/errors"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	artifacts "www.velocidex.com/golang/velociraptor/artifacts"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// Label parameter overrides may only set parameters that the hunt's
+// artifacts actually define, otherwise a typo would silently leave
+// the hunt's value in place.
+func validateHuntLabelParameters(
+	config_obj *config_proto.Config,
+	repository services.Repository,
+	hunt *api_proto.Hunt) error {
+
+	collected := make(map[string]bool)
+	for _, name := range hunt.StartRequest.Artifacts {
+		collected[name] = true
+	}
+	for _, spec := range hunt.StartRequest.Specs {
+		collected[spec.Artifact] = true
+	}
+
+	for _, label_parameters := range hunt.LabelParameters {
+		if strings.TrimSpace(label_parameters.Label) == "" {
+			return errors.New("Hunt label parameters must have a label")
+		}
+
+		for _, spec := range label_parameters.Specs {
+			if !collected[spec.Artifact] {
+				return errors.Errorf(
+					"Label %v overrides parameters of %v which the hunt does not collect",
+					label_parameters.Label, spec.Artifact)
+			}
+
+			if spec.Parameters == nil {
+				continue
+			}
+
+			artifact, pres := repository.Get(config_obj, spec.Artifact)
+			if !pres {
+				return errors.Errorf("Unknown artifact %v", spec.Artifact)
+			}
+
+			defined := make(map[string]bool)
+			for _, parameter := range artifact.Parameters {
+				defined[parameter.Name] = true
+			}
+
+			for _, env := range spec.Parameters.Env {
+				if !defined[env.Key] {
+					return errors.Errorf(
+						"Label %v overrides unknown parameter %v of %v",
+						label_parameters.Label, env.Key, spec.Artifact)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// The compiled collector args to schedule on a client, composed from
+// the hunt's compiled request and the parameter overrides of the
+// labels the client carries (has_label is called for each label).
+// Overrides are applied in the order they are listed on the hunt so
+// when several labels set the same parameter the last one wins.
+//
+// Also returns the labels whose overrides were applied. If there
+// are none the hunt's compiled request is returned as is.
+func ComposeHuntCollectorArgs(
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt,
+	has_label func(label string) bool) (
+	[]*actions_proto.VQLCollectorArgs, []string) {
+
+	if hunt.StartRequest == nil {
+		return nil, nil
+	}
+
+	applied := []*api_proto.HuntLabelParameters{}
+	labels := []string{}
+	for _, label_parameters := range hunt.LabelParameters {
+		if has_label(label_parameters.Label) {
+			applied = append(applied, label_parameters)
+			labels = append(labels, label_parameters.Label)
+		}
+	}
+
+	if len(applied) == 0 {
+		return hunt.StartRequest.CompiledCollectorArgs, nil
+	}
+
+	result := make([]*actions_proto.VQLCollectorArgs, 0,
+		len(hunt.StartRequest.CompiledCollectorArgs))
+	for _, compiled := range hunt.StartRequest.CompiledCollectorArgs {
+		args := proto.Clone(compiled).(*actions_proto.VQLCollectorArgs)
+		artifact := compiledArtifactName(config_obj, args)
+
+		for _, label_parameters := range applied {
+			for _, spec := range label_parameters.Specs {
+				if spec.Parameters == nil ||
+					(artifact != spec.Artifact &&
+						artifact != "Custom."+spec.Artifact) {
+					continue
+				}
+
+				for _, env := range spec.Parameters.Env {
+					args.Env = setCompiledParameter(
+						args.Env, env.Key, env.Value)
+				}
+			}
+		}
+		result = append(result, args)
+	}
+
+	return result, labels
+}
+
+// The artifact a compiled request collects, taken from the name of
+// its first source query (which may be obfuscated).
+func compiledArtifactName(
+	config_obj *config_proto.Config,
+	args *actions_proto.VQLCollectorArgs) string {
+	for _, query := range args.Query {
+		if query.Name == "" {
+			continue
+		}
+
+		name := query.Name
+		if config_obj.Frontend != nil {
+			name = artifacts.DeobfuscateString(config_obj, name)
+		}
+
+		// Source queries are named Artifact/Source.
+		return strings.SplitN(name, "/", 2)[0]
+	}
+	return ""
+}
+
+func setCompiledParameter(env []*actions_proto.VQLEnv,
+	key, value string) []*actions_proto.VQLEnv {
+	for _, item := range env {
+		if item.Key == key {
+			item.Value = value
+			return env
+		}
+	}
+	return append(env, &actions_proto.VQLEnv{Key: key, Value: value})
+}
@@ -0,0 +1,127 @@
+package flows
+
+import (
+	"context"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/result_sets"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+)
+
+// Find the clients which were expected to run the hunt but did
+// not: those in the hunt's client snapshot which were either never
+// scheduled, or whose flows never finished or failed. This answers
+// whether we really collected from every client we targeted. The
+// non responders are returned a page at a time.
+func GetHuntNonResponders(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string, offset, count uint64) (
+	*api_proto.GetHuntNonRespondersResponse, error) {
+
+	hunt_obj, err := GetHunt(config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api_proto.GetHuntNonRespondersResponse{}
+
+	// The snapshot is only taken when the hunt starts.
+	if !hunt_obj.SnapshotClients || hunt_obj.StartTime == 0 {
+		return result, nil
+	}
+
+	rs_reader, err := result_sets.NewResultSetReader(
+		file_store.GetFileStore(config_obj),
+		paths.NewHuntPathManager(hunt_id).ClientSnapshot())
+	if err != nil {
+		return result, nil
+	}
+	defer rs_reader.Close()
+
+	reported, scheduled, err := getHuntClientResponses(
+		ctx, config_obj, hunt_id)
+	if err != nil {
+		return nil, err
+	}
+
+	result.SnapshotAvailable = true
+	for row := range rs_reader.Rows(ctx) {
+		client_id, _ := row.GetString("ClientId")
+		if client_id == "" {
+			continue
+		}
+		result.SnapshotTotal++
+
+		if reported[client_id] {
+			continue
+		}
+
+		if result.Total >= offset && result.Total < offset+count {
+			hostname, _ := row.GetString("Hostname")
+			item := &api_proto.HuntNonResponder{
+				ClientId: client_id,
+				Hostname: hostname,
+				Reason:   "Not scheduled",
+			}
+
+			flow_id, pres := scheduled[client_id]
+			if pres {
+				item.FlowId = flow_id
+				item.Reason = "No response"
+			}
+			result.Items = append(result.Items, item)
+		}
+		result.Total++
+	}
+
+	return result, nil
+}
+
+// Read the hunt's flow index. Returns the clients with a finished or
+// failed flow, and the latest flow scheduled on each client.
+func getHuntClientResponses(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string) (map[string]bool, map[string]string, error) {
+
+	row_chan, err := file_store.GetTimeRange(ctx, config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), 0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reported := make(map[string]bool)
+	scheduled := make(map[string]string)
+
+	for row := range row_chan {
+		client_id, _ := row.GetString("ClientId")
+		flow_id, _ := row.GetString("FlowId")
+		if client_id == "" || flow_id == "" {
+			continue
+		}
+		scheduled[client_id] = flow_id
+
+		if reported[client_id] {
+			continue
+		}
+
+		collection_context, err := LoadCollectionContext(
+			config_obj, client_id, flow_id)
+		if err != nil {
+			continue
+		}
+
+		switch collection_context.State {
+		case flows_proto.ArtifactCollectorContext_FINISHED,
+			flows_proto.ArtifactCollectorContext_ERROR:
+			reported[client_id] = true
+		}
+	}
+
+	return reported, scheduled, nil
+}
@@ -0,0 +1,74 @@
+package flows
+
+import (
+	"path"
+
+	"github.com/golang/protobuf/proto"
+	errors "github.com/pkg/errors"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// Describe each source the hunt will collect, in the same order as
+// the hunt's ArtifactSources. The hunt need not be created yet so
+// the GUI can show this before the hunt is started.
+func PreviewHuntArtifacts(
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt) (*api_proto.HuntPreview, error) {
+	if hunt == nil || hunt.StartRequest == nil {
+		return nil, errors.New("No artifacts to collect.")
+	}
+
+	manager, err := services.GetRepositoryManager()
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api_proto.HuntPreview{}
+	for _, name := range hunt.StartRequest.Artifacts {
+		artifact, pres := repository.Get(config_obj, name)
+		if !pres {
+			return nil, errors.New("Unknown artifact " + name)
+		}
+
+		for _, source_name := range GetArtifactSources(config_obj, name) {
+			result.Sources = append(result.Sources,
+				previewSource(artifact, source_name))
+		}
+	}
+
+	return result, nil
+}
+
+func previewSource(
+	artifact *artifacts_proto.Artifact,
+	source_name string) *api_proto.HuntSourcePreview {
+	preview := &api_proto.HuntSourcePreview{
+		Name:        path.Join(artifact.Name, source_name),
+		Artifact:    artifact.Name,
+		Source:      source_name,
+		Description: artifact.Description,
+		ColumnTypes: []*artifacts_proto.ColumnType{},
+	}
+
+	for _, source := range artifact.Sources {
+		if source.Name == source_name && source.Description != "" {
+			preview.Description = source.Description
+			break
+		}
+	}
+
+	for _, column_type := range artifact.ColumnTypes {
+		preview.ColumnTypes = append(preview.ColumnTypes,
+			proto.Clone(column_type).(*artifacts_proto.ColumnType))
+	}
+
+	return preview
+}
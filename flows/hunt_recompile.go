@@ -0,0 +1,147 @@
+package flows
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/golang/protobuf/proto"
+	errors "github.com/pkg/errors"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+const recompileHuntWarning = "Clients already scheduled on the hunt " +
+	"keep running the previously compiled artifacts. Only clients " +
+	"scheduled after the hunt is resumed receive the new version."
+
+// Compile the hunt's start request again against the current
+// artifact repository, e.g. after a bug in one of its artifacts was
+// fixed, keeping the hunt and its stats. Only paused hunts may be
+// recompiled so that every client scheduled from now on gets the
+// same version. The change is recorded in the System.Hunt.Recompile
+// journal.
+//
+// Returns a warning that the clients which were already scheduled
+// are not affected.
+func RecompileHunt(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	hunt_id string) (string, error) {
+
+	dispatcher, err := services.RequireHuntDispatcher()
+	if err != nil {
+		return "", err
+	}
+
+	// Compiling may take a while so we work on a copy rather
+	// than holding the dispatcher lock.
+	var hunt *api_proto.Hunt
+	err = dispatcher.ModifyHunt(hunt_id, func(hunt_obj *api_proto.Hunt) error {
+		if hunt_obj.State != api_proto.Hunt_PAUSED {
+			return errors.Errorf("Only paused hunts can be recompiled, "+
+				"hunt %v is %v", hunt_id, hunt_obj.State)
+		}
+		hunt = proto.Clone(hunt_obj).(*api_proto.Hunt)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if hunt.StartRequest == nil {
+		return "", errors.New("Hunt has no start request")
+	}
+
+	manager, err := services.GetRepositoryManager()
+	if err != nil {
+		return "", err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	// The artifacts may now require different permissions or
+	// parameters.
+	err = checkHuntArtifactAccess(config_obj, acl_manager, repository, hunt)
+	if err != nil {
+		return "", err
+	}
+
+	err = validateHuntLabelParameters(config_obj, repository, hunt)
+	if err != nil {
+		return "", err
+	}
+
+	launcher, err := services.GetLauncher()
+	if err != nil {
+		return "", err
+	}
+
+	request := proto.Clone(hunt.StartRequest).(*flows_proto.ArtifactCollectorArgs)
+	request.CompiledCollectorArgs = nil
+	compiled, err := launcher.CompileCollectorArgs(
+		ctx, config_obj, acl_manager, repository,
+		!hunt.DisableObfuscation, /* should_obfuscate */
+		request)
+	if err != nil {
+		return "", err
+	}
+	hashes := huntArtifactHashes(config_obj, repository, request)
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	err = dispatcher.ModifyHunt(hunt_id, func(hunt_obj *api_proto.Hunt) error {
+		// The hunt may have been started while we were compiling.
+		if hunt_obj.State != api_proto.Hunt_PAUSED ||
+			hunt_obj.StartRequest == nil {
+			return errors.Errorf("Hunt %v is no longer paused", hunt_id)
+		}
+
+		hunt_obj.StartRequest.CompiledCollectorArgs = compiled
+		hunt_obj.ArtifactHashes = hashes
+		hunt_obj.StaleDefinition = false
+		hunt_obj.ChangedArtifacts = nil
+
+		hunt_path_manager := paths.NewHuntPathManager(hunt_id)
+		return db.SetSubject(config_obj, hunt_path_manager.Path(), hunt_obj)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	row := ordereddict.NewDict().
+		Set("Timestamp", HuntTimeNow()).
+		Set("HuntId", hunt_id).
+		Set("User", vql_subsystem.GetPrincipalFromACLManager(acl_manager)).
+		Set("Artifacts", request.Artifacts).
+		Set("Warning", recompileHuntWarning)
+
+	journal, err := services.GetJournal()
+	if err != nil {
+		return "", err
+	}
+
+	err = journal.PushRowsToArtifact(config_obj,
+		[]*ordereddict.Dict{row}, "System.Hunt.Recompile",
+		"server", hunt_id)
+	if err != nil {
+		return "", err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("RecompileHunt %v: %v", hunt_id, recompileHuntWarning)
+
+	return recompileHuntWarning, nil
+}
@@ -0,0 +1,96 @@
+package flows
+
+import (
+	"time"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+)
+
+const (
+	// The backoff doubles for each retry up to this many times.
+	max_retry_backoff_doublings = 10
+
+	// But it is never longer than this.
+	max_retry_backoff = 7 * 24 * time.Hour
+)
+
+// How long to wait before the retry attempt. The backoff doubles for
+// each retry without overflowing.
+func huntRetryBackoff(policy *api_proto.HuntRetryPolicy, attempt uint64) time.Duration {
+	max_seconds := uint64(max_retry_backoff / time.Second)
+	seconds := policy.Backoff
+	for i := uint64(0); i < attempt && i < max_retry_backoff_doublings &&
+		seconds < max_seconds; i++ {
+		seconds *= 2
+	}
+
+	if seconds > max_seconds {
+		seconds = max_seconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Update the hunt stats for a failed hunt collection. If the hunt's
+// retry policy allows another attempt the client is counted as
+// retrying and the retry to schedule is returned, otherwise the
+// client is counted as an error. Called with the hunt locked.
+func countHuntFlowFailure(
+	hunt *api_proto.Hunt,
+	collection_context *flows_proto.ArtifactCollectorContext,
+	now time.Time) *api_proto.HuntRetryRecord {
+	attempt := collection_context.Request.HuntRetry
+
+	policy := hunt.RetryPolicy
+	if policy != nil && attempt < policy.MaxRetries {
+		// The client stays retrying until its last attempt
+		// completes.
+		if attempt == 0 {
+			hunt.Stats.TotalClientsRetrying++
+		}
+
+		backoff := huntRetryBackoff(policy, attempt)
+
+		return &api_proto.HuntRetryRecord{
+			HuntId:    hunt.HuntId,
+			ClientId:  collection_context.ClientId,
+			FlowId:    collection_context.SessionId,
+			Error:     collection_context.Status,
+			Attempt:   attempt + 1,
+			NotBefore: HuntTimeFromTime(now.Add(backoff)),
+		}
+	}
+
+	if attempt > 0 && hunt.Stats.TotalClientsRetrying > 0 {
+		hunt.Stats.TotalClientsRetrying--
+	}
+	hunt.Stats.TotalClientsWithErrors++
+
+	return nil
+}
+
+// A retried collection completed so the client is no longer
+// retrying. Called with the hunt locked.
+func countHuntFlowRetrySuccess(
+	hunt *api_proto.Hunt,
+	collection_context *flows_proto.ArtifactCollectorContext) {
+	if collection_context.Request.HuntRetry > 0 &&
+		hunt.Stats.TotalClientsRetrying > 0 {
+		hunt.Stats.TotalClientsRetrying--
+	}
+}
+
+// Store the retry until the hunt manager schedules it.
+func recordHuntRetry(
+	config_obj *config_proto.Config, record *api_proto.HuntRetryRecord) error {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.SetSubject(config_obj,
+		paths.HuntRetryPath(record.HuntId, record.ClientId), record)
+}
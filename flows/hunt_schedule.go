@@ -0,0 +1,223 @@
+package flows
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	errors "github.com/pkg/errors"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+var (
+	// How often we check for scheduled hunts that are due.
+	hunt_schedule_period = time.Minute
+)
+
+// A hunt with a Schedule is never dispatched to clients itself.
+// Instead, while the scheduled hunt is RUNNING, each time the cron
+// schedule fires (evaluated in UTC) the hunt is cloned into a new
+// running hunt with the same start request, condition and client
+// limit.
+//
+// Each run expires at the next scheduled time (or at the scheduled
+// hunt's own Expires if that is sooner) so runs do not overlap. If a
+// previous run is still running anyway (e.g. its expiry was
+// extended) the tick is skipped. Once the scheduled hunt itself
+// expires the schedule is stopped.
+//
+// The next fire time is stored in the hunt object so the schedule
+// survives a restart. A tick missed while the server was down fires
+// once when it comes back up.
+func StartHuntScheduler(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	wg *sync.WaitGroup) error {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(hunt_schedule_period):
+				err := RunScheduledHunts(ctx, config_obj, time.Now())
+				if err != nil {
+					logger := logging.GetLogger(
+						config_obj, &logging.FrontendComponent)
+					logger.Error("RunScheduledHunts: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Start a run of every scheduled hunt which is due at now.
+func RunScheduledHunts(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	now time.Time) error {
+
+	dispatcher, err := services.RequireHuntDispatcher()
+	if err != nil {
+		return err
+	}
+
+	now_usec := HuntTimeFromTime(now)
+
+	// Take a copy of the due hunts so we do not hold the
+	// dispatcher lock while we create the runs.
+	due := []*api_proto.Hunt{}
+	running := make(map[string]bool)
+	err = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		if hunt.State == api_proto.Hunt_RUNNING &&
+			(hunt.Stats == nil || !hunt.Stats.Stopped) &&
+			now_usec < hunt.Expires {
+			running[hunt.HuntId] = true
+		}
+
+		if hunt.Schedule != "" &&
+			hunt.State == api_proto.Hunt_RUNNING &&
+			hunt.NextScheduledRun != 0 &&
+			hunt.NextScheduledRun <= now_usec {
+			due = append(due, proto.Clone(hunt).(*api_proto.Hunt))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	for _, hunt := range due {
+		// The schedule itself has expired.
+		if now_usec > hunt.Expires {
+			logger.Info("Hunt schedule %v expired", hunt.HuntId)
+			err := ModifyHunt(ctx, config_obj, &api_proto.Hunt{
+				HuntId: hunt.HuntId,
+				State:  api_proto.Hunt_STOPPED,
+			}, "")
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := runScheduledHunt(ctx, config_obj, hunt, now,
+			running[hunt.LastScheduledHuntId])
+		if err != nil {
+			logger.Error("Scheduled hunt %v: %v", hunt.HuntId, err)
+		}
+	}
+
+	return nil
+}
+
+func runScheduledHunt(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt,
+	now time.Time,
+	previous_running bool) error {
+
+	next_usec, err := nextScheduledRun(hunt.Schedule, now)
+	if err != nil {
+		return err
+	}
+
+	run_id := ""
+	if !previous_running {
+		run_id = GetNewHuntId()
+	}
+
+	// Persist the next fire time before starting the run so a
+	// crash can not start the same run twice.
+	err = services.GetHuntDispatcher().ModifyHunt(hunt.HuntId,
+		func(hunt_obj *api_proto.Hunt) error {
+			hunt_obj.NextScheduledRun = next_usec
+			if run_id != "" {
+				hunt_obj.LastScheduledHuntId = run_id
+			}
+
+			db, err := datastore.GetDB(config_obj)
+			if err != nil {
+				return err
+			}
+
+			hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+			return db.SetSubject(config_obj, hunt_path_manager.Path(), hunt_obj)
+		})
+	if err != nil {
+		return err
+	}
+
+	if previous_running {
+		return errors.Errorf("Previous run %v is still running, skipping",
+			hunt.LastScheduledHuntId)
+	}
+
+	expires := hunt.Expires
+	if next_usec < expires {
+		expires = next_usec
+	}
+
+	start_request := proto.Clone(hunt.StartRequest).(*flows_proto.ArtifactCollectorArgs)
+	start_request.CompiledCollectorArgs = nil
+
+	run := &api_proto.Hunt{
+		HuntId:                   run_id,
+		HuntDescription:          hunt.HuntDescription,
+		Creator:                  hunt.Creator,
+		StartRequest:             start_request,
+		Condition:                hunt.Condition,
+		ClientLimit:              hunt.ClientLimit,
+		RetentionDays:            hunt.RetentionDays,
+		RetentionIncludesResults: hunt.RetentionIncludesResults,
+		Expires:                  expires,
+		State:                    api_proto.Hunt_RUNNING,
+		CampaignId:               hunt.CampaignId,
+		Tags:                     hunt.Tags,
+		LabelParameters:          hunt.LabelParameters,
+		AutoStopErrorRate:        hunt.AutoStopErrorRate,
+		ClientsPerMinute:         hunt.ClientsPerMinute,
+		DisableObfuscation:       hunt.DisableObfuscation,
+		ScheduledBy:              hunt.HuntId,
+	}
+
+	// Runs are compiled with the permissions of the user that
+	// scheduled the hunt.
+	// Runs of an approved schedule do not need approving again.
+	acl_manager := vql_subsystem.NewServerACLManager(config_obj, hunt.Creator)
+	_, err = createHunt(ctx, config_obj, acl_manager, run, hunt.ApprovedBy)
+	return err
+}
+
+// The time the schedule fires after now in microseconds.
+func nextScheduledRun(schedule_expr string, now time.Time) (uint64, error) {
+	schedule, err := utils.ParseCronSchedule(schedule_expr)
+	if err != nil {
+		return 0, err
+	}
+
+	next := schedule.Next(now.UTC())
+	if next.IsZero() {
+		return 0, errors.Errorf(
+			"Cron schedule %q never fires", schedule_expr)
+	}
+
+	return HuntTimeFromTime(next), nil
+}
@@ -0,0 +1,66 @@
+package flows
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/result_sets"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// Record the clients matching the hunt's condition as it is started
+// in the hunt's client snapshot, replacing any earlier snapshot.
+// Returns how many clients matched.
+func snapshotHuntClients(ctx context.Context,
+	config_obj *config_proto.Config, hunt *api_proto.Hunt) (uint64, error) {
+	file_store_factory := file_store.GetFileStore(config_obj)
+	rs_writer, err := result_sets.NewResultSetWriter(file_store_factory,
+		paths.NewHuntPathManager(hunt.HuntId).ClientSnapshot(),
+		nil, true /* truncate */)
+	if err != nil {
+		return 0, err
+	}
+	defer rs_writer.Close()
+
+	return matchHuntClients(ctx, config_obj, hunt, func(client_id string) {
+		rs_writer.Write(ordereddict.NewDict().
+			Set("ClientId", client_id).
+			Set("Hostname", services.GetHostname(client_id)).
+			Set("StartTime", hunt.StartTime))
+	})
+}
+
+// Take the snapshot of a hunt which was just started by ModifyHunt
+// and record the number of clients on the hunt. The hunt is already
+// running so failures are only logged.
+func recordHuntClientSnapshot(ctx context.Context,
+	config_obj *config_proto.Config, hunt *api_proto.Hunt) {
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	count, err := snapshotHuntClients(ctx, config_obj, hunt)
+	if err != nil {
+		logger.Error("Snapshot of hunt %v clients: %v", hunt.HuntId, err)
+		return
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		logger.Error("Snapshot of hunt %v clients: %v", hunt.HuntId, err)
+		return
+	}
+
+	err = services.GetHuntDispatcher().ModifyHunt(hunt.HuntId,
+		func(hunt_obj *api_proto.Hunt) error {
+			hunt_obj.SnapshotClientCount = count
+			hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+			return db.SetSubject(config_obj, hunt_path_manager.Path(), hunt_obj)
+		})
+	if err != nil {
+		logger.Error("Snapshot of hunt %v clients: %v", hunt.HuntId, err)
+	}
+}
@@ -0,0 +1,104 @@
+package flows
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+var (
+	hunt_status_csv_header = []string{
+		"client_id", "hostname", "state", "completion_time",
+		"row_count", "error",
+	}
+)
+
+// Write a CSV report of each client the hunt was scheduled on and
+// the status of its flow. Unlike the hunt download this contains
+// none of the collected data. Rows are written as the hunt's flow
+// index is read so large hunts are never held in memory.
+//
+// The completion time is only set for flows which are done.
+func ExportHuntStatusCSV(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string, writer io.Writer) error {
+
+	row_chan, err := file_store.GetTimeRange(ctx, config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), 0, 0)
+	if err != nil {
+		return err
+	}
+
+	csv_writer := csv.NewWriter(writer)
+	err = csv_writer.Write(hunt_status_csv_header)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for row := range row_chan {
+		client_id, _ := row.GetString("ClientId")
+		flow_id, _ := row.GetString("FlowId")
+		if client_id == "" || flow_id == "" || seen[client_id+flow_id] {
+			continue
+		}
+		seen[client_id+flow_id] = true
+
+		err = csv_writer.Write(huntStatusRow(config_obj, client_id, flow_id))
+		if err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	csv_writer.Flush()
+	return csv_writer.Error()
+}
+
+func huntStatusRow(
+	config_obj *config_proto.Config, client_id, flow_id string) []string {
+	hostname := ""
+	if services.GetClientInfoManager() != nil {
+		hostname = services.GetHostname(client_id)
+	}
+
+	collection_context, err := LoadCollectionContext(
+		config_obj, client_id, flow_id)
+	if err != nil {
+		return []string{client_id, hostname, "UNKNOWN", "", "",
+			fmt.Sprintf("Unable to load flow %v: %v", flow_id, err)}
+	}
+
+	completion_time := ""
+	if collection_context.State != flows_proto.ArtifactCollectorContext_RUNNING &&
+		collection_context.ActiveTime > 0 {
+		completion_time = time.Unix(0,
+			int64(collection_context.ActiveTime)*1000).UTC().Format(time.RFC3339)
+	}
+
+	error_message := ""
+	if collection_context.State == flows_proto.ArtifactCollectorContext_ERROR {
+		error_message = collection_context.Status
+	}
+
+	return []string{
+		client_id,
+		hostname,
+		collection_context.State.String(),
+		completion_time,
+		fmt.Sprintf("%v", collection_context.TotalCollectedRows),
+		error_message,
+	}
+}
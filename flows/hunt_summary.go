@@ -0,0 +1,145 @@
+package flows
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	errors "github.com/pkg/errors"
+	"www.velocidex.com/golang/velociraptor/acls"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/paths"
+	artifact_paths "www.velocidex.com/golang/velociraptor/paths/artifacts"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+// The number of rows with a particular value in the summarized
+// column.
+type HuntValueCount struct {
+	Value string
+	Count uint64
+}
+
+type HuntSummary struct {
+	HuntId string
+	Source string
+	Column string
+
+	// The rows read and how many of them did not have the column.
+	TotalRows   uint64
+	MissingRows uint64
+
+	// The number of distinct values, of which the top values are
+	// returned most common first.
+	DistinctValues uint64
+	Top            []*HuntValueCount
+}
+
+// Count the distinct values of a column across all the results of
+// a hunt's artifact source and return the top_n most common. The
+// results are read a row at a time, so only the counts are kept in
+// memory. Values which are not strings are counted by their JSON
+// encoding.
+func SummarizeHunt(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	hunt_id, artifact_source, column string,
+	top_n int) (*HuntSummary, error) {
+
+	ok, err := acl_manager.CheckAccess(acls.READ_RESULTS)
+	if !ok || err != nil {
+		return nil, errors.Errorf("Permission denied: %v", acls.READ_RESULTS)
+	}
+
+	if artifact_source == "" || column == "" {
+		return nil, errors.New("An artifact source and column are required")
+	}
+
+	row_chan, err := file_store.GetTimeRange(ctx, config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &HuntSummary{
+		HuntId: hunt_id,
+		Source: artifact_source,
+		Column: column,
+	}
+	counts := make(map[string]uint64)
+	seen := make(map[string]bool)
+
+	for row := range row_chan {
+		client_id, _ := row.GetString("ClientId")
+		flow_id, _ := row.GetString("FlowId")
+		if client_id == "" || flow_id == "" || seen[client_id+flow_id] {
+			continue
+		}
+		seen[client_id+flow_id] = true
+
+		path_manager := artifact_paths.NewArtifactPathManager(
+			config_obj, client_id, flow_id, artifact_source)
+		result_chan, err := file_store.GetTimeRange(
+			ctx, config_obj, path_manager, 0, 0)
+		if err != nil {
+			continue
+		}
+
+		for result_row := range result_chan {
+			result.TotalRows++
+
+			value, pres := result_row.Get(column)
+			if !pres {
+				result.MissingRows++
+				continue
+			}
+
+			counts[summaryValue(value)]++
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	result.DistinctValues = uint64(len(counts))
+	for value, count := range counts {
+		result.Top = append(result.Top, &HuntValueCount{
+			Value: value,
+			Count: count,
+		})
+	}
+
+	// Most common first, ties in value order so the result is
+	// stable.
+	sort.Slice(result.Top, func(i, j int) bool {
+		if result.Top[i].Count != result.Top[j].Count {
+			return result.Top[i].Count > result.Top[j].Count
+		}
+		return result.Top[i].Value < result.Top[j].Value
+	})
+
+	if top_n > 0 && len(result.Top) > top_n {
+		result.Top = result.Top[:top_n]
+	}
+
+	return result, nil
+}
+
+func summaryValue(value interface{}) string {
+	switch t := value.(type) {
+	case string:
+		return t
+	case nil:
+		return "null"
+	}
+
+	serialized, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(serialized)
+}
@@ -0,0 +1,262 @@
+package flows
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	errors "github.com/pkg/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+// Save a hunt template, replacing any existing template with the
+// same name. All the template's artifacts must exist when it is
+// saved. Replacing a template keeps its original creator and create
+// time.
+func SaveHuntTemplate(
+	config_obj *config_proto.Config,
+	template *api_proto.HuntTemplate) error {
+
+	if strings.TrimSpace(template.Name) == "" {
+		return errors.New("Hunt templates must have a name.")
+	}
+
+	if template.Hunt == nil || template.Hunt.StartRequest == nil ||
+		len(template.Hunt.StartRequest.Artifacts) == 0 {
+		return errors.New("Hunt templates must collect some artifacts.")
+	}
+
+	if template.Hunt.Condition != nil {
+		err := validateHuntCondition(template.Hunt.Condition)
+		if err != nil {
+			return err
+		}
+	}
+
+	missing, err := missingTemplateArtifacts(config_obj, template)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("Unknown artifacts %v",
+			strings.Join(missing, ", "))
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	template = proto.Clone(template).(*api_proto.HuntTemplate)
+	template.Warnings = nil
+	template.ModifyTime = HuntTimeNow()
+	template.CreateTime = template.ModifyTime
+
+	existing, err := loadHuntTemplate(config_obj, template.Name)
+	if err == nil {
+		template.CreateTime = existing.CreateTime
+		if existing.Creator != "" {
+			template.Creator = existing.Creator
+		}
+	}
+
+	// These are set when the hunt is created.
+	hunt := template.Hunt
+	hunt.HuntId = ""
+	hunt.Creator = ""
+	hunt.CreateTime = 0
+	hunt.StartTime = 0
+	hunt.Stats = nil
+	hunt.Notes = nil
+	hunt.IdempotencyKey = ""
+	hunt.StartRequest.CompiledCollectorArgs = nil
+	hunt.ArtifactHashes = nil
+	hunt.SnapshotClientCount = 0
+	hunt.NextScheduledRun = 0
+	hunt.LastScheduledHuntId = ""
+	hunt.ScheduledBy = ""
+	hunt.ScheduledRuns = nil
+	hunt.RequestedBy = ""
+	hunt.ApprovedBy = ""
+
+	return db.SetSubject(config_obj,
+		paths.HuntTemplatePath(template.Name), template)
+}
+
+// Get a hunt template. If any of its artifacts no longer exist the
+// template is still returned, with warnings.
+func GetHuntTemplate(
+	config_obj *config_proto.Config,
+	name string) (*api_proto.HuntTemplate, error) {
+
+	template, err := loadHuntTemplate(config_obj, name)
+	if err != nil {
+		return nil, err
+	}
+
+	checkHuntTemplate(config_obj, template)
+	return template, nil
+}
+
+// List all the hunt templates by name.
+func ListHuntTemplates(
+	config_obj *config_proto.Config) (*api_proto.ListHuntTemplatesResponse, error) {
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	urns, err := db.ListChildren(config_obj,
+		paths.HuntTemplateDirectory(), 0, 1000)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api_proto.ListHuntTemplatesResponse{}
+	for _, urn := range urns {
+		template := &api_proto.HuntTemplate{}
+		err = db.GetSubject(config_obj, urn, template)
+		if err != nil || template.Name == "" {
+			continue
+		}
+
+		checkHuntTemplate(config_obj, template)
+		result.Items = append(result.Items, template)
+	}
+
+	sort.Slice(result.Items, func(i, j int) bool {
+		return result.Items[i].Name < result.Items[j].Name
+	})
+
+	return result, nil
+}
+
+func DeleteHuntTemplate(
+	config_obj *config_proto.Config, name string) error {
+
+	_, err := loadHuntTemplate(config_obj, name)
+	if err != nil {
+		return err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.DeleteSubject(config_obj, paths.HuntTemplatePath(name))
+}
+
+// Create a hunt from the template. Any fields set in overrides
+// replace the template's fields (e.g. setting the start request
+// replaces the template's start request entirely), then the hunt is
+// created just like CreateHunt does.
+func CreateHuntFromTemplate(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	template_name string,
+	overrides *api_proto.Hunt) (string, error) {
+
+	template, err := loadHuntTemplate(config_obj, template_name)
+	if err != nil {
+		return "", err
+	}
+
+	hunt := proto.Clone(template.Hunt).(*api_proto.Hunt)
+	if overrides != nil {
+		message := hunt.ProtoReflect()
+		proto.Clone(overrides).(*api_proto.Hunt).ProtoReflect().Range(
+			func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+				message.Set(field, value)
+				return true
+			})
+	}
+
+	return CreateHunt(ctx, config_obj, acl_manager, hunt)
+}
+
+func loadHuntTemplate(
+	config_obj *config_proto.Config,
+	name string) (*api_proto.HuntTemplate, error) {
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &api_proto.HuntTemplate{}
+	err = db.GetSubject(config_obj, paths.HuntTemplatePath(name), template)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.Name != name {
+		return nil, errors.Errorf("Unknown hunt template %v", name)
+	}
+
+	return template, nil
+}
+
+// Warn about artifacts removed since the template was saved.
+func checkHuntTemplate(
+	config_obj *config_proto.Config, template *api_proto.HuntTemplate) {
+	missing, err := missingTemplateArtifacts(config_obj, template)
+	if err != nil {
+		return
+	}
+
+	template.Warnings = nil
+	for _, name := range missing {
+		template.Warnings = append(template.Warnings,
+			"Artifact "+name+" no longer exists")
+	}
+}
+
+func missingTemplateArtifacts(
+	config_obj *config_proto.Config,
+	template *api_proto.HuntTemplate) ([]string, error) {
+	if template.Hunt == nil || template.Hunt.StartRequest == nil {
+		return nil, nil
+	}
+
+	manager, err := services.GetRepositoryManager()
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	request := template.Hunt.StartRequest
+	names := append([]string{}, request.Artifacts...)
+	for _, spec := range request.Specs {
+		names = append(names, spec.Artifact)
+	}
+
+	missing := []string{}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		_, pres := repository.Get(config_obj, name)
+		if !pres {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing, nil
+}
@@ -0,0 +1,32 @@
+package flows
+
+import "time"
+
+// All hunt timestamps - the times stored in the hunt object
+// (CreateTime, StartTime, Expires etc) as well as the Timestamp
+// column of rows written to the hunt's journals - are microseconds
+// since the epoch in UTC.
+
+// The current time as a hunt timestamp.
+func HuntTimeNow() uint64 {
+	return HuntTimeFromTime(time.Now())
+}
+
+// Convert a time to a hunt timestamp.
+func HuntTimeFromTime(t time.Time) uint64 {
+	return uint64(t.UTC().UnixNano() / 1000)
+}
+
+// Convert a hunt timestamp to a time.
+func HuntTimeToTime(usec uint64) time.Time {
+	return time.Unix(0, int64(usec)*1000).UTC()
+}
+
+// Format a hunt timestamp as an RFC3339 string in UTC. An unset (0)
+// timestamp is formatted as "".
+func HuntTimeToString(usec uint64) string {
+	if usec == 0 {
+		return ""
+	}
+	return HuntTimeToTime(usec).Format(time.RFC3339Nano)
+}
@@ -25,19 +25,27 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
 	"path"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Velocidex/ordereddict"
 	"github.com/golang/protobuf/proto"
 	errors "github.com/pkg/errors"
+	"www.velocidex.com/golang/velociraptor/acls"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	artifacts_proto "www.velocidex.com/golang/velociraptor/artifacts/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/constants"
 	"www.velocidex.com/golang/velociraptor/datastore"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 )
 
@@ -81,158 +89,1415 @@ func GetArtifactSources(
 	}
 
 	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return result
+	}
+
+	summary, pres := getArtifactSummary(config_obj, repository, artifact)
+	if pres {
+		result = append(result, summary.sources...)
+	}
+	return result
+}
+
+func CreateHunt(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	hunt *api_proto.Hunt) (string, error) {
+	return createHunt(ctx, config_obj, acl_manager, hunt, "")
+}
+
+// Create the hunt. If approved_by is set the hunt was already
+// approved (e.g. it is a run of an approved scheduled hunt) so it
+// starts straight away even when hunts require approval.
+func createHunt(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	hunt *api_proto.Hunt, approved_by string) (string, error) {
+
+	// The hunt is started once the dispatcher is refreshed below.
+	defer func() { releaseRunningHunt(hunt.HuntId) }()
+
+	hunt_id, created, err := storeNewHuntOnce(
+		ctx, config_obj, acl_manager, hunt, approved_by)
+	if err != nil {
+		return "", err
+	}
+
+	// A retry of an earlier request - the hunt already exists.
+	if !created {
+		return hunt_id, nil
+	}
+
+	// Trigger a refresh of the hunt dispatcher. This guarantees
+	// that fresh data will be read in subsequent ListHunt()
+	// calls.
+	err = services.GetHuntDispatcher().Refresh(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	if hunt.State == api_proto.Hunt_RUNNING {
+		err = notifyHuntClients(config_obj, hunt)
+	}
+
+	return hunt_id, err
+}
+
+// The outcome of creating one hunt of a batch.
+type CreateHuntResult struct {
+	HuntId string
+	Err    error
+}
+
+// Create a batch of hunts. Each hunt is created independently so a
+// hunt which fails to compile does not prevent the others from being
+// created. The hunt dispatcher is only refreshed once at the end.
+func CreateHunts(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	hunts []*api_proto.Hunt) ([]*CreateHuntResult, error) {
+
+	// The running hunts of the batch count towards the running
+	// hunts limit until the dispatcher is refreshed below.
+	defer func() {
+		for _, hunt := range hunts {
+			releaseRunningHunt(hunt.HuntId)
+		}
+	}()
+
+	result := make([]*CreateHuntResult, 0, len(hunts))
+	created := make([]bool, 0, len(hunts))
+	created_count := 0
+	for _, hunt := range hunts {
+		hunt_id, hunt_created, err := storeNewHuntOnce(
+			ctx, config_obj, acl_manager, hunt, "")
+		result = append(result, &CreateHuntResult{HuntId: hunt_id, Err: err})
+		created = append(created, hunt_created)
+		if hunt_created {
+			created_count++
+		}
+	}
+
+	if created_count == 0 {
+		return result, nil
+	}
+
+	err := services.GetHuntDispatcher().Refresh(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, hunt := range hunts {
+		if created[i] && hunt.State == api_proto.Hunt_RUNNING {
+			err = notifyHuntClients(config_obj, hunt)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Validate, compile and store a new hunt without refreshing the hunt
+// dispatcher.
+func storeNewHunt(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	hunt *api_proto.Hunt, approved_by string) (string, error) {
+
+	// Reject oversized requests before doing any work on them.
+	err := checkHuntRequestLimits(config_obj, hunt)
+	if err != nil {
+		return "", err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	if hunt.Stats == nil {
+		hunt.Stats = &api_proto.HuntStats{}
+	}
+
+	if hunt.HuntId == "" {
+		hunt.HuntId = GetNewHuntId()
+	}
+
+	// Attribute the hunt to the principal creating it if the
+	// caller did not already.
+	if hunt.Creator == "" {
+		hunt.Creator = vql_subsystem.GetPrincipalFromACLManager(acl_manager)
+	}
+
+	if hunt.StartRequest == nil || hunt.StartRequest.Artifacts == nil {
+		return "", errors.New("No artifacts to collect.")
+	}
+
+	hunt.CreateTime = HuntTimeNow()
+	if hunt.Expires == 0 {
+		hunt.Expires = HuntTimeFromTime(HuntTimeToTime(hunt.CreateTime).Add(
+			defaultHuntExpiry(config_obj)))
+	}
+
+	if hunt.Expires < hunt.CreateTime {
+		return "", errors.New("Hunt expiry is in the past!")
+	}
+
+	err = checkHuntExpiry(config_obj, hunt.CreateTime, hunt.Expires)
+	if err != nil {
+		return "", err
+	}
+
+	// A retention of 0 means keep the hunt's files forever.
+	if hunt.RetentionDays < 0 {
+		return "", errors.New("Hunt retention days must not be negative.")
+	}
+
+	if hunt.AutoStopErrorRate != nil {
+		percent := hunt.AutoStopErrorRate.MaxErrorPercent
+		if percent < 0 || percent > 100 {
+			return "", errors.New(
+				"Hunt auto stop error rate must be between 0 and 100 percent.")
+		}
+	}
+
+	hunt.Tags = normalizeHuntTags(hunt.Tags)
+
+	if hunt.Condition != nil {
+		err = validateHuntCondition(hunt.Condition)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Campaign membership is optional but must refer to a real
+	// campaign.
+	if hunt.CampaignId != "" {
+		_, err := loadCampaign(config_obj, hunt.CampaignId)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if hunt.Schedule != "" {
+		_, err := nextScheduledRun(hunt.Schedule, time.Now())
+		if err != nil {
+			return "", err
+		}
+	}
+
+	manager, err := services.GetRepositoryManager()
+	if err != nil {
+		return "", err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkHuntArtifactTypes(config_obj, repository, hunt)
+	if err != nil {
+		return "", err
+	}
+
+	// Check the user may collect every artifact before we compile
+	// them, so a hunt can not be used to collect artifacts the
+	// user could not collect directly.
+	err = checkHuntArtifactAccess(config_obj, acl_manager, repository, hunt)
+	if err != nil {
+		return "", err
+	}
+
+	err = validateHuntLabelParameters(config_obj, repository, hunt)
+	if err != nil {
+		return "", err
+	}
+
+	// Compile the start request and store it in the hunt. We will
+	// use this compiled version to launch all other flows from
+	// this hunt rather than re-compile the artifact each
+	// time. This ensures that if the artifact definition is
+	// changed after this point, the hunt will continue to
+	// schedule consistent VQL on the clients.
+	launcher, err := services.GetLauncher()
+	if err != nil {
+		return "", err
+	}
+
+	compiled, err := launcher.CompileCollectorArgs(
+		ctx, config_obj, acl_manager, repository,
+		!hunt.DisableObfuscation, /* should_obfuscate */
+		hunt.StartRequest)
+	if err != nil {
+		return "", err
+	}
+	hunt.StartRequest.CompiledCollectorArgs = append(
+		hunt.StartRequest.CompiledCollectorArgs, compiled...)
+
+	// Remember what the artifacts looked like when compiled so we
+	// can tell if they are edited later.
+	hunt.ArtifactHashes = huntArtifactHashes(
+		config_obj, repository, hunt.StartRequest)
+
+	// Only we record who asked for and approved the hunt.
+	hunt.RequestedBy = ""
+	hunt.ApprovedBy = ""
+
+	// Notes may only be added with AddHuntNote, which records who
+	// wrote them.
+	hunt.Notes = nil
+
+	// We allow our caller to determine if hunts are created in
+	// the running state or the paused state. Hunts created in the
+	// running state are started exactly as ModifyHunt would
+	// start them, unless they need to be approved first.
+	if hunt.State == api_proto.Hunt_UNSET {
+		hunt.State = api_proto.Hunt_PAUSED
+
+	} else if (hunt.State == api_proto.Hunt_RUNNING &&
+		HuntApprovalRequired(config_obj) && approved_by == "") ||
+		hunt.State == api_proto.Hunt_PENDING_APPROVAL {
+		err = requestHuntApproval(hunt, hunt.Creator)
+		if err != nil {
+			return "", err
+		}
+
+	} else if hunt.State == api_proto.Hunt_RUNNING {
+		hunt.RequestedBy = hunt.Creator
+		hunt.ApprovedBy = approved_by
+
+		err = reserveRunningHunt(config_obj, hunt)
+		if err != nil {
+			return "", err
+		}
+
+		hunt.StartWarning, err = checkHuntMatchesClients(
+			ctx, config_obj, hunt, hunt.Force)
+		if err != nil {
+			return "", err
+		}
+
+		err = startHunt(hunt)
+		if err != nil {
+			return "", err
+		}
+
+		if hunt.SnapshotClients {
+			hunt.SnapshotClientCount, err = snapshotHuntClients(
+				ctx, config_obj, hunt)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// Force only applies to this request.
+	hunt.Force = false
+
+	// Permissions and staleness are computed when the hunt is
+	// read.
+	hunt.Permissions = nil
+	hunt.StaleDefinition = false
+	hunt.ChangedArtifacts = nil
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt.HuntId)
+	err = db.SetSubject(config_obj, hunt_path_manager.Path(), hunt)
+	if err != nil {
+		return "", err
+	}
+
+	switch hunt.State {
+	case api_proto.Hunt_RUNNING:
+		EmitHuntStateChange(config_obj, hunt.HuntId,
+			api_proto.Hunt_PAUSED, api_proto.Hunt_RUNNING, hunt.Creator, "")
+
+	case api_proto.Hunt_PENDING_APPROVAL:
+		EmitHuntStateChange(config_obj, hunt.HuntId,
+			api_proto.Hunt_PAUSED, api_proto.Hunt_PENDING_APPROVAL,
+			hunt.Creator, "")
+		emitHuntApproval(config_obj, hunt, "Requested", hunt.Creator)
+	}
+
+	return hunt.HuntId, nil
+}
+
+// How long hunts run for when they do not specify an expiry. This
+// is never more than the configured maximum.
+func defaultHuntExpiry(config_obj *config_proto.Config) time.Duration {
+	expiry_hours := uint64(7 * 24)
+	if config_obj.Defaults != nil {
+		if config_obj.Defaults.HuntExpiryHours > 0 {
+			expiry_hours = config_obj.Defaults.HuntExpiryHours
+		}
+		max_hours := config_obj.Defaults.MaxHuntExpiryHours
+		if max_hours > 0 && expiry_hours > max_hours {
+			expiry_hours = max_hours
+		}
+	}
+	return time.Duration(expiry_hours) * time.Hour
+}
+
+// Hunts may not expire more than the configured maximum after they
+// were created.
+func checkHuntExpiry(config_obj *config_proto.Config,
+	create_time, expires uint64) error {
+	if config_obj.Defaults == nil ||
+		config_obj.Defaults.MaxHuntExpiryHours == 0 {
+		return nil
+	}
+
+	max_hours := config_obj.Defaults.MaxHuntExpiryHours
+	latest := HuntTimeFromTime(HuntTimeToTime(create_time).Add(
+		time.Duration(max_hours) * time.Hour))
+	if expires > latest {
+		return fmt.Errorf("Hunt expiry %v exceeds the configured maximum "+
+			"of %v hours after the hunt is created.",
+			HuntTimeToTime(expires).Format(time.RFC3339), max_hours)
+	}
+	return nil
+}
+
+// Enforce the configured limits on the size of the hunt's start
+// request.
+func checkHuntRequestLimits(
+	config_obj *config_proto.Config, hunt *api_proto.Hunt) error {
+	if hunt.StartRequest == nil {
+		return nil
+	}
+
+	max_artifacts := uint64(constants.MAX_HUNT_ARTIFACTS)
+	max_parameter_bytes := uint64(constants.MAX_HUNT_PARAMETER_BYTES)
+	if config_obj.Defaults != nil {
+		if config_obj.Defaults.MaxHuntArtifacts > 0 {
+			max_artifacts = config_obj.Defaults.MaxHuntArtifacts
+		}
+		if config_obj.Defaults.MaxHuntParameterBytes > 0 {
+			max_parameter_bytes = config_obj.Defaults.MaxHuntParameterBytes
+		}
+	}
+
+	if uint64(len(hunt.StartRequest.Artifacts)) > max_artifacts {
+		return fmt.Errorf("Hunt collects %v artifacts but at most %v are allowed.",
+			len(hunt.StartRequest.Artifacts), max_artifacts)
+	}
+
+	parameter_bytes := uint64(0)
+	for _, spec := range hunt.StartRequest.Specs {
+		if spec.Parameters == nil {
+			continue
+		}
+		for _, env := range spec.Parameters.Env {
+			parameter_bytes += uint64(len(env.Key) + len(env.Value))
+		}
+	}
+
+	if parameter_bytes > max_parameter_bytes {
+		return fmt.Errorf("Hunt parameters are %v bytes but at most %v are allowed.",
+			parameter_bytes, max_parameter_bytes)
+	}
+
+	return nil
+}
+
+// The permissions needed to collect the hunt's artifacts, as declared
+// by each artifact's required_permissions. The GUI can use this to
+// check if a user may start a hunt before they try to.
+func GetHuntRequiredPermissions(
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt) ([]string, error) {
+	manager, err := services.GetRepositoryManager()
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := huntRequiredPermissions(config_obj, repository, hunt)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(required))
+	for permission := range required {
+		result = append(result, permission.String())
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// Map each permission required by the hunt's artifacts to the
+// artifacts which require it.
+func huntRequiredPermissions(
+	config_obj *config_proto.Config,
+	repository services.Repository,
+	hunt *api_proto.Hunt) (map[acls.ACL_PERMISSION][]string, error) {
+	if hunt.StartRequest == nil {
+		return nil, errors.New("No artifacts to collect.")
+	}
+
+	result := make(map[acls.ACL_PERMISSION][]string)
+	for _, name := range hunt.StartRequest.Artifacts {
+		artifact, err := getHuntArtifact(
+			config_obj, repository, hunt.StartRequest, name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, perm := range artifact.RequiredPermissions {
+			permission := acls.GetPermission(perm)
+			result[permission] = append(result[permission], name)
+		}
+	}
+
+	return result, nil
+}
+
+// Get the artifact the hunt will collect for name, which may be a
+// custom override.
+func getHuntArtifact(
+	config_obj *config_proto.Config,
+	repository services.Repository,
+	request *flows_proto.ArtifactCollectorArgs,
+	name string) (*artifacts_proto.Artifact, error) {
+	var artifact *artifacts_proto.Artifact
+	if request.AllowCustomOverrides {
+		artifact, _ = repository.Get(config_obj, "Custom."+name)
+	}
+
+	if artifact == nil {
+		artifact, _ = repository.Get(config_obj, name)
+	}
+
+	if artifact == nil {
+		return nil, errors.New("Unknown artifact " + name)
+	}
+
+	return artifact, nil
+}
+
+// Hunts schedule their artifacts on clients so server artifacts
+// would compile but never collect anything useful. Only CLIENT and
+// CLIENT_EVENT artifacts may be hunted.
+func checkHuntArtifactTypes(
+	config_obj *config_proto.Config,
+	repository services.Repository,
+	hunt *api_proto.Hunt) error {
+	rejected := []string{}
+	for _, name := range hunt.StartRequest.Artifacts {
+		artifact, err := getHuntArtifact(
+			config_obj, repository, hunt.StartRequest, name)
+		if err != nil {
+			return err
+		}
+
+		switch strings.ToLower(artifact.Type) {
+		case "", "client", "client_event":
+		default:
+			rejected = append(rejected, fmt.Sprintf("%v (%v)",
+				name, strings.ToUpper(artifact.Type)))
+		}
+	}
+
+	if len(rejected) > 0 {
+		return fmt.Errorf(
+			"Hunts can only collect CLIENT or CLIENT_EVENT artifacts: "+
+				"%v can not be collected on clients",
+			strings.Join(rejected, ", "))
+	}
+
+	return nil
+}
+
+// Check that the principal holds all the permissions required by
+// each of the hunt's artifacts.
+func checkHuntArtifactAccess(
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	repository services.Repository,
+	hunt *api_proto.Hunt) error {
+	required, err := huntRequiredPermissions(config_obj, repository, hunt)
+	if err != nil {
+		return err
+	}
+
+	// Check in a stable order so the error is consistent.
+	permissions := make([]acls.ACL_PERMISSION, 0, len(required))
+	for permission := range required {
+		permissions = append(permissions, permission)
+	}
+	sort.Slice(permissions, func(i, j int) bool {
+		return permissions[i] < permissions[j]
+	})
+
+	for _, permission := range permissions {
+		ok, err := acl_manager.CheckAccess(permission)
+		if !ok || err != nil {
+			return fmt.Errorf(
+				"Permission denied: collecting artifact %v requires %v",
+				strings.Join(required[permission], ", "), permission)
+		}
+	}
+
+	return nil
+}
+
+// What the principal may do with the hunt. Managing hunts requires
+// COLLECT_CLIENT, and starting one also requires the permissions
+// needed to collect its artifacts. The result depends on the caller
+// so it must be computed for each request.
+func GetHuntPermissions(
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	hunt *api_proto.Hunt) (*api_proto.HuntPermissions, error) {
+	result := &api_proto.HuntPermissions{}
+
+	ok, err := acl_manager.CheckAccess(acls.COLLECT_CLIENT)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok || hunt.State == api_proto.Hunt_ARCHIVED {
+		return result, nil
+	}
+
+	result.CanModify = true
+	result.CanArchive = true
+	result.CanStop = hunt.State == api_proto.Hunt_RUNNING
+
+	// Hunts stopped automatically (e.g. expired) can not be
+	// restarted.
+	if hunt.State == api_proto.Hunt_RUNNING ||
+		(hunt.Stats != nil && hunt.Stats.Stopped) {
+		return result, nil
+	}
+
+	manager, err := services.GetRepositoryManager()
+	if err != nil {
+		return nil, err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	result.CanStart = checkHuntArtifactAccess(
+		config_obj, acl_manager, repository, hunt) == nil
+
+	return result, nil
+}
+
+var (
+	// Hunts which are being started but which the hunt dispatcher
+	// may not know are running yet (e.g. a new hunt is only loaded
+	// when the dispatcher is refreshed after the whole batch is
+	// stored).
+	starting_hunts_mu sync.Mutex
+	starting_hunts    = make(map[string]bool)
+)
+
+// Refuse to start another hunt if the configured number of hunts are
+// already running, otherwise reserve a place for it until
+// releaseRunningHunt is called once the hunt dispatcher knows the
+// hunt is running. The count comes from the hunt dispatcher's in
+// memory copy of the hunts and the hunts still being started, under
+// one lock so concurrent starts can not both take the last place.
+// Scheduled hunts are not counted since they are never sent to
+// clients themselves - their runs are.
+func reserveRunningHunt(
+	config_obj *config_proto.Config, new_hunt *api_proto.Hunt) error {
+	if config_obj.Defaults == nil || config_obj.Defaults.MaxRunningHunts == 0 ||
+		new_hunt.Schedule != "" {
+		return nil
+	}
+
+	starting_hunts_mu.Lock()
+	defer starting_hunts_mu.Unlock()
+
+	running := make(map[string]bool)
+	for hunt_id := range starting_hunts {
+		running[hunt_id] = true
+	}
+
+	now := HuntTimeNow()
+	err := services.GetHuntDispatcher().ApplyFuncOnHunts(
+		func(hunt *api_proto.Hunt) error {
+			if hunt.State == api_proto.Hunt_RUNNING &&
+				hunt.Schedule == "" &&
+				(hunt.Stats == nil || !hunt.Stats.Stopped) &&
+				now < hunt.Expires {
+				running[hunt.HuntId] = true
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	delete(running, new_hunt.HuntId)
+
+	if uint64(len(running)) >= config_obj.Defaults.MaxRunningHunts {
+		return fmt.Errorf("Too many active hunts: %v hunts are already running (limit %v).",
+			len(running), config_obj.Defaults.MaxRunningHunts)
+	}
+
+	starting_hunts[new_hunt.HuntId] = true
+	return nil
+}
+
+// The hunt dispatcher knows the hunt's state now (or it failed to
+// start).
+func releaseRunningHunt(hunt_id string) {
+	starting_hunts_mu.Lock()
+	defer starting_hunts_mu.Unlock()
+
+	delete(starting_hunts, hunt_id)
+}
+
+// Move the hunt into the RUNNING state. Both CreateHunt and
+// ModifyHunt use this so a hunt looks the same no matter how it
+// was started.
+func startHunt(hunt *api_proto.Hunt) error {
+	// The hunt has been expired.
+	if hunt.Stats != nil && hunt.Stats.Stopped {
+		return errors.New("Can not start a stopped hunt.")
+	}
+
+	hunt.State = api_proto.Hunt_RUNNING
+	hunt.StartTime = HuntTimeNow()
+
+	// A scheduled hunt first runs at the next scheduled time.
+	if hunt.Schedule != "" {
+		next, err := nextScheduledRun(hunt.Schedule, time.Now())
+		if err != nil {
+			return err
+		}
+		hunt.NextScheduledRun = next
+	}
+
+	return nil
+}
+
+// Emit a row to System.Hunt.StateChange for each change in a hunt's
+// state. The change has already been made by the time this is called
+// so failing to emit the row is only logged. Reason is set when the
+// hunt was stopped automatically (e.g. it expired).
+func EmitHuntStateChange(
+	config_obj *config_proto.Config,
+	hunt_id string, old_state, new_state api_proto.Hunt_State,
+	user, reason string) {
+
+	row := ordereddict.NewDict().
+		Set("Timestamp", HuntTimeNow()).
+		Set("HuntId", hunt_id).
+		Set("OldState", old_state.String()).
+		Set("NewState", new_state.String()).
+		Set("User", user).
+		Set("Reason", reason)
+
+	journal, err := services.GetJournal()
+	if err == nil {
+		err = journal.PushRowsToArtifact(config_obj,
+			[]*ordereddict.Dict{row}, "System.Hunt.StateChange",
+			"server", hunt_id)
+	}
+
+	if err != nil {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("EmitHuntStateChange %v: %v", hunt_id, err)
+	}
+}
+
+// Emit a row to System.Hunt.Archive once the archived hunt is
+// saved. Like EmitHuntStateChange the hunt is already archived so
+// failing to emit the row is only logged.
+func emitHuntArchive(
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt, user string) {
+
+	row := ordereddict.NewDict().
+		Set("Timestamp", HuntTimeNow()).
+		Set("Hunt", hunt).
+		Set("User", user)
+
+	journal, err := services.GetJournal()
 	if err == nil {
-		artifact_obj, pres := repository.Get(config_obj, artifact)
-		if pres {
-			for _, source := range artifact_obj.Sources {
-				result = append(result, source.Name)
+		err = journal.PushRowsToArtifact(config_obj,
+			[]*ordereddict.Dict{row}, "System.Hunt.Archive",
+			"server", hunt.HuntId)
+	}
+
+	if err != nil {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("emitHuntArchive %v: %v", hunt.HuntId, err)
+	}
+}
+
+// Check that a hunt with a condition will run on at least one of the
+// currently known clients. If no clients match we refuse to start
+// the hunt unless forced, in which case the returned warning should
+// be recorded on the hunt.
+func checkHuntMatchesClients(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt, force bool) (string, error) {
+	if !huntHasCondition(hunt) {
+		return "", nil
+	}
+
+	// If we can not estimate the hunt we do not stand in the way.
+	count, err := EstimateHunt(ctx, config_obj, hunt)
+	if err != nil || count > 0 {
+		return "", nil
+	}
+
+	warning := "Hunt condition does not match any known clients."
+	if !force {
+		return "", errors.New(warning + " Use force to start it anyway.")
+	}
+
+	return warning, nil
+}
+
+func huntHasCondition(hunt *api_proto.Hunt) bool {
+	if hunt.Condition == nil {
+		return false
+	}
+
+	if hunt.Condition.ExcludedLabels != nil &&
+		len(hunt.Condition.ExcludedLabels.Label) > 0 {
+		return true
+	}
+
+	labels := hunt.Condition.GetLabels()
+	if labels != nil && len(labels.Label) > 0 {
+		return true
+	}
+
+	if hunt.Condition.MinClientVersion != "" ||
+		hunt.Condition.MaxClientVersion != "" ||
+		hunt.Condition.ConditionVql != "" {
+		return true
+	}
+
+	os_condition := hunt.Condition.GetOs()
+	return os_condition != nil && os_condition.Os != api_proto.HuntOsCondition_ALL
+}
+
+// Estimate how many of the currently known clients match the hunt's
+// condition. Evaluating the condition VQL on a large fleet may take
+// a while so the estimate fails if it takes too long.
+func EstimateHunt(ctx context.Context,
+	config_obj *config_proto.Config, hunt *api_proto.Hunt) (uint64, error) {
+	sub_ctx, cancel := context.WithTimeout(ctx, hunt_estimate_timeout)
+	defer cancel()
+
+	return matchHuntClients(sub_ctx, config_obj, hunt, func(client_id string) {})
+}
+
+// Call cb with each of the currently known clients which match the
+// hunt's condition and return how many there were. Fails if the
+// context is done before all clients are checked.
+func matchHuntClients(ctx context.Context,
+	config_obj *config_proto.Config, hunt *api_proto.Hunt,
+	cb func(client_id string)) (uint64, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return 0, err
+	}
+
+	labeler := services.GetLabeler()
+	client_info_manager := services.GetClientInfoManager()
+	if labeler == nil || client_info_manager == nil {
+		return 0, errors.New("Client services not ready")
+	}
+
+	// When the hunt is restricted to labels only clients carrying
+	// those labels are candidates.
+	queries := []string{"all"}
+	labels := hunt.Condition.GetLabels()
+	if labels != nil && len(labels.Label) > 0 {
+		queries = nil
+		for _, label := range labels.Label {
+			queries = append(queries, "label:"+label)
+		}
+	}
+
+	var excluded_labels []string
+	if hunt.Condition != nil && hunt.Condition.ExcludedLabels != nil {
+		excluded_labels = hunt.Condition.ExcludedLabels.Label
+	}
+
+	seen := make(map[string]bool)
+	count := uint64(0)
+	for _, query := range queries {
+		for _, client_id := range db.SearchClients(
+			config_obj, constants.CLIENT_INDEX_URN,
+			query, "", 0, 1000000, datastore.UNSORTED) {
+			if seen[client_id] || !strings.HasPrefix(client_id, "C.") {
+				continue
+			}
+			seen[client_id] = true
+
+			if ctx.Err() != nil {
+				return count, errors.Wrap(ctx.Err(), "Matching hunt clients")
+			}
+
+			if !clientMatchesOS(client_info_manager, hunt, client_id) {
+				continue
+			}
+
+			client_info, err := client_info_manager.Get(client_id)
+			if err != nil {
+				client_info = &services.ClientInfo{}
+			}
+			if !ClientMatchesVersion(hunt, client_info) {
+				continue
+			}
+
+			if !ClientMatchesConditionVQL(ctx, config_obj, hunt, client_id) {
+				continue
+			}
+
+			excluded := false
+			for _, label := range excluded_labels {
+				if labeler.IsLabelSet(config_obj, client_id, label) {
+					excluded = true
+					break
+				}
+			}
+
+			if !excluded {
+				cb(client_id)
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
+func clientMatchesOS(client_info_manager services.ClientInfoManager,
+	hunt *api_proto.Hunt, client_id string) bool {
+	os_condition := hunt.Condition.GetOs()
+	if os_condition == nil {
+		return true
+	}
+
+	client_info, err := client_info_manager.Get(client_id)
+	if err != nil {
+		return false
+	}
+
+	switch os_condition.Os {
+	case api_proto.HuntOsCondition_WINDOWS:
+		return client_info.OS == services.Windows
+	case api_proto.HuntOsCondition_LINUX:
+		return client_info.OS == services.Linux
+	case api_proto.HuntOsCondition_OSX:
+		return client_info.OS == services.MacOS
+	}
+
+	return true
+}
+
+// Is the client's version within the hunt's minimum and maximum
+// client versions? Clients which did not report a valid version are
+// matched according to the hunt's unknown version policy.
+func ClientMatchesVersion(
+	hunt *api_proto.Hunt, client_info *services.ClientInfo) bool {
+	if hunt.Condition == nil || (hunt.Condition.MinClientVersion == "" &&
+		hunt.Condition.MaxClientVersion == "") {
+		return true
+	}
+
+	if !utils.IsValidVersion(client_info.Version) {
+		return hunt.Condition.UnknownVersionPolicy ==
+			api_proto.HuntCondition_MATCH_UNKNOWN_VERSION
+	}
+
+	if hunt.Condition.MinClientVersion != "" && utils.CompareVersions(
+		client_info.Version, hunt.Condition.MinClientVersion) < 0 {
+		return false
+	}
+
+	return hunt.Condition.MaxClientVersion == "" || utils.CompareVersions(
+		client_info.Version, hunt.Condition.MaxClientVersion) <= 0
+}
+
+// Notify the clients that may be interested in the hunt. When the
+// hunt is restricted to some labels we only need to wake up the
+// clients carrying those labels, otherwise all clients are
+// notified. New hunts are not that common so notifying all the
+// clients at once is probably ok.
+func notifyHuntClients(
+	config_obj *config_proto.Config, hunt *api_proto.Hunt) error {
+	// Scheduled hunts are never sent to clients - only their runs
+	// are.
+	if hunt.Schedule != "" {
+		return nil
+	}
+
+	notifier := services.GetNotifier()
+	if notifier == nil {
+		return errors.New("Notifier not ready")
+	}
+
+	// Waking the whole fleet at once causes a spike of load on the
+	// frontends so clients are notified in batches.
+	options := huntNotificationBatchOptions(config_obj)
+
+	labels := hunt.Condition.GetLabels()
+	if labels == nil || len(labels.Label) == 0 {
+		return notifier.NotifyByRegexInBatches(config_obj, "^[Cc]\\.", options)
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	client_ids := []string{}
+	for _, label := range labels.Label {
+		for _, client_id := range db.SearchClients(
+			config_obj, constants.CLIENT_INDEX_URN,
+			"label:"+label, "", 0, 1000000, datastore.UNSORTED) {
+			if !seen[client_id] {
+				seen[client_id] = true
+				client_ids = append(client_ids, client_id)
+			}
+		}
+	}
+
+	return notifier.NotifyListenersInBatches(config_obj, client_ids, options)
+}
+
+func huntNotificationBatchOptions(
+	config_obj *config_proto.Config) services.NotificationBatchOptions {
+	result := services.NotificationBatchOptions{
+		BatchSize: 1000,
+		Delay:     100 * time.Millisecond,
+	}
+
+	if config_obj.Defaults != nil {
+		if config_obj.Defaults.HuntNotificationBatchSize > 0 {
+			result.BatchSize = int(config_obj.Defaults.HuntNotificationBatchSize)
+		}
+		if config_obj.Defaults.HuntNotificationBatchDelayMs > 0 {
+			result.Delay = time.Duration(
+				config_obj.Defaults.HuntNotificationBatchDelayMs) * time.Millisecond
+		}
+	}
+
+	return result
+}
+
+// Notify the hunt's clients again, e.g. to speed up clients which
+// missed the original notification. The hunt itself is not changed.
+func RenotifyHunt(config_obj *config_proto.Config, hunt_id string) error {
+	hunt_obj, err := GetHunt(config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	if err != nil {
+		return err
+	}
+
+	if hunt_obj.State != api_proto.Hunt_RUNNING ||
+		(hunt_obj.Stats != nil && hunt_obj.Stats.Stopped) {
+		return fmt.Errorf("Hunt %v is not running (%v) - clients not notified.",
+			hunt_id, hunt_obj.State)
+	}
+
+	return notifyHuntClients(config_obj, hunt_obj)
+}
+
+// Flows scheduled by RunHuntOnClient are created by this prefix and
+// the hunt id. They must not look like the hunt's own flows, or their
+// completion would be counted in the hunt's stats.
+const hunt_test_creator_prefix = "HuntTest:"
+
+// Run the hunt's compiled request as a one-off flow on a single
+// client, so the exact VQL the hunt will send can be checked before it
+// runs across the fleet. The hunt's condition is not checked and the
+// flow is not part of the hunt: it is not recorded in the hunt's flow
+// index and does not count towards its stats or client limit.
+//
+// The flow runs as the caller, who must hold the permissions the
+// hunt's artifacts require, just as if they created the hunt.
+func RunHuntOnClient(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	acl_manager vql_subsystem.ACLManager,
+	hunt_id, client_id string) (string, error) {
+
+	if client_id == "" {
+		return "", errors.New("Client id not provided.")
+	}
+
+	hunt_obj, err := GetHunt(config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	if err != nil {
+		return "", err
+	}
+
+	if hunt_obj.StartRequest == nil ||
+		len(hunt_obj.StartRequest.CompiledCollectorArgs) == 0 {
+		return "", fmt.Errorf("Hunt %v has no compiled request", hunt_id)
+	}
+
+	request := proto.Clone(
+		hunt_obj.StartRequest).(*flows_proto.ArtifactCollectorArgs)
+	request.ClientId = client_id
+	request.Creator = hunt_test_creator_prefix + hunt_id
+
+	// The client gets the same parameters it would get from the
+	// hunt itself.
+	if len(hunt_obj.LabelParameters) > 0 {
+		labeler := services.GetLabeler()
+		compiled, labels := ComposeHuntCollectorArgs(config_obj, hunt_obj,
+			func(label string) bool {
+				return labeler.IsLabelSet(config_obj, client_id, label)
+			})
+		if len(labels) > 0 {
+			request.CompiledCollectorArgs = compiled
+		}
+	}
+
+	manager, err := services.GetRepositoryManager()
+	if err != nil {
+		return "", err
+	}
+
+	repository, err := manager.GetGlobalRepository(config_obj)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkHuntArtifactAccess(config_obj, acl_manager, repository, hunt_obj)
+	if err != nil {
+		return "", err
+	}
+
+	launcher, err := services.GetLauncher()
+	if err != nil {
+		return "", err
+	}
+
+	flow_id, err := launcher.ScheduleArtifactCollection(
+		ctx, config_obj, acl_manager, repository, request)
+	if err != nil {
+		return "", err
+	}
+
+	notifier := services.GetNotifier()
+	if notifier != nil {
+		err = notifier.NotifyListener(config_obj, client_id)
+		if err != nil {
+			return flow_id, err
+		}
+	}
+
+	return flow_id, nil
+}
+
+func ListHunts(config_obj *config_proto.Config, in *api_proto.ListHuntsRequest) (
+	*api_proto.ListHuntsResponse, error) {
+
+	dispatcher, err := services.RequireHuntDispatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api_proto.ListHuntsResponse{}
+	search := strings.ToLower(in.Search)
+
+	err = dispatcher.ApplyFuncOnHunts(
+		func(hunt *api_proto.Hunt) error {
+			if search != "" && !huntMatchesSearch(hunt, search) {
+				return nil
+			}
+
+			if !huntMatchesFilters(hunt, in) {
+				return nil
+			}
+
+			if in.CampaignId != "" && hunt.CampaignId != in.CampaignId {
+				return nil
+			}
+
+			if (in.CreateTimeAfter > 0 && hunt.CreateTime < in.CreateTimeAfter) ||
+				(in.CreateTimeBefore > 0 && hunt.CreateTime > in.CreateTimeBefore) {
+				return nil
 			}
-		}
-	}
-	return result
-}
 
-func CreateHunt(
-	ctx context.Context,
-	config_obj *config_proto.Config,
-	acl_manager vql_subsystem.ACLManager,
-	hunt *api_proto.Hunt) (string, error) {
-	db, err := datastore.GetDB(config_obj)
+			if in.IncludeArchived || in.State == api_proto.Hunt_ARCHIVED ||
+				hunt.State != api_proto.Hunt_ARCHIVED {
+
+				// FIXME: Backwards compatibility.
+				hunt.HuntId = path.Base(hunt.HuntId)
+
+				result.Items = append(result.Items, hunt)
+			}
+			return nil
+		})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if hunt.Stats == nil {
-		hunt.Stats = &api_proto.HuntStats{}
-	}
+	// Sort before paging so successive pages are consistent.
+	sort.Slice(result.Items, func(i, j int) bool {
+		return result.Items[i].CreateTime > result.Items[j].CreateTime
+	})
 
-	if hunt.HuntId == "" {
-		hunt.HuntId = GetNewHuntId()
+	// Group before the scheduled runs are folded into their
+	// scheduled hunts so the runs are counted too.
+	if in.GroupByTag {
+		result.TagGroups = groupHuntsByTag(result.Items)
 	}
 
-	if hunt.StartRequest == nil || hunt.StartRequest.Artifacts == nil {
-		return "", errors.New("No artifacts to collect.")
+	if in.GroupScheduledRuns {
+		result.Items = groupScheduledRuns(result.Items)
 	}
 
-	hunt.CreateTime = uint64(time.Now().UTC().UnixNano() / 1000)
-	if hunt.Expires == 0 {
-		hunt.Expires = uint64(time.Now().Add(7*24*time.Hour).
-			UTC().UnixNano() / 1000)
+	if in.Offset >= uint64(len(result.Items)) {
+		result.Items = nil
+		return result, nil
 	}
 
-	if hunt.Expires < hunt.CreateTime {
-		return "", errors.New("Hunt expiry is in the past!")
+	end := in.Offset + in.Count
+	if end > uint64(len(result.Items)) {
+		end = uint64(len(result.Items))
 	}
+	result.Items = result.Items[in.Offset:end]
 
-	manager, err := services.GetRepositoryManager()
-	if err != nil {
-		return "", err
+	return result, nil
+}
+
+// Move the runs of scheduled hunts into their scheduled hunt's
+// ScheduledRuns, keeping the order of items (newest first). Runs
+// whose scheduled hunt is not in items (e.g. it was filtered out or
+// deleted) stay as separate items. The scheduled hunts are copied as
+// items point at the dispatcher's hunts.
+func groupScheduledRuns(items []*api_proto.Hunt) []*api_proto.Hunt {
+	parents := make(map[string]*api_proto.Hunt)
+	for idx, hunt := range items {
+		if hunt.Schedule != "" {
+			parent := proto.Clone(hunt).(*api_proto.Hunt)
+			parent.ScheduledRuns = nil
+			parents[hunt.HuntId] = parent
+			items[idx] = parent
+		}
 	}
 
-	repository, err := manager.GetGlobalRepository(config_obj)
-	if err != nil {
-		return "", err
+	result := make([]*api_proto.Hunt, 0, len(items))
+	for _, hunt := range items {
+		parent, pres := parents[hunt.ScheduledBy]
+		if !pres || hunt.ScheduledBy == "" {
+			result = append(result, hunt)
+			continue
+		}
+
+		run := &api_proto.HuntScheduledRun{
+			HuntId:     hunt.HuntId,
+			CreateTime: hunt.CreateTime,
+			State:      hunt.State,
+		}
+		if hunt.Stats != nil {
+			run.TotalClientsScheduled = hunt.Stats.TotalClientsScheduled
+			run.TotalClientsWithResults = hunt.Stats.TotalClientsWithResults
+			run.TotalClientsWithErrors = hunt.Stats.TotalClientsWithErrors
+		}
+		parent.ScheduledRuns = append(parent.ScheduledRuns, run)
 	}
 
-	// Compile the start request and store it in the hunt. We will
-	// use this compiled version to launch all other flows from
-	// this hunt rather than re-compile the artifact each
-	// time. This ensures that if the artifact definition is
-	// changed after this point, the hunt will continue to
-	// schedule consistent VQL on the clients.
-	launcher, err := services.GetLauncher()
+	return result
+}
+
+// Find the hunts which collect the artifact, e.g. to see which hunts
+// depend on an artifact before changing it. With include_sources the
+// name may also be a single source (Artifact/Source). Archived hunts
+// are included. The hunts returned are copies, newest first.
+func FindHuntsByArtifact(
+	config_obj *config_proto.Config,
+	artifact_name string, include_sources bool) ([]*api_proto.Hunt, error) {
+
+	// Copy the hunts so we do not look up the artifact sources
+	// under the dispatcher lock.
+	dispatcher, err := services.RequireHuntDispatcher()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	compiled, err := launcher.CompileCollectorArgs(
-		ctx, config_obj, acl_manager, repository,
-		true, /* should_obfuscate */
-		hunt.StartRequest)
+	hunts := []*api_proto.Hunt{}
+	err = dispatcher.ApplyFuncOnHunts(
+		func(hunt *api_proto.Hunt) error {
+			hunts = append(hunts, proto.Clone(hunt).(*api_proto.Hunt))
+			return nil
+		})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	hunt.StartRequest.CompiledCollectorArgs = append(
-		hunt.StartRequest.CompiledCollectorArgs, compiled...)
-
-	// We allow our caller to determine if hunts are created in
-	// the running state or the paused state.
-	if hunt.State == api_proto.Hunt_UNSET {
-		hunt.State = api_proto.Hunt_PAUSED
 
-		// IF we are creating the hunt in the running state
-		// set it started.
-	} else if hunt.State == api_proto.Hunt_RUNNING {
-		hunt.StartTime = hunt.CreateTime
+	result := []*api_proto.Hunt{}
+	for _, hunt := range hunts {
+		FindCollectedArtifacts(config_obj, hunt)
 
-		// Notify all the clients.
-		notifier := services.GetNotifier()
-		if notifier != nil {
-			err = notifier.NotifyByRegex(config_obj, "^[Cc]\\.")
-			if err != nil {
-				return "", err
-			}
+		if utils.InString(hunt.Artifacts, artifact_name) ||
+			(include_sources &&
+				utils.InString(hunt.ArtifactSources, artifact_name)) {
+			result = append(result, hunt)
 		}
 	}
 
-	hunt_path_manager := paths.NewHuntPathManager(hunt.HuntId)
-	err = db.SetSubject(config_obj, hunt_path_manager.Path(), hunt)
-	if err != nil {
-		return "", err
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreateTime > result[j].CreateTime
+	})
+
+	return result, nil
+}
+
+// Does the hunt's description, id or artifacts contain the lower
+// cased search string?
+func huntMatchesSearch(hunt *api_proto.Hunt, search string) bool {
+	if strings.Contains(strings.ToLower(hunt.HuntDescription), search) ||
+		strings.Contains(strings.ToLower(hunt.HuntId), search) {
+		return true
 	}
 
-	// Trigger a refresh of the hunt dispatcher. This guarantees
-	// that fresh data will be read in subsequent ListHunt()
-	// calls.
-	err = services.GetHuntDispatcher().Refresh(config_obj)
+	for _, artifact := range huntArtifacts(hunt) {
+		if strings.Contains(strings.ToLower(artifact), search) {
+			return true
+		}
+	}
 
-	return hunt.HuntId, err
+	return false
 }
 
-func ListHunts(config_obj *config_proto.Config, in *api_proto.ListHuntsRequest) (
-	*api_proto.ListHuntsResponse, error) {
+// Check the hunt against the request's creator, state, artifact,
+// label and tag filters. Unset filters match all hunts.
+func huntMatchesFilters(hunt *api_proto.Hunt, in *api_proto.ListHuntsRequest) bool {
+	if in.Creator != "" && hunt.Creator != in.Creator {
+		return false
+	}
 
-	result := &api_proto.ListHuntsResponse{}
+	if in.State != api_proto.Hunt_UNSET && hunt.State != in.State {
+		return false
+	}
 
-	err := services.GetHuntDispatcher().ApplyFuncOnHunts(
-		func(hunt *api_proto.Hunt) error {
-			if uint64(len(result.Items)) < in.Offset {
-				return nil
-			}
+	if in.Artifact != "" && !containsFold(huntArtifacts(hunt), in.Artifact) {
+		return false
+	}
 
-			if uint64(len(result.Items)) >= in.Offset+in.Count {
-				return constants.STOP_ITERATION
-			}
+	if in.Label != "" &&
+		!containsFold(hunt.Condition.GetLabels().GetLabel(), in.Label) {
+		return false
+	}
 
-			if in.IncludeArchived || hunt.State != api_proto.Hunt_ARCHIVED {
+	if in.Tag != "" && !containsFold(hunt.Tags, in.Tag) {
+		return false
+	}
 
-				// FIXME: Backwards compatibility.
-				hunt.HuntId = path.Base(hunt.HuntId)
+	return true
+}
 
-				result.Items = append(result.Items, hunt)
+// The artifacts the hunt collects. Older hunts only recorded them in
+// their start request.
+func huntArtifacts(hunt *api_proto.Hunt) []string {
+	if len(hunt.Artifacts) == 0 && hunt.StartRequest != nil {
+		return hunt.StartRequest.Artifacts
+	}
+	return hunt.Artifacts
+}
+
+// Group the hunts' ids by their tags. Tags differing only in case
+// are grouped together under the first spelling seen.
+func groupHuntsByTag(items []*api_proto.Hunt) []*api_proto.HuntTagGroup {
+	groups := make(map[string]*api_proto.HuntTagGroup)
+	for _, hunt := range items {
+		for _, tag := range hunt.Tags {
+			key := strings.ToLower(tag)
+			group, pres := groups[key]
+			if !pres {
+				group = &api_proto.HuntTagGroup{Tag: tag}
+				groups[key] = group
 			}
-			return nil
-		})
+			group.HuntIds = append(group.HuntIds, hunt.HuntId)
+		}
+	}
 
-	if err == constants.STOP_ITERATION {
-		err = nil
+	result := make([]*api_proto.HuntTagGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, group)
 	}
 
-	sort.Slice(result.Items, func(i, j int) bool {
-		return result.Items[i].CreateTime > result.Items[j].CreateTime
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Tag) < strings.ToLower(result[j].Tag)
 	})
 
-	return result, err
+	return result
+}
+
+// Trim the tags and drop empty and repeated ones (ignoring case) so
+// tags compare the same way they are filtered.
+func normalizeHuntTags(tags []string) []string {
+	var result []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !containsFold(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func containsFold(items []string, item string) bool {
+	for _, i := range items {
+		if strings.EqualFold(i, item) {
+			return true
+		}
+	}
+	return false
 }
 
 func GetHunt(config_obj *config_proto.Config, in *api_proto.GetHuntRequest) (
 	hunt *api_proto.Hunt, err error) {
 
+	dispatcher, err := services.RequireHuntDispatcher()
+	if err != nil {
+		return nil, err
+	}
+
 	var result *api_proto.Hunt
 
-	err = services.GetHuntDispatcher().ModifyHunt(
+	err = dispatcher.ModifyHunt(
 		in.HuntId,
 		func(hunt_obj *api_proto.Hunt) error {
+			// Only copy what is needed to poll the hunt.
+			if in.StatsOnly {
+				result = &api_proto.Hunt{
+					HuntId: hunt_obj.HuntId,
+					State:  hunt_obj.State,
+				}
+				if hunt_obj.Stats != nil {
+					result.Stats = proto.Clone(
+						hunt_obj.Stats).(*api_proto.HuntStats)
+					result.Stats.EstimatedCompletion = estimateHuntCompletion(
+						hunt_obj, HuntTimeNow())
+				}
+				return nil
+			}
+
 			// Make a copy
 			result = proto.Clone(hunt_obj).(*api_proto.Hunt)
 
@@ -245,6 +1510,15 @@ func GetHunt(config_obj *config_proto.Config, in *api_proto.GetHuntRequest) (
 			// the number of possible flows significantly.
 			FindCollectedArtifacts(config_obj, result)
 
+			result.CreateTimeString = HuntTimeToString(result.CreateTime)
+			result.StartTimeString = HuntTimeToString(result.StartTime)
+			result.ExpiresString = HuntTimeToString(result.Expires)
+
+			if result.Stats != nil {
+				result.Stats.EstimatedCompletion = estimateHuntCompletion(
+					hunt_obj, HuntTimeNow())
+			}
+
 			return nil
 		})
 
@@ -252,11 +1526,55 @@ func GetHunt(config_obj *config_proto.Config, in *api_proto.GetHuntRequest) (
 		return result, errors.New("Not found")
 	}
 
+	if in.StatsOnly {
+		return result, err
+	}
+
 	result.Stats.AvailableDownloads, _ = availableHuntDownloadFiles(config_obj, in.HuntId)
 
+	// The result is a copy so the repository is checked without
+	// holding the dispatcher lock.
+	checkHuntDefinitions(config_obj, result)
+
 	return result, err
 }
 
+// Estimate when the clients scheduled so far will have completed,
+// assuming they keep completing at the average rate since the hunt
+// started. Returns 0 when there is nothing to base an estimate on:
+// the hunt is not running, no client has completed yet or all the
+// scheduled clients have already completed.
+func estimateHuntCompletion(hunt *api_proto.Hunt, now uint64) uint64 {
+	if hunt.State != api_proto.Hunt_RUNNING || hunt.Stats == nil ||
+		hunt.Stats.Stopped || hunt.StartTime == 0 || now <= hunt.StartTime {
+		return 0
+	}
+
+	completed := hunt.Stats.TotalClientsWithResults +
+		hunt.Stats.TotalClientsWithErrors
+	if completed == 0 || completed >= hunt.Stats.TotalClientsScheduled {
+		return 0
+	}
+
+	return EstimateCompletionTime(hunt.StartTime, now, completed,
+		hunt.Stats.TotalClientsScheduled-completed)
+}
+
+// When the remaining clients will have completed if they complete at
+// the same rate completed clients did since start. All times are in
+// microseconds. Returns 0 if there is no estimate.
+func EstimateCompletionTime(
+	start, now, completed, remaining uint64) uint64 {
+	if start == 0 || now <= start || completed == 0 || remaining == 0 {
+		return 0
+	}
+
+	elapsed := now - start
+
+	// Work in floating point so large hunts do not overflow.
+	return now + uint64(float64(elapsed)/float64(completed)*float64(remaining))
+}
+
 // availableHuntDownloadFiles returns the prepared zip downloads available to
 // be fetched by the user at this moment.
 func availableHuntDownloadFiles(config_obj *config_proto.Config,
@@ -266,7 +1584,16 @@ func availableHuntDownloadFiles(config_obj *config_proto.Config,
 	download_file := hunt_path_manager.GetHuntDownloadsFile(false, "")
 	download_path := path.Dir(download_file)
 
-	return getAvailableDownloadFiles(config_obj, download_path)
+	result, err := getAvailableDownloadFiles(config_obj, download_path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range result.Files {
+		item.Type = paths.HuntDownloadType(item.Name)
+	}
+
+	return result, nil
 }
 
 // This method modifies the hunt. Only the following modifications are allowed:
@@ -275,6 +1602,9 @@ func availableHuntDownloadFiles(config_obj *config_proto.Config,
 //    will update the StartTime.
 // 2. A hunt in the running state can go to the Stop state
 // 3. A hunt's description can be modified.
+// 4. A hunt's expiry can be changed to a time in the future.
+// 5. A hunt's tags can be replaced or cleared.
+// 6. Clients can be excluded from (or included in) a running hunt.
 
 // It is not possible to restart a stopped hunt. This is because the
 // hunt manager watches the hunt participation events for all hunts at
@@ -286,6 +1616,33 @@ func ModifyHunt(
 	config_obj *config_proto.Config,
 	hunt_modification *api_proto.Hunt,
 	user string) error {
+	var modified_hunt *api_proto.Hunt
+
+	// Refuse to start a hunt that will not run anywhere unless
+	// forced. We check before taking the dispatcher lock since
+	// estimating the hunt may take a while.
+	start_warning := ""
+	if hunt_modification.State == api_proto.Hunt_RUNNING {
+		hunt_obj, err := GetHunt(config_obj,
+			&api_proto.GetHuntRequest{HuntId: hunt_modification.HuntId})
+		if err != nil {
+			return err
+		}
+
+		err = reserveRunningHunt(config_obj, hunt_obj)
+		if err != nil {
+			return err
+		}
+		defer releaseRunningHunt(hunt_obj.HuntId)
+
+		start_warning, err = checkHuntMatchesClients(
+			ctx, config_obj, hunt_obj, hunt_modification.Force)
+		if err != nil {
+			return err
+		}
+	}
+
+	old_state := api_proto.Hunt_UNSET
 	dispatcher := services.GetHuntDispatcher()
 	err := dispatcher.ModifyHunt(
 		hunt_modification.HuntId,
@@ -293,74 +1650,252 @@ func ModifyHunt(
 			if hunt.Stats == nil {
 				return errors.New("Invalid hunt")
 			}
+			old_state = hunt.State
 
-			// Is the description changed?
-			if hunt_modification.HuntDescription != "" {
-				hunt.HuntDescription = hunt_modification.HuntDescription
+			// Apply the changes to a copy so a modification
+			// which fails part way leaves the hunt unchanged.
+			updated := proto.Clone(hunt).(*api_proto.Hunt)
+			err := applyHuntModification(config_obj, updated,
+				hunt_modification, user, start_warning)
+			if err != nil {
+				return err
+			}
 
-				// Archive the hunt.
-			} else if hunt_modification.State == api_proto.Hunt_ARCHIVED {
-				hunt.State = api_proto.Hunt_ARCHIVED
+			// Write the new hunt object to the datastore.
+			db, err := datastore.GetDB(config_obj)
+			if err != nil {
+				return err
+			}
 
-				row := ordereddict.NewDict().
-					Set("Timestamp", time.Now().UTC().Unix()).
-					Set("Hunt", hunt).
-					Set("User", user)
+			hunt_path_manager := paths.NewHuntPathManager(hunt.HuntId)
+			err = db.SetSubject(
+				config_obj, hunt_path_manager.Path(), updated)
+			if err != nil {
+				return err
+			}
 
-				journal, err := services.GetJournal()
-				if err != nil {
-					return err
-				}
+			hunt.Reset()
+			proto.Merge(hunt, updated)
 
-				err = journal.PushRowsToArtifact(config_obj,
-					[]*ordereddict.Dict{row}, "System.Hunt.Archive",
-					"server", hunt_modification.HuntId)
-				if err != nil {
-					return err
-				}
+			modified_hunt = updated
+			return nil
+		})
 
-				// We are trying to start the hunt.
-			} else if hunt_modification.State == api_proto.Hunt_RUNNING {
+	if err != nil {
+		return err
+	}
 
-				// The hunt has been expired.
-				if hunt.Stats.Stopped {
-					return errors.New("Can not start a stopped hunt.")
-				}
+	if modified_hunt.State != old_state {
+		EmitHuntStateChange(config_obj, modified_hunt.HuntId,
+			old_state, modified_hunt.State, user, "")
+	}
 
-				hunt.State = api_proto.Hunt_RUNNING
-				hunt.StartTime = uint64(time.Now().UnixNano() / 1000)
+	action := huntApprovalAction(old_state, modified_hunt, user)
+	if action != "" {
+		emitHuntApproval(config_obj, modified_hunt, action, user)
+	}
 
-				// We are trying to pause or stop the hunt.
-			} else {
-				hunt.State = api_proto.Hunt_STOPPED
-			}
+	if hunt_modification.State == api_proto.Hunt_ARCHIVED {
+		emitHuntArchive(config_obj, modified_hunt, user)
+	}
 
-			// Write the new hunt object to the datastore.
-			db, err := datastore.GetDB(config_obj)
+	// Taking the snapshot may take a while so we do it after
+	// releasing the dispatcher lock.
+	if modified_hunt.SnapshotClients &&
+		modified_hunt.State == api_proto.Hunt_RUNNING &&
+		old_state != api_proto.Hunt_RUNNING {
+		recordHuntClientSnapshot(ctx, config_obj, modified_hunt)
+	}
+
+	// Optionally stop the flows the hunt already scheduled too.
+	if hunt_modification.CancelFlows &&
+		modified_hunt.State == api_proto.Hunt_STOPPED &&
+		old_state != api_proto.Hunt_STOPPED {
+		// The hunt is already stopped so failing to cancel
+		// its flows is only logged.
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		cancellation, err := CancelHuntFlows(
+			ctx, config_obj, modified_hunt.HuntId, user)
+		if err != nil {
+			logger.Error("ModifyHunt: cancelling the flows of hunt %v: %v",
+				modified_hunt.HuntId, err)
+		} else {
+			logger.Info("ModifyHunt: cancelled %v flows of hunt %v (%v clients offline)",
+				cancellation.Cancelled, modified_hunt.HuntId, cancellation.Offline)
+		}
+	}
+
+	// Stop the flows already running on the excluded clients.
+	if len(hunt_modification.ExcludedClients) > 0 {
+		// The clients are already excluded so failing to
+		// cancel their flows is only logged.
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		cancellation, err := CancelHuntClientFlows(ctx, config_obj,
+			modified_hunt.HuntId, user, hunt_modification.ExcludedClients)
+		if err != nil {
+			logger.Error("ModifyHunt: cancelling the flows of clients excluded from hunt %v: %v",
+				modified_hunt.HuntId, err)
+		} else {
+			logger.Info("ModifyHunt: excluded %v clients from hunt %v, cancelled %v flows",
+				len(hunt_modification.ExcludedClients), modified_hunt.HuntId,
+				cancellation.Cancelled)
+		}
+	}
+
+	// Notify the clients about the modified hunt.
+	return notifyHuntClients(config_obj, modified_hunt)
+}
+
+// Apply each of the changes set in the modification to the hunt, so
+// for example a hunt may be renamed and started at once. A
+// modification which sets no state stops the hunt unless it changes
+// something else.
+func applyHuntModification(
+	config_obj *config_proto.Config,
+	hunt, hunt_modification *api_proto.Hunt,
+	user, start_warning string) error {
+
+	if user != "" {
+		hunt.LastModifiedBy = user
+	}
+
+	changed := false
+
+	// Is the description changed?
+	if hunt_modification.HuntDescription != "" {
+		hunt.HuntDescription = hunt_modification.HuntDescription
+		changed = true
+	}
+
+	// Is the expiry changed?
+	if hunt_modification.Expires != 0 {
+		if hunt_modification.Expires < HuntTimeNow() {
+			return errors.New("Hunt expiry is in the past!")
+		}
+		err := checkHuntExpiry(config_obj, hunt.CreateTime,
+			hunt_modification.Expires)
+		if err != nil {
+			return err
+		}
+		hunt.Expires = hunt_modification.Expires
+		changed = true
+	}
+
+	// Are the tags changed? New tags replace the old ones.
+	if hunt_modification.ClearTags || len(hunt_modification.Tags) > 0 {
+		hunt.Tags = normalizeHuntTags(hunt_modification.Tags)
+		changed = true
+	}
+
+	// Are clients excluded or included again?
+	if len(hunt_modification.ExcludedClients) > 0 ||
+		len(hunt_modification.IncludedClients) > 0 {
+		hunt.ExcludedClients = updateExcludedClients(hunt.ExcludedClients,
+			hunt_modification.ExcludedClients,
+			hunt_modification.IncludedClients)
+		changed = true
+	}
+
+	switch hunt_modification.State {
+
+	// Archive the hunt.
+	case api_proto.Hunt_ARCHIVED:
+		if hunt.State == api_proto.Hunt_PENDING_APPROVAL {
+			err := checkHuntApprover(config_obj, hunt, user, false)
 			if err != nil {
 				return err
 			}
+		}
+		hunt.State = api_proto.Hunt_ARCHIVED
 
-			hunt_path_manager := paths.NewHuntPathManager(hunt.HuntId)
-			err = db.SetSubject(
-				config_obj, hunt_path_manager.Path(), hunt)
+	// We are trying to start the hunt.
+	case api_proto.Hunt_RUNNING, api_proto.Hunt_PENDING_APPROVAL:
+		// Approve the pending hunt.
+		if hunt.State == api_proto.Hunt_PENDING_APPROVAL {
+			err := checkHuntApprover(config_obj, hunt, user, true)
 			if err != nil {
 				return err
 			}
+			hunt.ApprovedBy = user
 
-			return nil
-		})
+		} else if hunt.State != api_proto.Hunt_RUNNING &&
+			(HuntApprovalRequired(config_obj) ||
+				hunt_modification.State == api_proto.Hunt_PENDING_APPROVAL) {
+			return requestHuntApproval(hunt, user)
+
+		} else if hunt.State != api_proto.Hunt_RUNNING {
+			hunt.RequestedBy = user
+			hunt.ApprovedBy = ""
+		}
 
+		err := startHunt(hunt)
+		if err != nil {
+			return err
+		}
+		hunt.StartWarning = start_warning
+
+	// Only the other fields are changed.
+	case api_proto.Hunt_UNSET:
+		if !changed {
+			return stopHunt(config_obj, hunt, user)
+		}
+
+	// We are trying to pause or stop the hunt.
+	default:
+		return stopHunt(config_obj, hunt, user)
+	}
+
+	return nil
+}
+
+// Stop the hunt. A hunt waiting for approval is denied instead and
+// goes back to PAUSED so it may be requested again.
+func stopHunt(
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt, user string) error {
+	if hunt.State != api_proto.Hunt_PENDING_APPROVAL {
+		hunt.State = api_proto.Hunt_STOPPED
+		return nil
+	}
+
+	err := checkHuntApprover(config_obj, hunt, user, false)
 	if err != nil {
 		return err
 	}
 
-	// Notify all the clients about the new hunt. New hunts are
-	// not that common so notifying all the clients at once is
-	// probably ok.
-	notifier := services.GetNotifier()
-	if notifier == nil {
-		return errors.New("Notifier not ready")
+	hunt.State = api_proto.Hunt_PAUSED
+	return nil
+}
+
+// Append a note to the hunt. Notes can not be changed or removed
+// once added.
+func AddHuntNote(
+	config_obj *config_proto.Config,
+	hunt_id, user, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return errors.New("Hunt note is empty")
 	}
-	return notifier.NotifyByRegex(config_obj, "^[Cc]\\.")
+
+	// The dispatcher holds its lock while we append so concurrent
+	// notes are not lost.
+	return services.GetHuntDispatcher().ModifyHunt(hunt_id,
+		func(hunt *api_proto.Hunt) error {
+			if hunt.Stats == nil {
+				return errors.New("Invalid hunt")
+			}
+
+			hunt.Notes = append(hunt.Notes, &api_proto.HuntNote{
+				Timestamp: HuntTimeNow(),
+				User:      user,
+				Text:      text,
+			})
+
+			db, err := datastore.GetDB(config_obj)
+			if err != nil {
+				return err
+			}
+
+			hunt_path_manager := paths.NewHuntPathManager(hunt.HuntId)
+			return db.SetSubject(config_obj, hunt_path_manager.Path(), hunt)
+		})
 }
@@ -1,25 +1,55 @@
 package flows
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/Velocidex/ordereddict"
+	"github.com/alexmullins/zip"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+	"www.velocidex.com/golang/velociraptor/acls"
+	acl_proto "www.velocidex.com/golang/velociraptor/acls/proto"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	"www.velocidex.com/golang/velociraptor/config"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/file_store/memory"
+	"www.velocidex.com/golang/velociraptor/file_store/result_sets"
 	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/paths/artifacts"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/client_info"
 	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
 	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/services/labels"
 	"www.velocidex.com/golang/velociraptor/services/launcher"
 	"www.velocidex.com/golang/velociraptor/services/notifications"
 	"www.velocidex.com/golang/velociraptor/services/repository"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	_ "www.velocidex.com/golang/velociraptor/vql/functions"
+	"www.velocidex.com/golang/velociraptor/vtesting"
 )
 
 type HuntTestSuite struct {
@@ -46,6 +76,8 @@ func (self *HuntTestSuite) SetupTest() {
 	require.NoError(self.T(), self.sm.Start(launcher.StartLauncherService))
 	require.NoError(self.T(), self.sm.Start(hunt_dispatcher.StartHuntDispatcher))
 	require.NoError(self.T(), self.sm.Start(repository.StartRepositoryManager))
+	require.NoError(self.T(), self.sm.Start(labels.StartLabelService))
+	require.NoError(self.T(), self.sm.Start(client_info.StartClientInfoService))
 }
 
 func (self *HuntTestSuite) TearDownTest() {
@@ -120,6 +152,3805 @@ sources:
 		[]string{"TestArtifact_Arg1", "AnotherTestArtifact_Arg1"})
 }
 
+func (self *HuntTestSuite) TestCreateHunts() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	results, err := CreateHunts(self.ctx, self.config_obj, acl_manager,
+		[]*api_proto.Hunt{{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		}, {
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"No.Such.Artifact"},
+			},
+		}, {
+			State: api_proto.Hunt_RUNNING,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		}})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 3, len(results))
+
+	// The broken hunt does not stop the others from being created.
+	assert.NoError(self.T(), results[0].Err)
+	assert.Error(self.T(), results[1].Err)
+	assert.NoError(self.T(), results[2].Err)
+
+	for _, i := range []int{0, 2} {
+		hunt_obj, err := GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: results[i].HuntId})
+		assert.NoError(self.T(), err)
+		assert.Equal(self.T(), results[i].HuntId, hunt_obj.HuntId)
+	}
+
+	result, err := ListHunts(self.config_obj, &api_proto.ListHuntsRequest{Count: 10})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(result.Items))
+}
+
+func (self *HuntTestSuite) TestMaxRunningHunts() {
+	self.config_obj.Defaults = &config_proto.Defaults{MaxRunningHunts: 1}
+	defer func() {
+		self.config_obj.Defaults = nil
+	}()
+
+	acl_manager := vql_subsystem.NullACLManager{}
+	new_hunt := func(state api_proto.Hunt_State) *api_proto.Hunt {
+		return &api_proto.Hunt{
+			State: state,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		}
+	}
+
+	running_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt(api_proto.Hunt_RUNNING))
+	assert.NoError(self.T(), err)
+
+	// Can not create another running hunt.
+	_, err = CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt(api_proto.Hunt_RUNNING))
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "Too many active hunts")
+
+	// Paused hunts are fine but can not be started.
+	paused_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt(api_proto.Hunt_UNSET))
+	assert.NoError(self.T(), err)
+
+	start := &api_proto.Hunt{HuntId: paused_id, State: api_proto.Hunt_RUNNING}
+	err = ModifyHunt(self.ctx, self.config_obj, start, "admin")
+	assert.Error(self.T(), err)
+
+	// Once the running hunt stops there is room again.
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: running_id,
+		State:  api_proto.Hunt_STOPPED,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	err = ModifyHunt(self.ctx, self.config_obj, start, "admin")
+	assert.NoError(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestMaxRunningHuntsBatch() {
+	self.config_obj.Defaults = &config_proto.Defaults{MaxRunningHunts: 1}
+	defer func() {
+		self.config_obj.Defaults = nil
+	}()
+
+	acl_manager := vql_subsystem.NullACLManager{}
+	new_hunt := func() *api_proto.Hunt {
+		return &api_proto.Hunt{
+			State: api_proto.Hunt_RUNNING,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		}
+	}
+
+	// The dispatcher only learns about the batch's hunts at the
+	// end, but the first running hunt already counts.
+	results, err := CreateHunts(self.ctx, self.config_obj, acl_manager,
+		[]*api_proto.Hunt{new_hunt(), new_hunt()})
+	assert.NoError(self.T(), err)
+	require.Equal(self.T(), 2, len(results))
+	assert.NoError(self.T(), results[0].Err)
+	assert.Error(self.T(), results[1].Err)
+	assert.Contains(self.T(), results[1].Err.Error(), "Too many active hunts")
+}
+
+func (self *HuntTestSuite) TestMaxRunningHuntsConcurrent() {
+	self.config_obj.Defaults = &config_proto.Defaults{MaxRunningHunts: 1}
+	defer func() {
+		self.config_obj.Defaults = nil
+	}()
+
+	acl_manager := vql_subsystem.NullACLManager{}
+
+	// Only one of the concurrent creations may start its hunt.
+	var created int64
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+				&api_proto.Hunt{
+					State: api_proto.Hunt_RUNNING,
+					StartRequest: &flows_proto.ArtifactCollectorArgs{
+						Artifacts: []string{"Generic.Client.Info"},
+					},
+				})
+			if err == nil {
+				atomic.AddInt64(&created, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(self.T(), int64(1), atomic.LoadInt64(&created))
+}
+
+func (self *HuntTestSuite) TestAddHuntNote() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			HuntDescription: "Original description",
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	assert.Error(self.T(), AddHuntNote(self.config_obj, hunt_id, "admin", " "))
+	assert.Error(self.T(), AddHuntNote(self.config_obj, "H.Missing", "admin", "note"))
+
+	// Concurrent notes are all kept.
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := AddHuntNote(self.config_obj, hunt_id,
+				fmt.Sprintf("user%d", i), fmt.Sprintf("note %d", i))
+			assert.NoError(self.T(), err)
+		}(i)
+	}
+	wg.Wait()
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 20, len(hunt_obj.Notes))
+	assert.Equal(self.T(), "Original description", hunt_obj.HuntDescription)
+
+	for _, note := range hunt_obj.Notes {
+		assert.True(self.T(), note.Timestamp > 0)
+		assert.Equal(self.T(), "note "+strings.TrimPrefix(note.User, "user"),
+			note.Text)
+	}
+
+	// The notes are stored with the hunt.
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	stored := &api_proto.Hunt{}
+	err = db.GetSubject(self.config_obj,
+		paths.NewHuntPathManager(hunt_id).Path(), stored)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 20, len(stored.Notes))
+}
+
+func (self *HuntTestSuite) TestCreateHuntIgnoresNotes() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+			Notes: []*api_proto.HuntNote{{
+				User: "someone_else",
+				Text: "Approved by security",
+			}},
+		})
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(hunt_obj.Notes))
+}
+
+func (self *HuntTestSuite) TestHuntStateChanges() {
+	journal, err := services.GetJournal()
+	assert.NoError(self.T(), err)
+
+	state_changes, cancel := journal.Watch("System.Hunt.StateChange")
+	defer cancel()
+
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	// Changing the description is not a state change.
+	for _, modification := range []*api_proto.Hunt{
+		{State: api_proto.Hunt_RUNNING},
+		{HuntDescription: "New description"},
+		{State: api_proto.Hunt_STOPPED},
+		{State: api_proto.Hunt_ARCHIVED},
+	} {
+		modification.HuntId = hunt_id
+		err = ModifyHunt(self.ctx, self.config_obj, modification, "admin")
+		assert.NoError(self.T(), err)
+	}
+
+	// A hunt created running is started.
+	running_hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			Creator: "creator",
+			State:   api_proto.Hunt_RUNNING,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	expected := []string{
+		hunt_id + " PAUSED RUNNING admin",
+		hunt_id + " RUNNING STOPPED admin",
+		hunt_id + " STOPPED ARCHIVED admin",
+		running_hunt_id + " PAUSED RUNNING creator",
+	}
+
+	for _, change := range expected {
+		select {
+		case row := <-state_changes:
+			hunt_id, _ := row.GetString("HuntId")
+			old_state, _ := row.GetString("OldState")
+			new_state, _ := row.GetString("NewState")
+			user, _ := row.GetString("User")
+			assert.Equal(self.T(), change, strings.Join(
+				[]string{hunt_id, old_state, new_state, user}, " "))
+
+			timestamp, _ := row.Get("Timestamp")
+			assert.True(self.T(), timestamp.(uint64) > 0)
+
+		case <-time.After(5 * time.Second):
+			self.T().Fatalf("Missing state change %v", change)
+		}
+	}
+}
+
+func (self *HuntTestSuite) TestHuntApproval() {
+	self.config_obj.Defaults = &config_proto.Defaults{
+		HuntApprovalRequired: true,
+	}
+	defer func() { self.config_obj.Defaults = nil }()
+
+	for _, user := range []string{"Requester", "Approver"} {
+		err := acls.SetPolicy(self.config_obj, user,
+			&acl_proto.ApiClientACL{
+				CollectClient: true,
+				ApproveHunt:   user == "Approver",
+			})
+		assert.NoError(self.T(), err)
+	}
+
+	journal, err := services.GetJournal()
+	assert.NoError(self.T(), err)
+
+	approvals, cancel := journal.Watch("System.Hunt.Approval")
+	defer cancel()
+
+	get_hunt := func(hunt_id string) *api_proto.Hunt {
+		hunt_obj, err := GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: hunt_id})
+		assert.NoError(self.T(), err)
+		return hunt_obj
+	}
+
+	modify := func(hunt_id string, state api_proto.Hunt_State, user string) error {
+		return ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+			HuntId: hunt_id,
+			State:  state,
+		}, user)
+	}
+
+	// A hunt created running waits for approval.
+	acl_manager := vql_subsystem.NewServerACLManager(self.config_obj, "Requester")
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			State: api_proto.Hunt_RUNNING,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	hunt_obj := get_hunt(hunt_id)
+	assert.Equal(self.T(), api_proto.Hunt_PENDING_APPROVAL, hunt_obj.State)
+	assert.Equal(self.T(), "Requester", hunt_obj.RequestedBy)
+	assert.Equal(self.T(), uint64(0), hunt_obj.StartTime)
+
+	// The requester can not approve their own hunt, and neither can
+	// a user without the APPROVE_HUNT permission.
+	err = modify(hunt_id, api_proto.Hunt_RUNNING, "Requester")
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "different user")
+
+	err = modify(hunt_id, api_proto.Hunt_RUNNING, "Other")
+	assert.Error(self.T(), err)
+	assert.Equal(self.T(), api_proto.Hunt_PENDING_APPROVAL,
+		get_hunt(hunt_id).State)
+
+	// Denying the hunt returns it to paused.
+	err = modify(hunt_id, api_proto.Hunt_STOPPED, "Approver")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), api_proto.Hunt_PAUSED, get_hunt(hunt_id).State)
+
+	// Starting the paused hunt asks for approval again.
+	err = modify(hunt_id, api_proto.Hunt_RUNNING, "Requester")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), api_proto.Hunt_PENDING_APPROVAL,
+		get_hunt(hunt_id).State)
+
+	err = modify(hunt_id, api_proto.Hunt_RUNNING, "Approver")
+	assert.NoError(self.T(), err)
+
+	hunt_obj = get_hunt(hunt_id)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, hunt_obj.State)
+	assert.Equal(self.T(), "Requester", hunt_obj.RequestedBy)
+	assert.Equal(self.T(), "Approver", hunt_obj.ApprovedBy)
+	assert.True(self.T(), hunt_obj.StartTime > 0)
+
+	// The approver can not be set by the requester.
+	hunt_id_2, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			State:      api_proto.Hunt_RUNNING,
+			ApprovedBy: "Approver",
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	hunt_obj = get_hunt(hunt_id_2)
+	assert.Equal(self.T(), api_proto.Hunt_PENDING_APPROVAL, hunt_obj.State)
+	assert.Equal(self.T(), "", hunt_obj.ApprovedBy)
+
+	// The requester may withdraw their request.
+	err = modify(hunt_id_2, api_proto.Hunt_PAUSED, "Requester")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), api_proto.Hunt_PAUSED, get_hunt(hunt_id_2).State)
+
+	expected := []string{
+		hunt_id + " Requested Requester Requester",
+		hunt_id + " Denied Requester Approver",
+		hunt_id + " Requested Requester Requester",
+		hunt_id + " Approved Requester Approver",
+		hunt_id_2 + " Requested Requester Requester",
+		hunt_id_2 + " Withdrawn Requester Requester",
+	}
+
+	for _, approval := range expected {
+		select {
+		case row := <-approvals:
+			hunt_id, _ := row.GetString("HuntId")
+			action, _ := row.GetString("Action")
+			requested_by, _ := row.GetString("RequestedBy")
+			user, _ := row.GetString("User")
+			assert.Equal(self.T(), approval, strings.Join(
+				[]string{hunt_id, action, requested_by, user}, " "))
+
+		case <-time.After(5 * time.Second):
+			self.T().Fatalf("Missing approval %v", approval)
+		}
+	}
+}
+
+func (self *HuntTestSuite) TestHuntResourceLimits() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+				CpuLimit:  20,
+				IopsLimit: 500,
+				Timeout:   60,
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+
+	// The limits are compiled into every query sent to clients.
+	compiled := hunt_obj.StartRequest.CompiledCollectorArgs
+	assert.True(self.T(), len(compiled) > 0)
+	for _, args := range compiled {
+		assert.Equal(self.T(), float32(20), args.CpuLimit)
+		assert.Equal(self.T(), float32(500), args.IopsLimit)
+		assert.Equal(self.T(), uint64(60), args.Timeout)
+	}
+
+	_, err = CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+				CpuLimit:  150,
+			},
+		})
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "CPU limit")
+}
+
+func (self *HuntTestSuite) TestModifyHuntCombined() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			HuntDescription: "Original",
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	get_hunt := func() *api_proto.Hunt {
+		hunt_obj, err := GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: hunt_id})
+		assert.NoError(self.T(), err)
+		return hunt_obj
+	}
+
+	// Rename and start the hunt at once.
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId:          hunt_id,
+		HuntDescription: "Renamed",
+		State:           api_proto.Hunt_RUNNING,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	hunt_obj := get_hunt()
+	assert.Equal(self.T(), "Renamed", hunt_obj.HuntDescription)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, hunt_obj.State)
+	assert.True(self.T(), hunt_obj.StartTime > 0)
+
+	// Changing the expiry alone keeps the hunt running.
+	expires := HuntTimeFromTime(time.Now().Add(time.Hour))
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId:  hunt_id,
+		Expires: expires,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	hunt_obj = get_hunt()
+	assert.Equal(self.T(), expires, hunt_obj.Expires)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, hunt_obj.State)
+
+	// Change the description and expiry and stop the hunt.
+	expires = HuntTimeFromTime(time.Now().Add(2 * time.Hour))
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId:          hunt_id,
+		HuntDescription: "Stopped",
+		Expires:         expires,
+		State:           api_proto.Hunt_STOPPED,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	hunt_obj = get_hunt()
+	assert.Equal(self.T(), "Stopped", hunt_obj.HuntDescription)
+	assert.Equal(self.T(), expires, hunt_obj.Expires)
+	assert.Equal(self.T(), api_proto.Hunt_STOPPED, hunt_obj.State)
+
+	// A failed modification changes nothing.
+	err = services.GetHuntDispatcher().ModifyHunt(hunt_id,
+		func(hunt_obj *api_proto.Hunt) error {
+			hunt_obj.Stats.Stopped = true
+			return nil
+		})
+	assert.NoError(self.T(), err)
+
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId:          hunt_id,
+		HuntDescription: "Restarted",
+		State:           api_proto.Hunt_RUNNING,
+		Force:           true,
+	}, "admin")
+	assert.Error(self.T(), err)
+
+	hunt_obj = get_hunt()
+	assert.Equal(self.T(), "Stopped", hunt_obj.HuntDescription)
+	assert.Equal(self.T(), api_proto.Hunt_STOPPED, hunt_obj.State)
+
+	// A modification which changes nothing else still stops the
+	// hunt.
+	paused_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	err = ModifyHunt(self.ctx, self.config_obj,
+		&api_proto.Hunt{HuntId: paused_id}, "admin")
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: paused_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), api_proto.Hunt_STOPPED, hunt_obj.State)
+}
+
+func (self *HuntTestSuite) TestRenotifyHunt() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	// Paused hunts are not notified.
+	err = RenotifyHunt(self.config_obj, hunt_id)
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "not running")
+
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_id,
+		State:  api_proto.Hunt_RUNNING,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	before, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+
+	notification, cancel := services.GetNotifier().ListenForNotification("C.123")
+	defer cancel()
+
+	err = RenotifyHunt(self.config_obj, hunt_id)
+	assert.NoError(self.T(), err)
+
+	select {
+	case <-notification:
+	case <-time.After(5 * time.Second):
+		self.T().Fatalf("Client was not notified of hunt %v", hunt_id)
+	}
+
+	// The hunt is unchanged.
+	after, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), before.StartTime, after.StartTime)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, after.State)
+}
+
+func (self *HuntTestSuite) TestRunHuntOnClient() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+			Condition: &api_proto.HuntCondition{
+				Labels: &api_proto.HuntLabelCondition{
+					Label: []string{"NoClientHasThis"},
+				},
+			},
+			ClientLimit: 1,
+		})
+	assert.NoError(self.T(), err)
+
+	_, err = RunHuntOnClient(self.ctx, self.config_obj, acl_manager, "H.Missing", "C.123")
+	assert.Error(self.T(), err)
+
+	// The client does not match the hunt's condition but is
+	// tested anyway.
+	flow_id, err := RunHuntOnClient(self.ctx, self.config_obj, acl_manager, hunt_id, "C.123")
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+
+	collection_context, err := LoadCollectionContext(
+		self.config_obj, "C.123", flow_id)
+	assert.NoError(self.T(), err)
+
+	// The flow runs exactly what the hunt would run.
+	assert.True(self.T(), proto.Equal(
+		&flows_proto.ArtifactCollectorArgs{
+			CompiledCollectorArgs: hunt_obj.StartRequest.CompiledCollectorArgs,
+		},
+		&flows_proto.ArtifactCollectorArgs{
+			CompiledCollectorArgs: collection_context.Request.CompiledCollectorArgs,
+		}))
+
+	// But it is not one of the hunt's flows.
+	assert.Equal(self.T(), "HuntTest:"+hunt_id, collection_context.Request.Creator)
+	assert.Equal(self.T(), uint64(0), hunt_obj.Stats.TotalClientsScheduled)
+
+	// The client limit is not used up so the test can be repeated.
+	_, err = RunHuntOnClient(self.ctx, self.config_obj, acl_manager, hunt_id, "C.123")
+	assert.NoError(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestRunHuntOnClientArtifactPermissions() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Execve
+required_permissions:
+  - EXECVE
+sources:
+- query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Test.Artifact.Execve"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	// Collecting from clients is not enough to run the hunt's
+	// artifacts.
+	err = acls.SetPolicy(self.config_obj, "UserX",
+		&acl_proto.ApiClientACL{CollectClient: true})
+	assert.NoError(self.T(), err)
+
+	_, err = RunHuntOnClient(self.ctx, self.config_obj,
+		vql_subsystem.NewServerACLManager(self.config_obj, "UserX"),
+		hunt_id, "C.123")
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "EXECVE")
+
+	err = acls.SetPolicy(self.config_obj, "UserX",
+		&acl_proto.ApiClientACL{CollectClient: true, Execve: true})
+	assert.NoError(self.T(), err)
+
+	_, err = RunHuntOnClient(self.ctx, self.config_obj,
+		vql_subsystem.NewServerACLManager(self.config_obj, "UserX"),
+		hunt_id, "C.123")
+	assert.NoError(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestGetHuntStatsOnly() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			State: api_proto.Hunt_RUNNING,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	// The hunt manager updates the stats in the dispatcher.
+	err = services.GetHuntDispatcher().ModifyHunt(hunt_id,
+		func(hunt_obj *api_proto.Hunt) error {
+			hunt_obj.Stats.TotalClientsScheduled = 5
+			return nil
+		})
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err := GetHunt(self.config_obj, &api_proto.GetHuntRequest{
+		HuntId:    hunt_id,
+		StatsOnly: true,
+	})
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), hunt_id, hunt_obj.HuntId)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, hunt_obj.State)
+	assert.Equal(self.T(), uint64(5), hunt_obj.Stats.TotalClientsScheduled)
+	assert.Nil(self.T(), hunt_obj.StartRequest)
+	assert.Nil(self.T(), hunt_obj.Stats.AvailableDownloads)
+
+	// The full hunt is still returned by default.
+	hunt_obj, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.NotNil(self.T(), hunt_obj.StartRequest)
+	assert.Equal(self.T(), uint64(5), hunt_obj.Stats.TotalClientsScheduled)
+}
+
+func (self *HuntTestSuite) TestEstimateHuntCompletion() {
+	hour := uint64(time.Hour / time.Microsecond)
+	new_hunt := func(state api_proto.Hunt_State, start_time,
+		scheduled, completed uint64) *api_proto.Hunt {
+		return &api_proto.Hunt{
+			State:     state,
+			StartTime: start_time,
+			Stats: &api_proto.HuntStats{
+				TotalClientsScheduled:   scheduled,
+				TotalClientsWithResults: completed,
+			},
+		}
+	}
+
+	now := 10 * hour
+
+	// 10 of 30 clients completed in 2 hours - 20 more take 4 hours.
+	assert.Equal(self.T(), now+4*hour, estimateHuntCompletion(
+		new_hunt(api_proto.Hunt_RUNNING, now-2*hour, 30, 10), now))
+
+	// No estimate for hunts which have not started, have no
+	// completions, are complete or are not running.
+	assert.Equal(self.T(), uint64(0), estimateHuntCompletion(
+		new_hunt(api_proto.Hunt_PAUSED, 0, 30, 0), now))
+	assert.Equal(self.T(), uint64(0), estimateHuntCompletion(
+		new_hunt(api_proto.Hunt_RUNNING, now-2*hour, 30, 0), now))
+	assert.Equal(self.T(), uint64(0), estimateHuntCompletion(
+		new_hunt(api_proto.Hunt_RUNNING, now-2*hour, 30, 30), now))
+	assert.Equal(self.T(), uint64(0), estimateHuntCompletion(
+		new_hunt(api_proto.Hunt_STOPPED, now-2*hour, 30, 10), now))
+
+	stopped := new_hunt(api_proto.Hunt_RUNNING, now-2*hour, 30, 10)
+	stopped.Stats.Stopped = true
+	assert.Equal(self.T(), uint64(0), estimateHuntCompletion(stopped, now))
+
+	// GetHunt fills in the estimate without storing it.
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			State: api_proto.Hunt_RUNNING,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	err = services.GetHuntDispatcher().ModifyHunt(hunt_id,
+		func(hunt_obj *api_proto.Hunt) error {
+			hunt_obj.StartTime = HuntTimeNow() - hour
+			hunt_obj.Stats.TotalClientsScheduled = 4
+			hunt_obj.Stats.TotalClientsWithResults = 1
+			hunt_obj.Stats.TotalClientsWithErrors = 1
+			return nil
+		})
+	assert.NoError(self.T(), err)
+
+	for _, stats_only := range []bool{false, true} {
+		hunt_obj, err := GetHunt(self.config_obj, &api_proto.GetHuntRequest{
+			HuntId:    hunt_id,
+			StatsOnly: stats_only,
+		})
+		assert.NoError(self.T(), err)
+
+		eta := hunt_obj.Stats.EstimatedCompletion
+		assert.True(self.T(), eta > HuntTimeNow()+hour-hour/10, eta)
+		assert.True(self.T(), eta < HuntTimeNow()+hour+hour/10, eta)
+	}
+
+	err = services.GetHuntDispatcher().ApplyFuncOnHunts(
+		func(hunt_obj *api_proto.Hunt) error {
+			assert.Equal(self.T(), uint64(0), hunt_obj.Stats.EstimatedCompletion)
+			return nil
+		})
+	assert.NoError(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestHuntTimestampUnits() {
+	before := HuntTimeNow()
+
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	journal, err := services.GetJournal()
+	assert.NoError(self.T(), err)
+
+	archived, cancel := journal.Watch("System.Hunt.Archive")
+	defer cancel()
+
+	for _, state := range []api_proto.Hunt_State{
+		api_proto.Hunt_RUNNING, api_proto.Hunt_ARCHIVED} {
+		err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+			HuntId: hunt_id,
+			State:  state,
+		}, "admin")
+		assert.NoError(self.T(), err)
+	}
+
+	var row *ordereddict.Dict
+	select {
+	case row = <-archived:
+	case <-time.After(5 * time.Second):
+		self.T().Fatalf("Hunt %v was not archived", hunt_id)
+	}
+
+	after := HuntTimeNow()
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+
+	// The row is emitted once the archived hunt is saved.
+	archived_hunt, _ := row.Get("Hunt")
+	assert.Equal(self.T(), api_proto.Hunt_ARCHIVED,
+		archived_hunt.(*api_proto.Hunt).State)
+	assert.Equal(self.T(), api_proto.Hunt_ARCHIVED, hunt_obj.State)
+
+	timestamp, _ := row.Get("Timestamp")
+	for _, value := range []uint64{
+		hunt_obj.CreateTime, hunt_obj.StartTime, timestamp.(uint64)} {
+		assert.True(self.T(), value >= before && value <= after)
+	}
+
+	// Hunts expire after a week by default.
+	assert.Equal(self.T(), 7*24*time.Hour,
+		HuntTimeToTime(hunt_obj.Expires).Sub(
+			HuntTimeToTime(hunt_obj.CreateTime)).Round(time.Second))
+
+	// The timestamps are also returned as RFC3339 strings.
+	for _, pair := range []struct {
+		value uint64
+		str   string
+	}{
+		{hunt_obj.CreateTime, hunt_obj.CreateTimeString},
+		{hunt_obj.StartTime, hunt_obj.StartTimeString},
+		{hunt_obj.Expires, hunt_obj.ExpiresString},
+	} {
+		parsed, err := time.Parse(time.RFC3339, pair.str)
+		assert.NoError(self.T(), err)
+		assert.Equal(self.T(), HuntTimeToTime(pair.value), parsed)
+	}
+}
+
+func TestHuntTime(t *testing.T) {
+	now := time.Unix(1600000000, 123456789)
+	assert.Equal(t, uint64(1600000000123456), HuntTimeFromTime(now))
+	assert.Equal(t, now.Truncate(time.Microsecond).UTC(),
+		HuntTimeToTime(1600000000123456))
+
+	assert.Equal(t, "2020-09-13T12:26:40.123456Z",
+		HuntTimeToString(1600000000123456))
+	assert.Equal(t, "", HuntTimeToString(0))
+}
+
+func TestJournalTimestamp(t *testing.T) {
+	for _, test := range []struct {
+		timestamp interface{}
+		expected  uint64
+	}{
+		// Old rows are in seconds.
+		{int64(1600000000), 1600000000000000},
+		{uint64(1600000000), 1600000000000000},
+
+		// New rows are already in microseconds.
+		{uint64(1600000000123456), 1600000000123456},
+
+		{nil, 0},
+	} {
+		row := ordereddict.NewDict().Set("Timestamp", test.timestamp)
+		assert.Equal(t, test.expected, journalTimestamp(row))
+	}
+}
+
+func TestHuntRetryBackoff(t *testing.T) {
+	for _, test := range []struct {
+		backoff, attempt uint64
+		expected         time.Duration
+	}{
+		{60, 0, time.Minute},
+		{60, 1, 2 * time.Minute},
+		{60, 3, 8 * time.Minute},
+
+		// The backoff only doubles so many times.
+		{1, 20, 1024 * time.Second},
+		{60, 20, 1024 * time.Minute},
+
+		// And is never longer than a week, even when it would
+		// overflow.
+		{1000, 10, max_retry_backoff},
+		{1 << 62, 2, max_retry_backoff},
+		{math.MaxUint64, 0, max_retry_backoff},
+	} {
+		assert.Equal(t, test.expected, huntRetryBackoff(
+			&api_proto.HuntRetryPolicy{Backoff: test.backoff}, test.attempt),
+			"backoff %v attempt %v", test.backoff, test.attempt)
+	}
+}
+
+func (self *HuntTestSuite) TestCreateRunningHuntMatchesModifyHunt() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	repository.LoadYaml(`
+name: TestArtifact
+sources:
+- query:
+    SELECT * FROM info()
+`, true)
+
+	acl_manager := vql_subsystem.NullACLManager{}
+	notifier := services.GetNotifier()
+	db := test_utils.GetMemoryDataStore(self.T(), self.config_obj)
+
+	// Start a hunt and return the hunt object as stored in the
+	// datastore, checking that clients were notified.
+	start_hunt := func(create_running bool) *api_proto.Hunt {
+		notification, cancel := notifier.ListenForNotification("C.123")
+		defer cancel()
+
+		request := &api_proto.Hunt{
+			HuntDescription: "My hunt",
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"TestArtifact"},
+			},
+		}
+		if create_running {
+			request.State = api_proto.Hunt_RUNNING
+		}
+
+		hunt_id, err := CreateHunt(
+			self.ctx, self.config_obj, acl_manager, request)
+		assert.NoError(self.T(), err)
+
+		if !create_running {
+			err = ModifyHunt(self.ctx, self.config_obj,
+				&api_proto.Hunt{
+					HuntId: hunt_id,
+					State:  api_proto.Hunt_RUNNING,
+				}, "admin")
+			assert.NoError(self.T(), err)
+		}
+
+		select {
+		case <-notification:
+		case <-time.After(5 * time.Second):
+			self.T().Fatalf("Client was not notified of hunt %v", hunt_id)
+		}
+
+		hunt_obj, pres := db.Subjects["/hunts/"+hunt_id].(*api_proto.Hunt)
+		assert.True(self.T(), pres)
+
+		return hunt_obj
+	}
+
+	created := start_hunt(true)
+	modified := start_hunt(false)
+
+	for _, hunt_obj := range []*api_proto.Hunt{created, modified} {
+		assert.Equal(self.T(), api_proto.Hunt_RUNNING, hunt_obj.State)
+		assert.NotEqual(self.T(), uint64(0), hunt_obj.StartTime)
+		assert.True(self.T(), hunt_obj.StartTime >= hunt_obj.CreateTime)
+	}
+
+	assert.Equal(self.T(), created.Stats, modified.Stats)
+	assert.Equal(self.T(), created.StartRequest.Artifacts,
+		modified.StartRequest.Artifacts)
+}
+
+func (self *HuntTestSuite) TestHuntAttribution() {
+	request := &api_proto.Hunt{
+		HuntDescription: "My hunt",
+		StartRequest: &flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{"Generic.Client.Info"},
+		},
+	}
+
+	acl_manager := vql_subsystem.NewServerACLManager(self.config_obj, "admin")
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager, request)
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "admin", hunt_obj.Creator)
+	assert.Equal(self.T(), "", hunt_obj.LastModifiedBy)
+
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId:          hunt_id,
+		HuntDescription: "Modified hunt",
+	}, "bob")
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "admin", hunt_obj.Creator)
+	assert.Equal(self.T(), "bob", hunt_obj.LastModifiedBy)
+}
+
+func (self *HuntTestSuite) TestListHuntsPaging() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	for i := 0; i < 3; i++ {
+		_, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+			&api_proto.Hunt{
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: []string{"Generic.Client.Info"},
+				},
+			})
+		assert.NoError(self.T(), err)
+	}
+
+	seen := make(map[string]bool)
+	for _, offset := range []uint64{0, 2} {
+		result, err := ListHunts(self.config_obj, &api_proto.ListHuntsRequest{
+			Offset: offset,
+			Count:  2,
+		})
+		assert.NoError(self.T(), err)
+
+		for _, hunt_obj := range result.Items {
+			seen[hunt_obj.HuntId] = true
+		}
+	}
+
+	assert.Equal(self.T(), 3, len(seen))
+}
+
+func (self *HuntTestSuite) TestListHuntsSearch() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	for _, description := range []string{"Find Evil", "Routine inventory"} {
+		_, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+			&api_proto.Hunt{
+				HuntDescription: description,
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: []string{"Generic.Client.Info"},
+				},
+			})
+		assert.NoError(self.T(), err)
+	}
+
+	search := func(term string) []*api_proto.Hunt {
+		result, err := ListHunts(self.config_obj, &api_proto.ListHuntsRequest{
+			Count:  10,
+			Search: term,
+		})
+		assert.NoError(self.T(), err)
+		return result.Items
+	}
+
+	result := search("EVIL")
+	assert.Equal(self.T(), 1, len(result))
+	assert.Equal(self.T(), "Find Evil", result[0].HuntDescription)
+
+	// Artifact names match too.
+	assert.Equal(self.T(), 2, len(search("client.info")))
+
+	// So does the hunt id.
+	assert.Equal(self.T(), 1, len(search(result[0].HuntId)))
+
+	assert.Equal(self.T(), 0, len(search("Windows.")))
+	assert.Equal(self.T(), 2, len(search("")))
+}
+
+func (self *HuntTestSuite) TestListHuntsCreateTimeRange() {
+	now := time.Now()
+	days_ago := func(days int) uint64 {
+		return HuntTimeFromTime(now.AddDate(0, 0, -days))
+	}
+
+	acl_manager := vql_subsystem.NullACLManager{}
+	ages := []int{1, 5, 10}
+	hunt_ids := []string{}
+	for _, age := range ages {
+		hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+			&api_proto.Hunt{
+				HuntDescription: fmt.Sprintf("%d days old", age),
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: []string{"Generic.Client.Info"},
+				},
+			})
+		assert.NoError(self.T(), err)
+		hunt_ids = append(hunt_ids, hunt_id)
+	}
+
+	// Backdate the hunts once they are all created, since creating
+	// a hunt refreshes the dispatcher.
+	for i, hunt_id := range hunt_ids {
+		err := services.GetHuntDispatcher().ModifyHunt(hunt_id,
+			func(hunt *api_proto.Hunt) error {
+				hunt.CreateTime = days_ago(ages[i])
+				return nil
+			})
+		assert.NoError(self.T(), err)
+	}
+
+	list := func(in *api_proto.ListHuntsRequest) []string {
+		in.Count = 10
+		result, err := ListHunts(self.config_obj, in)
+		assert.NoError(self.T(), err)
+
+		ids := []string{}
+		for _, hunt := range result.Items {
+			ids = append(ids, hunt.HuntId)
+		}
+		return ids
+	}
+
+	// The last week.
+	assert.Equal(self.T(), []string{hunt_ids[0], hunt_ids[1]},
+		list(&api_proto.ListHuntsRequest{CreateTimeAfter: days_ago(7)}))
+
+	assert.Equal(self.T(), []string{hunt_ids[1], hunt_ids[2]},
+		list(&api_proto.ListHuntsRequest{CreateTimeBefore: days_ago(2)}))
+
+	assert.Equal(self.T(), []string{hunt_ids[1]},
+		list(&api_proto.ListHuntsRequest{
+			CreateTimeAfter:  days_ago(7),
+			CreateTimeBefore: days_ago(2),
+		}))
+
+	// The range composes with the other filters and paging.
+	assert.Equal(self.T(), []string{hunt_ids[2]},
+		list(&api_proto.ListHuntsRequest{
+			CreateTimeBefore: days_ago(2),
+			Search:           "10 days",
+		}))
+
+	assert.Equal(self.T(), []string{hunt_ids[1]},
+		list(&api_proto.ListHuntsRequest{
+			CreateTimeAfter: days_ago(7),
+			Offset:          1,
+		}))
+}
+
+func (self *HuntTestSuite) TestListHuntsFilters() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_ids := []string{}
+	for _, hunt := range []*api_proto.Hunt{{
+		Creator: "alice",
+		Tags:    []string{"IR-1234"},
+		StartRequest: &flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{"Generic.Client.Info"},
+		},
+	}, {
+		Creator: "bob",
+		State:   api_proto.Hunt_RUNNING,
+		Force:   true,
+		Condition: &api_proto.HuntCondition{
+			Labels: &api_proto.HuntLabelCondition{
+				Label: []string{"Finance"},
+			},
+		},
+		StartRequest: &flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{"Generic.Client.Info"},
+		},
+	}} {
+		hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager, hunt)
+		assert.NoError(self.T(), err)
+		hunt_ids = append(hunt_ids, hunt_id)
+	}
+
+	list := func(in *api_proto.ListHuntsRequest) []string {
+		in.Count = 10
+		result, err := ListHunts(self.config_obj, in)
+		assert.NoError(self.T(), err)
+
+		ids := []string{}
+		for _, hunt := range result.Items {
+			ids = append(ids, hunt.HuntId)
+		}
+		return ids
+	}
+
+	assert.Equal(self.T(), []string{hunt_ids[0]},
+		list(&api_proto.ListHuntsRequest{Creator: "alice"}))
+
+	assert.Equal(self.T(), []string{hunt_ids[1]},
+		list(&api_proto.ListHuntsRequest{State: api_proto.Hunt_RUNNING}))
+
+	assert.Equal(self.T(), []string{hunt_ids[1]},
+		list(&api_proto.ListHuntsRequest{Label: "finance"}))
+
+	assert.Equal(self.T(), []string{hunt_ids[0]},
+		list(&api_proto.ListHuntsRequest{Tag: "ir-1234"}))
+
+	// Artifacts must match the whole name.
+	assert.Equal(self.T(), 2, len(list(&api_proto.ListHuntsRequest{
+		Artifact: "generic.client.info"})))
+	assert.Equal(self.T(), 0, len(list(&api_proto.ListHuntsRequest{
+		Artifact: "Generic.Client"})))
+
+	// Filters compose.
+	assert.Equal(self.T(), 0, len(list(&api_proto.ListHuntsRequest{
+		Creator: "alice",
+		State:   api_proto.Hunt_RUNNING,
+	})))
+
+	// Asking for archived hunts returns them.
+	err := ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_ids[0],
+		State:  api_proto.Hunt_ARCHIVED,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), []string{hunt_ids[1]},
+		list(&api_proto.ListHuntsRequest{}))
+	assert.Equal(self.T(), []string{hunt_ids[0]},
+		list(&api_proto.ListHuntsRequest{State: api_proto.Hunt_ARCHIVED}))
+}
+
+func (self *HuntTestSuite) TestHuntTags() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_ids := []string{}
+	for _, tags := range [][]string{
+		{" IR-2024-003 ", "ransomware", "Ransomware", ""},
+		{"ir-2024-003"},
+		nil,
+	} {
+		hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+			&api_proto.Hunt{
+				Tags: tags,
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: []string{"Generic.Client.Info"},
+				},
+			})
+		assert.NoError(self.T(), err)
+		hunt_ids = append(hunt_ids, hunt_id)
+	}
+
+	get_tags := func(hunt_id string) []string {
+		hunt_obj, err := GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: hunt_id})
+		assert.NoError(self.T(), err)
+		return hunt_obj.Tags
+	}
+
+	// Tags are trimmed and repeats dropped.
+	assert.Equal(self.T(), []string{"IR-2024-003", "ransomware"},
+		get_tags(hunt_ids[0]))
+
+	// Changing the tags does not stop the hunt.
+	err := ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_ids[2],
+		Tags:   []string{"phishing"},
+	}, "admin")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), []string{"phishing"}, get_tags(hunt_ids[2]))
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_ids[2]})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), api_proto.Hunt_PAUSED, hunt_obj.State)
+
+	// Tags are grouped across all pages, ignoring case.
+	result, err := ListHunts(self.config_obj, &api_proto.ListHuntsRequest{
+		Count:      1,
+		GroupByTag: true,
+	})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(result.Items))
+
+	groups := make(map[string][]string)
+	for _, group := range result.TagGroups {
+		sort.Strings(group.HuntIds)
+		groups[strings.ToLower(group.Tag)] = group.HuntIds
+	}
+	both := []string{hunt_ids[0], hunt_ids[1]}
+	sort.Strings(both)
+	assert.Equal(self.T(), map[string][]string{
+		"ir-2024-003": both,
+		"ransomware":  {hunt_ids[0]},
+		"phishing":    {hunt_ids[2]},
+	}, groups)
+
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId:    hunt_ids[2],
+		ClearTags: true,
+	}, "admin")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(get_tags(hunt_ids[2])))
+}
+
+func (self *HuntTestSuite) TestStartHuntWithNoMatchingClients() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+			Condition: &api_proto.HuntCondition{
+				Os: &api_proto.HuntOsCondition{
+					Os: api_proto.HuntOsCondition_WINDOWS,
+				},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	// There are no Windows clients so the hunt is not started.
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_id,
+		State:  api_proto.Hunt_RUNNING,
+	}, "admin")
+	assert.Error(self.T(), err)
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), api_proto.Hunt_PAUSED, hunt_obj.State)
+
+	// Forcing the hunt starts it and records the warning.
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_id,
+		State:  api_proto.Hunt_RUNNING,
+		Force:  true,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, hunt_obj.State)
+	assert.NotEqual(self.T(), "", hunt_obj.StartWarning)
+
+	// Once a Windows client exists the hunt matches it.
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	client_id := "C.1234"
+	err = db.SetSubject(self.config_obj,
+		paths.NewClientPathManager(client_id).Path(),
+		&actions_proto.ClientInfo{System: "windows"})
+	assert.NoError(self.T(), err)
+
+	err = db.SetIndex(self.config_obj, constants.CLIENT_INDEX_URN,
+		client_id, []string{"all"})
+	assert.NoError(self.T(), err)
+
+	count, err := EstimateHunt(self.ctx, self.config_obj, hunt_obj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(1), count)
+}
+
+// Label and OS conditions are both applied when estimating a hunt.
+func (self *HuntTestSuite) TestEstimateHuntLabelAndOS() {
+	hunt_obj := &api_proto.Hunt{
+		Condition: &api_proto.HuntCondition{
+			Labels: &api_proto.HuntLabelCondition{
+				Label: []string{"Finance"},
+			},
+			Os: &api_proto.HuntOsCondition{
+				Os: api_proto.HuntOsCondition_WINDOWS,
+			},
+		},
+	}
+	assert.True(self.T(), huntHasCondition(hunt_obj))
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	labeler := services.GetLabeler()
+
+	// A labeled windows client, an unlabeled windows client and a
+	// labeled linux client.
+	for _, client := range []struct {
+		client_id, system string
+		labeled           bool
+	}{
+		{"C.1", "windows", true},
+		{"C.2", "windows", false},
+		{"C.3", "linux", true},
+	} {
+		err = db.SetSubject(self.config_obj,
+			paths.NewClientPathManager(client.client_id).Path(),
+			&actions_proto.ClientInfo{System: client.system})
+		assert.NoError(self.T(), err)
+
+		err = db.SetIndex(self.config_obj, constants.CLIENT_INDEX_URN,
+			client.client_id, []string{"all"})
+		assert.NoError(self.T(), err)
+
+		if client.labeled {
+			err = labeler.SetClientLabel(
+				self.config_obj, client.client_id, "Finance")
+			assert.NoError(self.T(), err)
+		}
+	}
+
+	count, err := EstimateHunt(self.ctx, self.config_obj, hunt_obj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(1), count)
+
+	// Without the OS condition both labeled clients match.
+	hunt_obj.Condition.Os = nil
+	count, err = EstimateHunt(self.ctx, self.config_obj, hunt_obj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(2), count)
+
+	// The estimate gives up once the caller is done.
+	ctx, cancel := context.WithCancel(self.ctx)
+	cancel()
+
+	_, err = EstimateHunt(ctx, self.config_obj, hunt_obj)
+	assert.Error(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestHuntRequestLimits() {
+	self.config_obj.Defaults = &config_proto.Defaults{
+		MaxHuntArtifacts:      1,
+		MaxHuntParameterBytes: 10,
+	}
+	defer func() { self.config_obj.Defaults = nil }()
+
+	acl_manager := vql_subsystem.NullACLManager{}
+	_, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info", "Generic.Client.Stats"},
+			},
+		})
+	assert.Error(self.T(), err)
+
+	_, err = CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+				Specs: []*flows_proto.ArtifactSpec{{
+					Artifact: "Generic.Client.Info",
+					Parameters: &flows_proto.ArtifactParameters{
+						Env: []*actions_proto.VQLEnv{{
+							Key:   "Parameter",
+							Value: "A value which is too long",
+						}},
+					},
+				}},
+			},
+		})
+	assert.Error(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestHuntExpiryDefaults() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	new_hunt := func(expires uint64) *api_proto.Hunt {
+		return &api_proto.Hunt{
+			Expires: expires,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		}
+	}
+	get_hunt := func(hunt_id string) *api_proto.Hunt {
+		hunt_obj, err := GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: hunt_id})
+		assert.NoError(self.T(), err)
+		return hunt_obj
+	}
+
+	// Without config hunts expire after 7 days.
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt(0))
+	assert.NoError(self.T(), err)
+	hunt_obj := get_hunt(hunt_id)
+	assert.Equal(self.T(), uint64(7*24*time.Hour/time.Microsecond),
+		hunt_obj.Expires-hunt_obj.CreateTime)
+
+	self.config_obj.Defaults = &config_proto.Defaults{
+		HuntExpiryHours:    24,
+		MaxHuntExpiryHours: 48,
+	}
+	defer func() { self.config_obj.Defaults = nil }()
+
+	hunt_id, err = CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt(0))
+	assert.NoError(self.T(), err)
+	hunt_obj = get_hunt(hunt_id)
+	assert.Equal(self.T(), uint64(24*time.Hour/time.Microsecond),
+		hunt_obj.Expires-hunt_obj.CreateTime)
+
+	// An explicit expiry within the maximum is kept.
+	expires := HuntTimeFromTime(time.Now().Add(36 * time.Hour))
+	hunt_id, err = CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt(expires))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), expires, get_hunt(hunt_id).Expires)
+
+	_, err = CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt(HuntTimeFromTime(time.Now().Add(72*time.Hour))))
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "configured maximum of 48 hours")
+
+	// The expiry can not be extended past the maximum either.
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId:  hunt_id,
+		Expires: HuntTimeFromTime(time.Now().Add(72 * time.Hour)),
+	}, "admin")
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "configured maximum of 48 hours")
+
+	// A default above the maximum is capped.
+	self.config_obj.Defaults.HuntExpiryHours = 100
+	hunt_id, err = CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt(0))
+	assert.NoError(self.T(), err)
+	hunt_obj = get_hunt(hunt_id)
+	assert.True(self.T(), hunt_obj.Expires-hunt_obj.CreateTime <=
+		uint64(48*time.Hour/time.Microsecond))
+}
+
+func (self *HuntTestSuite) TestCreateHuntIdempotencyKey() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	new_hunt := func(key, description string) *api_proto.Hunt {
+		return &api_proto.Hunt{
+			// The API assigns a new id to each request.
+			HuntId:          GetNewHuntId(),
+			HuntDescription: description,
+			IdempotencyKey:  key,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		}
+	}
+
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt("key1", "First"))
+	assert.NoError(self.T(), err)
+
+	// A retry returns the same hunt.
+	retry_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt("key1", "First"))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), hunt_id, retry_id)
+
+	// Reusing the key for a different hunt is rejected.
+	_, err = CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt("key1", "Second"))
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), hunt_id)
+
+	// Concurrent retries only create one hunt.
+	wg := &sync.WaitGroup{}
+	ids := make([]string, 10)
+	for i := 0; i < len(ids); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+				new_hunt("key2", "Concurrent"))
+			ids[i] = id
+			assert.NoError(self.T(), err)
+		}(i)
+	}
+	wg.Wait()
+	for _, id := range ids {
+		assert.Equal(self.T(), ids[0], id)
+	}
+
+	result, err := ListHunts(self.config_obj, &api_proto.ListHuntsRequest{Count: 10})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(result.Items))
+
+	// Once the window passes the key creates a new hunt.
+	old_window := hunt_idempotency_window
+	hunt_idempotency_window = 0
+	defer func() { hunt_idempotency_window = old_window }()
+
+	new_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt("key1", "Second"))
+	assert.NoError(self.T(), err)
+	assert.NotEqual(self.T(), hunt_id, new_id)
+}
+
+func (self *HuntTestSuite) TestSweepHuntIdempotencyKeys() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	for _, key := range []string{"key1", "key2"} {
+		_, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+			&api_proto.Hunt{
+				IdempotencyKey: key,
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: []string{"Generic.Client.Info"},
+				},
+			})
+		assert.NoError(self.T(), err)
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	count_records := func() int {
+		urns, err := db.ListChildren(self.config_obj,
+			paths.HuntIdempotencyDirectory(), 0, 100)
+		assert.NoError(self.T(), err)
+		return len(urns)
+	}
+
+	// Records within the window are kept.
+	err = SweepHuntIdempotencyKeys(self.config_obj, time.Now())
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, count_records())
+
+	// Expired records are removed.
+	err = SweepHuntIdempotencyKeys(self.config_obj,
+		time.Now().Add(hunt_idempotency_window))
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, count_records())
+}
+
+func (self *HuntTestSuite) TestCampaigns() {
+	_, err := CreateCampaign(self.config_obj, &api_proto.Campaign{})
+	assert.Error(self.T(), err)
+
+	campaign_id, err := CreateCampaign(self.config_obj, &api_proto.Campaign{
+		Name:        "Engagement",
+		Description: "Hunts for the engagement",
+	})
+	assert.NoError(self.T(), err)
+
+	other_id, err := CreateCampaign(self.config_obj, &api_proto.Campaign{
+		Name: "Other",
+	})
+	assert.NoError(self.T(), err)
+
+	acl_manager := vql_subsystem.NullACLManager{}
+	new_hunt := func(campaign_id string,
+		state api_proto.Hunt_State) (string, error) {
+		return CreateHunt(self.ctx, self.config_obj, acl_manager,
+			&api_proto.Hunt{
+				CampaignId: campaign_id,
+				State:      state,
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: []string{"Generic.Client.Info"},
+				},
+			})
+	}
+
+	running_id, err := new_hunt(campaign_id, api_proto.Hunt_RUNNING)
+	assert.NoError(self.T(), err)
+
+	paused_id, err := new_hunt(campaign_id, api_proto.Hunt_UNSET)
+	assert.NoError(self.T(), err)
+
+	// Campaigns are optional.
+	_, err = new_hunt("", api_proto.Hunt_UNSET)
+	assert.NoError(self.T(), err)
+
+	_, err = new_hunt("CMP.Missing", api_proto.Hunt_UNSET)
+	assert.Error(self.T(), err)
+
+	for hunt_id, scheduled := range map[string]uint64{
+		running_id: 10, paused_id: 5} {
+		err = services.GetHuntDispatcher().ModifyHunt(hunt_id,
+			func(hunt_obj *api_proto.Hunt) error {
+				hunt_obj.Stats.TotalClientsScheduled = scheduled
+				hunt_obj.Stats.TotalClientsWithResults = scheduled - 2
+				hunt_obj.Stats.TotalClientsWithErrors = 1
+				return nil
+			})
+		assert.NoError(self.T(), err)
+	}
+
+	result, err := ListHunts(self.config_obj, &api_proto.ListHuntsRequest{
+		Count:      10,
+		CampaignId: campaign_id,
+	})
+	assert.NoError(self.T(), err)
+	hunt_ids := []string{}
+	for _, hunt := range result.Items {
+		hunt_ids = append(hunt_ids, hunt.HuntId)
+	}
+	assert.ElementsMatch(self.T(), []string{running_id, paused_id}, hunt_ids)
+
+	campaigns, err := ListCampaigns(self.config_obj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(campaigns.Items))
+
+	for _, campaign := range campaigns.Items {
+		switch campaign.CampaignId {
+		case campaign_id:
+			assert.Equal(self.T(), "Engagement", campaign.Name)
+			assert.ElementsMatch(self.T(),
+				[]string{running_id, paused_id}, campaign.HuntIds)
+			assert.Equal(self.T(), uint64(1), campaign.RunningHunts)
+			assert.Equal(self.T(), uint64(15), campaign.Stats.TotalClientsScheduled)
+			assert.Equal(self.T(), uint64(11), campaign.Stats.TotalClientsWithResults)
+			assert.Equal(self.T(), uint64(2), campaign.Stats.TotalClientsWithErrors)
+
+		case other_id:
+			assert.Equal(self.T(), 0, len(campaign.HuntIds))
+			assert.Equal(self.T(), uint64(0), campaign.Stats.TotalClientsScheduled)
+
+		default:
+			self.T().Fatalf("Unexpected campaign %v", campaign.CampaignId)
+		}
+	}
+
+	campaign, err := GetCampaign(self.config_obj, campaign_id)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(campaign.HuntIds))
+
+	_, err = GetCampaign(self.config_obj, "CMP.Missing")
+	assert.Error(self.T(), err)
+
+	// Only well formed campaign ids are looked up.
+	for _, bad_id := range []string{
+		"CMP.", "CMP.a/../b", "CMP.a.b", "H.1234", "XCMP.1234"} {
+		_, err = GetCampaign(self.config_obj, bad_id)
+		assert.Error(self.T(), err)
+		assert.Contains(self.T(), err.Error(), "Invalid campaign id")
+	}
+}
+
+func (self *HuntTestSuite) TestHuntAvailableDownloads() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	// Build a password protected zip.
+	buf := &bytes.Buffer{}
+	zip_writer := zip.NewWriter(buf)
+	header := &zip.FileHeader{Name: "data.zip", Method: zip.Store}
+	header.SetPassword("secret")
+	fd, err := zip_writer.CreateHeader(header)
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte("hello"))
+	assert.NoError(self.T(), err)
+	zip_writer.Close()
+
+	// And an uncompressed one.
+	stored := &bytes.Buffer{}
+	zip_writer = zip.NewWriter(stored)
+	fd, err = zip_writer.CreateHeader(
+		&zip.FileHeader{Name: "HuntDetails", Method: zip.Store})
+	assert.NoError(self.T(), err)
+	_, err = fd.Write([]byte("hello"))
+	assert.NoError(self.T(), err)
+	zip_writer.Close()
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_id)
+	protected := hunt_path_manager.GetHuntDownloadsFile(false, "")
+	in_progress := hunt_path_manager.GetHuntDownloadsFile(true, "")
+	delta := hunt_path_manager.GetHuntDeltaDownloadsFile(
+		time.Unix(1600000000, 0), time.Unix(1600003600, 0), "")
+	preparing := hunt_path_manager.GetHuntDeltaDownloadsFile(
+		time.Unix(1600003600, 0), time.Unix(1600007200, 0), "")
+
+	file_store_factory := test_utils.GetMemoryFileStore(self.T(), self.config_obj)
+	file_store_factory.Data[protected] = buf.Bytes()
+	file_store_factory.Data[in_progress] = []byte("truncated")
+	file_store_factory.Data[in_progress+".lock"] = []byte("X")
+	file_store_factory.Data[delta] = stored.Bytes()
+
+	// Downloads being prepared report their progress in the lock
+	// file.
+	file_store_factory.Data[preparing] = []byte("partial")
+	file_store_factory.Data[preparing+".lock"] = []byte(
+		`{"Started":1600007300,"Updated":1600007310,"Members":4,"Bytes":2048,` +
+			`"Clients":3,"TotalClients":10}`)
+
+	// The bundle hash recorded when the delta was prepared is not
+	// a download itself.
+	delta_hash := strings.Repeat("ab", 32)
+	file_store_factory.Data[delta+".sha256"] = []byte(delta_hash)
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+
+	files := make(map[string]*api_proto.AvailableDownloadFile)
+	for _, item := range hunt_obj.Stats.AvailableDownloads.Files {
+		files[item.Path] = item
+	}
+
+	assert.Equal(self.T(), 4, len(files))
+	assert.True(self.T(), files[protected].Complete)
+	assert.True(self.T(), files[protected].PasswordProtected)
+	assert.Equal(self.T(), uint64(buf.Len()), files[protected].Size)
+	assert.False(self.T(), files[in_progress].Complete)
+	assert.False(self.T(), files[in_progress].PasswordProtected)
+
+	assert.Equal(self.T(), "full", files[protected].Type)
+	assert.Equal(self.T(), "summary", files[in_progress].Type)
+	assert.Equal(self.T(), "delta", files[delta].Type)
+
+	// The compression of encrypted members is unknown.
+	assert.Equal(self.T(), "", files[protected].Compression)
+	assert.Equal(self.T(), "store", files[delta].Compression)
+
+	assert.Equal(self.T(), delta_hash, files[delta].Sha256)
+	assert.Equal(self.T(), "", files[protected].Sha256)
+
+	// Lock files without progress do not report any.
+	assert.Equal(self.T(), "", files[in_progress].PreparationStarted)
+
+	assert.False(self.T(), files[preparing].Complete)
+	assert.Equal(self.T(), "2020-09-13T14:28:20Z",
+		files[preparing].PreparationStarted)
+	assert.Equal(self.T(), uint64(4), files[preparing].PreparedMembers)
+	assert.Equal(self.T(), uint64(2048), files[preparing].PreparedBytes)
+	assert.Equal(self.T(), uint64(3), files[preparing].PreparedClients)
+	assert.Equal(self.T(), uint64(10), files[preparing].TotalClients)
+
+	// Complete downloads are only inspected once, later listings
+	// use the cached info.
+	_, pres := file_store_factory.Data[delta+DOWNLOAD_INFO_EXT]
+	assert.True(self.T(), pres)
+	_, pres = file_store_factory.Data[preparing+DOWNLOAD_INFO_EXT]
+	assert.False(self.T(), pres)
+
+	delete(file_store_factory.Data, delta+".sha256")
+	hunt_obj, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 4, len(hunt_obj.Stats.AvailableDownloads.Files))
+
+	for _, item := range hunt_obj.Stats.AvailableDownloads.Files {
+		if item.Path == delta {
+			assert.Equal(self.T(), delta_hash, item.Sha256)
+			assert.Equal(self.T(), "store", item.Compression)
+		}
+	}
+
+	// A download which was prepared again is inspected again.
+	file_store_factory.Data[delta] = buf.Bytes()
+	hunt_obj, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+
+	for _, item := range hunt_obj.Stats.AvailableDownloads.Files {
+		if item.Path == delta {
+			assert.Equal(self.T(), "", item.Sha256)
+			assert.True(self.T(), item.PasswordProtected)
+		}
+	}
+}
+
+func TestGetDownloadCompressionMethod(t *testing.T) {
+	for name, expected := range map[string]uint16{
+		"": zip.Deflate, "deflate": zip.Deflate,
+		"Store": zip.Store, "xz": 95} {
+		method, err := GetDownloadCompressionMethod(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, expected, method, name)
+	}
+
+	_, err := GetDownloadCompressionMethod("zstd")
+	assert.Error(t, err)
+}
+
+func TestInspectDownloadEncryption(t *testing.T) {
+	entity, err := openpgp.NewEntity("Analyst", "", "analyst@example.com",
+		&packet.Config{RSABits: 1024})
+	require.NoError(t, err)
+
+	armored := &bytes.Buffer{}
+	armor_writer, err := armor.Encode(armored, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(armor_writer))
+	require.NoError(t, armor_writer.Close())
+
+	recipients, err := ParseDownloadPublicKeys(armored.String())
+	require.NoError(t, err)
+
+	filename := "/downloads/hunts/H.1234/H.1234.zip.gpg"
+	buf := &bytes.Buffer{}
+	fd, err := EncryptDownload(buf, filename, recipients)
+	require.NoError(t, err)
+	_, err = fd.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, fd.Close())
+
+	file_store_factory := &memory.MemoryFileStore{
+		Data: map[string][]byte{filename: buf.Bytes()},
+	}
+
+	download_file := &api_proto.AvailableDownloadFile{
+		Name: path.Base(filename),
+		Path: filename,
+	}
+	inspectDownloadEncryption(file_store_factory, download_file)
+
+	assert.Equal(t, DOWNLOAD_ENCRYPTION_PGP, download_file.Encryption)
+	assert.Equal(t, []string{
+		fmt.Sprintf("%016X", entity.Subkeys[0].PublicKey.KeyId)},
+		download_file.EncryptionKeyIds)
+	assert.Contains(t, download_file.DecryptionInstructions,
+		"--output H.1234.zip --decrypt H.1234.zip.gpg")
+	assert.Equal(t, "full", paths.HuntDownloadType(filename))
+
+	// Plain downloads are not encrypted.
+	download_file = &api_proto.AvailableDownloadFile{Name: "H.1234.zip"}
+	inspectDownloadEncryption(file_store_factory, download_file)
+	assert.Equal(t, "", download_file.Encryption)
+}
+
+func (self *HuntTestSuite) TestScheduledHunt() {
+	acl_manager := vql_subsystem.NullACLManager{}
+
+	_, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			Schedule: "not a schedule",
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.Error(self.T(), err)
+
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			HuntDescription:    "Nightly hunt",
+			Schedule:           "0 * * * *",
+			State:              api_proto.Hunt_RUNNING,
+			DisableObfuscation: true,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	get_hunt := func(hunt_id string) *api_proto.Hunt {
+		hunt_obj, err := GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: hunt_id})
+		assert.NoError(self.T(), err)
+		return hunt_obj
+	}
+
+	count_hunts := func() int {
+		result, err := ListHunts(self.config_obj, &api_proto.ListHuntsRequest{
+			Count: 100,
+		})
+		assert.NoError(self.T(), err)
+		return len(result.Items)
+	}
+
+	// The first run is at the top of the next hour.
+	scheduled := get_hunt(hunt_id)
+	first_run := time.Unix(0, int64(scheduled.NextScheduledRun)*1000)
+	assert.Equal(self.T(), 0, first_run.Minute())
+	assert.True(self.T(), first_run.After(time.Now()))
+
+	// Nothing is due yet.
+	assert.NoError(self.T(), RunScheduledHunts(
+		self.ctx, self.config_obj, first_run.Add(-time.Minute)))
+	assert.Equal(self.T(), 1, count_hunts())
+
+	// When the schedule fires a run is cloned from the hunt.
+	assert.NoError(self.T(), RunScheduledHunts(
+		self.ctx, self.config_obj, first_run))
+	assert.Equal(self.T(), 2, count_hunts())
+
+	scheduled = get_hunt(hunt_id)
+	second_run := first_run.Add(time.Hour)
+	assert.Equal(self.T(), uint64(second_run.UnixNano()/1000),
+		scheduled.NextScheduledRun)
+
+	run := get_hunt(scheduled.LastScheduledHuntId)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, run.State)
+	assert.Equal(self.T(), "Nightly hunt", run.HuntDescription)
+	assert.Equal(self.T(), "", run.Schedule)
+	assert.Equal(self.T(), scheduled.NextScheduledRun, run.Expires)
+	assert.Equal(self.T(), hunt_id, run.ScheduledBy)
+	assert.True(self.T(), run.DisableObfuscation)
+
+	// The schedule state survives a reload from the datastore.
+	assert.NoError(self.T(), services.GetHuntDispatcher().Refresh(self.config_obj))
+	assert.Equal(self.T(), scheduled.NextScheduledRun,
+		get_hunt(hunt_id).NextScheduledRun)
+
+	// Runs do not overlap - if the previous run is still going the
+	// tick is skipped.
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId:  run.HuntId,
+		Expires: uint64(second_run.Add(time.Hour).UnixNano() / 1000),
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	assert.NoError(self.T(), RunScheduledHunts(
+		self.ctx, self.config_obj, second_run))
+	assert.Equal(self.T(), 2, count_hunts())
+	assert.Equal(self.T(), run.HuntId, get_hunt(hunt_id).LastScheduledHuntId)
+
+	// Grouped, the run is listed under its scheduled hunt.
+	grouped, err := ListHunts(self.config_obj, &api_proto.ListHuntsRequest{
+		Count:              100,
+		GroupScheduledRuns: true,
+	})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(grouped.Items))
+	assert.Equal(self.T(), hunt_id, grouped.Items[0].HuntId)
+	assert.Equal(self.T(), 1, len(grouped.Items[0].ScheduledRuns))
+	assert.Equal(self.T(), run.HuntId, grouped.Items[0].ScheduledRuns[0].HuntId)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING,
+		grouped.Items[0].ScheduledRuns[0].State)
+
+	// The dispatcher's hunt is not modified.
+	assert.Equal(self.T(), 0, len(get_hunt(hunt_id).ScheduledRuns))
+}
+
+func (self *HuntTestSuite) TestGetHuntErrors() {
+	hunt_id := "H.1234"
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	// Three failed flows with two distinct errors and one
+	// successful flow.
+	collections := []struct {
+		client_id string
+		state     flows_proto.ArtifactCollectorContext_State
+		status    string
+	}{
+		{"C.1", flows_proto.ArtifactCollectorContext_ERROR, "Permission denied"},
+		{"C.2", flows_proto.ArtifactCollectorContext_FINISHED, ""},
+		{"C.3", flows_proto.ArtifactCollectorContext_ERROR, "Timeout"},
+		{"C.4", flows_proto.ArtifactCollectorContext_ERROR, "Permission denied"},
+	}
+
+	rows := []*ordereddict.Dict{}
+	for _, flow := range collections {
+		err := db.SetSubject(self.config_obj,
+			paths.NewFlowPathManager(flow.client_id, "F.1").Path(),
+			&flows_proto.ArtifactCollectorContext{
+				SessionId: "F.1",
+				ClientId:  flow.client_id,
+				State:     flow.state,
+				Status:    flow.status,
+			})
+		assert.NoError(self.T(), err)
+
+		rows = append(rows, ordereddict.NewDict().
+			Set("HuntId", hunt_id).
+			Set("ClientId", flow.client_id).
+			Set("FlowId", "F.1"))
+	}
+
+	journal, err := services.GetJournal()
+	assert.NoError(self.T(), err)
+
+	err = journal.PushRows(self.config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), rows)
+	assert.NoError(self.T(), err)
+
+	result, err := GetHuntErrors(self.ctx, self.config_obj, hunt_id, 1, 10)
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), uint64(3), result.Total)
+	assert.Equal(self.T(), 2, len(result.Items))
+	assert.Equal(self.T(), "C.3", result.Items[0].ClientId)
+	assert.Equal(self.T(), "Timeout", result.Items[0].Error)
+
+	assert.Equal(self.T(), 2, len(result.Groups))
+	assert.Equal(self.T(), "Permission denied", result.Groups[0].Error)
+	assert.Equal(self.T(), uint64(2), result.Groups[0].Count)
+	assert.Equal(self.T(), uint64(1), result.Groups[1].Count)
+}
+
+func (self *HuntTestSuite) TestSummarizeHunt() {
+	hunt_id := "H.1234"
+	source := "Generic.Client.Info/Users"
+
+	journal, err := services.GetJournal()
+	assert.NoError(self.T(), err)
+
+	// Each client returns some users.
+	users := map[string][]interface{}{
+		"C.1": {"alice", "bob", "root"},
+		"C.2": {"root", "bob"},
+		"C.3": {"root", 1, nil},
+	}
+
+	participation := []*ordereddict.Dict{}
+	for client_id, names := range users {
+		participation = append(participation, ordereddict.NewDict().
+			Set("HuntId", hunt_id).
+			Set("ClientId", client_id).
+			Set("FlowId", "F.1"))
+
+		rows := []*ordereddict.Dict{}
+		for _, name := range names {
+			rows = append(rows, ordereddict.NewDict().Set("Name", name))
+		}
+		rows = append(rows, ordereddict.NewDict().Set("Other", 1))
+
+		err = journal.PushRows(self.config_obj,
+			artifacts.NewArtifactPathManager(
+				self.config_obj, client_id, "F.1", source), rows)
+		assert.NoError(self.T(), err)
+	}
+
+	// A client may be listed more than once.
+	participation = append(participation, participation[0])
+	err = journal.PushRows(self.config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), participation)
+	assert.NoError(self.T(), err)
+
+	summary, err := SummarizeHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, hunt_id, source, "Name", 2)
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), uint64(11), summary.TotalRows)
+	assert.Equal(self.T(), uint64(3), summary.MissingRows)
+	assert.Equal(self.T(), uint64(5), summary.DistinctValues)
+	assert.Equal(self.T(), []*HuntValueCount{
+		{Value: "root", Count: 3},
+		{Value: "bob", Count: 2},
+	}, summary.Top)
+
+	// Users need to be able to read results.
+	_, err = SummarizeHunt(self.ctx, self.config_obj,
+		vql_subsystem.NewServerACLManager(self.config_obj, "UserX"),
+		hunt_id, source, "Name", 2)
+	assert.Error(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestCreateHuntArtifactPermissions() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Execve
+required_permissions:
+  - EXECVE
+sources:
+- query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	request := func() *api_proto.Hunt {
+		return &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info", "Test.Artifact.Execve"},
+			},
+		}
+	}
+
+	permissions, err := GetHuntRequiredPermissions(self.config_obj, request())
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), []string{"EXECVE"}, permissions)
+
+	acl_manager := vql_subsystem.NewServerACLManager(self.config_obj, "UserX")
+	_, err = CreateHunt(self.ctx, self.config_obj, acl_manager, request())
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "Test.Artifact.Execve")
+	assert.Contains(self.T(), err.Error(), "EXECVE")
+
+	result, err := ListHunts(self.config_obj, &api_proto.ListHuntsRequest{Count: 10})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 0, len(result.Items))
+
+	err = acls.SetPolicy(self.config_obj, "UserX",
+		&acl_proto.ApiClientACL{Execve: true})
+	assert.NoError(self.T(), err)
+
+	acl_manager = vql_subsystem.NewServerACLManager(self.config_obj, "UserX")
+	_, err = CreateHunt(self.ctx, self.config_obj, acl_manager, request())
+	assert.NoError(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestGetHuntPermissions() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Execve
+required_permissions:
+  - EXECVE
+sources:
+- query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	// Permissions sent with the request are not stored.
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Test.Artifact.Execve"},
+			},
+			Permissions: &api_proto.HuntPermissions{CanStart: true},
+		})
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Nil(self.T(), hunt_obj.Permissions)
+
+	for user, policy := range map[string]*acl_proto.ApiClientACL{
+		"Reader":       {ReadResults: true},
+		"Investigator": {CollectClient: true},
+		"Admin":        {CollectClient: true, Execve: true},
+	} {
+		err = acls.SetPolicy(self.config_obj, user, policy)
+		assert.NoError(self.T(), err)
+	}
+
+	permissions := func(user string) *api_proto.HuntPermissions {
+		hunt_obj, err := GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: hunt_id})
+		assert.NoError(self.T(), err)
+
+		result, err := GetHuntPermissions(self.config_obj,
+			vql_subsystem.NewServerACLManager(self.config_obj, user), hunt_obj)
+		assert.NoError(self.T(), err)
+		return result
+	}
+
+	assert.Equal(self.T(), &api_proto.HuntPermissions{}, permissions("Reader"))
+
+	// Starting the hunt needs EXECVE for its artifact.
+	assert.Equal(self.T(), &api_proto.HuntPermissions{
+		CanModify: true, CanArchive: true}, permissions("Investigator"))
+	assert.Equal(self.T(), &api_proto.HuntPermissions{
+		CanModify: true, CanArchive: true, CanStart: true}, permissions("Admin"))
+
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_id,
+		State:  api_proto.Hunt_RUNNING,
+	}, "Admin")
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), &api_proto.HuntPermissions{
+		CanModify: true, CanArchive: true, CanStop: true}, permissions("Investigator"))
+
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_id,
+		State:  api_proto.Hunt_ARCHIVED,
+	}, "Admin")
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), &api_proto.HuntPermissions{}, permissions("Admin"))
+}
+
+func (self *HuntTestSuite) TestGetHuntActivity() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			HuntDescription: "My hunt",
+			Creator:         "admin",
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	// Nothing has been journaled yet.
+	result, err := GetHuntActivity(self.ctx, self.config_obj, hunt_id, 0, 10)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(1), result.Total)
+	assert.Equal(self.T(), "Created", result.Items[0].Type)
+	assert.Equal(self.T(), "admin", result.Items[0].User)
+	assert.Equal(self.T(), "My hunt", result.Items[0].Description)
+
+	for _, state := range []api_proto.Hunt_State{
+		api_proto.Hunt_RUNNING, api_proto.Hunt_STOPPED} {
+		err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+			HuntId: hunt_id,
+			State:  state,
+		}, "admin")
+		assert.NoError(self.T(), err)
+	}
+
+	err = AddHuntNote(self.config_obj, hunt_id, "analyst", "Looks clean")
+	assert.NoError(self.T(), err)
+
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_id,
+		State:  api_proto.Hunt_ARCHIVED,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	result, err = GetHuntActivity(self.ctx, self.config_obj, hunt_id, 0, 10)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(5), result.Total)
+
+	descriptions := []string{}
+	for i, item := range result.Items {
+		descriptions = append(descriptions, item.Description)
+		if i > 0 {
+			assert.True(self.T(),
+				result.Items[i-1].Timestamp <= item.Timestamp)
+		}
+	}
+	assert.ElementsMatch(self.T(), []string{
+		"My hunt", "PAUSED -> RUNNING", "RUNNING -> STOPPED",
+		"Looks clean", "STOPPED -> ARCHIVED"}, descriptions)
+
+	page, err := GetHuntActivity(self.ctx, self.config_obj, hunt_id, 3, 10)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(5), page.Total)
+	assert.Equal(self.T(), 2, len(page.Items))
+	assert.Equal(self.T(), result.Items[3].Description, page.Items[0].Description)
+
+	_, err = GetHuntActivity(self.ctx, self.config_obj, "H.Missing", 0, 10)
+	assert.Error(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestGetHuntActivityJournalLimit() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	for i := 0; i < 5; i++ {
+		EmitHuntStateChange(self.config_obj, hunt_id,
+			api_proto.Hunt_PAUSED, api_proto.Hunt_RUNNING, "admin",
+			fmt.Sprintf("Change %v", i))
+	}
+
+	old_limit := max_hunt_activity_journal_rows
+	defer func() { max_hunt_activity_journal_rows = old_limit }()
+
+	// Reading stops at the limit, keeping the oldest rows.
+	max_hunt_activity_journal_rows = 3
+	result, err := GetHuntActivity(self.ctx, self.config_obj, hunt_id, 0, 10)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(4), result.Total)
+	assert.Equal(self.T(), "PAUSED -> RUNNING (Change 2)",
+		result.Items[3].Description)
+
+	max_hunt_activity_journal_rows = old_limit
+	result, err = GetHuntActivity(self.ctx, self.config_obj, hunt_id, 0, 10)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(6), result.Total)
+}
+
+func (self *HuntTestSuite) TestFindHuntsByArtifact() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Sources
+sources:
+- name: First
+  query: SELECT * FROM info()
+- name: Second
+  query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_ids := []string{}
+	for _, artifacts := range [][]string{
+		{"Test.Artifact.Sources"},
+		{"Generic.Client.Info", "Test.Artifact.Sources"},
+		{"Generic.Client.Info"},
+	} {
+		hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+			&api_proto.Hunt{
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: artifacts,
+				},
+			})
+		assert.NoError(self.T(), err)
+		hunt_ids = append(hunt_ids, hunt_id)
+	}
+
+	found := func(name string, include_sources bool) []string {
+		hunts, err := FindHuntsByArtifact(self.config_obj, name, include_sources)
+		assert.NoError(self.T(), err)
+
+		result := []string{}
+		for _, hunt := range hunts {
+			result = append(result, hunt.HuntId)
+		}
+		return result
+	}
+
+	assert.ElementsMatch(self.T(), hunt_ids[:2],
+		found("Test.Artifact.Sources", false))
+	assert.ElementsMatch(self.T(), hunt_ids[1:],
+		found("Generic.Client.Info", false))
+
+	// Names must match exactly.
+	assert.Empty(self.T(), found("Test.Artifact", true))
+
+	// Sources only match when asked for.
+	assert.Empty(self.T(), found("Test.Artifact.Sources/Second", false))
+	assert.ElementsMatch(self.T(), hunt_ids[:2],
+		found("Test.Artifact.Sources/Second", true))
+}
+
+func (self *HuntTestSuite) TestCloneHunt() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	label_condition := &api_proto.HuntCondition{
+		Labels: &api_proto.HuntLabelCondition{
+			Label: []string{"Workstations"},
+		},
+	}
+
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			HuntDescription:    "Original",
+			ClientLimit:        10,
+			Condition:          label_condition,
+			DisableObfuscation: true,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+				Specs: []*flows_proto.ArtifactSpec{{
+					Artifact: "Generic.Client.Info",
+					Parameters: &flows_proto.ArtifactParameters{
+						Env: []*actions_proto.VQLEnv{{
+							Key:   "Parameter",
+							Value: "Value",
+						}},
+					},
+				}},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	original, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+
+	// Run the same collection on the Windows clients instead.
+	os_condition := &api_proto.HuntCondition{
+		Os: &api_proto.HuntOsCondition{
+			Os: api_proto.HuntOsCondition_WINDOWS,
+		},
+	}
+	clone_id, err := CloneHunt(self.ctx, self.config_obj, acl_manager,
+		hunt_id, os_condition)
+	assert.NoError(self.T(), err)
+	assert.NotEqual(self.T(), hunt_id, clone_id)
+
+	clone, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: clone_id})
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), api_proto.Hunt_PAUSED, clone.State)
+	assert.Equal(self.T(), "Original", clone.HuntDescription)
+	assert.Equal(self.T(), uint64(10), clone.ClientLimit)
+	assert.True(self.T(), clone.DisableObfuscation)
+	assert.True(self.T(), proto.Equal(os_condition, clone.Condition))
+	assert.True(self.T(), proto.Equal(original.StartRequest, clone.StartRequest))
+
+	// Without a condition the original condition is kept.
+	clone_id, err = CloneHunt(self.ctx, self.config_obj, acl_manager,
+		hunt_id, nil)
+	assert.NoError(self.T(), err)
+
+	clone, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: clone_id})
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), proto.Equal(label_condition, clone.Condition))
+
+	// The new condition is validated.
+	_, err = CloneHunt(self.ctx, self.config_obj, acl_manager, hunt_id,
+		&api_proto.HuntCondition{
+			Labels: &api_proto.HuntLabelCondition{},
+		})
+	assert.Error(self.T(), err)
+
+	_, err = CloneHunt(self.ctx, self.config_obj, acl_manager, "H.Missing", nil)
+	assert.Error(self.T(), err)
+}
+
 func TestHunts(t *testing.T) {
 	suite.Run(t, &HuntTestSuite{})
 }
+
+func (self *HuntTestSuite) TestHuntDispatcherNotReady() {
+	dispatcher := services.GetHuntDispatcher()
+	assert.True(self.T(), services.HuntDispatcherReady())
+
+	// A nil callback is an error rather than a crash under lock.
+	assert.Error(self.T(), dispatcher.ApplyFuncOnHunts(nil))
+
+	// Simulate the server still starting up.
+	services.RegisterHuntDispatcher(nil)
+	defer services.RegisterHuntDispatcher(dispatcher)
+
+	assert.False(self.T(), services.HuntDispatcherReady())
+
+	_, err := ListHunts(self.config_obj, &api_proto.ListHuntsRequest{})
+	assert.True(self.T(), errors.Is(err, services.ErrHuntDispatcherNotReady))
+
+	_, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: "H.1234"})
+	assert.True(self.T(), errors.Is(err, services.ErrHuntDispatcherNotReady))
+
+	_, err = FindHuntsByArtifact(self.config_obj, "Generic.Client.Info", false)
+	assert.True(self.T(), errors.Is(err, services.ErrHuntDispatcherNotReady))
+
+	// Other failures are distinguishable.
+	services.RegisterHuntDispatcher(dispatcher)
+	_, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: "H.1234"})
+	assert.Error(self.T(), err)
+	assert.False(self.T(), errors.Is(err, services.ErrHuntDispatcherNotReady))
+}
+
+func (self *HuntTestSuite) TestHuntLabelParametersValidation() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Params
+parameters:
+- name: Path
+  default: /tmp
+sources:
+- query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	create := func(label string, spec *flows_proto.ArtifactSpec) error {
+		_, err := CreateHunt(self.ctx, self.config_obj,
+			vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: []string{"Test.Artifact.Params"},
+				},
+				LabelParameters: []*api_proto.HuntLabelParameters{{
+					Label: label,
+					Specs: []*flows_proto.ArtifactSpec{spec},
+				}},
+			})
+		return err
+	}
+
+	parameters := func(key string) *flows_proto.ArtifactParameters {
+		return &flows_proto.ArtifactParameters{
+			Env: []*actions_proto.VQLEnv{{Key: key, Value: "/var"}},
+		}
+	}
+
+	assert.NoError(self.T(), create("Linux", &flows_proto.ArtifactSpec{
+		Artifact: "Test.Artifact.Params", Parameters: parameters("Path")}))
+
+	// A label is required.
+	assert.Error(self.T(), create(" ", &flows_proto.ArtifactSpec{
+		Artifact: "Test.Artifact.Params", Parameters: parameters("Path")}))
+
+	// Only parameters the artifact defines may be overridden.
+	assert.Error(self.T(), create("Linux", &flows_proto.ArtifactSpec{
+		Artifact: "Test.Artifact.Params", Parameters: parameters("Pth")}))
+
+	// Only artifacts the hunt collects may be overridden.
+	assert.Error(self.T(), create("Linux", &flows_proto.ArtifactSpec{
+		Artifact: "Generic.Client.Info", Parameters: parameters("Path")}))
+}
+
+func (self *HuntTestSuite) TestGetHuntStaleDefinition() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Stale
+sources:
+- query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Test.Artifact.Stale", "Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(hunt_obj.ArtifactHashes))
+	assert.False(self.T(), hunt_obj.StaleDefinition)
+	assert.Empty(self.T(), hunt_obj.ChangedArtifacts)
+	compiled := hunt_obj.StartRequest.CompiledCollectorArgs
+
+	// Edit the artifact after the hunt was created.
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Stale
+sources:
+- query: SELECT * FROM pslist()
+`, true)
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), hunt_obj.StaleDefinition)
+	assert.Equal(self.T(), []string{"Test.Artifact.Stale"},
+		hunt_obj.ChangedArtifacts)
+
+	// The hunt still runs what it was compiled with.
+	assert.True(self.T(), proto.Equal(compiled[0],
+		hunt_obj.StartRequest.CompiledCollectorArgs[0]))
+}
+
+func (self *HuntTestSuite) TestHuntNotificationBatches() {
+	// Only the first batch is sent before the test ends.
+	self.config_obj.Defaults = &config_proto.Defaults{
+		HuntNotificationBatchSize:    2,
+		HuntNotificationBatchDelayMs: 3600 * 1000,
+	}
+
+	notifications := []chan bool{}
+	for i := 0; i < 5; i++ {
+		notification, cancel := services.GetNotifier().ListenForNotification(
+			fmt.Sprintf("C.%d", i))
+		defer cancel()
+		notifications = append(notifications, notification)
+	}
+
+	_, err := CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+			State: api_proto.Hunt_RUNNING,
+		})
+	assert.NoError(self.T(), err)
+
+	notified := func() int {
+		result := 0
+		for _, notification := range notifications {
+			select {
+			case <-notification:
+				result++
+			default:
+			}
+		}
+		return result
+	}
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		return notified() == 2
+	})
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(self.T(), 2, notified())
+
+	// Shutting down abandons the remaining batches rather than
+	// waiting for them.
+	start := time.Now()
+	self.sm.Close()
+	assert.True(self.T(), time.Now().Sub(start) < 5*time.Second)
+}
+
+func (self *HuntTestSuite) TestExportHuntStatusCSV() {
+	hunt_id := "H.1235"
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	// 2020-09-13T12:26:40Z in microseconds.
+	active_time := uint64(1600000000000000)
+
+	collections := []*flows_proto.ArtifactCollectorContext{
+		{
+			SessionId:          "F.1",
+			ClientId:           "C.1",
+			State:              flows_proto.ArtifactCollectorContext_FINISHED,
+			ActiveTime:         active_time,
+			TotalCollectedRows: 10,
+		},
+		{
+			SessionId:  "F.1",
+			ClientId:   "C.2",
+			State:      flows_proto.ArtifactCollectorContext_ERROR,
+			ActiveTime: active_time,
+			Status:     `Access denied, path "C:\Windows"`,
+		},
+		{
+			SessionId:          "F.1",
+			ClientId:           "C.3",
+			State:              flows_proto.ArtifactCollectorContext_RUNNING,
+			ActiveTime:         active_time,
+			TotalCollectedRows: 5,
+		},
+	}
+
+	rows := []*ordereddict.Dict{}
+	for _, collection := range collections {
+		err := db.SetSubject(self.config_obj,
+			paths.NewFlowPathManager(collection.ClientId, "F.1").Path(),
+			collection)
+		assert.NoError(self.T(), err)
+
+		rows = append(rows, ordereddict.NewDict().
+			Set("HuntId", hunt_id).
+			Set("ClientId", collection.ClientId).
+			Set("FlowId", "F.1"))
+	}
+
+	// A duplicate entry and a client whose flow is missing.
+	rows = append(rows,
+		ordereddict.NewDict().
+			Set("HuntId", hunt_id).
+			Set("ClientId", "C.1").
+			Set("FlowId", "F.1"),
+		ordereddict.NewDict().
+			Set("HuntId", hunt_id).
+			Set("ClientId", "C.4").
+			Set("FlowId", "F.2"))
+
+	journal, err := services.GetJournal()
+	assert.NoError(self.T(), err)
+
+	err = journal.PushRows(self.config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), rows)
+	assert.NoError(self.T(), err)
+
+	buffer := &bytes.Buffer{}
+	err = ExportHuntStatusCSV(self.ctx, self.config_obj, hunt_id, buffer)
+	assert.NoError(self.T(), err)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Equal(self.T(), 5, len(lines))
+	assert.Equal(self.T(),
+		"client_id,hostname,state,completion_time,row_count,error", lines[0])
+	assert.Equal(self.T(), "C.1,,FINISHED,2020-09-13T12:26:40Z,10,", lines[1])
+	assert.Equal(self.T(),
+		`C.2,,ERROR,2020-09-13T12:26:40Z,0,"Access denied, path ""C:\Windows"""`,
+		lines[2])
+	assert.Equal(self.T(), "C.3,,RUNNING,,5,", lines[3])
+	assert.True(self.T(), strings.HasPrefix(lines[4], "C.4,,UNKNOWN,,,"))
+}
+
+func (self *HuntTestSuite) TestHuntMatchMetrics() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	for _, state := range []api_proto.Hunt_State{
+		api_proto.Hunt_RUNNING, api_proto.Hunt_UNSET} {
+		_, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+			&api_proto.Hunt{
+				State: state,
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: []string{"Generic.Client.Info"},
+				},
+			})
+		assert.NoError(self.T(), err)
+	}
+
+	start := testutil.ToFloat64(huntMatchEvaluationsCounter)
+
+	// Both hunts are evaluated but only the running one matches.
+	hunts, err := huntsNewerThan(services.GetHuntDispatcher(), 0)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 1, len(hunts))
+	assert.Equal(self.T(), float64(2),
+		testutil.ToFloat64(huntMatchEvaluationsCounter)-start)
+}
+
+func (self *HuntTestSuite) TestHuntMinClientVersion() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	new_hunt := func(version string) *api_proto.Hunt {
+		return &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+			Condition: &api_proto.HuntCondition{
+				MinClientVersion: version,
+			},
+		}
+	}
+
+	_, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt("not a version"))
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "Invalid minimum client version")
+
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		new_hunt("0.5.4"))
+	assert.NoError(self.T(), err)
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	// An old, a current, a newer and an unknown version client.
+	for client_id, version := range map[string]string{
+		"C.1": "0.5.3", "C.2": "0.5.4", "C.3": "0.6.0", "C.4": ""} {
+		err = db.SetSubject(self.config_obj,
+			paths.NewClientPathManager(client_id).Path(),
+			&actions_proto.ClientInfo{ClientVersion: version})
+		assert.NoError(self.T(), err)
+
+		err = db.SetIndex(self.config_obj, constants.CLIENT_INDEX_URN,
+			client_id, []string{"all"})
+		assert.NoError(self.T(), err)
+	}
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+
+	count, err := EstimateHunt(self.ctx, self.config_obj, hunt_obj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(2), count)
+
+	// Clients with unknown versions may be matched instead.
+	hunt_obj.Condition.UnknownVersionPolicy =
+		api_proto.HuntCondition_MATCH_UNKNOWN_VERSION
+	count, err = EstimateHunt(self.ctx, self.config_obj, hunt_obj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(3), count)
+
+	// A maximum version restricts the hunt to a range of versions.
+	hunt_obj.Condition.UnknownVersionPolicy =
+		api_proto.HuntCondition_SKIP_UNKNOWN_VERSION
+	hunt_obj.Condition.MaxClientVersion = "0.5.9"
+	count, err = EstimateHunt(self.ctx, self.config_obj, hunt_obj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(1), count)
+
+	hunt_obj.Condition.MinClientVersion = ""
+	count, err = EstimateHunt(self.ctx, self.config_obj, hunt_obj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(2), count)
+
+	range_hunt := new_hunt("0.6.0")
+	range_hunt.Condition.MaxClientVersion = "0.5.4"
+	_, err = CreateHunt(self.ctx, self.config_obj, acl_manager, range_hunt)
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "above the maximum client version")
+
+	range_hunt.Condition.MaxClientVersion = "latest"
+	_, err = CreateHunt(self.ctx, self.config_obj, acl_manager, range_hunt)
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "Invalid maximum client version")
+}
+
+func (self *HuntTestSuite) TestRecompileHunt() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Recompile
+sources:
+- query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+		&api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Test.Artifact.Recompile"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	compiled := hunt_obj.StartRequest.CompiledCollectorArgs
+
+	// Fix the artifact after the hunt was created.
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Recompile
+sources:
+- query: SELECT * FROM pslist()
+`, true)
+	assert.NoError(self.T(), err)
+
+	warning, err := RecompileHunt(self.ctx, self.config_obj, acl_manager, hunt_id)
+	assert.NoError(self.T(), err)
+	assert.Contains(self.T(), warning, "already scheduled")
+
+	hunt_obj, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.False(self.T(), hunt_obj.StaleDefinition)
+	assert.Equal(self.T(), len(compiled),
+		len(hunt_obj.StartRequest.CompiledCollectorArgs))
+	assert.False(self.T(), proto.Equal(compiled[0],
+		hunt_obj.StartRequest.CompiledCollectorArgs[0]))
+
+	// The recompiled request is what is stored.
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	stored := &api_proto.Hunt{}
+	err = db.GetSubject(self.config_obj,
+		paths.NewHuntPathManager(hunt_id).Path(), stored)
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), proto.Equal(
+		hunt_obj.StartRequest.CompiledCollectorArgs[0],
+		stored.StartRequest.CompiledCollectorArgs[0]))
+
+	activity, err := GetHuntActivity(self.ctx, self.config_obj, hunt_id, 0, 10)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(2), activity.Total)
+	assert.Equal(self.T(), "Recompiled", activity.Items[1].Type)
+
+	// Running hunts can not be recompiled.
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_id,
+		State:  api_proto.Hunt_RUNNING,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	_, err = RecompileHunt(self.ctx, self.config_obj, acl_manager, hunt_id)
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "Only paused hunts")
+}
+
+func (self *HuntTestSuite) TestCancelHuntFlows() {
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			State: api_proto.Hunt_RUNNING,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	journal, err := services.GetJournal()
+	assert.NoError(self.T(), err)
+
+	schedule := func(client_id string,
+		state flows_proto.ArtifactCollectorContext_State) {
+		err := db.SetSubject(self.config_obj,
+			paths.NewFlowPathManager(client_id, "F.1").Path(),
+			&flows_proto.ArtifactCollectorContext{
+				SessionId: "F.1",
+				ClientId:  client_id,
+				State:     state,
+				Request:   &flows_proto.ArtifactCollectorArgs{},
+			})
+		assert.NoError(self.T(), err)
+
+		err = journal.PushRows(self.config_obj,
+			paths.NewHuntPathManager(hunt_id).Clients(),
+			[]*ordereddict.Dict{ordereddict.NewDict().
+				Set("HuntId", hunt_id).
+				Set("ClientId", client_id).
+				Set("FlowId", "F.1")})
+		assert.NoError(self.T(), err)
+	}
+
+	schedule("C.1", flows_proto.ArtifactCollectorContext_RUNNING)
+	schedule("C.2", flows_proto.ArtifactCollectorContext_FINISHED)
+
+	// Only the running flow is cancelled. The client is not
+	// connected so it gets the cancellation when it next polls.
+	result, err := CancelHuntFlows(self.ctx, self.config_obj, hunt_id, "admin")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(1), result.Cancelled)
+	assert.Equal(self.T(), uint64(1), result.Offline)
+
+	state := func(client_id string) string {
+		collection_context, err := LoadCollectionContext(
+			self.config_obj, client_id, "F.1")
+		assert.NoError(self.T(), err)
+		return collection_context.State.String() + " " + collection_context.Status
+	}
+	assert.Equal(self.T(), "ERROR Cancelled by admin", state("C.1"))
+	assert.Equal(self.T(), "FINISHED ", state("C.2"))
+
+	cancelled := func(client_id string) bool {
+		tasks, err := db.GetClientTasks(self.config_obj, client_id, true)
+		assert.NoError(self.T(), err)
+		for _, task := range tasks {
+			if task.Cancel != nil && task.SessionId == "F.1" {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(self.T(), cancelled("C.1"))
+	assert.False(self.T(), cancelled("C.2"))
+
+	// Stopping the hunt may cancel its flows too.
+	schedule("C.3", flows_proto.ArtifactCollectorContext_RUNNING)
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId:      hunt_id,
+		State:       api_proto.Hunt_STOPPED,
+		CancelFlows: true,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	assert.Equal(self.T(), "ERROR Cancelled by admin", state("C.3"))
+	assert.True(self.T(), cancelled("C.3"))
+}
+
+func (self *HuntTestSuite) TestExcludeHuntClients() {
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			State: api_proto.Hunt_RUNNING,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	journal, err := services.GetJournal()
+	assert.NoError(self.T(), err)
+
+	for _, client_id := range []string{"C.1", "C.2"} {
+		err := db.SetSubject(self.config_obj,
+			paths.NewFlowPathManager(client_id, "F.1").Path(),
+			&flows_proto.ArtifactCollectorContext{
+				SessionId: "F.1",
+				ClientId:  client_id,
+				State:     flows_proto.ArtifactCollectorContext_RUNNING,
+				Request:   &flows_proto.ArtifactCollectorArgs{},
+			})
+		assert.NoError(self.T(), err)
+
+		err = journal.PushRows(self.config_obj,
+			paths.NewHuntPathManager(hunt_id).Clients(),
+			[]*ordereddict.Dict{ordereddict.NewDict().
+				Set("HuntId", hunt_id).
+				Set("ClientId", client_id).
+				Set("FlowId", "F.1")})
+		assert.NoError(self.T(), err)
+	}
+
+	get_hunt := func() *api_proto.Hunt {
+		hunt_obj, err := GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: hunt_id})
+		assert.NoError(self.T(), err)
+		return hunt_obj
+	}
+
+	state := func(client_id string) flows_proto.ArtifactCollectorContext_State {
+		collection_context, err := LoadCollectionContext(
+			self.config_obj, client_id, "F.1")
+		assert.NoError(self.T(), err)
+		return collection_context.State
+	}
+
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId:          hunt_id,
+		ExcludedClients: []string{"C.1", "C.1", "C.3"},
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	// Only the excluded client's flow is cancelled and the hunt
+	// keeps running for everyone else.
+	hunt_obj := get_hunt()
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, hunt_obj.State)
+	assert.Equal(self.T(), []string{"C.1", "C.3"}, hunt_obj.ExcludedClients)
+	assert.True(self.T(), HuntExcludesClient(hunt_obj, "C.1"))
+	assert.False(self.T(), HuntExcludesClient(hunt_obj, "C.2"))
+
+	assert.Equal(self.T(), flows_proto.ArtifactCollectorContext_ERROR, state("C.1"))
+	assert.Equal(self.T(), flows_proto.ArtifactCollectorContext_RUNNING, state("C.2"))
+
+	// The exclusions are persisted.
+	stored := &api_proto.Hunt{}
+	err = db.GetSubject(self.config_obj,
+		paths.NewHuntPathManager(hunt_id).Path(), stored)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), []string{"C.1", "C.3"}, stored.ExcludedClients)
+
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId:          hunt_id,
+		IncludedClients: []string{"C.3"},
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	hunt_obj = get_hunt()
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, hunt_obj.State)
+	assert.Equal(self.T(), []string{"C.1"}, hunt_obj.ExcludedClients)
+}
+
+func (self *HuntTestSuite) TestHuntTemplates() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Template
+sources:
+- query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	template := &api_proto.HuntTemplate{
+		Name: "Triage",
+		Hunt: &api_proto.Hunt{
+			HuntDescription: "Triage hunt",
+			HuntId:          "H.1234",
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Test.Artifact.Template", "No.Such.Artifact"},
+			},
+			Condition: &api_proto.HuntCondition{
+				Labels: &api_proto.HuntLabelCondition{
+					Label: []string{"Triage"},
+				},
+			},
+		},
+	}
+
+	// All the artifacts must exist when the template is saved.
+	err = SaveHuntTemplate(self.config_obj, template)
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "No.Such.Artifact")
+
+	template.Hunt.StartRequest.Artifacts = []string{"Test.Artifact.Template"}
+	template.Creator = "admin"
+	err = SaveHuntTemplate(self.config_obj, template)
+	assert.NoError(self.T(), err)
+
+	// Updating the template keeps its creator and drops the
+	// schedule state of the hunt it was saved from.
+	template.Creator = "other"
+	template.Description = "Updated"
+	template.Hunt.LastScheduledHuntId = "H.5678"
+	template.Hunt.ScheduledBy = "H.5678"
+	err = SaveHuntTemplate(self.config_obj, template)
+	assert.NoError(self.T(), err)
+
+	saved, err := GetHuntTemplate(self.config_obj, "Triage")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "admin", saved.Creator)
+	assert.Equal(self.T(), "Updated", saved.Description)
+	assert.Equal(self.T(), "", saved.Hunt.LastScheduledHuntId)
+	assert.Equal(self.T(), "", saved.Hunt.ScheduledBy)
+
+	err = SaveHuntTemplate(self.config_obj, &api_proto.HuntTemplate{
+		Name: "Info",
+		Hunt: &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		},
+	})
+	assert.NoError(self.T(), err)
+
+	templates, err := ListHuntTemplates(self.config_obj)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), 2, len(templates.Items))
+	assert.Equal(self.T(), "Info", templates.Items[0].Name)
+	assert.Equal(self.T(), "Triage", templates.Items[1].Name)
+
+	// Overrides replace the template's fields.
+	acl_manager := vql_subsystem.NullACLManager{}
+	hunt_id, err := CreateHuntFromTemplate(self.ctx, self.config_obj,
+		acl_manager, "Triage", &api_proto.Hunt{
+			HuntDescription: "Triage for incident 42",
+		})
+	assert.NoError(self.T(), err)
+	assert.NotEqual(self.T(), "H.1234", hunt_id)
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "Triage for incident 42", hunt_obj.HuntDescription)
+	assert.Equal(self.T(), []string{"Test.Artifact.Template"},
+		hunt_obj.StartRequest.Artifacts)
+	assert.Equal(self.T(), []string{"Triage"},
+		hunt_obj.Condition.GetLabels().Label)
+
+	hunt_id, err = CreateHuntFromTemplate(self.ctx, self.config_obj,
+		acl_manager, "Triage", &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), "Triage hunt", hunt_obj.HuntDescription)
+	assert.Equal(self.T(), []string{"Generic.Client.Info"},
+		hunt_obj.StartRequest.Artifacts)
+
+	// Removing an artifact only warns about the template.
+	repository.Del("Test.Artifact.Template")
+	saved, err = GetHuntTemplate(self.config_obj, "Triage")
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), []string{
+		"Artifact Test.Artifact.Template no longer exists"}, saved.Warnings)
+
+	err = DeleteHuntTemplate(self.config_obj, "Triage")
+	assert.NoError(self.T(), err)
+
+	_, err = GetHuntTemplate(self.config_obj, "Triage")
+	assert.Error(self.T(), err)
+
+	_, err = CreateHuntFromTemplate(self.ctx, self.config_obj,
+		acl_manager, "Triage", nil)
+	assert.Error(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestHuntDisableObfuscation() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	compiled_names := func(disable_obfuscation bool) []string {
+		hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+			&api_proto.Hunt{
+				HuntDescription:    "Obfuscation",
+				DisableObfuscation: disable_obfuscation,
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: []string{"Generic.Client.Info"},
+				},
+			})
+		assert.NoError(self.T(), err)
+
+		hunt_obj, err := GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: hunt_id})
+		assert.NoError(self.T(), err)
+		assert.Equal(self.T(), disable_obfuscation, hunt_obj.DisableObfuscation)
+
+		names := []string{}
+		for _, compiled := range hunt_obj.StartRequest.CompiledCollectorArgs {
+			for _, query := range compiled.Query {
+				if query.Name != "" {
+					names = append(names, query.Name)
+				}
+			}
+		}
+		assert.NotEmpty(self.T(), names)
+		return names
+	}
+
+	// By default the query names sent to the clients are encrypted.
+	for _, name := range compiled_names(false) {
+		assert.NotContains(self.T(), name, "Generic.Client.Info")
+	}
+
+	for _, name := range compiled_names(true) {
+		assert.Contains(self.T(), name, "Generic.Client.Info")
+	}
+}
+
+func (self *HuntTestSuite) TestEvictHunt() {
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			HuntDescription: "Original",
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	dispatcher := services.GetHuntDispatcher()
+	get_description := func() string {
+		description := ""
+		_ = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+			if hunt.HuntId == hunt_id {
+				description = hunt.HuntDescription
+			}
+			return nil
+		})
+		return description
+	}
+	assert.Equal(self.T(), "Original", get_description())
+
+	// Edit the hunt directly in the data store.
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_id)
+	hunt_obj := &api_proto.Hunt{}
+	assert.NoError(self.T(), db.GetSubject(
+		self.config_obj, hunt_path_manager.Path(), hunt_obj))
+	hunt_obj.HuntDescription = "Edited"
+	assert.NoError(self.T(), db.SetSubject(
+		self.config_obj, hunt_path_manager.Path(), hunt_obj))
+
+	// The dispatcher still has the old hunt until it is evicted.
+	assert.Equal(self.T(), "Original", get_description())
+	assert.NoError(self.T(), dispatcher.EvictHunt(self.config_obj, hunt_id))
+	assert.Equal(self.T(), "Edited", get_description())
+
+	// Hunts which are gone from the data store are dropped.
+	assert.NoError(self.T(), db.DeleteSubject(
+		self.config_obj, hunt_path_manager.Path()))
+	assert.NoError(self.T(), dispatcher.EvictHunt(self.config_obj, hunt_id))
+	assert.Equal(self.T(), "", get_description())
+
+	assert.Error(self.T(), dispatcher.EvictHunt(self.config_obj, "../etc"))
+}
+
+func (self *HuntTestSuite) TestHuntClientSnapshot() {
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	add_client := func(client_id, version string) {
+		err := db.SetSubject(self.config_obj,
+			paths.NewClientPathManager(client_id).Path(),
+			&actions_proto.ClientInfo{ClientVersion: version})
+		assert.NoError(self.T(), err)
+
+		err = db.SetIndex(self.config_obj, constants.CLIENT_INDEX_URN,
+			client_id, []string{"all"})
+		assert.NoError(self.T(), err)
+	}
+
+	get_snapshot := func(hunt_id string) []string {
+		rs_reader, err := result_sets.NewResultSetReader(
+			file_store.GetFileStore(self.config_obj),
+			paths.NewHuntPathManager(hunt_id).ClientSnapshot())
+		if err != nil {
+			return nil
+		}
+		defer rs_reader.Close()
+
+		result := []string{}
+		for row := range rs_reader.Rows(self.ctx) {
+			client_id, _ := row.GetString("ClientId")
+			result = append(result, client_id)
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	add_client("C.1", "0.5.3")
+	add_client("C.2", "0.6.0")
+
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			SnapshotClients: true,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+			Condition: &api_proto.HuntCondition{
+				MinClientVersion: "0.5.4",
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	// Nothing is recorded until the hunt starts.
+	assert.Empty(self.T(), get_snapshot(hunt_id))
+
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_id,
+		State:  api_proto.Hunt_RUNNING,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err := GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(1), hunt_obj.SnapshotClientCount)
+	assert.Equal(self.T(), []string{"C.2"}, get_snapshot(hunt_id))
+
+	// Restarting the hunt replaces the snapshot.
+	add_client("C.3", "0.6.1")
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_id,
+		State:  api_proto.Hunt_PAUSED,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	err = ModifyHunt(self.ctx, self.config_obj, &api_proto.Hunt{
+		HuntId: hunt_id,
+		State:  api_proto.Hunt_RUNNING,
+	}, "admin")
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(2), hunt_obj.SnapshotClientCount)
+	assert.Equal(self.T(), []string{"C.2", "C.3"}, get_snapshot(hunt_id))
+
+	// Hunts created running take the snapshot straight away, and
+	// without a condition every client matches.
+	hunt_id, err = CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			SnapshotClients: true,
+			State:           api_proto.Hunt_RUNNING,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	hunt_obj, err = GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(3), hunt_obj.SnapshotClientCount)
+	assert.Equal(self.T(), []string{"C.1", "C.2", "C.3"}, get_snapshot(hunt_id))
+}
+
+func (self *HuntTestSuite) TestGetHuntNonResponders() {
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	journal, err := services.GetJournal()
+	assert.NoError(self.T(), err)
+
+	// Hunts without a snapshot can not be checked.
+	hunt_id, err := CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			State: api_proto.Hunt_RUNNING,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	result, err := GetHuntNonResponders(
+		self.ctx, self.config_obj, hunt_id, 0, 10)
+	assert.NoError(self.T(), err)
+	assert.False(self.T(), result.SnapshotAvailable)
+
+	// C.1 finished, C.2 is still running, C.3 failed and C.4 was
+	// never scheduled.
+	collections := map[string]flows_proto.ArtifactCollectorContext_State{
+		"C.1": flows_proto.ArtifactCollectorContext_FINISHED,
+		"C.2": flows_proto.ArtifactCollectorContext_RUNNING,
+		"C.3": flows_proto.ArtifactCollectorContext_ERROR,
+		"C.4": flows_proto.ArtifactCollectorContext_UNSET,
+	}
+	for client_id := range collections {
+		err = db.SetSubject(self.config_obj,
+			paths.NewClientPathManager(client_id).Path(),
+			&actions_proto.ClientInfo{ClientId: client_id})
+		assert.NoError(self.T(), err)
+
+		err = db.SetIndex(self.config_obj, constants.CLIENT_INDEX_URN,
+			client_id, []string{"all"})
+		assert.NoError(self.T(), err)
+	}
+
+	hunt_id, err = CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			SnapshotClients: true,
+			State:           api_proto.Hunt_RUNNING,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	assert.NoError(self.T(), err)
+
+	rows := []*ordereddict.Dict{}
+	for client_id, state := range collections {
+		if state == flows_proto.ArtifactCollectorContext_UNSET {
+			continue
+		}
+
+		err := db.SetSubject(self.config_obj,
+			paths.NewFlowPathManager(client_id, "F.1").Path(),
+			&flows_proto.ArtifactCollectorContext{
+				SessionId: "F.1",
+				ClientId:  client_id,
+				State:     state,
+			})
+		assert.NoError(self.T(), err)
+
+		rows = append(rows, ordereddict.NewDict().
+			Set("HuntId", hunt_id).
+			Set("ClientId", client_id).
+			Set("FlowId", "F.1"))
+	}
+
+	err = journal.PushRows(self.config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), rows)
+	assert.NoError(self.T(), err)
+
+	result, err = GetHuntNonResponders(
+		self.ctx, self.config_obj, hunt_id, 0, 10)
+	assert.NoError(self.T(), err)
+	assert.True(self.T(), result.SnapshotAvailable)
+	assert.Equal(self.T(), uint64(4), result.SnapshotTotal)
+	assert.Equal(self.T(), uint64(2), result.Total)
+
+	reasons := make(map[string]string)
+	for _, item := range result.Items {
+		reasons[item.ClientId] = item.Reason + " " + item.FlowId
+	}
+	assert.Equal(self.T(), map[string]string{
+		"C.2": "No response F.1",
+		"C.4": "Not scheduled ",
+	}, reasons)
+
+	// Paging through the non responders.
+	result, err = GetHuntNonResponders(
+		self.ctx, self.config_obj, hunt_id, 1, 10)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), uint64(2), result.Total)
+	assert.Equal(self.T(), 1, len(result.Items))
+}
+
+func (self *HuntTestSuite) TestDiffHunts() {
+	acl_manager := vql_subsystem.NullACLManager{}
+	expires := HuntTimeFromTime(time.Now().Add(24 * time.Hour))
+	create := func(label string, artifacts []string,
+		value string, expires uint64) string {
+		hunt_id, err := CreateHunt(self.ctx, self.config_obj, acl_manager,
+			&api_proto.Hunt{
+				HuntDescription: "Diff",
+				Expires:         expires,
+				Condition: &api_proto.HuntCondition{
+					Labels: &api_proto.HuntLabelCondition{
+						Label: []string{label},
+					},
+				},
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: artifacts,
+					Specs: []*flows_proto.ArtifactSpec{{
+						Artifact: "Generic.Client.Info",
+						Parameters: &flows_proto.ArtifactParameters{
+							Env: []*actions_proto.VQLEnv{{
+								Key:   "Parameter",
+								Value: value,
+							}},
+						},
+					}},
+				},
+			})
+		assert.NoError(self.T(), err)
+		return hunt_id
+	}
+
+	hunt_a := create("Workstations", []string{"Generic.Client.Info"},
+		"Value", expires)
+	hunt_b := create("Servers",
+		[]string{"Generic.Client.Info", "Generic.Client.Stats"},
+		"Other Value", expires+1000000)
+
+	// Identical definitions have no differences even though their
+	// ids and creation times differ.
+	hunt_c := create("Workstations", []string{"Generic.Client.Info"},
+		"Value", expires)
+	diff, err := DiffHunts(self.config_obj, hunt_a, hunt_c)
+	assert.NoError(self.T(), err)
+	assert.Empty(self.T(), diff.Differences)
+
+	diff, err = DiffHunts(self.config_obj, hunt_a, hunt_b)
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), hunt_a, diff.HuntIdA)
+	assert.Equal(self.T(), hunt_b, diff.HuntIdB)
+
+	differences := make(map[string][]string)
+	for _, item := range diff.Differences {
+		differences[item.Field] = []string{item.A, item.B}
+	}
+	assert.Equal(self.T(), map[string][]string{
+		"condition.labels.label": {`["Workstations"]`, `["Servers"]`},
+		"expires": {
+			fmt.Sprintf(`"%d"`, expires), fmt.Sprintf(`"%d"`, expires+1000000)},
+		"parameters[Generic.Client.Info].Parameter": {`"Value"`, `"Other Value"`},
+		"start_request.artifacts": {
+			`["Generic.Client.Info"]`,
+			`["Generic.Client.Info","Generic.Client.Stats"]`},
+	}, differences)
+
+	_, err = DiffHunts(self.config_obj, hunt_a, "H.Missing")
+	assert.Error(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestCreateHuntArtifactTypes() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	for _, artifact_type := range []string{
+		"CLIENT", "CLIENT_EVENT", "SERVER", "SERVER_EVENT", "INTERNAL"} {
+		_, err = repository.LoadYaml(fmt.Sprintf(`
+name: Test.Artifact.%v
+type: %v
+sources:
+- query: SELECT * FROM info()
+`, artifact_type, artifact_type), true)
+		assert.NoError(self.T(), err)
+	}
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Untyped
+sources:
+- query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	create := func(artifacts ...string) error {
+		_, err := CreateHunt(self.ctx, self.config_obj,
+			vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+				StartRequest: &flows_proto.ArtifactCollectorArgs{
+					Artifacts: artifacts,
+				},
+			})
+		return err
+	}
+
+	// Artifacts without a type are client artifacts.
+	assert.NoError(self.T(), create("Test.Artifact.Untyped"))
+	assert.NoError(self.T(), create("Test.Artifact.CLIENT"))
+	assert.NoError(self.T(), create("Test.Artifact.CLIENT_EVENT"))
+
+	for _, artifact_type := range []string{
+		"SERVER", "SERVER_EVENT", "INTERNAL"} {
+		err := create("Test.Artifact." + artifact_type)
+		assert.Error(self.T(), err, artifact_type)
+		assert.Contains(self.T(), err.Error(), fmt.Sprintf(
+			"Test.Artifact.%v (%v)", artifact_type, artifact_type))
+	}
+
+	// All the artifacts which can not be collected are named.
+	err = create("Test.Artifact.SERVER", "Test.Artifact.CLIENT",
+		"Test.Artifact.SERVER_EVENT")
+	assert.Error(self.T(), err)
+	assert.Equal(self.T(), "Hunts can only collect CLIENT or CLIENT_EVENT "+
+		"artifacts: Test.Artifact.SERVER (SERVER), "+
+		"Test.Artifact.SERVER_EVENT (SERVER_EVENT) can not be collected "+
+		"on clients", err.Error())
+}
+
+func (self *HuntTestSuite) TestPreviewHuntArtifacts() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	repository, err := manager.GetGlobalRepository(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.Preview
+description: Lists things.
+column_types:
+- name: Size
+  type: int
+- name: Mtime
+  type: timestamp
+sources:
+- name: Files
+  description: The files found.
+  query: SELECT * FROM info()
+- name: Dirs
+  query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	_, err = repository.LoadYaml(`
+name: Test.Artifact.NoColumns
+sources:
+- query: SELECT * FROM info()
+`, true)
+	assert.NoError(self.T(), err)
+
+	hunt := &api_proto.Hunt{
+		StartRequest: &flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{
+				"Test.Artifact.Preview", "Test.Artifact.NoColumns"},
+		},
+	}
+
+	preview, err := PreviewHuntArtifacts(self.config_obj, hunt)
+	assert.NoError(self.T(), err)
+	require.Equal(self.T(), 3, len(preview.Sources))
+
+	// The sources line up with the hunt's artifact sources.
+	FindCollectedArtifacts(self.config_obj, hunt)
+	for idx, source := range preview.Sources {
+		assert.Equal(self.T(), hunt.ArtifactSources[idx], source.Name)
+	}
+
+	files := preview.Sources[0]
+	assert.Equal(self.T(), "Test.Artifact.Preview/Files", files.Name)
+	assert.Equal(self.T(), "Test.Artifact.Preview", files.Artifact)
+	assert.Equal(self.T(), "Files", files.Source)
+	assert.Equal(self.T(), "The files found.", files.Description)
+	require.Equal(self.T(), 2, len(files.ColumnTypes))
+	assert.Equal(self.T(), "Size", files.ColumnTypes[0].Name)
+	assert.Equal(self.T(), "int", files.ColumnTypes[0].Type)
+	assert.Equal(self.T(), "Mtime", files.ColumnTypes[1].Name)
+	assert.Equal(self.T(), "timestamp", files.ColumnTypes[1].Type)
+
+	// Sources without a description use the artifact's.
+	dirs := preview.Sources[1]
+	assert.Equal(self.T(), "Dirs", dirs.Source)
+	assert.Equal(self.T(), "Lists things.", dirs.Description)
+	assert.Equal(self.T(), 2, len(dirs.ColumnTypes))
+
+	// No declared columns is not an error.
+	no_columns := preview.Sources[2]
+	assert.Equal(self.T(), "Test.Artifact.NoColumns", no_columns.Name)
+	assert.Equal(self.T(), "", no_columns.Source)
+	assert.Equal(self.T(), 0, len(no_columns.ColumnTypes))
+
+	_, err = PreviewHuntArtifacts(self.config_obj, &api_proto.Hunt{
+		StartRequest: &flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{"Test.Artifact.Missing"},
+		},
+	})
+	assert.Error(self.T(), err)
+}
+
+func (self *HuntTestSuite) TestClientMatchesConditionVQL() {
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	for client_id, department := range map[string]string{
+		"C.1": "Finance", "C.2": "HR"} {
+		client_path_manager := paths.NewClientPathManager(client_id)
+		err = db.SetSubject(self.config_obj,
+			client_path_manager.Path(), &actions_proto.ClientInfo{
+				Hostname: "host-" + client_id,
+				System:   "windows",
+			})
+		assert.NoError(self.T(), err)
+
+		err = db.SetSubject(self.config_obj,
+			client_path_manager.Metadata(), &api_proto.ClientMetadata{
+				ClientId: client_id,
+				Items: []*api_proto.ClientMetadataItem{{
+					Key: "Department", Value: department,
+				}},
+			})
+		assert.NoError(self.T(), err)
+	}
+
+	hunt := &api_proto.Hunt{
+		HuntId: "H.1",
+		Condition: &api_proto.HuntCondition{
+			ConditionVql: "Client.OS = 'windows' AND " +
+				"Client.Metadata.Department = 'Finance'",
+		},
+	}
+
+	matches := func(client_id string) bool {
+		return ClientMatchesConditionVQL(self.ctx, self.config_obj,
+			hunt, client_id)
+	}
+
+	assert.True(self.T(), matches("C.1"))
+	assert.False(self.T(), matches("C.2"))
+
+	// Clients without metadata just do not match.
+	err = db.SetSubject(self.config_obj,
+		paths.NewClientPathManager("C.3").Path(), &actions_proto.ClientInfo{
+			System: "windows",
+		})
+	assert.NoError(self.T(), err)
+	assert.False(self.T(), matches("C.3"))
+
+	// An expression which takes too long fails closed.
+	old_timeout := hunt_condition_vql_timeout
+	defer func() { hunt_condition_vql_timeout = old_timeout }()
+	hunt_condition_vql_timeout = 100 * time.Millisecond
+
+	hunt.Condition.ConditionVql = "sleep(time=10) OR TRUE"
+	start := time.Now()
+	assert.False(self.T(), matches("C.1"))
+	assert.True(self.T(), time.Since(start) < 5*time.Second)
+
+	// Hunts with invalid expressions can not be created.
+	_, err = CreateHunt(self.ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+			Condition: &api_proto.HuntCondition{
+				ConditionVql: "Client.OS = ",
+			},
+		})
+	assert.Error(self.T(), err)
+	assert.Contains(self.T(), err.Error(), "Invalid hunt condition VQL")
+}
@@ -80,6 +80,20 @@ func (self *NotificationPool) NotifyByRegex(re *regexp.Regexp) {
 	}
 }
 
+// The ids of the listeners matching the regex.
+func (self *NotificationPool) MatchingListeners(re *regexp.Regexp) []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := []string{}
+	for key := range self.clients {
+		if re.MatchString(key) {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
 func (self *NotificationPool) Shutdown() {
 	self.mu.Lock()
 	defer self.mu.Unlock()
@@ -2,7 +2,12 @@ package paths
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"path"
+	"strings"
+	"time"
 
 	"www.velocidex.com/golang/velociraptor/file_store/api"
 )
@@ -37,6 +42,36 @@ func (self HuntPathManager) GetHuntDownloadsFile(only_combined bool,
 		base_filename+self.hunt_id+suffix+".zip")
 }
 
+// Get the file store path for a delta download zip containing only
+// the flows completed between start and end.
+func (self HuntPathManager) GetHuntDeltaDownloadsFile(
+	start, end time.Time, base_filename string) string {
+	return path.Join(
+		"/downloads/hunts", self.hunt_id,
+		fmt.Sprintf("%s%s-delta-%s-%s.zip", base_filename, self.hunt_id,
+			start.UTC().Format(delta_time_format),
+			end.UTC().Format(delta_time_format)))
+}
+
+const delta_time_format = "20060102T150405Z"
+
+// Classify a hunt download file by its name as either "full",
+// "summary" or "delta". Encrypted downloads have an extra .gpg
+// extension.
+func HuntDownloadType(filename string) string {
+	name := strings.TrimSuffix(path.Base(filename), ".gpg")
+	name = strings.TrimSuffix(name, ".zip")
+	if strings.Contains(name, "-delta-") {
+		return "delta"
+	}
+
+	if strings.HasSuffix(name, "-summary") {
+		return "summary"
+	}
+
+	return "full"
+}
+
 func (self HuntPathManager) GeneratePaths(ctx context.Context) <-chan *api.ResultSetFileProperties {
 	output := make(chan *api.ResultSetFileProperties)
 	go func() {
@@ -73,6 +108,62 @@ func (self HuntPathManager) Clients() *HuntPathManager {
 	return &self
 }
 
+func HuntIdempotencyDirectory() string {
+	return "/hunt_idempotency"
+}
+
+// Where to record the hunt created with an idempotency key. Keys are
+// chosen by the caller so we only use their hash in the path.
+func HuntIdempotencyKeyPath(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return path.Join(HuntIdempotencyDirectory(), hex.EncodeToString(hash[:]))
+}
+
+// Campaigns group related hunts.
+func CampaignDirectory() string {
+	return "/campaigns"
+}
+
+func CampaignPath(campaign_id string) string {
+	return path.Join(CampaignDirectory(), campaign_id)
+}
+
+// Hunt templates are stored by the hash of their name since names
+// are chosen by the user.
+func HuntTemplateDirectory() string {
+	return "/hunt_templates"
+}
+
+func HuntTemplatePath(name string) string {
+	hash := sha256.Sum256([]byte(name))
+	return path.Join(HuntTemplateDirectory(), hex.EncodeToString(hash[:]))
+}
+
+// Pending retries of failed hunt collections, one for each client.
+func HuntRetryDirectory(hunt_id string) string {
+	return path.Join("/hunt_retries", hunt_id)
+}
+
+func HuntRetryPath(hunt_id, client_id string) string {
+	return path.Join(HuntRetryDirectory(hunt_id), client_id)
+}
+
+// Clients waiting for the hunt's rollout rate to allow scheduling
+// them, one for each client.
+func HuntQueueDirectory(hunt_id string) string {
+	return path.Join("/hunt_queue", hunt_id)
+}
+
+func HuntQueuePath(hunt_id, client_id string) string {
+	return path.Join(HuntQueueDirectory(hunt_id), client_id)
+}
+
+// The clients which matched the hunt when it was last started.
+func (self HuntPathManager) ClientSnapshot() *HuntPathManager {
+	self.path = path.Join("/hunts", self.hunt_id+"_snapshot.json")
+	return &self
+}
+
 // Where to store client errors.
 func (self HuntPathManager) ClientErrors() *HuntPathManager {
 	self.path = path.Join("/hunts", self.hunt_id+"_errors.json")
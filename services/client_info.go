@@ -35,6 +35,9 @@ func RegisterClientInfoManager(m ClientInfoManager) {
 type ClientInfo struct {
 	Hostname string
 	OS       ClientOS
+
+	// The version the client reported, empty if it is not known.
+	Version string
 }
 
 func (self ClientInfo) OSString() string {
@@ -66,6 +66,7 @@ func (self *ClientInfoManager) Get(client_id string) (*services.ClientInfo, erro
 	client_info := &services.ClientInfo{
 		Hostname: record.Hostname,
 		OS:       os,
+		Version:  record.ClientVersion,
 	}
 
 	self.lru.Set(client_id, &CachedInfo{
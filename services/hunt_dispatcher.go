@@ -23,6 +23,7 @@ package services
 // client requests.
 
 import (
+	"errors"
 	"sync"
 
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
@@ -33,12 +34,28 @@ var (
 	mu sync.Mutex
 
 	global_hunt_dispatcher IHuntDispatcher
+
+	// Returned when the hunt dispatcher is used before it is
+	// started (e.g. the server is still starting up). Unlike other
+	// errors callers may retry later.
+	ErrHuntDispatcherNotReady = errors.New(
+		"Hunt dispatcher not ready - the server may still be starting")
+
+	// Callbacks given to ApplyFuncOnHunts return this to stop the
+	// iteration early, in which case ApplyFuncOnHunts returns
+	// nil. Only the sentinel itself stops the iteration: an error
+	// which wraps it (e.g. a nested iteration's result passed up
+	// with context) is returned to the caller like any other
+	// error so real failures are never swallowed.
+	StopHuntIteration = errors.New("stop hunt iteration")
 )
 
 type IHuntDispatcher interface {
 	// Applies the function on all the hunts. Functions may not
 	// modify the hunt but will have read only access to the hunt
-	// objects under lock.
+	// objects under lock. The callback may return
+	// StopHuntIteration to stop early without an error, any other
+	// error stops the iteration and is returned.
 	ApplyFuncOnHunts(cb func(hunt *api_proto.Hunt) error) error
 
 	// As an optimization callers may get the latest hunt's
@@ -52,12 +69,24 @@ type IHuntDispatcher interface {
 	// all frontends.
 	ModifyHunt(hunt_id string, cb func(hunt *api_proto.Hunt) error) error
 
+	// Update the hunt's stats under the hunt's own lock rather
+	// than the dispatcher lock, so concurrent updates of the
+	// hunts' stats (e.g. as flows complete) are not lost and do
+	// not block the foreman. The callback may only modify
+	// hunt.Stats.
+	UpdateHuntStats(hunt_id string, cb func(hunt *api_proto.Hunt) error) error
+
 	// Re-read the hunts from the data store. This happens
 	// periodically and can also be triggered when a change is
 	// written to the datastore (e.g. new hunt scheduled) to pick
 	// up the latest hunts.
 	Refresh(config_obj *config_proto.Config) error
 
+	// Drop a single hunt from memory and re-read it from the data
+	// store (or forget it if it is gone). A cheaper alternative to
+	// Refresh after a single hunt was changed in the data store.
+	EvictHunt(config_obj *config_proto.Config, hunt_id string) error
+
 	// Clean up and close the hunt dispatcher. Only used in tests.
 	Close(config_obj *config_proto.Config)
 }
@@ -75,3 +104,18 @@ func GetHuntDispatcher() IHuntDispatcher {
 
 	return global_hunt_dispatcher
 }
+
+// Is the hunt dispatcher started?
+func HuntDispatcherReady() bool {
+	return GetHuntDispatcher() != nil
+}
+
+// Get the hunt dispatcher or ErrHuntDispatcherNotReady if it is not
+// started yet.
+func RequireHuntDispatcher() (IHuntDispatcher, error) {
+	dispatcher := GetHuntDispatcher()
+	if dispatcher == nil {
+		return nil, ErrHuntDispatcherNotReady
+	}
+	return dispatcher, nil
+}
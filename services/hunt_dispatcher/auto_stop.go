@@ -0,0 +1,114 @@
+package hunt_dispatcher
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// Check the hunt's errors against its auto stop thresholds and
+// return the reason to stop it, or "" if it should keep running.
+// Clients which are still retrying have not completed yet so they
+// are not counted.
+func checkHuntErrorRate(hunt *api_proto.Hunt) string {
+	policy := hunt.AutoStopErrorRate
+	if policy == nil ||
+		hunt.Stats == nil || hunt.Stats.Stopped ||
+		hunt.State != api_proto.Hunt_RUNNING {
+		return ""
+	}
+
+	errored := hunt.Stats.TotalClientsWithErrors
+	if policy.MaxErrors > 0 && errored >= policy.MaxErrors {
+		return fmt.Sprintf(
+			"%v clients failed, reaching the limit of %v errors",
+			errored, policy.MaxErrors)
+	}
+
+	completed := hunt.Stats.TotalClientsWithResults + errored
+	if policy.MaxErrorPercent <= 0 ||
+		completed == 0 || completed < policy.MinClients {
+		return ""
+	}
+
+	error_percent := float64(errored) * 100 / float64(completed)
+	if error_percent <= policy.MaxErrorPercent {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"%v of %v completed clients failed (%.1f%%), exceeding the error rate of %v%%",
+		errored, completed, error_percent, policy.MaxErrorPercent)
+}
+
+// Stop the hunt because of its error rate. The hunt object and its
+// stats are written immediately rather than waiting for the next
+// flush (which only writes the stats). Called with the hunt locked.
+func (self *HuntDispatcher) autoStopHunt(
+	hunt *api_proto.Hunt, reason string) {
+	hunt.State = api_proto.Hunt_STOPPED
+	hunt.Stats.Stopped = true
+	hunt.Stats.ErrorMessage = reason
+
+	if self.config_obj == nil {
+		return
+	}
+
+	logger := logging.GetLogger(self.config_obj, &logging.FrontendComponent)
+	logger.Info("Stopping hunt %v: %v", hunt.HuntId, reason)
+
+	db, err := datastore.GetDB(self.config_obj)
+	if err == nil {
+		hunt_path_manager := paths.NewHuntPathManager(hunt.HuntId)
+		err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt)
+		if err == nil {
+			err = db.SetSubject(self.config_obj,
+				hunt_path_manager.Stats().Path(), hunt.Stats)
+		}
+	}
+	if err != nil {
+		logger.Error("Stopping hunt %v: %v", hunt.HuntId, err)
+	}
+}
+
+// Record the state change in the same System.Hunt.StateChange row
+// as flows.EmitHuntStateChange (which we can not import), and in
+// System.Hunt.AutoStopped so auto stops can be watched on their own.
+func emitAutoStop(config_obj *config_proto.Config, hunt_id, reason string) {
+	now := uint64(time.Now().UnixNano() / 1000)
+	row := ordereddict.NewDict().
+		Set("Timestamp", now).
+		Set("HuntId", hunt_id).
+		Set("OldState", api_proto.Hunt_RUNNING.String()).
+		Set("NewState", api_proto.Hunt_STOPPED.String()).
+		Set("User", "").
+		Set("Reason", reason)
+
+	journal, err := services.GetJournal()
+	if err == nil {
+		err = journal.PushRowsToArtifact(config_obj,
+			[]*ordereddict.Dict{row}, "System.Hunt.StateChange",
+			"server", hunt_id)
+	}
+
+	if err == nil {
+		err = journal.PushRowsToArtifact(config_obj,
+			[]*ordereddict.Dict{ordereddict.NewDict().
+				Set("Timestamp", now).
+				Set("HuntId", hunt_id).
+				Set("Reason", reason)},
+			"System.Hunt.AutoStopped", "server", hunt_id)
+	}
+
+	if err != nil {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Error("emitAutoStop %v: %v", hunt_id, err)
+	}
+}
@@ -20,6 +20,7 @@ package hunt_dispatcher
 import (
 	"context"
 	"errors"
+	"fmt"
 	"path"
 	"sync"
 	"sync/atomic"
@@ -56,6 +57,11 @@ type HuntDispatcher struct {
 	mu    sync.Mutex
 	hunts map[string]*api_proto.Hunt
 	dirty bool
+
+	// Per hunt locks guarding the hunt objects (see stats.go).
+	hunt_locks map[string]*huntLock
+
+	config_obj *config_proto.Config
 }
 
 func (self *HuntDispatcher) GetLastTimestamp() uint64 {
@@ -64,15 +70,28 @@ func (self *HuntDispatcher) GetLastTimestamp() uint64 {
 
 // Applies a callback on all hunts. Note that the entire dispatcher is
 // locked while this function is running so it should be quick. It is
-// not allowed to modify the hunts.
+// not allowed to modify the hunts. Returning
+// services.StopHuntIteration from the callback stops early without
+// an error.
 func (self *HuntDispatcher) ApplyFuncOnHunts(
 	cb func(hunt *api_proto.Hunt) error) error {
+	if cb == nil {
+		return errors.New("ApplyFuncOnHunts: callback is nil")
+	}
 
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
-	for _, hunt := range self.hunts {
+	for hunt_id, hunt := range self.hunts {
+		hunt_lock := self.getHuntLock(hunt_id)
+		hunt_lock.mu.Lock()
 		err := cb(hunt)
+		hunt_lock.mu.Unlock()
+
+		if err == services.StopHuntIteration {
+			return nil
+		}
+
 		if err != nil {
 			return err
 		}
@@ -81,17 +100,35 @@ func (self *HuntDispatcher) ApplyFuncOnHunts(
 	return nil
 }
 
-// Modify the hunt object under lock.
+// Modify the hunt object under lock. The hunt stats accumulate here
+// and in UpdateHuntStats, so a hunt whose error rate exceeds its
+// AutoStopErrorRate is stopped by either.
 func (self *HuntDispatcher) ModifyHunt(
 	hunt_id string, cb func(hunt *api_proto.Hunt) error) error {
+	stopped_reason, err := self.modifyHunt(hunt_id, cb)
+
+	// Emit the state change once we released the lock.
+	if stopped_reason != "" && self.config_obj != nil {
+		emitAutoStop(self.config_obj, hunt_id, stopped_reason)
+	}
+
+	return err
+}
+
+func (self *HuntDispatcher) modifyHunt(
+	hunt_id string, cb func(hunt *api_proto.Hunt) error) (string, error) {
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
 	hunt_obj, pres := self.hunts[hunt_id]
 	if !pres {
-		return errors.New("not found")
+		return "", errors.New("not found")
 	}
 
+	hunt_lock := self.getHuntLock(hunt_id)
+	hunt_lock.mu.Lock()
+	defer hunt_lock.mu.Unlock()
+
 	err := cb(hunt_obj)
 
 	// The hunts start time could have been modified - we need to
@@ -102,11 +139,17 @@ func (self *HuntDispatcher) ModifyHunt(
 
 	self.dirty = true
 
-	return err
+	stopped_reason := checkHuntErrorRate(hunt_obj)
+	if stopped_reason != "" {
+		self.autoStopHunt(hunt_obj, stopped_reason)
+	}
+
+	return stopped_reason, err
 }
 
 // Write the hunt stats to the data store. This is only called by the
-// hunt manager and so should be concurrently safe.
+// hunt manager and so should be concurrently safe. Called with the
+// dispatcher locked and the hunt locks retired.
 func (self *HuntDispatcher) _flush_stats(config_obj *config_proto.Config) error {
 	// Only do something if we are dirty.
 	if !self.dirty {
@@ -140,6 +183,7 @@ func (self *HuntDispatcher) Close(config_obj *config_proto.Config) {
 	defer self.mu.Unlock()
 	atomic.SwapUint64(&self.last_timestamp, 0)
 
+	self.retireHuntLocks()
 	_ = self._flush_stats(config_obj)
 }
 
@@ -161,6 +205,10 @@ func (self *HuntDispatcher) Refresh(config_obj *config_proto.Config) error {
 	self.mu.Lock()
 	defer self.mu.Unlock()
 
+	// Stop any stats updates - they will be applied to the hunts
+	// we read below instead.
+	self.retireHuntLocks()
+
 	// First flush all the stats to the data store.
 	err := self._flush_stats(config_obj)
 	if err != nil {
@@ -186,28 +234,11 @@ func (self *HuntDispatcher) Refresh(config_obj *config_proto.Config) error {
 			continue
 		}
 
-		hunt_obj := &api_proto.Hunt{}
-		hunt_path_manager := paths.NewHuntPathManager(hunt_id)
-		err = db.GetSubject(
-			config_obj, hunt_path_manager.Path(), hunt_obj)
+		hunt_obj, err := loadHunt(config_obj, db, hunt_id)
 		if err != nil {
 			continue
 		}
 
-		// Re-read the stats into the hunt object.
-		hunt_stats := &api_proto.HuntStats{}
-		err := db.GetSubject(config_obj,
-			hunt_path_manager.Stats().Path(), hunt_stats)
-		if err == nil {
-			hunt_obj.Stats = hunt_stats
-		}
-
-		// Should not really happen but if the file is
-		// corrupted we skip it.
-		if hunt_obj.HuntId != hunt_id {
-			continue
-		}
-
 		// This hunt is newer than the last_timestamp, we need
 		// to update it.
 		if hunt_obj.StartTime > last_timestamp {
@@ -219,13 +250,82 @@ func (self *HuntDispatcher) Refresh(config_obj *config_proto.Config) error {
 	return nil
 }
 
+// Drop a single hunt from memory and read it again from the data
+// store, e.g. after the data store was edited by hand. This is much
+// cheaper than a full Refresh. If the hunt is no longer in the data
+// store it is just dropped. Any stats for this hunt which were not
+// flushed yet are discarded in favour of the stored ones.
+//
+// The foreman only sees hunts under the dispatcher lock so it
+// either sees the old hunt or the reloaded one.
+func (self *HuntDispatcher) EvictHunt(
+	config_obj *config_proto.Config, hunt_id string) error {
+	if !constants.HuntIdRegex.MatchString(hunt_id) {
+		return fmt.Errorf("Invalid hunt id %v", hunt_id)
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.retireHuntLock(hunt_id)
+	delete(self.hunts, hunt_id)
+
+	hunt_obj, err := loadHunt(config_obj, db, hunt_id)
+	if err != nil {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Info("EvictHunt: Dropping hunt %v: %v", hunt_id, err)
+		return nil
+	}
+
+	if hunt_obj.StartTime > self.GetLastTimestamp() {
+		atomic.StoreUint64(&self.last_timestamp, hunt_obj.StartTime)
+	}
+
+	self.hunts[hunt_id] = hunt_obj
+	return nil
+}
+
+// Read the hunt and its stats from the data store.
+func loadHunt(config_obj *config_proto.Config,
+	db datastore.DataStore, hunt_id string) (*api_proto.Hunt, error) {
+	hunt_obj := &api_proto.Hunt{}
+	hunt_path_manager := paths.NewHuntPathManager(hunt_id)
+	err := db.GetSubject(
+		config_obj, hunt_path_manager.Path(), hunt_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-read the stats into the hunt object.
+	hunt_stats := &api_proto.HuntStats{}
+	err = db.GetSubject(config_obj,
+		hunt_path_manager.Stats().Path(), hunt_stats)
+	if err == nil {
+		hunt_obj.Stats = hunt_stats
+	}
+
+	// Should not really happen but if the file is corrupted we
+	// skip it.
+	if hunt_obj.HuntId != hunt_id {
+		return nil, fmt.Errorf("Hunt %v is corrupted", hunt_id)
+	}
+
+	return hunt_obj, nil
+}
+
 func StartHuntDispatcher(
 	ctx context.Context,
 	wg *sync.WaitGroup,
 	config_obj *config_proto.Config) error {
 
 	result := &HuntDispatcher{
-		hunts: make(map[string]*api_proto.Hunt),
+		hunts:      make(map[string]*api_proto.Hunt),
+		config_obj: config_obj,
 	}
 
 	// flush the hunts every 10 seconds.
@@ -258,11 +358,36 @@ func StartHuntDispatcher(
 		}
 	}()
 
+	// Optionally correct any drift in the hunt stats.
+	if config_obj.Defaults != nil &&
+		config_obj.Defaults.HuntStatsReconcilePeriod > 0 {
+		period := time.Duration(
+			config_obj.Defaults.HuntStatsReconcilePeriod) * time.Second
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+
+				case <-time.After(period):
+					reconcileAllHunts(ctx, config_obj, result)
+				}
+			}
+		}()
+	}
+
 	services.RegisterHuntDispatcher(result)
 
 	// Try to refresh the hunts table the first time. If we cant
 	// we will just keep trying anyway later.
-	_ = result.Refresh(config_obj)
+	err := result.Refresh(config_obj)
+	if err == nil && config_obj.Datastore != nil {
+		reconcileRunningHunts(ctx, config_obj, result)
+	}
 
 	return nil
 }
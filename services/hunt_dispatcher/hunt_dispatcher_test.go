@@ -0,0 +1,216 @@
+package hunt_dispatcher
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+func TestApplyFuncOnHuntsIteration(t *testing.T) {
+	dispatcher := &HuntDispatcher{
+		hunts: map[string]*api_proto.Hunt{
+			"H.1": {HuntId: "H.1"},
+			"H.2": {HuntId: "H.2"},
+			"H.3": {HuntId: "H.3"},
+		},
+	}
+
+	visited := 0
+	err := dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		visited++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, visited)
+
+	// The sentinel stops the iteration without an error.
+	visited = 0
+	err = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		visited++
+		return services.StopHuntIteration
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, visited)
+
+	// Real errors stop the iteration and are returned.
+	real_error := errors.New("Datastore failure")
+	visited = 0
+	err = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		visited++
+		return real_error
+	})
+	assert.Equal(t, real_error, err)
+	assert.Equal(t, 1, visited)
+
+	// An error which wraps the sentinel (e.g. from a nested
+	// iteration) is a real error too.
+	err = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		return errors.Wrap(services.StopHuntIteration, "nested")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, services.StopHuntIteration, errors.Cause(err))
+
+	// Other iterations' sentinels are real errors here.
+	err = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		return constants.STOP_ITERATION
+	})
+	assert.Equal(t, constants.STOP_ITERATION, err)
+
+	assert.Error(t, dispatcher.ApplyFuncOnHunts(nil))
+}
+
+func TestCheckHuntErrorRate(t *testing.T) {
+	hunt := &api_proto.Hunt{
+		State: api_proto.Hunt_RUNNING,
+		Stats: &api_proto.HuntStats{
+			TotalClientsWithResults: 1,
+			TotalClientsWithErrors:  3,
+		},
+	}
+
+	// The threshold is opt in.
+	assert.Equal(t, "", checkHuntErrorRate(hunt))
+
+	// Not enough clients completed yet.
+	hunt.AutoStopErrorRate = &api_proto.HuntAutoStopErrorRate{
+		MaxErrorPercent: 50,
+		MinClients:      5,
+	}
+	assert.Equal(t, "", checkHuntErrorRate(hunt))
+
+	hunt.AutoStopErrorRate.MinClients = 4
+	assert.Equal(t,
+		"3 of 4 completed clients failed (75.0%), exceeding the error rate of 50%",
+		checkHuntErrorRate(hunt))
+
+	// Exactly at the threshold is fine.
+	hunt.Stats.TotalClientsWithResults = 3
+	hunt.AutoStopErrorRate.MinClients = 0
+	assert.Equal(t, "", checkHuntErrorRate(hunt))
+
+	// Stopped hunts are not stopped again.
+	hunt.Stats.TotalClientsWithErrors = 4
+	assert.NotEqual(t, "", checkHuntErrorRate(hunt))
+
+	hunt.Stats.Stopped = true
+	assert.Equal(t, "", checkHuntErrorRate(hunt))
+
+	// A maximum number of errors applies however few clients
+	// completed, even without an error rate.
+	hunt.Stats.Stopped = false
+	hunt.AutoStopErrorRate = &api_proto.HuntAutoStopErrorRate{
+		MinClients: 100,
+		MaxErrors:  5,
+	}
+	assert.Equal(t, "", checkHuntErrorRate(hunt))
+
+	hunt.Stats.TotalClientsWithErrors = 5
+	assert.Equal(t,
+		"5 clients failed, reaching the limit of 5 errors",
+		checkHuntErrorRate(hunt))
+}
+
+// Run with -race: many flows of one hunt complete at once while the
+// hunts are refreshed and read by the foreman. No update may be lost.
+func TestConcurrentStatsUpdates(t *testing.T) {
+	config_obj := config.GetDefaultConfig()
+	config_obj.Datastore.Implementation = "Test"
+
+	db, err := datastore.GetDB(config_obj)
+	require.NoError(t, err)
+
+	hunt_obj := &api_proto.Hunt{HuntId: "H.1", Stats: &api_proto.HuntStats{}}
+	err = db.SetSubject(config_obj,
+		paths.NewHuntPathManager("H.1").Path(), hunt_obj)
+	require.NoError(t, err)
+
+	dispatcher := &HuntDispatcher{
+		hunts:      make(map[string]*api_proto.Hunt),
+		config_obj: config_obj,
+	}
+	require.NoError(t, dispatcher.Refresh(config_obj))
+
+	workers := 20
+	completions := 200
+
+	done := make(chan bool)
+	background_wg := &sync.WaitGroup{}
+	background_wg.Add(1)
+	go func() {
+		defer background_wg.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			assert.NoError(t, dispatcher.Refresh(config_obj))
+			_ = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+				_ = hunt.Stats.TotalClientsWithResults
+				return nil
+			})
+		}
+	}()
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < completions; j++ {
+				err := dispatcher.UpdateHuntStats("H.1",
+					func(hunt *api_proto.Hunt) error {
+						if (i+j)%2 == 0 {
+							hunt.Stats.TotalClientsWithResults++
+						} else {
+							hunt.Stats.TotalClientsWithErrors++
+						}
+						return nil
+					})
+				assert.NoError(t, err)
+
+				err = dispatcher.ModifyHunt("H.1",
+					func(hunt *api_proto.Hunt) error {
+						hunt.Stats.TotalClientsScheduled++
+						return nil
+					})
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(done)
+	background_wg.Wait()
+
+	// The counts survive another round trip through the data
+	// store too.
+	require.NoError(t, dispatcher.Refresh(config_obj))
+
+	var stats *api_proto.HuntStats
+	err = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		stats = hunt.Stats
+		return nil
+	})
+	assert.NoError(t, err)
+
+	total := uint64(workers * completions)
+	assert.Equal(t, total, stats.TotalClientsScheduled)
+	assert.Equal(t, total/2, stats.TotalClientsWithResults)
+	assert.Equal(t, total/2, stats.TotalClientsWithErrors)
+
+	assert.Error(t, dispatcher.UpdateHuntStats("H.2",
+		func(hunt *api_proto.Hunt) error { return nil }))
+}
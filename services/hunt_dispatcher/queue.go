@@ -0,0 +1,86 @@
+package hunt_dispatcher
+
+import (
+	"sort"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+)
+
+// Queue the client until the hunt's rollout rate allows scheduling
+// it.
+func QueueHuntClient(
+	config_obj *config_proto.Config, record *api_proto.HuntQueuedClient) error {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.SetSubject(config_obj,
+		paths.HuntQueuePath(record.HuntId, record.ClientId), record)
+}
+
+// The clients queued on the hunt, oldest first.
+func ListHuntQueue(
+	config_obj *config_proto.Config,
+	hunt_id string) ([]*api_proto.HuntQueuedClient, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	urns, err := db.ListChildren(config_obj,
+		paths.HuntQueueDirectory(hunt_id), 0, 100000)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*api_proto.HuntQueuedClient{}
+	for _, urn := range urns {
+		record := &api_proto.HuntQueuedClient{}
+		err = db.GetSubject(config_obj, urn, record)
+		if err != nil || record.ClientId == "" {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].QueuedTime < result[j].QueuedTime
+	})
+
+	return result, nil
+}
+
+// Remove the client from the queue once it is scheduled.
+func DeleteHuntQueuedClient(
+	config_obj *config_proto.Config, record *api_proto.HuntQueuedClient) error {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.DeleteSubject(config_obj,
+		paths.HuntQueuePath(record.HuntId, record.ClientId))
+}
+
+// Remove all the clients queued on the hunt, e.g. once the hunt is
+// stopped. Returns how many clients were removed.
+func DeleteHuntQueue(
+	config_obj *config_proto.Config, hunt_id string) (int, error) {
+	records, err := ListHuntQueue(config_obj, hunt_id)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, record := range records {
+		err = DeleteHuntQueuedClient(config_obj, record)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(records), nil
+}
@@ -0,0 +1,275 @@
+package hunt_dispatcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+// The hunt stats are incremented as flows are scheduled and complete
+// so they may drift from the real flow records (e.g. if the server
+// crashed before the stats were flushed). ReconcileHuntStats
+// recomputes the stats from the hunt's flow index and returns the
+// stats before and after correction.
+func ReconcileHuntStats(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string) (before *api_proto.HuntStats,
+	after *api_proto.HuntStats, err error) {
+
+	dispatcher, err := services.RequireHuntDispatcher()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The hunt manager records every flow it schedules in the
+	// hunt's flow index.
+	row_chan, err := file_store.GetTimeRange(ctx, config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), 0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counted := &api_proto.HuntStats{}
+	seen := make(map[string]bool)
+
+	// A retry replaces the client's failed flow, so only the last
+	// flow of each client counts towards the results.
+	scheduled := []*huntFlow{}
+	latest := make(map[string]int)
+	for row := range row_chan {
+		client_id, _ := row.GetString("ClientId")
+		flow_id, _ := row.GetString("FlowId")
+		if client_id == "" || flow_id == "" || seen[client_id+flow_id] {
+			continue
+		}
+		seen[client_id+flow_id] = true
+
+		retry, _ := row.GetInt64("Retry")
+		if retry > 0 {
+			idx, pres := latest[client_id]
+			if pres {
+				scheduled[idx] = nil
+			}
+		} else {
+			counted.TotalClientsScheduled++
+		}
+
+		latest[client_id] = len(scheduled)
+		scheduled = append(scheduled, &huntFlow{
+			client_id: client_id,
+			flow_id:   flow_id,
+			retry:     retry,
+		})
+	}
+
+	pending, err := ListHuntRetries(config_obj, hunt_id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queued, err := ListHuntQueue(config_obj, hunt_id)
+	if err != nil {
+		return nil, nil, err
+	}
+	counted.TotalClientsQueued = uint64(len(queued))
+
+	pending_clients := make(map[string]bool)
+	for _, record := range pending {
+		pending_clients[record.ClientId] = true
+	}
+
+	for _, flow := range scheduled {
+		if flow == nil {
+			continue
+		}
+
+		collection_context := &flows_proto.ArtifactCollectorContext{}
+		err := db.GetSubject(config_obj,
+			paths.NewFlowPathManager(flow.client_id, flow.flow_id).Path(),
+			collection_context)
+		if err != nil {
+			continue
+		}
+
+		switch collection_context.State {
+		case flows_proto.ArtifactCollectorContext_FINISHED:
+			counted.TotalClientsWithResults++
+		case flows_proto.ArtifactCollectorContext_ERROR:
+			if pending_clients[flow.client_id] {
+				counted.TotalClientsRetrying++
+			} else {
+				counted.TotalClientsWithErrors++
+			}
+		default:
+			if flow.retry > 0 {
+				counted.TotalClientsRetrying++
+			}
+		}
+	}
+
+	err = dispatcher.ModifyHunt(hunt_id, func(hunt *api_proto.Hunt) error {
+		if hunt.Stats == nil {
+			hunt.Stats = &api_proto.HuntStats{}
+		}
+		before = proto.Clone(hunt.Stats).(*api_proto.HuntStats)
+
+		hunt.Stats.TotalClientsScheduled = counted.TotalClientsScheduled
+		hunt.Stats.TotalClientsWithResults = counted.TotalClientsWithResults
+		hunt.Stats.TotalClientsWithErrors = counted.TotalClientsWithErrors
+		hunt.Stats.TotalClientsRetrying = counted.TotalClientsRetrying
+		hunt.Stats.TotalClientsQueued = counted.TotalClientsQueued
+
+		after = proto.Clone(hunt.Stats).(*api_proto.HuntStats)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return before, after, nil
+}
+
+// A flow in the hunt's flow index.
+type huntFlow struct {
+	client_id string
+	flow_id   string
+
+	// Which retry of the client's collection this is, 0 for the
+	// first attempt.
+	retry int64
+}
+
+// Reconcile the stats of all hunts, logging any that drifted.
+func reconcileAllHunts(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	dispatcher *HuntDispatcher) {
+
+	hunt_ids := []string{}
+	_ = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		hunt_ids = append(hunt_ids, hunt.HuntId)
+		return nil
+	})
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	for _, hunt_id := range hunt_ids {
+		before, after, err := ReconcileHuntStats(ctx, config_obj, hunt_id)
+		if err != nil {
+			logger.Error("ReconcileHuntStats %v: %v", hunt_id, err)
+			continue
+		}
+
+		if before.TotalClientsScheduled != after.TotalClientsScheduled ||
+			before.TotalClientsWithResults != after.TotalClientsWithResults ||
+			before.TotalClientsWithErrors != after.TotalClientsWithErrors ||
+			before.TotalClientsRetrying != after.TotalClientsRetrying {
+			logger.Info("ReconcileHuntStats %v: scheduled %v -> %v, "+
+				"with results %v -> %v, with errors %v -> %v, "+
+				"retrying %v -> %v", hunt_id,
+				before.TotalClientsScheduled, after.TotalClientsScheduled,
+				before.TotalClientsWithResults, after.TotalClientsWithResults,
+				before.TotalClientsWithErrors, after.TotalClientsWithErrors,
+				before.TotalClientsRetrying, after.TotalClientsRetrying)
+		}
+	}
+}
+
+// The hunt manager decides whether to schedule a client using the
+// hunt index (so each client is only scheduled once per hunt) and
+// the hunt's TotalClientsScheduled (to honor the ClientLimit). After
+// a restart the stats may be stale since they are only flushed
+// periodically, and an index entry may be missing if the server went
+// down while scheduling. The flow index is the record of what was
+// really scheduled, so on startup we rebuild the scheduling state of
+// all running hunts from it.
+func reconcileRunningHunts(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	dispatcher *HuntDispatcher) {
+
+	now := uint64(time.Now().UnixNano() / 1000)
+	hunt_ids := []string{}
+	_ = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		if hunt.State == api_proto.Hunt_RUNNING &&
+			(hunt.Stats == nil || !hunt.Stats.Stopped) &&
+			now < hunt.Expires {
+			hunt_ids = append(hunt_ids, hunt.HuntId)
+		}
+		return nil
+	})
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	for _, hunt_id := range hunt_ids {
+		err := rebuildSchedulingState(ctx, config_obj, dispatcher, hunt_id)
+		if err != nil {
+			logger.Error("Rebuilding scheduling state of %v: %v", hunt_id, err)
+		}
+	}
+}
+
+func rebuildSchedulingState(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	dispatcher *HuntDispatcher,
+	hunt_id string) error {
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	row_chan, err := file_store.GetTimeRange(ctx, config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), 0, 0)
+	if err != nil {
+		return err
+	}
+
+	// Index entries are only ever added so this is safe to
+	// repeat for clients which are already indexed.
+	scheduled := make(map[string]bool)
+	for row := range row_chan {
+		client_id, _ := row.GetString("ClientId")
+		flow_id, _ := row.GetString("FlowId")
+		if client_id == "" || flow_id == "" || scheduled[client_id] {
+			continue
+		}
+		scheduled[client_id] = true
+
+		err = db.SetIndex(config_obj, constants.HUNT_INDEX, client_id,
+			[]string{hunt_id})
+		if err != nil {
+			return err
+		}
+	}
+
+	count := uint64(len(scheduled))
+	return dispatcher.ModifyHunt(hunt_id, func(hunt *api_proto.Hunt) error {
+		if hunt.Stats == nil {
+			hunt.Stats = &api_proto.HuntStats{}
+		}
+
+		if hunt.Stats.TotalClientsScheduled < count {
+			logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+			logger.Info("Hunt %v: %v clients scheduled but stats record %v",
+				hunt_id, count, hunt.Stats.TotalClientsScheduled)
+			hunt.Stats.TotalClientsScheduled = count
+		}
+		return nil
+	})
+}
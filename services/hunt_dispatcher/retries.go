@@ -0,0 +1,48 @@
+package hunt_dispatcher
+
+import (
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/paths"
+)
+
+// The retries of the hunt which are waiting to be scheduled.
+func ListHuntRetries(
+	config_obj *config_proto.Config,
+	hunt_id string) ([]*api_proto.HuntRetryRecord, error) {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return nil, err
+	}
+
+	urns, err := db.ListChildren(config_obj,
+		paths.HuntRetryDirectory(hunt_id), 0, 100000)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*api_proto.HuntRetryRecord{}
+	for _, urn := range urns {
+		record := &api_proto.HuntRetryRecord{}
+		err = db.GetSubject(config_obj, urn, record)
+		if err != nil || record.ClientId == "" {
+			continue
+		}
+		result = append(result, record)
+	}
+
+	return result, nil
+}
+
+// Remove the retry once it is scheduled.
+func DeleteHuntRetry(
+	config_obj *config_proto.Config, record *api_proto.HuntRetryRecord) error {
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.DeleteSubject(config_obj,
+		paths.HuntRetryPath(record.HuntId, record.ClientId))
+}
@@ -0,0 +1,118 @@
+package hunt_dispatcher
+
+import (
+	"errors"
+	"sync"
+
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+)
+
+// Each hunt object has its own lock so the stats of different hunts
+// may be updated concurrently without holding the dispatcher lock.
+// Anything touching a hunt object holds its lock - the dispatcher
+// takes it while calling back into ApplyFuncOnHunts and ModifyHunt.
+//
+// When the hunt object is replaced (it was re-read from the data
+// store) or its stats flushed, the lock is retired under the
+// dispatcher lock. An update which finds its lock retired looks the
+// hunt up again so it is applied to the current object and not lost.
+type huntLock struct {
+	mu      sync.Mutex
+	retired bool
+}
+
+// Get the lock of the hunt. Called with the dispatcher locked.
+func (self *HuntDispatcher) getHuntLock(hunt_id string) *huntLock {
+	if self.hunt_locks == nil {
+		self.hunt_locks = make(map[string]*huntLock)
+	}
+
+	hunt_lock, pres := self.hunt_locks[hunt_id]
+	if !pres {
+		hunt_lock = &huntLock{}
+		self.hunt_locks[hunt_id] = hunt_lock
+	}
+	return hunt_lock
+}
+
+// Wait for any update of the hunt to finish and retire its
+// lock. Called with the dispatcher locked.
+func (self *HuntDispatcher) retireHuntLock(hunt_id string) {
+	hunt_lock, pres := self.hunt_locks[hunt_id]
+	if !pres {
+		return
+	}
+
+	hunt_lock.mu.Lock()
+	hunt_lock.retired = true
+	hunt_lock.mu.Unlock()
+
+	delete(self.hunt_locks, hunt_id)
+}
+
+func (self *HuntDispatcher) retireHuntLocks() {
+	for hunt_id := range self.hunt_locks {
+		self.retireHuntLock(hunt_id)
+	}
+}
+
+// Look up the hunt and lock it. The caller must unlock the returned
+// lock.
+func (self *HuntDispatcher) lockHunt(
+	hunt_id string) (*api_proto.Hunt, *huntLock, error) {
+	for {
+		self.mu.Lock()
+		hunt_obj, pres := self.hunts[hunt_id]
+		if !pres {
+			self.mu.Unlock()
+			return nil, nil, errors.New("not found")
+		}
+		hunt_lock := self.getHuntLock(hunt_id)
+		self.dirty = true
+		self.mu.Unlock()
+
+		hunt_lock.mu.Lock()
+		if !hunt_lock.retired {
+			return hunt_obj, hunt_lock, nil
+		}
+		hunt_lock.mu.Unlock()
+	}
+}
+
+// Update the hunt's stats under the hunt's own lock. Unlike
+// ModifyHunt this does not hold the dispatcher lock, so updates of
+// different hunts do not wait for each other or for the foreman. The
+// callback may only modify hunt.Stats.
+func (self *HuntDispatcher) UpdateHuntStats(
+	hunt_id string, cb func(hunt *api_proto.Hunt) error) error {
+	stopped_reason, err := self.updateHuntStats(hunt_id, cb)
+
+	// Emit the state change once we released the lock.
+	if stopped_reason != "" && self.config_obj != nil {
+		emitAutoStop(self.config_obj, hunt_id, stopped_reason)
+	}
+
+	return err
+}
+
+func (self *HuntDispatcher) updateHuntStats(
+	hunt_id string, cb func(hunt *api_proto.Hunt) error) (string, error) {
+	hunt_obj, hunt_lock, err := self.lockHunt(hunt_id)
+	if err != nil {
+		return "", err
+	}
+	defer hunt_lock.mu.Unlock()
+
+	if hunt_obj.Stats == nil {
+		hunt_obj.Stats = &api_proto.HuntStats{}
+	}
+
+	err = cb(hunt_obj)
+
+	stopped_reason := checkHuntErrorRate(hunt_obj)
+	if stopped_reason != "" {
+		self.autoStopHunt(hunt_obj, stopped_reason)
+	}
+
+	return stopped_reason, err
+}
@@ -56,10 +56,12 @@ import (
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/constants"
 	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/flows"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
 	"www.velocidex.com/golang/velociraptor/utils"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/vfilter"
@@ -76,7 +78,17 @@ type ParticipationRecord struct {
 	TS          uint64 `vfilter:"optional,field=_ts"`
 }
 
-type HuntManager struct{}
+type HuntManager struct {
+	// Protects the hunt index check and update so a client is
+	// only ever scheduled once for each hunt.
+	mu sync.Mutex
+
+	// Enforces the hunts' clients_per_minute rollout rate.
+	rollout rolloutLimiter
+
+	// The estimated eligible clients of running hunts.
+	eligible eligibleClientsCache
+}
 
 func (self *HuntManager) Start(
 	ctx context.Context,
@@ -90,7 +102,37 @@ func (self *HuntManager) Start(
 		return err
 	}
 
-	return self.StartFlowCompletion(ctx, config_obj, wg)
+	err = self.StartFlowCompletion(ctx, config_obj, wg)
+	if err != nil {
+		return err
+	}
+
+	err = self.StartRetentionSweeper(ctx, config_obj, wg)
+	if err != nil {
+		return err
+	}
+
+	err = self.StartRetrySweeper(ctx, config_obj, wg)
+	if err != nil {
+		return err
+	}
+
+	err = self.StartRolloutSweeper(ctx, config_obj, wg)
+	if err != nil {
+		return err
+	}
+
+	err = self.StartProgressUpdater(ctx, config_obj, wg)
+	if err != nil {
+		return err
+	}
+
+	err = flows.StartArtifactSourcesCache(ctx, wg, config_obj)
+	if err != nil {
+		return err
+	}
+
+	return flows.StartHuntScheduler(ctx, config_obj, wg)
 }
 
 // Watch the Participation queue and schedule new collections.
@@ -195,6 +237,24 @@ func (self *HuntManager) ProcessFlowCompletion(
 		return
 	}
 
+	// The completed counts were already updated when the flow
+	// completed so the progress is current.
+	dispatcher := services.GetHuntDispatcher()
+	if dispatcher != nil {
+		_ = dispatcher.ModifyHunt(hunt_id, func(hunt_obj *api_proto.Hunt) error {
+			if hunt_obj.Stats == nil {
+				return nil
+			}
+
+			if flow.ExecutionDuration > 0 {
+				recordHuntFlowDuration(hunt_obj.Stats,
+					uint64(flow.ExecutionDuration/1000))
+			}
+			updateHuntProgress(hunt_obj, flows.HuntTimeNow())
+			return nil
+		})
+	}
+
 	path_manager := paths.NewHuntPathManager(hunt_id)
 	journal, err := services.GetJournal()
 	if err != nil {
@@ -246,28 +306,26 @@ func (self *HuntManager) ProcessRow(
 		return
 	}
 
-	// Check if we already launched it on this client. We maintain
-	// a data store index of all the clients and hunts to be able
-	// to quickly check if a certain hunt ran on a particular
-	// client. We dont care too much how fast this is because the
-	// hunt manager is running as an independent service and not
-	// in the critical path.
-	db, err := datastore.GetDB(config_obj)
-	if err != nil {
+	// Excluded clients are not marked as scheduled so they may be
+	// scheduled if they are included again.
+	if !participation_row.Override &&
+		huntExcludesClient(participation_row.HuntId, participation_row.ClientId) {
+		scope.Log("hunt manager: %v is excluded from hunt %v",
+			participation_row.ClientId, participation_row.HuntId)
 		return
 	}
 
-	hunt_ids := []string{participation_row.HuntId}
-	err = db.CheckIndex(config_obj, constants.HUNT_INDEX,
-		participation_row.ClientId, hunt_ids)
-	if err == nil {
+	scheduled, err := self.markClientScheduled(config_obj,
+		participation_row.ClientId, participation_row.HuntId)
+	if err != nil {
+		scope.Log("Setting hunt index: %v", err)
 		return
 	}
 
-	err = db.SetIndex(config_obj, constants.HUNT_INDEX,
-		participation_row.ClientId, hunt_ids)
-	if err != nil {
-		scope.Log("Setting hunt index: %v", err)
+	// The client may report the same hunt more than once (e.g. it
+	// polls again before the first participation is processed) -
+	// we only schedule it once.
+	if !scheduled {
 		return
 	}
 
@@ -276,8 +334,37 @@ func (self *HuntManager) ProcessRow(
 		Creator:  participation_row.HuntId,
 	}
 
+	// The hunt's condition VQL may take a while so we evaluate it
+	// before locking the hunt.
+	if !participation_row.Override {
+		matched, err := huntMatchesConditionVQL(ctx, config_obj,
+			participation_row.HuntId, participation_row.ClientId)
+
+		// The condition may be evaluated when the client
+		// participates again, so the client is not marked as
+		// scheduled.
+		if err != nil {
+			scope.Log("hunt manager: hunt %v: %v", participation_row.HuntId, err)
+			err = self.unmarkClientScheduled(config_obj,
+				participation_row.ClientId, participation_row.HuntId)
+			if err != nil {
+				scope.Log("Setting hunt index: %v", err)
+			}
+			return
+		}
+
+		if !matched {
+			scope.Log("hunt manager: %v does not match the condition VQL of hunt %v",
+				participation_row.ClientId, participation_row.HuntId)
+			return
+		}
+	}
+
 	// Get hunt information about this hunt.
-	now := uint64(time.Now().UnixNano() / 1000)
+	now := flows.HuntTimeNow()
+	stopped_reason := ""
+	queued := false
+	excluded := false
 	err = services.GetHuntDispatcher().ModifyHunt(
 		participation_row.HuntId,
 		func(hunt_obj *api_proto.Hunt) error {
@@ -289,6 +376,7 @@ func (self *HuntManager) ProcessRow(
 			// logic.
 			if participation_row.Override {
 				proto.Merge(request, hunt_obj.StartRequest)
+				applyLabelParameters(config_obj, hunt_obj, request)
 				hunt_obj.Stats.TotalClientsScheduled += 1
 
 				return nil
@@ -300,10 +388,20 @@ func (self *HuntManager) ProcessRow(
 				return errors.New("hunt is stopped")
 			}
 
+			// The client was excluded since we checked above.
+			if flows.HuntExcludesClient(hunt_obj, participation_row.ClientId) {
+				excluded = true
+				return errors.New("client is excluded from the hunt")
+			}
+
 			if !huntMatchesOS(hunt_obj, client_info) {
 				return errors.New("Hunt does not match OS condition")
 			}
 
+			if !flows.ClientMatchesVersion(hunt_obj, client_info) {
+				return errors.New("Client version is outside the hunt's version range")
+			}
+
 			// Ignore hunts with label conditions which
 			// exclude this client.
 			if !huntHasLabel(config_obj, hunt_obj,
@@ -312,78 +410,193 @@ func (self *HuntManager) ProcessRow(
 			}
 
 			// Hunt limit exceeded or it expired - we stop it.
-			if (hunt_obj.ClientLimit > 0 &&
-				hunt_obj.Stats.TotalClientsScheduled >= hunt_obj.ClientLimit) ||
-				now > hunt_obj.Expires {
-
+			stopped_reason = huntStoppedReason(hunt_obj, now)
+			if stopped_reason != "" {
 				// Stop the hunt.
 				hunt_obj.Stats.Stopped = true
 				return errors.New("hunt is expired")
 			}
 
+			// Beyond the hunt's rollout rate the client waits
+			// its turn, behind any clients already waiting.
+			if hunt_obj.ClientsPerMinute > 0 {
+				allowed := false
+				if hunt_obj.Stats.TotalClientsQueued == 0 {
+					allowed, hunt_obj.Stats.SchedulingRate = self.rollout.Allow(
+						hunt_obj.HuntId, hunt_obj.ClientsPerMinute, time.Now())
+				}
+
+				if !allowed {
+					hunt_obj.Stats.TotalClientsQueued++
+					queued = true
+					return errRolloutRateReached
+				}
+			}
+
 			// Use hunt information to launch the flow
 			// against this client.
 			proto.Merge(request, hunt_obj.StartRequest)
+			applyLabelParameters(config_obj, hunt_obj, request)
 			hunt_obj.Stats.TotalClientsScheduled += 1
 
 			return nil
 		})
 
+	if stopped_reason != "" {
+		flows.EmitHuntStateChange(config_obj, participation_row.HuntId,
+			api_proto.Hunt_RUNNING, api_proto.Hunt_STOPPED, "",
+			stopped_reason)
+	}
+
+	if excluded {
+		err = self.unmarkClientScheduled(config_obj,
+			participation_row.ClientId, participation_row.HuntId)
+		if err != nil {
+			scope.Log("Setting hunt index: %v", err)
+		}
+		return
+	}
+
+	if queued {
+		err = hunt_dispatcher.QueueHuntClient(config_obj,
+			&api_proto.HuntQueuedClient{
+				HuntId:     participation_row.HuntId,
+				ClientId:   participation_row.ClientId,
+				QueuedTime: now,
+			})
+		if err != nil {
+			scope.Log("hunt manager: queueing %v: %v",
+				participation_row.ClientId, err)
+		}
+		return
+	}
+
 	if err != nil {
 		return
 	}
 
+	// Direct the request against our client and schedule it.
+	request.ClientId = participation_row.ClientId
+	err = launchHuntFlow(ctx, config_obj, participation_row.HuntId, request, row)
+	if err != nil {
+		scope.Log("hunt manager: %v", err)
+	}
+}
+
+// Why the hunt must stop before scheduling another client, or "".
+func huntStoppedReason(hunt_obj *api_proto.Hunt, now uint64) string {
+	if hunt_obj.ClientLimit > 0 &&
+		hunt_obj.Stats.TotalClientsScheduled >= hunt_obj.ClientLimit {
+		return "client limit reached"
+	}
+
+	if now > hunt_obj.Expires {
+		return "expired"
+	}
+
+	return ""
+}
+
+// Schedule the hunt's collection on the client, record the flow in
+// the hunt's flow index (using row as the index entry) and notify the
+// client.
+func launchHuntFlow(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string,
+	request *flows_proto.ArtifactCollectorArgs,
+	row *ordereddict.Dict) error {
 	manager, err := services.GetRepositoryManager()
 	if err != nil {
-		scope.Log("hunt_manager: %v", err)
-		return
+		return err
 	}
 
 	repository, err := manager.GetGlobalRepository(config_obj)
 	if err != nil {
-		scope.Log("hunt manager: GetGlobalRepository: %v", err)
-		return
+		return fmt.Errorf("GetGlobalRepository: %v", err)
 	}
 
 	launcher, err := services.GetLauncher()
 	if err != nil {
-		return
+		return err
 	}
 
-	// Direct the request against our client and schedule it.
-	request.ClientId = participation_row.ClientId
 	flow_id, err := launcher.ScheduleArtifactCollection(
 		ctx, config_obj, vql_subsystem.NullACLManager{}, repository, request)
 	if err != nil {
-		scope.Log("hunt manager: %v", err)
-		return
+		return err
 	}
 
 	row.Set("FlowId", flow_id)
-	row.Set("Timestamp", time.Now().Unix())
+	row.Set("Timestamp", flows.HuntTimeNow())
 	journal, err := services.GetJournal()
 	if err != nil {
-		scope.Log("hunt manager: %v", err)
-		return
+		return err
 	}
 
-	path_manager := paths.NewHuntPathManager(participation_row.HuntId)
+	path_manager := paths.NewHuntPathManager(hunt_id)
 	err = journal.PushRows(config_obj,
 		path_manager.Clients(), []*ordereddict.Dict{row})
 	if err != nil {
-		scope.Log("hunt manager: %v", err)
-		return
+		return err
 	}
 
 	// Notify the client
 	notifier := services.GetNotifier()
 	if notifier != nil {
-		err = services.GetNotifier().NotifyListener(
-			config_obj, participation_row.ClientId)
-		if err != nil {
-			scope.Log("hunt manager: %v", err)
-		}
+		return notifier.NotifyListener(config_obj, request.ClientId)
 	}
+
+	return nil
+}
+
+// Record that the hunt is scheduled on the client, returning false if
+// it already was. We maintain a data store index of all the clients
+// and hunts to be able to quickly check if a certain hunt ran on a
+// particular client. Being in the data store, the record survives
+// restarts and hunt dispatcher refreshes. We dont care too much how
+// fast this is because the hunt manager is running as an independent
+// service and not in the critical path.
+func (self *HuntManager) markClientScheduled(
+	config_obj *config_proto.Config,
+	client_id, hunt_id string) (bool, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return false, err
+	}
+
+	hunt_ids := []string{hunt_id}
+	err = db.CheckIndex(config_obj, constants.HUNT_INDEX, client_id, hunt_ids)
+	if err == nil {
+		return false, nil
+	}
+
+	err = db.SetIndex(config_obj, constants.HUNT_INDEX, client_id, hunt_ids)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Forget that the hunt was scheduled on the client, so it is
+// scheduled when it checks in again.
+func (self *HuntManager) unmarkClientScheduled(
+	config_obj *config_proto.Config,
+	client_id, hunt_id string) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	db, err := datastore.GetDB(config_obj)
+	if err != nil {
+		return err
+	}
+
+	return db.UnsetIndex(config_obj, constants.HUNT_INDEX, client_id,
+		[]string{hunt_id})
 }
 
 func StartHuntManager(
@@ -396,6 +609,27 @@ func StartHuntManager(
 }
 
 // Check if the client should be scheduled based on required labels.
+// The hunt's compiled request is shared by all clients, but clients
+// carrying labels with parameter overrides get their own copy with
+// the overrides applied.
+func applyLabelParameters(
+	config_obj *config_proto.Config,
+	hunt_obj *api_proto.Hunt,
+	request *flows_proto.ArtifactCollectorArgs) {
+	if len(hunt_obj.LabelParameters) == 0 {
+		return
+	}
+
+	labeler := services.GetLabeler()
+	compiled, labels := flows.ComposeHuntCollectorArgs(config_obj, hunt_obj,
+		func(label string) bool {
+			return labeler.IsLabelSet(config_obj, request.ClientId, label)
+		})
+	if len(labels) > 0 {
+		request.CompiledCollectorArgs = compiled
+	}
+}
+
 func huntHasLabel(
 	config_obj *config_proto.Config,
 	hunt_obj *api_proto.Hunt, client_id string) bool {
@@ -442,6 +676,49 @@ func huntHasExcludeLabel(
 	return true
 }
 
+func huntExcludesClient(hunt_id, client_id string) bool {
+	excluded := false
+	_ = services.GetHuntDispatcher().ApplyFuncOnHunts(
+		func(hunt_obj *api_proto.Hunt) error {
+			if hunt_obj.HuntId != hunt_id {
+				return nil
+			}
+			excluded = flows.HuntExcludesClient(hunt_obj, client_id)
+			return services.StopHuntIteration
+		})
+	return excluded
+}
+
+func huntMatchesConditionVQL(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id, client_id string) (bool, error) {
+	var hunt *api_proto.Hunt
+	_ = services.GetHuntDispatcher().ApplyFuncOnHunts(
+		func(hunt_obj *api_proto.Hunt) error {
+			if hunt_obj.HuntId != hunt_id {
+				return nil
+			}
+
+			if hunt_obj.Condition != nil &&
+				hunt_obj.Condition.ConditionVql != "" {
+				hunt = &api_proto.Hunt{
+					HuntId:  hunt_obj.HuntId,
+					Creator: hunt_obj.Creator,
+					Condition: proto.Clone(
+						hunt_obj.Condition).(*api_proto.HuntCondition),
+				}
+			}
+			return services.StopHuntIteration
+		})
+
+	if hunt == nil {
+		return true, nil
+	}
+
+	return flows.EvaluateConditionVQL(ctx, config_obj, hunt, client_id)
+}
+
 func huntMatchesOS(hunt_obj *api_proto.Hunt, client_info *services.ClientInfo) bool {
 	if hunt_obj.Condition == nil {
 		return true
@@ -2,10 +2,12 @@ package hunt_manager
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/Velocidex/ordereddict"
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,13 +17,16 @@ import (
 	"www.velocidex.com/golang/velociraptor/config"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/constants"
+	crypto_proto "www.velocidex.com/golang/velociraptor/crypto/proto"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/flows"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
 	"www.velocidex.com/golang/velociraptor/paths"
 	"www.velocidex.com/golang/velociraptor/paths/artifacts"
 	"www.velocidex.com/golang/velociraptor/services"
 	"www.velocidex.com/golang/velociraptor/services/client_info"
+	"www.velocidex.com/golang/velociraptor/services/client_monitoring"
 	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
 	"www.velocidex.com/golang/velociraptor/services/inventory"
 	"www.velocidex.com/golang/velociraptor/services/journal"
@@ -29,7 +34,9 @@ import (
 	"www.velocidex.com/golang/velociraptor/services/launcher"
 	"www.velocidex.com/golang/velociraptor/services/notifications"
 	"www.velocidex.com/golang/velociraptor/services/repository"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	"www.velocidex.com/golang/velociraptor/vtesting"
+	"www.velocidex.com/golang/vfilter"
 )
 
 type HuntTestSuite struct {
@@ -125,6 +132,57 @@ func (self *HuntTestSuite) TestHuntManager() {
 	assert.Equal(t, collection_context.Request.Artifacts, self.expected.Artifacts)
 }
 
+func (self *HuntTestSuite) TestHuntExpiryStateChange() {
+	t := self.T()
+
+	// The hunt has already expired.
+	hunt_obj := &api_proto.Hunt{
+		HuntId:       self.hunt_id,
+		StartRequest: self.expected,
+		State:        api_proto.Hunt_RUNNING,
+		Stats:        &api_proto.HuntStats{},
+		Expires:      flows.HuntTimeFromTime(time.Now().Add(-time.Hour)),
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	state_changes, cancel := journal.Watch("System.Hunt.StateChange")
+	defer cancel()
+
+	journal.PushRowsToArtifact(self.config_obj,
+		[]*ordereddict.Dict{ordereddict.NewDict().
+			Set("HuntId", self.hunt_id).
+			Set("ClientId", self.client_id).
+			Set("Participate", true)},
+		"System.Hunt.Participation", self.client_id, "")
+
+	select {
+	case row := <-state_changes:
+		hunt_id, _ := row.GetString("HuntId")
+		assert.Equal(t, self.hunt_id, hunt_id)
+
+		old_state, _ := row.GetString("OldState")
+		new_state, _ := row.GetString("NewState")
+		reason, _ := row.GetString("Reason")
+		assert.Equal(t, "RUNNING", old_state)
+		assert.Equal(t, "STOPPED", new_state)
+		assert.Equal(t, "expired", reason)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("No state change for expired hunt %v", self.hunt_id)
+	}
+}
+
 func (self *HuntTestSuite) TestHuntWithLabelClientNoLabel() {
 	t := self.T()
 
@@ -141,10 +199,8 @@ func (self *HuntTestSuite) TestHuntWithLabelClientNoLabel() {
 		Stats:        &api_proto.HuntStats{},
 		Expires:      uint64(time.Now().Add(7*24*time.Hour).UTC().UnixNano() / 1000),
 		Condition: &api_proto.HuntCondition{
-			UnionField: &api_proto.HuntCondition_Labels{
-				Labels: &api_proto.HuntLabelCondition{
-					Label: []string{"MyLabel"},
-				},
+			Labels: &api_proto.HuntLabelCondition{
+				Label: []string{"MyLabel"},
 			},
 		},
 	}
@@ -199,10 +255,8 @@ func (self *HuntTestSuite) TestHuntWithLabelClientHasLabelDifferentCase() {
 		Stats:        &api_proto.HuntStats{},
 		Expires:      uint64(time.Now().Add(7*24*time.Hour).UTC().UnixNano() / 1000),
 		Condition: &api_proto.HuntCondition{
-			UnionField: &api_proto.HuntCondition_Labels{
-				Labels: &api_proto.HuntLabelCondition{
-					Label: []string{"LABEL"}, // Upper case condition
-				},
+			Labels: &api_proto.HuntLabelCondition{
+				Label: []string{"LABEL"}, // Upper case condition
 			},
 		},
 	}
@@ -325,10 +379,8 @@ func (self *HuntTestSuite) TestHuntWithLabelClientHasLabel() {
 		Stats:        &api_proto.HuntStats{},
 		Expires:      uint64(time.Now().Add(7*24*time.Hour).UTC().UnixNano() / 1000),
 		Condition: &api_proto.HuntCondition{
-			UnionField: &api_proto.HuntCondition_Labels{
-				Labels: &api_proto.HuntLabelCondition{
-					Label: []string{"MyLabel"},
-				},
+			Labels: &api_proto.HuntLabelCondition{
+				Label: []string{"MyLabel"},
 			},
 		},
 	}
@@ -394,10 +446,8 @@ func (self *HuntTestSuite) TestHuntWithLabelClientHasExcludedLabel() {
 		Stats:        &api_proto.HuntStats{},
 		Expires:      uint64(time.Now().Add(7*24*time.Hour).UTC().UnixNano() / 1000),
 		Condition: &api_proto.HuntCondition{
-			UnionField: &api_proto.HuntCondition_Labels{
-				Labels: &api_proto.HuntLabelCondition{
-					Label: []string{"MyLabel"},
-				},
+			Labels: &api_proto.HuntLabelCondition{
+				Label: []string{"MyLabel"},
 			},
 			// Exclude all clients belonging to this label.
 			ExcludedLabels: &api_proto.HuntLabelCondition{
@@ -449,6 +499,97 @@ func (self *HuntTestSuite) TestHuntWithLabelClientHasExcludedLabel() {
 	assert.Error(t, err)
 }
 
+func (self *HuntTestSuite) TestHuntExcludedClient() {
+	t := self.T()
+
+	launcher, err := services.GetLauncher()
+	assert.NoError(t, err)
+
+	launcher.SetFlowIdForTests("F.1234")
+
+	hunt_obj := &api_proto.Hunt{
+		HuntId:          self.hunt_id,
+		StartRequest:    self.expected,
+		State:           api_proto.Hunt_RUNNING,
+		Stats:           &api_proto.HuntStats{},
+		Expires:         flows.HuntTimeFromTime(time.Now().Add(time.Hour)),
+		ExcludedClients: []string{self.client_id},
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	// Simulate a System.Hunt.Participation event
+	path_manager := artifacts.NewArtifactPathManager(self.config_obj,
+		self.client_id, "", "System.Hunt.Participation")
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	// Another client checks in after the excluded one so we know
+	// when the excluded client was processed.
+	other_client_id := "C.12322"
+	err = db.SetSubject(self.config_obj,
+		paths.NewClientPathManager(other_client_id).Path(),
+		&actions_proto.ClientInfo{ClientId: other_client_id})
+	assert.NoError(t, err)
+
+	participate := func(client_id string) *ordereddict.Dict {
+		return ordereddict.NewDict().
+			Set("HuntId", self.hunt_id).
+			Set("ClientId", client_id).
+			Set("Fqdn", "MyHost").
+			Set("Participate", true)
+	}
+
+	journal.PushRows(self.config_obj, path_manager,
+		[]*ordereddict.Dict{participate(self.client_id),
+			participate(other_client_id)})
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		err = db.CheckIndex(self.config_obj, constants.HUNT_INDEX,
+			other_client_id, []string{hunt_obj.HuntId})
+		return err == nil
+	})
+
+	// The client is not scheduled but the hunt keeps running.
+	hunt, err := flows.GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: self.hunt_id})
+	assert.NoError(t, err)
+	assert.Equal(t, api_proto.Hunt_RUNNING, hunt.State)
+	assert.Equal(t, uint64(1), hunt.Stats.TotalClientsScheduled)
+
+	_, err = LoadCollectionContext(self.config_obj, self.client_id, "F.1234")
+	assert.Error(t, err)
+
+	// The excluded client is not marked as scheduled so it is
+	// scheduled once it is included again.
+	err = db.CheckIndex(self.config_obj, constants.HUNT_INDEX,
+		self.client_id, []string{hunt_obj.HuntId})
+	assert.Error(t, err)
+
+	err = services.GetHuntDispatcher().ModifyHunt(self.hunt_id,
+		func(hunt *api_proto.Hunt) error {
+			hunt.ExcludedClients = nil
+			return nil
+		})
+	assert.NoError(t, err)
+
+	launcher.SetFlowIdForTests("F.1234")
+	journal.PushRows(self.config_obj, path_manager,
+		[]*ordereddict.Dict{participate(self.client_id)})
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		_, err := LoadCollectionContext(self.config_obj, self.client_id, "F.1234")
+		return err == nil
+	})
+}
+
 func (self *HuntTestSuite) TestHuntClientOSCondition() {
 	t := self.T()
 
@@ -465,10 +606,8 @@ func (self *HuntTestSuite) TestHuntClientOSCondition() {
 		Stats:        &api_proto.HuntStats{},
 		Expires:      uint64(time.Now().Add(7*24*time.Hour).UTC().UnixNano() / 1000),
 		Condition: &api_proto.HuntCondition{
-			UnionField: &api_proto.HuntCondition_Os{
-				Os: &api_proto.HuntOsCondition{
-					Os: api_proto.HuntOsCondition_WINDOWS,
-				},
+			Os: &api_proto.HuntOsCondition{
+				Os: api_proto.HuntOsCondition_WINDOWS,
 			},
 		},
 	}
@@ -537,6 +676,1804 @@ func (self *HuntTestSuite) TestHuntClientOSCondition() {
 	assert.Error(t, err)
 }
 
+// A hunt can be restricted to clients carrying a label and running
+// an OS at the same time.
+func (self *HuntTestSuite) TestHuntClientLabelAndOSCondition() {
+	t := self.T()
+
+	launcher, err := services.GetLauncher()
+	assert.NoError(t, err)
+
+	launcher.SetFlowIdForTests("F.1234")
+
+	hunt_obj := &api_proto.Hunt{
+		HuntId:       self.hunt_id,
+		StartRequest: self.expected,
+		State:        api_proto.Hunt_RUNNING,
+		Stats:        &api_proto.HuntStats{},
+		Expires:      uint64(time.Now().Add(7*24*time.Hour).UTC().UnixNano() / 1000),
+		Condition: &api_proto.HuntCondition{
+			Labels: &api_proto.HuntLabelCondition{
+				Label: []string{"MyLabel"},
+			},
+			Os: &api_proto.HuntOsCondition{
+				Os: api_proto.HuntOsCondition_WINDOWS,
+			},
+		},
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	// A labeled windows client, an unlabeled windows client and a
+	// labeled linux client.
+	client_ids := []string{"C.12321", "C.12322", "C.12323"}
+	systems := []string{"windows", "windows", "linux"}
+	labeler := services.GetLabeler()
+	for i, client_id := range client_ids {
+		client_path_manager := paths.NewClientPathManager(client_id)
+		err = db.SetSubject(self.config_obj,
+			client_path_manager.Path(), &actions_proto.ClientInfo{
+				System: systems[i],
+			})
+		assert.NoError(t, err)
+
+		if i != 1 {
+			err = labeler.SetClientLabel(self.config_obj, client_id, "MyLabel")
+			assert.NoError(t, err)
+		}
+	}
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	// Simulate a System.Hunt.Participation event
+	path_manager := artifacts.NewArtifactPathManager(self.config_obj,
+		"server", "", "System.Hunt.Participation")
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	rows := []*ordereddict.Dict{}
+	for _, client_id := range client_ids {
+		rows = append(rows, ordereddict.NewDict().
+			Set("HuntId", self.hunt_id).
+			Set("ClientId", client_id).
+			Set("Fqdn", "MyHost").
+			Set("Participate", true))
+	}
+	journal.PushRows(self.config_obj, path_manager, rows)
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		for _, client_id := range client_ids {
+			err := db.CheckIndex(self.config_obj, constants.HUNT_INDEX,
+				client_id, []string{hunt_obj.HuntId})
+			if err != nil {
+				return false
+			}
+		}
+		return true
+	})
+
+	// Only the labeled windows client matches both conditions.
+	_, err = LoadCollectionContext(self.config_obj, client_ids[0], "F.1234")
+	assert.NoError(t, err)
+
+	_, err = LoadCollectionContext(self.config_obj, client_ids[1], "F.1234")
+	assert.Error(t, err)
+
+	_, err = LoadCollectionContext(self.config_obj, client_ids[2], "F.1234")
+	assert.Error(t, err)
+}
+
+func (self *HuntTestSuite) TestHuntMinClientVersionCondition() {
+	t := self.T()
+
+	launcher, err := services.GetLauncher()
+	assert.NoError(t, err)
+
+	launcher.SetFlowIdForTests("F.1234")
+
+	hunt_obj := &api_proto.Hunt{
+		HuntId:       self.hunt_id,
+		StartRequest: self.expected,
+		State:        api_proto.Hunt_RUNNING,
+		Stats:        &api_proto.HuntStats{},
+		Expires:      uint64(time.Now().Add(7*24*time.Hour).UTC().UnixNano() / 1000),
+		Condition: &api_proto.HuntCondition{
+			MinClientVersion: "0.5.4",
+		},
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	// A new, an old and an unknown version client.
+	client_ids := []string{"C.12321", "C.12322", "C.12323"}
+	for i, version := range []string{"0.5.4", "0.5.3", ""} {
+		client_path_manager := paths.NewClientPathManager(client_ids[i])
+		err = db.SetSubject(self.config_obj,
+			client_path_manager.Path(), &actions_proto.ClientInfo{
+				ClientVersion: version,
+			})
+		assert.NoError(t, err)
+	}
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	// Simulate a System.Hunt.Participation event
+	path_manager := artifacts.NewArtifactPathManager(self.config_obj,
+		"server", "", "System.Hunt.Participation")
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	rows := []*ordereddict.Dict{}
+	for _, client_id := range client_ids {
+		rows = append(rows, ordereddict.NewDict().
+			Set("HuntId", self.hunt_id).
+			Set("ClientId", client_id).
+			Set("Participate", true))
+	}
+	journal.PushRows(self.config_obj, path_manager, rows)
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		err = db.CheckIndex(self.config_obj, constants.HUNT_INDEX,
+			client_ids[2], []string{hunt_obj.HuntId})
+		return err == nil
+	})
+
+	// Only the client which is recent enough is scheduled.
+	_, err = LoadCollectionContext(self.config_obj, client_ids[0], "F.1234")
+	assert.NoError(t, err)
+
+	_, err = LoadCollectionContext(self.config_obj, client_ids[1], "F.1234")
+	assert.Error(t, err)
+
+	_, err = LoadCollectionContext(self.config_obj, client_ids[2], "F.1234")
+	assert.Error(t, err)
+}
+
+func (self *HuntTestSuite) TestHuntConditionVQL() {
+	t := self.T()
+
+	launcher, err := services.GetLauncher()
+	assert.NoError(t, err)
+
+	launcher.SetFlowIdForTests("F.1234")
+
+	hunt_obj := &api_proto.Hunt{
+		HuntId:       self.hunt_id,
+		StartRequest: self.expected,
+		State:        api_proto.Hunt_RUNNING,
+		Stats:        &api_proto.HuntStats{},
+		Expires:      uint64(time.Now().Add(7*24*time.Hour).UTC().UnixNano() / 1000),
+		Condition: &api_proto.HuntCondition{
+			ConditionVql: "Client.Release =~ 'Server'",
+		},
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	// Only the server matches.
+	client_ids := []string{"C.12321", "C.12322"}
+	for i, release := range []string{"Windows Server 2019", "Windows 10"} {
+		client_path_manager := paths.NewClientPathManager(client_ids[i])
+		err = db.SetSubject(self.config_obj,
+			client_path_manager.Path(), &actions_proto.ClientInfo{
+				System:  "windows",
+				Release: release,
+			})
+		assert.NoError(t, err)
+	}
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	// Simulate a System.Hunt.Participation event
+	path_manager := artifacts.NewArtifactPathManager(self.config_obj,
+		"server", "", "System.Hunt.Participation")
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	rows := []*ordereddict.Dict{}
+	for _, client_id := range client_ids {
+		rows = append(rows, ordereddict.NewDict().
+			Set("HuntId", self.hunt_id).
+			Set("ClientId", client_id).
+			Set("Participate", true))
+	}
+	journal.PushRows(self.config_obj, path_manager, rows)
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		err = db.CheckIndex(self.config_obj, constants.HUNT_INDEX,
+			client_ids[1], []string{hunt_obj.HuntId})
+		return err == nil
+	})
+
+	_, err = LoadCollectionContext(self.config_obj, client_ids[0], "F.1234")
+	assert.NoError(t, err)
+
+	_, err = LoadCollectionContext(self.config_obj, client_ids[1], "F.1234")
+	assert.Error(t, err)
+
+	// The client which did not match is not counted as scheduled.
+	hunt, err := flows.GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: self.hunt_id, StatsOnly: true})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), hunt.Stats.TotalClientsScheduled)
+}
+
+func (self *HuntTestSuite) TestHuntConditionVQLTimeout() {
+	t := self.T()
+
+	launcher, err := services.GetLauncher()
+	assert.NoError(t, err)
+
+	launcher.SetFlowIdForTests("F.1234")
+
+	hunt_obj := &api_proto.Hunt{
+		HuntId:       self.hunt_id,
+		StartRequest: self.expected,
+		State:        api_proto.Hunt_RUNNING,
+		Stats:        &api_proto.HuntStats{},
+		Expires:      uint64(time.Now().Add(7*24*time.Hour).UTC().UnixNano() / 1000),
+		Condition: &api_proto.HuntCondition{
+			ConditionVql: "Client.Release =~ 'Server'",
+		},
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	client_path_manager := paths.NewClientPathManager(self.client_id)
+	err = db.SetSubject(self.config_obj,
+		client_path_manager.Path(), &actions_proto.ClientInfo{
+			System:  "windows",
+			Release: "Windows Server 2019",
+		})
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	row := ordereddict.NewDict().
+		Set("HuntId", self.hunt_id).
+		Set("ClientId", self.client_id).
+		Set("Participate", true)
+
+	// The first evaluation runs out of time.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	scope := vql_subsystem.MakeScope()
+	defer scope.Close()
+
+	(&HuntManager{}).ProcessRow(ctx, self.config_obj, scope, row)
+
+	// The client is not marked as scheduled.
+	err = db.CheckIndex(self.config_obj, constants.HUNT_INDEX,
+		self.client_id, []string{hunt_obj.HuntId})
+	assert.Error(t, err)
+
+	_, err = LoadCollectionContext(self.config_obj, self.client_id, "F.1234")
+	assert.Error(t, err)
+
+	// So it is scheduled when it participates again.
+	path_manager := artifacts.NewArtifactPathManager(self.config_obj,
+		"server", "", "System.Hunt.Participation")
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	journal.PushRows(self.config_obj, path_manager,
+		[]*ordereddict.Dict{row})
+
+	vtesting.WaitUntil(5*time.Second, self.T(), func() bool {
+		_, err = LoadCollectionContext(self.config_obj, self.client_id, "F.1234")
+		return err == nil
+	})
+}
+
+func (self *HuntTestSuite) TestHuntRetention() {
+	t := self.T()
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	stopped_hunt := &api_proto.Hunt{
+		HuntId:        self.hunt_id,
+		StartRequest:  self.expected,
+		State:         api_proto.Hunt_STOPPED,
+		Stats:         &api_proto.HuntStats{},
+		RetentionDays: 1,
+	}
+
+	running_hunt := &api_proto.Hunt{
+		HuntId:        self.hunt_id + "R",
+		StartRequest:  self.expected,
+		State:         api_proto.Hunt_RUNNING,
+		Stats:         &api_proto.HuntStats{},
+		RetentionDays: 1,
+	}
+
+	file_store_factory := test_utils.GetMemoryFileStore(t, self.config_obj)
+	for _, hunt_obj := range []*api_proto.Hunt{stopped_hunt, running_hunt} {
+		hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+		err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+		assert.NoError(t, err)
+
+		download_file := hunt_path_manager.GetHuntDownloadsFile(false, "")
+		file_store_factory.Data[download_file] = []byte("data")
+	}
+
+	// A download that is still being prepared.
+	in_progress := paths.NewHuntPathManager(stopped_hunt.HuntId).
+		GetHuntDownloadsFile(true, "")
+	file_store_factory.Data[in_progress] = []byte("data")
+	file_store_factory.Data[in_progress+".lock"] = []byte("X")
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	// The memory file store reports files as infinitely old so
+	// they are all outside the retention period.
+	err = SweepRetention(context.Background(), self.config_obj, time.Now())
+	assert.NoError(t, err)
+
+	// The stopped hunt's download is removed.
+	_, pres := file_store_factory.Get(
+		paths.NewHuntPathManager(stopped_hunt.HuntId).
+			GetHuntDownloadsFile(false, ""))
+	assert.False(t, pres)
+
+	// Locked downloads are left alone.
+	_, pres = file_store_factory.Get(in_progress)
+	assert.True(t, pres)
+
+	// Running hunts are never touched.
+	_, pres = file_store_factory.Get(
+		paths.NewHuntPathManager(running_hunt.HuntId).
+			GetHuntDownloadsFile(false, ""))
+	assert.True(t, pres)
+}
+
+func (self *HuntTestSuite) TestReconcileHuntStats() {
+	t := self.T()
+
+	// The cached stats have drifted from the flow records.
+	hunt_obj := &api_proto.Hunt{
+		HuntId:       self.hunt_id,
+		StartRequest: self.expected,
+		State:        api_proto.Hunt_STOPPED,
+		Stats: &api_proto.HuntStats{
+			TotalClientsScheduled:  5,
+			TotalClientsWithErrors: 2,
+		},
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Stats().Path(),
+		hunt_obj.Stats)
+	assert.NoError(t, err)
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	// Only a single flow was actually scheduled and it completed.
+	err = db.SetSubject(self.config_obj,
+		paths.NewFlowPathManager(self.client_id, "F.1234").Path(),
+		&flows_proto.ArtifactCollectorContext{
+			SessionId: "F.1234",
+			ClientId:  self.client_id,
+			State:     flows_proto.ArtifactCollectorContext_FINISHED,
+		})
+	assert.NoError(t, err)
+
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	err = journal.PushRows(self.config_obj, hunt_path_manager.Clients(),
+		[]*ordereddict.Dict{ordereddict.NewDict().
+			Set("HuntId", self.hunt_id).
+			Set("ClientId", self.client_id).
+			Set("FlowId", "F.1234").
+			Set("Participate", true)})
+	assert.NoError(t, err)
+
+	before, after, err := hunt_dispatcher.ReconcileHuntStats(
+		context.Background(), self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(5), before.TotalClientsScheduled)
+	assert.Equal(t, uint64(2), before.TotalClientsWithErrors)
+
+	assert.Equal(t, uint64(1), after.TotalClientsScheduled)
+	assert.Equal(t, uint64(1), after.TotalClientsWithResults)
+	assert.Equal(t, uint64(0), after.TotalClientsWithErrors)
+}
+
+func (self *HuntTestSuite) TestHuntPriority() {
+	t := self.T()
+
+	require.NoError(t, self.sm.Start(
+		client_monitoring.StartClientMonitoringService))
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	// The low priority hunt is older so would otherwise be
+	// scheduled first.
+	low_hunt_id := self.hunt_id + "Low"
+	high_hunt_id := self.hunt_id + "High"
+	for i, hunt_obj := range []*api_proto.Hunt{
+		{HuntId: low_hunt_id, Priority: 0},
+		{HuntId: high_hunt_id, Priority: 10},
+	} {
+		hunt_obj.CreateTime = uint64(i + 1)
+		hunt_obj.StartTime = uint64(i + 1)
+		hunt_obj.StartRequest = proto.Clone(
+			self.expected).(*flows_proto.ArtifactCollectorArgs)
+		hunt_obj.StartRequest.Creator = hunt_obj.HuntId
+		hunt_obj.State = api_proto.Hunt_RUNNING
+		hunt_obj.Stats = &api_proto.HuntStats{}
+		hunt_obj.Expires = uint64(time.Now().Add(
+			7*24*time.Hour).UTC().UnixNano() / 1000)
+
+		hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+		err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+		assert.NoError(t, err)
+	}
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	// The client checks in and participates in both hunts.
+	err = flows.ForemanProcessMessage(context.Background(), self.config_obj,
+		self.client_id, &actions_proto.ForemanCheckin{})
+	assert.NoError(t, err)
+
+	// Collect the hunts of the flows scheduled on the client in
+	// the order they were queued.
+	scheduled := func() []string {
+		result := []string{}
+		tasks, _ := db.GetClientTasks(self.config_obj, self.client_id, true)
+		for _, task := range tasks {
+			collection_context, err := LoadCollectionContext(
+				self.config_obj, self.client_id, task.SessionId)
+			if err == nil && (len(result) == 0 ||
+				result[len(result)-1] != collection_context.Request.Creator) {
+				result = append(result, collection_context.Request.Creator)
+			}
+		}
+		return result
+	}
+
+	vtesting.WaitUntil(5*time.Second, t, func() bool {
+		return len(scheduled()) == 2
+	})
+
+	assert.Equal(t, []string{high_hunt_id, low_hunt_id}, scheduled())
+}
+
+func (self *HuntTestSuite) TestHuntMaxHuntsPerClientPoll() {
+	t := self.T()
+
+	self.config_obj.Defaults = &config_proto.Defaults{MaxHuntsPerClientPoll: 2}
+	defer func() {
+		self.config_obj.Defaults = nil
+	}()
+
+	require.NoError(t, self.sm.Start(
+		client_monitoring.StartClientMonitoringService))
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	// Three hunts match the client. The lowest priority hunt
+	// started first.
+	hunt_ids := []string{}
+	for i, priority := range []int64{0, 5, 10} {
+		hunt_obj := &api_proto.Hunt{
+			HuntId:     fmt.Sprintf("%s%d", self.hunt_id, i),
+			Priority:   priority,
+			CreateTime: uint64(i + 1),
+			StartTime:  uint64(i + 1),
+			State:      api_proto.Hunt_RUNNING,
+			Stats:      &api_proto.HuntStats{},
+			Expires:    flows.HuntTimeFromTime(time.Now().Add(time.Hour)),
+		}
+		hunt_obj.StartRequest = proto.Clone(
+			self.expected).(*flows_proto.ArtifactCollectorArgs)
+		hunt_obj.StartRequest.Creator = hunt_obj.HuntId
+		hunt_ids = append(hunt_ids, hunt_obj.HuntId)
+
+		hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+		err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+		assert.NoError(t, err)
+	}
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	// The hunts of the flows scheduled on the client, and the last
+	// hunt timestamp the client was told to report.
+	scheduled := func() ([]string, uint64) {
+		result := []string{}
+		seen := make(map[string]bool)
+		last_timestamp := uint64(0)
+		tasks, _ := db.GetClientTasks(self.config_obj, self.client_id, true)
+		for _, task := range tasks {
+			if task.UpdateForeman != nil {
+				last_timestamp = task.UpdateForeman.LastHuntTimestamp
+				continue
+			}
+
+			collection_context, err := LoadCollectionContext(
+				self.config_obj, self.client_id, task.SessionId)
+			if err == nil && !seen[collection_context.Request.Creator] {
+				seen[collection_context.Request.Creator] = true
+				result = append(result, collection_context.Request.Creator)
+			}
+		}
+		return result, last_timestamp
+	}
+
+	err = flows.ForemanProcessMessage(context.Background(), self.config_obj,
+		self.client_id, &actions_proto.ForemanCheckin{})
+	assert.NoError(t, err)
+
+	vtesting.WaitUntil(5*time.Second, t, func() bool {
+		hunts, _ := scheduled()
+		return len(hunts) == 2
+	})
+
+	// Only the two highest priority hunts are scheduled, and the
+	// client is kept before the deferred hunt.
+	hunts, last_timestamp := scheduled()
+	assert.Equal(t, []string{hunt_ids[2], hunt_ids[1]}, hunts)
+	assert.Equal(t, uint64(0), last_timestamp)
+
+	// The next poll schedules the deferred hunt.
+	err = flows.ForemanProcessMessage(context.Background(), self.config_obj,
+		self.client_id, &actions_proto.ForemanCheckin{
+			LastHuntTimestamp: last_timestamp,
+		})
+	assert.NoError(t, err)
+
+	vtesting.WaitUntil(5*time.Second, t, func() bool {
+		hunts, _ := scheduled()
+		return len(hunts) == 3
+	})
+
+	hunts, last_timestamp = scheduled()
+	assert.Equal(t, []string{hunt_ids[2], hunt_ids[1], hunt_ids[0]}, hunts)
+	assert.Equal(t, uint64(3), last_timestamp)
+}
+
+func (self *HuntTestSuite) TestHuntScheduledOncePerClient() {
+	t := self.T()
+
+	require.NoError(t, self.sm.Start(
+		client_monitoring.StartClientMonitoringService))
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	// A paused hunt is only scheduled via an override below.
+	other_hunt_id := self.hunt_id + "Other"
+	for _, hunt_obj := range []*api_proto.Hunt{
+		{HuntId: self.hunt_id, State: api_proto.Hunt_RUNNING, StartTime: 1},
+		{HuntId: other_hunt_id, State: api_proto.Hunt_PAUSED},
+	} {
+		hunt_obj.StartRequest = proto.Clone(
+			self.expected).(*flows_proto.ArtifactCollectorArgs)
+		hunt_obj.StartRequest.Creator = hunt_obj.HuntId
+		hunt_obj.Stats = &api_proto.HuntStats{}
+		hunt_obj.Expires = flows.HuntTimeFromTime(time.Now().Add(time.Hour))
+
+		hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+		err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+		assert.NoError(t, err)
+	}
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	// The client polls twice before it records the hunt, and the
+	// hunt dispatcher is refreshed in between.
+	for i := 0; i < 2; i++ {
+		err = flows.ForemanProcessMessage(context.Background(), self.config_obj,
+			self.client_id, &actions_proto.ForemanCheckin{})
+		assert.NoError(t, err)
+
+		services.GetHuntDispatcher().Refresh(self.config_obj)
+	}
+
+	// Participation rows are processed in order so once the
+	// other hunt is scheduled both polls have been processed.
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	journal.PushRowsToArtifact(self.config_obj,
+		[]*ordereddict.Dict{ordereddict.NewDict().
+			Set("HuntId", other_hunt_id).
+			Set("ClientId", self.client_id).
+			Set("Override", true).
+			Set("Participate", true)},
+		"System.Hunt.Participation", self.client_id, "")
+
+	flows_by_hunt := func() map[string][]string {
+		result := make(map[string][]string)
+		seen := make(map[string]bool)
+		tasks, _ := db.GetClientTasks(self.config_obj, self.client_id, true)
+		for _, task := range tasks {
+			if seen[task.SessionId] {
+				continue
+			}
+			seen[task.SessionId] = true
+
+			collection_context, err := LoadCollectionContext(
+				self.config_obj, self.client_id, task.SessionId)
+			if err == nil {
+				creator := collection_context.Request.Creator
+				result[creator] = append(result[creator], task.SessionId)
+			}
+		}
+		return result
+	}
+
+	vtesting.WaitUntil(5*time.Second, t, func() bool {
+		return len(flows_by_hunt()[other_hunt_id]) == 1
+	})
+
+	assert.Equal(t, 1, len(flows_by_hunt()[self.hunt_id]))
+
+	hunt_obj, err := flows.GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: self.hunt_id, StatsOnly: true})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), hunt_obj.Stats.TotalClientsScheduled)
+}
+
+func (self *HuntTestSuite) TestHuntSchedulingAfterRestart() {
+	t := self.T()
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	// Two clients were scheduled before the server went down, but
+	// the stats were not flushed and one index entry was lost.
+	hunt_obj := &api_proto.Hunt{
+		HuntId:       self.hunt_id,
+		StartRequest: self.expected,
+		State:        api_proto.Hunt_RUNNING,
+		Stats:        &api_proto.HuntStats{},
+		ClientLimit:  2,
+		Expires:      flows.HuntTimeFromTime(time.Now().Add(time.Hour)),
+	}
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Stats().Path(),
+		hunt_obj.Stats)
+	assert.NoError(t, err)
+
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	other_client_id := self.client_id + "1"
+	for i, client_id := range []string{self.client_id, other_client_id} {
+		err = journal.PushRows(self.config_obj, hunt_path_manager.Clients(),
+			[]*ordereddict.Dict{ordereddict.NewDict().
+				Set("HuntId", self.hunt_id).
+				Set("ClientId", client_id).
+				Set("FlowId", fmt.Sprintf("F.%d", i)).
+				Set("Participate", true)})
+		assert.NoError(t, err)
+	}
+
+	err = db.SetIndex(self.config_obj, constants.HUNT_INDEX,
+		other_client_id, []string{self.hunt_id})
+	assert.NoError(t, err)
+
+	// Simulate a restart by starting a new hunt dispatcher which
+	// loads the hunts from the data store.
+	require.NoError(t, self.sm.Start(hunt_dispatcher.StartHuntDispatcher))
+
+	hunt_stats, err := flows.GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: self.hunt_id, StatsOnly: true})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), hunt_stats.Stats.TotalClientsScheduled)
+
+	err = db.CheckIndex(self.config_obj, constants.HUNT_INDEX,
+		self.client_id, []string{self.hunt_id})
+	assert.NoError(t, err)
+
+	state_changes, cancel := journal.Watch("System.Hunt.StateChange")
+	defer cancel()
+
+	// The first client polls again and a new client arrives.
+	for _, client_id := range []string{self.client_id, self.client_id + "2"} {
+		journal.PushRowsToArtifact(self.config_obj,
+			[]*ordereddict.Dict{ordereddict.NewDict().
+				Set("HuntId", self.hunt_id).
+				Set("ClientId", client_id).
+				Set("Participate", true)},
+			"System.Hunt.Participation", client_id, "")
+	}
+
+	// The client limit was already reached so the new client stops
+	// the hunt.
+	select {
+	case row := <-state_changes:
+		reason, _ := row.GetString("Reason")
+		assert.Equal(t, "client limit reached", reason)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Hunt %v was not stopped", self.hunt_id)
+	}
+
+	// Neither client was scheduled again.
+	for _, client_id := range []string{self.client_id, self.client_id + "2"} {
+		tasks, err := db.GetClientTasks(self.config_obj, client_id, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(tasks), client_id)
+	}
+}
+
+func (self *HuntTestSuite) TestHuntLabelParameters() {
+	t := self.T()
+
+	env := func(kv ...string) *flows_proto.ArtifactParameters {
+		result := &flows_proto.ArtifactParameters{}
+		for i := 0; i < len(kv); i += 2 {
+			result.Env = append(result.Env, &actions_proto.VQLEnv{
+				Key: kv[i], Value: kv[i+1]})
+		}
+		return result
+	}
+
+	hunt_obj := &api_proto.Hunt{
+		HuntId: self.hunt_id,
+		StartRequest: &flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{"Test.Params", "Other.Params"},
+			CompiledCollectorArgs: []*actions_proto.VQLCollectorArgs{{
+				Query: []*actions_proto.VQLRequest{{
+					Name: "Test.Params/Source", VQL: "SELECT * FROM info()"}},
+				Env: env("Path", "default", "Depth", "1").Env,
+			}, {
+				Query: []*actions_proto.VQLRequest{{
+					Name: "Other.Params", VQL: "SELECT * FROM info()"}},
+				Env: env("Path", "other").Env,
+			}},
+		},
+		State:   api_proto.Hunt_RUNNING,
+		Stats:   &api_proto.HuntStats{},
+		Expires: uint64(time.Now().Add(7*24*time.Hour).UTC().UnixNano() / 1000),
+
+		// The client has the first two labels - the second one
+		// wins for Path.
+		LabelParameters: []*api_proto.HuntLabelParameters{{
+			Label: "Linux",
+			Specs: []*flows_proto.ArtifactSpec{{
+				Artifact: "Test.Params", Parameters: env("Path", "/linux", "Depth", "5")}},
+		}, {
+			Label: "Special",
+			Specs: []*flows_proto.ArtifactSpec{{
+				Artifact: "Test.Params", Parameters: env("Path", "/special")}},
+		}, {
+			Label: "Windows",
+			Specs: []*flows_proto.ArtifactSpec{{
+				Artifact: "Test.Params", Parameters: env("Path", "C:/windows")}},
+		}},
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	labeler := services.GetLabeler()
+	for _, label := range []string{"Linux", "Special"} {
+		err = labeler.SetClientLabel(self.config_obj, self.client_id, label)
+		assert.NoError(t, err)
+	}
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	// Simulate a System.Hunt.Participation event
+	path_manager := artifacts.NewArtifactPathManager(self.config_obj,
+		self.client_id, "", "System.Hunt.Participation")
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	journal.PushRows(self.config_obj, path_manager,
+		[]*ordereddict.Dict{ordereddict.NewDict().
+			Set("HuntId", self.hunt_id).
+			Set("ClientId", self.client_id).
+			Set("Participate", true)})
+
+	var tasks []*crypto_proto.GrrMessage
+	vtesting.WaitUntil(5*time.Second, t, func() bool {
+		tasks, err = db.GetClientTasks(self.config_obj, self.client_id, true)
+		return err == nil && len(tasks) == 2
+	})
+
+	values := func(args *actions_proto.VQLCollectorArgs) map[string]string {
+		result := make(map[string]string)
+		for _, item := range args.Env {
+			result[item.Key] = item.Value
+		}
+		return result
+	}
+
+	assert.Equal(t, map[string]string{"Path": "/special", "Depth": "5"},
+		values(tasks[0].VQLClientAction))
+
+	// Overrides only apply to the artifact they name.
+	assert.Equal(t, map[string]string{"Path": "other"},
+		values(tasks[1].VQLClientAction))
+
+	// The hunt's own compiled request is not changed.
+	stored, err := flows.GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: self.hunt_id})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Path": "default", "Depth": "1"},
+		values(stored.StartRequest.CompiledCollectorArgs[0]))
+}
+
+func (self *HuntTestSuite) TestHuntRetryPolicy() {
+	t := self.T()
+
+	launcher, err := services.GetLauncher()
+	assert.NoError(t, err)
+
+	hunt_obj := &api_proto.Hunt{
+		HuntId:       self.hunt_id,
+		StartRequest: self.expected,
+		State:        api_proto.Hunt_RUNNING,
+		Stats:        &api_proto.HuntStats{},
+		Expires:      flows.HuntTimeFromTime(time.Now().Add(time.Hour)),
+		RetryPolicy: &api_proto.HuntRetryPolicy{
+			MaxRetries: 2,
+			Backoff:    60,
+		},
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	get_stats := func() *api_proto.HuntStats {
+		hunt, err := flows.GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: self.hunt_id, StatsOnly: true})
+		assert.NoError(t, err)
+		return hunt.Stats
+	}
+
+	launcher.SetFlowIdForTests("F.1234")
+
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	journal.PushRowsToArtifact(self.config_obj,
+		[]*ordereddict.Dict{ordereddict.NewDict().
+			Set("HuntId", self.hunt_id).
+			Set("ClientId", self.client_id).
+			Set("Participate", true)},
+		"System.Hunt.Participation", self.client_id, "")
+
+	vtesting.WaitUntil(5*time.Second, t, func() bool {
+		_, err := LoadCollectionContext(self.config_obj,
+			self.client_id, "F.1234")
+		return err == nil
+	})
+
+	// The client fails the collection the first time.
+	collection_context, err := LoadCollectionContext(self.config_obj,
+		self.client_id, "F.1234")
+	assert.NoError(t, err)
+
+	err = flows.ArtifactCollectorProcessOneMessage(self.config_obj,
+		collection_context, &crypto_proto.GrrMessage{
+			RequestId: constants.ProcessVQLResponses,
+			Status: &crypto_proto.GrrStatus{
+				Status:       crypto_proto.GrrStatus_GENERIC_ERROR,
+				ErrorMessage: "Access is denied",
+			},
+		})
+	assert.Error(t, err)
+	err = db.SetSubject(self.config_obj,
+		paths.NewFlowPathManager(self.client_id, "F.1234").Path(),
+		collection_context)
+	assert.NoError(t, err)
+
+	// The client is retrying rather than failed.
+	stats := get_stats()
+	assert.Equal(t, uint64(1), stats.TotalClientsRetrying)
+	assert.Equal(t, uint64(0), stats.TotalClientsWithErrors)
+
+	// The pending retry survives a restart.
+	require.NoError(t, self.sm.Start(hunt_dispatcher.StartHuntDispatcher))
+
+	retries, err := hunt_dispatcher.ListHuntRetries(
+		self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	require.Equal(t, 1, len(retries))
+	assert.Equal(t, uint64(1), retries[0].Attempt)
+	assert.Equal(t, "F.1234", retries[0].FlowId)
+	assert.Equal(t, "Access is denied", retries[0].Error)
+
+	// The retry is not due until the backoff passed.
+	launcher.SetFlowIdForTests("F.5678")
+	err = SweepRetries(context.Background(), self.config_obj, time.Now())
+	assert.NoError(t, err)
+
+	_, err = LoadCollectionContext(self.config_obj, self.client_id, "F.5678")
+	assert.Error(t, err)
+
+	err = SweepRetries(context.Background(), self.config_obj,
+		time.Now().Add(2*time.Minute))
+	assert.NoError(t, err)
+
+	collection_context, err = LoadCollectionContext(self.config_obj,
+		self.client_id, "F.5678")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), collection_context.Request.HuntRetry)
+	assert.Equal(t, self.hunt_id, collection_context.Request.Creator)
+
+	retries, err = hunt_dispatcher.ListHuntRetries(
+		self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(retries))
+
+	// This time the collection succeeds.
+	err = flows.ArtifactCollectorProcessOneMessage(self.config_obj,
+		collection_context, &crypto_proto.GrrMessage{
+			RequestId: constants.ProcessVQLResponses,
+			Status:    &crypto_proto.GrrStatus{},
+		})
+	assert.NoError(t, err)
+	err = db.SetSubject(self.config_obj,
+		paths.NewFlowPathManager(self.client_id, "F.5678").Path(),
+		collection_context)
+	assert.NoError(t, err)
+
+	stats = get_stats()
+	assert.Equal(t, uint64(1), stats.TotalClientsScheduled)
+	assert.Equal(t, uint64(1), stats.TotalClientsWithResults)
+	assert.Equal(t, uint64(0), stats.TotalClientsRetrying)
+	assert.Equal(t, uint64(0), stats.TotalClientsWithErrors)
+
+	// Reconciling the stats from the flow index agrees - the
+	// retried flow replaces the failed one.
+	_, after, err := hunt_dispatcher.ReconcileHuntStats(
+		context.Background(), self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), after.TotalClientsScheduled)
+	assert.Equal(t, uint64(1), after.TotalClientsWithResults)
+	assert.Equal(t, uint64(0), after.TotalClientsRetrying)
+	assert.Equal(t, uint64(0), after.TotalClientsWithErrors)
+}
+
+func (self *HuntTestSuite) TestHuntRetryDropped() {
+	t := self.T()
+
+	hunt_obj := &api_proto.Hunt{
+		HuntId:       self.hunt_id,
+		StartRequest: self.expected,
+		State:        api_proto.Hunt_RUNNING,
+		Stats:        &api_proto.HuntStats{},
+		Expires:      flows.HuntTimeFromTime(time.Now().Add(time.Hour)),
+		RetryPolicy: &api_proto.HuntRetryPolicy{
+			MaxRetries: 2,
+		},
+		Condition: &api_proto.HuntCondition{
+			Os: &api_proto.HuntOsCondition{
+				Os: api_proto.HuntOsCondition_WINDOWS,
+			},
+		},
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	dispatcher := services.GetHuntDispatcher()
+	dispatcher.Refresh(self.config_obj)
+
+	// The client failed once and is waiting for its retry.
+	add_retry := func() {
+		err := db.SetSubject(self.config_obj,
+			paths.HuntRetryPath(self.hunt_id, self.client_id),
+			&api_proto.HuntRetryRecord{
+				HuntId:   self.hunt_id,
+				ClientId: self.client_id,
+				FlowId:   "F.1",
+				Attempt:  1,
+			})
+		assert.NoError(t, err)
+
+		err = dispatcher.ModifyHunt(self.hunt_id, func(hunt *api_proto.Hunt) error {
+			hunt.Stats = &api_proto.HuntStats{
+				TotalClientsScheduled: 1,
+				TotalClientsRetrying:  1,
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+
+	check_dropped := func() {
+		retries, err := hunt_dispatcher.ListHuntRetries(
+			self.config_obj, self.hunt_id)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(retries))
+
+		hunt, err := flows.GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: self.hunt_id, StatsOnly: true})
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), hunt.Stats.TotalClientsRetrying)
+		assert.Equal(t, uint64(1), hunt.Stats.TotalClientsWithErrors)
+	}
+
+	// Meanwhile the client was reinstalled on linux so it no
+	// longer matches the hunt.
+	err = db.SetSubject(self.config_obj,
+		paths.NewClientPathManager(self.client_id).Path(),
+		&actions_proto.ClientInfo{ClientId: self.client_id, System: "linux"})
+	assert.NoError(t, err)
+
+	add_retry()
+	err = SweepRetries(context.Background(), self.config_obj, time.Now())
+	assert.NoError(t, err)
+	check_dropped()
+
+	// The retries of a stopped hunt are dropped.
+	add_retry()
+	err = dispatcher.ModifyHunt(self.hunt_id, func(hunt *api_proto.Hunt) error {
+		hunt.State = api_proto.Hunt_STOPPED
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = SweepRetries(context.Background(), self.config_obj, time.Now())
+	assert.NoError(t, err)
+	check_dropped()
+}
+
+func (self *HuntTestSuite) TestRetryFailedClients() {
+	t := self.T()
+
+	launcher, err := services.GetLauncher()
+	assert.NoError(t, err)
+
+	// The hunt already completed.
+	hunt_obj := &api_proto.Hunt{
+		HuntId:          self.hunt_id,
+		StartRequest:    self.expected,
+		State:           api_proto.Hunt_STOPPED,
+		Stats:           &api_proto.HuntStats{},
+		Expires:         flows.HuntTimeFromTime(time.Now().Add(time.Hour)),
+		ExcludedClients: []string{"C.3"},
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	dispatcher := services.GetHuntDispatcher()
+	dispatcher.Refresh(self.config_obj)
+
+	err = dispatcher.ModifyHunt(self.hunt_id, func(hunt *api_proto.Hunt) error {
+		hunt.Stats.TotalClientsScheduled = 4
+		hunt.Stats.TotalClientsWithResults = 2
+		hunt.Stats.TotalClientsWithErrors = 2
+		return nil
+	})
+	assert.NoError(t, err)
+
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	add_flow := func(client_id, flow_id string,
+		state flows_proto.ArtifactCollectorContext_State) {
+		err := db.SetSubject(self.config_obj,
+			paths.NewFlowPathManager(client_id, flow_id).Path(),
+			&flows_proto.ArtifactCollectorContext{
+				SessionId: flow_id,
+				ClientId:  client_id,
+				State:     state,
+				Status:    "Timeout",
+				Request:   &flows_proto.ArtifactCollectorArgs{},
+			})
+		assert.NoError(t, err)
+
+		err = journal.PushRows(self.config_obj, hunt_path_manager.Clients(),
+			[]*ordereddict.Dict{ordereddict.NewDict().
+				Set("HuntId", self.hunt_id).
+				Set("ClientId", client_id).
+				Set("FlowId", flow_id)})
+		assert.NoError(t, err)
+	}
+
+	add_flow(self.client_id, "F.1", flows_proto.ArtifactCollectorContext_ERROR)
+	add_flow("C.2", "F.1", flows_proto.ArtifactCollectorContext_FINISHED)
+
+	// Excluded clients are not retried.
+	add_flow("C.3", "F.1", flows_proto.ArtifactCollectorContext_ERROR)
+
+	// The client's latest flow succeeded.
+	add_flow("C.4", "F.1", flows_proto.ArtifactCollectorContext_ERROR)
+	add_flow("C.4", "F.2", flows_proto.ArtifactCollectorContext_FINISHED)
+
+	launcher.SetFlowIdForTests("F.5678")
+	result, err := RetryFailedClients(
+		context.Background(), self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	require.Equal(t, 1, len(result.Items))
+	assert.Equal(t, self.client_id, result.Items[0].ClientId)
+	assert.Equal(t, "F.1", result.Items[0].FailedFlowId)
+	assert.Equal(t, "Timeout", result.Items[0].Error)
+	assert.Equal(t, "F.5678", result.Items[0].FlowId)
+
+	// The new flow belongs to the same hunt.
+	collection_context, err := LoadCollectionContext(self.config_obj,
+		self.client_id, "F.5678")
+	assert.NoError(t, err)
+	assert.Equal(t, self.hunt_id, collection_context.Request.Creator)
+	assert.Equal(t, uint64(1), collection_context.Request.HuntRetry)
+	assert.Equal(t, self.expected.Artifacts, collection_context.Request.Artifacts)
+
+	hunt, err := flows.GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: self.hunt_id, StatsOnly: true})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), hunt.Stats.TotalClientsWithErrors)
+	assert.Equal(t, uint64(1), hunt.Stats.TotalClientsRetrying)
+
+	// The retried flow has not failed yet.
+	result, err = RetryFailedClients(
+		context.Background(), self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(result.Items))
+}
+
+func (self *HuntTestSuite) TestRetryFailedClientsRestrictions() {
+	t := self.T()
+
+	launcher, err := services.GetLauncher()
+	assert.NoError(t, err)
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(self.hunt_id)
+	dispatcher := services.GetHuntDispatcher()
+
+	set_hunt := func(hunt_obj *api_proto.Hunt) {
+		err := db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+		assert.NoError(t, err)
+		dispatcher.Refresh(self.config_obj)
+
+		// The dispatcher keeps the stats it already has.
+		err = dispatcher.ModifyHunt(self.hunt_id, func(hunt *api_proto.Hunt) error {
+			hunt.Stats = &api_proto.HuntStats{}
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	// Two clients failed.
+	for _, client_id := range []string{"C.1", "C.2"} {
+		err := db.SetSubject(self.config_obj,
+			paths.NewFlowPathManager(client_id, "F.1").Path(),
+			&flows_proto.ArtifactCollectorContext{
+				SessionId: "F.1",
+				ClientId:  client_id,
+				State:     flows_proto.ArtifactCollectorContext_ERROR,
+				Request:   &flows_proto.ArtifactCollectorArgs{},
+			})
+		assert.NoError(t, err)
+
+		err = journal.PushRows(self.config_obj, hunt_path_manager.Clients(),
+			[]*ordereddict.Dict{ordereddict.NewDict().
+				Set("HuntId", self.hunt_id).
+				Set("ClientId", client_id).
+				Set("FlowId", "F.1")})
+		assert.NoError(t, err)
+	}
+
+	new_hunt := func(state api_proto.Hunt_State) *api_proto.Hunt {
+		return &api_proto.Hunt{
+			HuntId:       self.hunt_id,
+			StartRequest: self.expected,
+			State:        state,
+			Stats:        &api_proto.HuntStats{},
+			Expires:      flows.HuntTimeFromTime(time.Now().Add(time.Hour)),
+		}
+	}
+
+	// Expired hunts are not retried.
+	hunt_obj := new_hunt(api_proto.Hunt_STOPPED)
+	hunt_obj.Expires = flows.HuntTimeFromTime(time.Now().Add(-time.Hour))
+	set_hunt(hunt_obj)
+
+	_, err = RetryFailedClients(context.Background(), self.config_obj, self.hunt_id)
+	assert.Error(t, err)
+
+	// Neither are automatically stopped hunts.
+	set_hunt(new_hunt(api_proto.Hunt_STOPPED))
+	err = dispatcher.ModifyHunt(self.hunt_id, func(hunt *api_proto.Hunt) error {
+		hunt.Stats.Stopped = true
+		return nil
+	})
+	assert.NoError(t, err)
+
+	_, err = RetryFailedClients(context.Background(), self.config_obj, self.hunt_id)
+	assert.Error(t, err)
+
+	// When hunts require approval a stopped hunt must be started
+	// again first.
+	self.config_obj.Defaults = &config_proto.Defaults{HuntApprovalRequired: true}
+	defer func() {
+		self.config_obj.Defaults = nil
+	}()
+
+	set_hunt(new_hunt(api_proto.Hunt_STOPPED))
+
+	_, err = RetryFailedClients(context.Background(), self.config_obj, self.hunt_id)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "approved")
+
+	// Only the clients within the client limit are retried.
+	hunt_obj = new_hunt(api_proto.Hunt_RUNNING)
+	hunt_obj.ClientLimit = 1
+	set_hunt(hunt_obj)
+
+	launcher.SetFlowIdForTests("F.5678")
+	result, err := RetryFailedClients(
+		context.Background(), self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	require.Equal(t, 1, len(result.Items))
+	assert.Equal(t, "C.1", result.Items[0].ClientId)
+}
+
+func (self *HuntTestSuite) TestHuntProgress() {
+	t := self.T()
+
+	now := time.Now()
+	hunt_obj := &api_proto.Hunt{
+		HuntId:              self.hunt_id,
+		StartRequest:        self.expected,
+		State:               api_proto.Hunt_RUNNING,
+		StartTime:           flows.HuntTimeFromTime(now.Add(-10 * time.Minute)),
+		Stats:               &api_proto.HuntStats{},
+		Expires:             flows.HuntTimeFromTime(now.Add(time.Hour)),
+		SnapshotClients:     true,
+		SnapshotClientCount: 10,
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	err = services.GetHuntDispatcher().ModifyHunt(self.hunt_id,
+		func(hunt *api_proto.Hunt) error {
+			hunt.Stats.TotalClientsScheduled = 4
+			hunt.Stats.TotalClientsWithResults = 1
+			return nil
+		})
+	assert.NoError(t, err)
+
+	get_stats := func() *api_proto.HuntStats {
+		hunt, err := flows.GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: self.hunt_id, StatsOnly: true})
+		assert.NoError(t, err)
+		return hunt.Stats
+	}
+
+	// The hunt runs on the clients in its snapshot. One client
+	// completed in 10 minutes so the other 9 take another 90.
+	manager := &HuntManager{}
+	err = manager.UpdateHuntProgress(context.Background(), self.config_obj, now)
+	assert.NoError(t, err)
+
+	stats := get_stats()
+	assert.Equal(t, uint64(10), stats.TotalClientsEligible)
+	assert.Equal(t, uint64(9), stats.TotalClientsRemaining)
+	assert.Equal(t, flows.HuntTimeFromTime(now.Add(90*time.Minute)), stats.Eta)
+
+	// Completed collections update the average duration.
+	for _, duration := range []time.Duration{2 * time.Second, 4 * time.Second} {
+		manager.ProcessFlowCompletion(context.Background(), self.config_obj,
+			vfilter.NewScope(), ordereddict.NewDict().
+				Set("Flow", &flows_proto.ArtifactCollectorContext{
+					ClientId:          "C.12321",
+					SessionId:         "F.1234",
+					ExecutionDuration: int64(duration),
+					Request: &flows_proto.ArtifactCollectorArgs{
+						Creator: self.hunt_id,
+					},
+				}))
+	}
+
+	stats = get_stats()
+	assert.Equal(t, uint64(2), stats.TotalFlowsTimed)
+	assert.Equal(t, uint64(3000000), stats.AverageFlowDuration)
+
+	// The client limit caps the eligible clients but never below
+	// the clients already scheduled.
+	err = services.GetHuntDispatcher().ModifyHunt(self.hunt_id,
+		func(hunt *api_proto.Hunt) error {
+			hunt.ClientLimit = 2
+			return nil
+		})
+	assert.NoError(t, err)
+
+	err = manager.UpdateHuntProgress(context.Background(), self.config_obj, now)
+	assert.NoError(t, err)
+
+	stats = get_stats()
+	assert.Equal(t, uint64(4), stats.TotalClientsEligible)
+	assert.Equal(t, uint64(3), stats.TotalClientsRemaining)
+}
+
+func (self *HuntTestSuite) TestHuntRolloutRate() {
+	t := self.T()
+
+	hunt_obj := &api_proto.Hunt{
+		HuntId:           self.hunt_id,
+		StartRequest:     self.expected,
+		State:            api_proto.Hunt_RUNNING,
+		Stats:            &api_proto.HuntStats{},
+		Expires:          flows.HuntTimeFromTime(time.Now().Add(time.Hour)),
+		ClientsPerMinute: 1,
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	services.GetHuntDispatcher().Refresh(self.config_obj)
+
+	get_stats := func() *api_proto.HuntStats {
+		hunt, err := flows.GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: self.hunt_id, StatsOnly: true})
+		assert.NoError(t, err)
+		return hunt.Stats
+	}
+
+	// Three clients check in at once.
+	client_ids := []string{"C.12321", "C.12322", "C.12323"}
+	rows := []*ordereddict.Dict{}
+	for _, client_id := range client_ids {
+		err = db.SetSubject(self.config_obj,
+			paths.NewClientPathManager(client_id).Path(),
+			&actions_proto.ClientInfo{ClientId: client_id})
+		assert.NoError(t, err)
+
+		rows = append(rows, ordereddict.NewDict().
+			Set("HuntId", self.hunt_id).
+			Set("ClientId", client_id).
+			Set("Participate", true))
+	}
+
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	path_manager := artifacts.NewArtifactPathManager(self.config_obj,
+		"server", "", "System.Hunt.Participation")
+	journal.PushRows(self.config_obj, path_manager, rows)
+
+	vtesting.WaitUntil(5*time.Second, t, func() bool {
+		stats := get_stats()
+		return stats.TotalClientsScheduled+stats.TotalClientsQueued == 3
+	})
+
+	// Only one client is scheduled in the first minute, the rest
+	// wait their turn.
+	stats := get_stats()
+	assert.Equal(t, uint64(1), stats.TotalClientsScheduled)
+	assert.Equal(t, uint64(2), stats.TotalClientsQueued)
+	assert.Equal(t, uint64(1), stats.SchedulingRate)
+
+	queue, err := hunt_dispatcher.ListHuntQueue(self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(queue))
+
+	// A minute later the next client is scheduled.
+	manager := &HuntManager{}
+	now := time.Now().Add(time.Minute)
+	err = manager.SweepHuntQueues(context.Background(), self.config_obj, now)
+	assert.NoError(t, err)
+
+	stats = get_stats()
+	assert.Equal(t, uint64(2), stats.TotalClientsScheduled)
+	assert.Equal(t, uint64(1), stats.TotalClientsQueued)
+	assert.Equal(t, uint64(1), stats.SchedulingRate)
+
+	remaining, err := hunt_dispatcher.ListHuntQueue(
+		self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	require.Equal(t, 1, len(remaining))
+	assert.Equal(t, queue[1].ClientId, remaining[0].ClientId)
+
+	// The scheduled client is in the flow index.
+	_, after, err := hunt_dispatcher.ReconcileHuntStats(
+		context.Background(), self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), after.TotalClientsScheduled)
+	assert.Equal(t, uint64(1), after.TotalClientsQueued)
+
+	// An excluded client leaves the queue without being scheduled.
+	err = services.GetHuntDispatcher().ModifyHunt(self.hunt_id,
+		func(hunt *api_proto.Hunt) error {
+			hunt.ExcludedClients = []string{remaining[0].ClientId}
+			return nil
+		})
+	assert.NoError(t, err)
+
+	err = manager.SweepHuntQueues(context.Background(), self.config_obj,
+		now.Add(time.Minute))
+	assert.NoError(t, err)
+
+	stats = get_stats()
+	assert.Equal(t, uint64(2), stats.TotalClientsScheduled)
+	assert.Equal(t, uint64(0), stats.TotalClientsQueued)
+
+	remaining, err = hunt_dispatcher.ListHuntQueue(
+		self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(remaining))
+}
+
+func TestRolloutLimiter(t *testing.T) {
+	limiter := &rolloutLimiter{}
+	now := time.Now()
+
+	allowed, rate := limiter.Allow("H.1", 2, now)
+	assert.True(t, allowed)
+	assert.Equal(t, uint64(1), rate)
+
+	allowed, rate = limiter.Allow("H.1", 2, now.Add(10*time.Second))
+	assert.True(t, allowed)
+	assert.Equal(t, uint64(2), rate)
+
+	// Other hunts have their own rate.
+	allowed, _ = limiter.Allow("H.2", 2, now)
+	assert.True(t, allowed)
+
+	allowed, rate = limiter.Allow("H.1", 2, now.Add(30*time.Second))
+	assert.False(t, allowed)
+	assert.Equal(t, uint64(2), rate)
+
+	// Once the first client is a minute old there is room again.
+	assert.Equal(t, uint64(1), limiter.Rate("H.1", now.Add(time.Minute)))
+	allowed, rate = limiter.Allow("H.1", 2, now.Add(time.Minute))
+	assert.True(t, allowed)
+	assert.Equal(t, uint64(2), rate)
+
+	// A slot which was not used is given back.
+	limiter.Release("H.1", now.Add(time.Minute))
+	assert.Equal(t, uint64(1), limiter.Rate("H.1", now.Add(time.Minute)))
+}
+
+func TestEligibleClientsCache(t *testing.T) {
+	cache := &eligibleClientsCache{}
+	now := time.Now()
+
+	estimates := uint64(0)
+	estimate := func() (uint64, error) {
+		estimates++
+		return estimates, nil
+	}
+
+	hunt := &api_proto.Hunt{
+		HuntId: "H.1",
+		Condition: &api_proto.HuntCondition{
+			Labels: &api_proto.HuntLabelCondition{Label: []string{"Foo"}},
+		},
+	}
+
+	count, err := cache.Get(hunt, now, estimate)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	// The estimate is reused until it is too old.
+	count, err = cache.Get(hunt, now.Add(time.Minute), estimate)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	count, err = cache.Get(hunt, now.Add(hunt_eligible_refresh_period), estimate)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	// Changing the condition refreshes the estimate.
+	hunt = proto.Clone(hunt).(*api_proto.Hunt)
+	hunt.Condition.Labels.Label = []string{"Bar"}
+	count, err = cache.Get(hunt, now.Add(hunt_eligible_refresh_period), estimate)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	// Hunts which are no longer running are forgotten.
+	cache.Prune(map[string]bool{})
+	count, err = cache.Get(hunt, now.Add(hunt_eligible_refresh_period), estimate)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), count)
+}
+
+func (self *HuntTestSuite) TestHuntRolloutFailures() {
+	t := self.T()
+
+	// The hunt's artifact can not be collected so scheduling any
+	// client fails.
+	hunt_obj := &api_proto.Hunt{
+		HuntId: self.hunt_id,
+		StartRequest: &flows_proto.ArtifactCollectorArgs{
+			Artifacts: []string{"Unknown.Artifact"},
+		},
+		State:            api_proto.Hunt_RUNNING,
+		Stats:            &api_proto.HuntStats{},
+		Expires:          flows.HuntTimeFromTime(time.Now().Add(time.Hour)),
+		ClientsPerMinute: 10,
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	dispatcher := services.GetHuntDispatcher()
+	dispatcher.Refresh(self.config_obj)
+
+	get_stats := func() *api_proto.HuntStats {
+		hunt, err := flows.GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: self.hunt_id, StatsOnly: true})
+		assert.NoError(t, err)
+		return hunt.Stats
+	}
+
+	for i, client_id := range []string{"C.1", "C.2"} {
+		err = hunt_dispatcher.QueueHuntClient(self.config_obj,
+			&api_proto.HuntQueuedClient{
+				HuntId:     self.hunt_id,
+				ClientId:   client_id,
+				QueuedTime: uint64(i + 1),
+			})
+		assert.NoError(t, err)
+	}
+
+	err = dispatcher.ModifyHunt(self.hunt_id, func(hunt *api_proto.Hunt) error {
+		hunt.Stats = &api_proto.HuntStats{TotalClientsQueued: 2}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// Failed clients are not counted as scheduled, do not use up
+	// the rollout rate and do not hold up the clients behind them.
+	manager := &HuntManager{}
+	now := time.Now()
+	err = manager.SweepHuntQueues(context.Background(), self.config_obj, now)
+	assert.NoError(t, err)
+
+	stats := get_stats()
+	assert.Equal(t, uint64(0), stats.TotalClientsScheduled)
+	assert.Equal(t, uint64(2), stats.TotalClientsQueued)
+	assert.Equal(t, uint64(0), stats.SchedulingRate)
+
+	queue, err := hunt_dispatcher.ListHuntQueue(self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	require.Equal(t, 2, len(queue))
+	for _, record := range queue {
+		assert.Equal(t, uint64(1), record.Failures)
+		assert.NotEqual(t, "", record.Error)
+	}
+
+	// After too many failures the clients are dropped.
+	for i := 0; i < int(max_queued_client_failures); i++ {
+		err = manager.SweepHuntQueues(context.Background(), self.config_obj, now)
+		assert.NoError(t, err)
+	}
+
+	stats = get_stats()
+	assert.Equal(t, uint64(0), stats.TotalClientsScheduled)
+	assert.Equal(t, uint64(0), stats.TotalClientsQueued)
+
+	queue, err = hunt_dispatcher.ListHuntQueue(self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(queue))
+}
+
+func (self *HuntTestSuite) TestHuntRolloutStoppedHunt() {
+	t := self.T()
+
+	hunt_obj := &api_proto.Hunt{
+		HuntId:           self.hunt_id,
+		StartRequest:     self.expected,
+		State:            api_proto.Hunt_STOPPED,
+		Stats:            &api_proto.HuntStats{},
+		Expires:          flows.HuntTimeFromTime(time.Now().Add(time.Hour)),
+		ClientsPerMinute: 1,
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	dispatcher := services.GetHuntDispatcher()
+	dispatcher.Refresh(self.config_obj)
+
+	for _, client_id := range []string{"C.1", "C.2"} {
+		err = hunt_dispatcher.QueueHuntClient(self.config_obj,
+			&api_proto.HuntQueuedClient{
+				HuntId:   self.hunt_id,
+				ClientId: client_id,
+			})
+		assert.NoError(t, err)
+	}
+
+	err = dispatcher.ModifyHunt(self.hunt_id, func(hunt *api_proto.Hunt) error {
+		hunt.Stats = &api_proto.HuntStats{TotalClientsQueued: 2}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// The queue of a stopped hunt is dropped.
+	manager := &HuntManager{}
+	err = manager.SweepHuntQueues(context.Background(), self.config_obj, time.Now())
+	assert.NoError(t, err)
+
+	queue, err := hunt_dispatcher.ListHuntQueue(self.config_obj, self.hunt_id)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(queue))
+
+	hunt, err := flows.GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: self.hunt_id, StatsOnly: true})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), hunt.Stats.TotalClientsQueued)
+}
+
+func (self *HuntTestSuite) TestHuntAutoStopErrorRate() {
+	t := self.T()
+
+	hunt_obj := &api_proto.Hunt{
+		HuntId:       self.hunt_id,
+		StartRequest: self.expected,
+		State:        api_proto.Hunt_RUNNING,
+		Stats:        &api_proto.HuntStats{},
+		Expires:      flows.HuntTimeFromTime(time.Now().Add(time.Hour)),
+		AutoStopErrorRate: &api_proto.HuntAutoStopErrorRate{
+			MaxErrorPercent: 50,
+			MinClients:      3,
+		},
+	}
+
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(t, err)
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
+	err = db.SetSubject(self.config_obj, hunt_path_manager.Path(), hunt_obj)
+	assert.NoError(t, err)
+
+	dispatcher := services.GetHuntDispatcher()
+	dispatcher.Refresh(self.config_obj)
+
+	journal, err := services.GetJournal()
+	assert.NoError(t, err)
+
+	state_changes, cancel := journal.Watch("System.Hunt.StateChange")
+	defer cancel()
+
+	auto_stops, cancel_auto_stops := journal.Watch("System.Hunt.AutoStopped")
+	defer cancel_auto_stops()
+
+	get_hunt := func() *api_proto.Hunt {
+		hunt, err := flows.GetHunt(self.config_obj,
+			&api_proto.GetHuntRequest{HuntId: self.hunt_id})
+		assert.NoError(t, err)
+		return hunt
+	}
+
+	// Count completed clients the same way the flows do.
+	complete := func(with_error bool) {
+		err := dispatcher.ModifyHunt(self.hunt_id,
+			func(hunt *api_proto.Hunt) error {
+				if with_error {
+					hunt.Stats.TotalClientsWithErrors++
+				} else {
+					hunt.Stats.TotalClientsWithResults++
+				}
+				return nil
+			})
+		assert.NoError(t, err)
+	}
+
+	// Two errors out of two is too few clients to judge.
+	complete(true)
+	complete(true)
+	assert.Equal(t, api_proto.Hunt_RUNNING, get_hunt().State)
+
+	// Two out of three is over the threshold.
+	complete(false)
+
+	hunt := get_hunt()
+	assert.Equal(t, api_proto.Hunt_STOPPED, hunt.State)
+	assert.True(t, hunt.Stats.Stopped)
+	assert.Equal(t,
+		"2 of 3 completed clients failed (66.7%), exceeding the error rate of 50%",
+		hunt.Stats.ErrorMessage)
+
+	select {
+	case row := <-state_changes:
+		new_state, _ := row.GetString("NewState")
+		reason, _ := row.GetString("Reason")
+		assert.Equal(t, "STOPPED", new_state)
+		assert.Equal(t, hunt.Stats.ErrorMessage, reason)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("No state change for hunt %v", self.hunt_id)
+	}
+
+	select {
+	case row := <-auto_stops:
+		hunt_id, _ := row.GetString("HuntId")
+		reason, _ := row.GetString("Reason")
+		assert.Equal(t, self.hunt_id, hunt_id)
+		assert.Equal(t, hunt.Stats.ErrorMessage, reason)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("No auto stop for hunt %v", self.hunt_id)
+	}
+
+	// The hunt stays stopped after a restart.
+	require.NoError(t, self.sm.Start(hunt_dispatcher.StartHuntDispatcher))
+
+	hunt = get_hunt()
+	assert.Equal(t, api_proto.Hunt_STOPPED, hunt.State)
+	assert.True(t, hunt.Stats.Stopped)
+}
+
 func TestHuntTestSuite(t *testing.T) {
 	config_obj := config.GetDefaultConfig()
 	config_obj.Datastore.Implementation = "Test"
@@ -0,0 +1,221 @@
+package hunt_manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/flows"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+)
+
+var (
+	// How often we update the progress of running hunts.
+	hunt_progress_period = time.Minute
+
+	// How often we estimate how many clients each running hunt
+	// will run on. This searches all the clients so it should not
+	// be too often. The estimate is also refreshed when the
+	// hunt's condition changes.
+	hunt_eligible_refresh_period = time.Hour
+)
+
+// Caches the estimated eligible clients of each running hunt
+// between progress updates.
+type eligibleClientsCache struct {
+	mu      sync.Mutex
+	entries map[string]*eligibleClientsEntry
+}
+
+type eligibleClientsEntry struct {
+	condition *api_proto.HuntCondition
+	count     uint64
+	updated   time.Time
+}
+
+// Get the hunt's estimate, calling estimate if there is none yet,
+// it is too old or the hunt's condition changed since.
+func (self *eligibleClientsCache) Get(
+	hunt *api_proto.Hunt, now time.Time,
+	estimate func() (uint64, error)) (uint64, error) {
+	self.mu.Lock()
+	entry, pres := self.entries[hunt.HuntId]
+	self.mu.Unlock()
+
+	if pres && proto.Equal(entry.condition, hunt.Condition) &&
+		now.Sub(entry.updated) < hunt_eligible_refresh_period {
+		return entry.count, nil
+	}
+
+	count, err := estimate()
+	if err != nil {
+		return 0, err
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.entries == nil {
+		self.entries = make(map[string]*eligibleClientsEntry)
+	}
+	self.entries[hunt.HuntId] = &eligibleClientsEntry{
+		condition: hunt.Condition,
+		count:     count,
+		updated:   now,
+	}
+
+	return count, nil
+}
+
+// Forget the estimates of hunts which are no longer running.
+func (self *eligibleClientsCache) Prune(running map[string]bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	for hunt_id := range self.entries {
+		if !running[hunt_id] {
+			delete(self.entries, hunt_id)
+		}
+	}
+}
+
+// Periodically update the progress of running hunts.
+func (self *HuntManager) StartProgressUpdater(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	wg *sync.WaitGroup) error {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(hunt_progress_period):
+				err := self.UpdateHuntProgress(ctx, config_obj, time.Now())
+				if err != nil {
+					logger := logging.GetLogger(
+						config_obj, &logging.FrontendComponent)
+					logger.Error("UpdateHuntProgress: %v", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Estimate how many clients each running hunt will run on and update
+// how many remain and when the hunt is expected to complete.
+// Scheduled hunts do not run on clients themselves so have no
+// progress.
+func (self *HuntManager) UpdateHuntProgress(ctx context.Context,
+	config_obj *config_proto.Config, now time.Time) error {
+
+	dispatcher := services.GetHuntDispatcher()
+	if dispatcher == nil {
+		return nil
+	}
+
+	// Take a copy of the hunts so we do not hold the dispatcher
+	// lock while we search the clients.
+	hunts := []*api_proto.Hunt{}
+	err := dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		if hunt.State == api_proto.Hunt_RUNNING &&
+			hunt.Schedule == "" &&
+			hunt.Stats != nil && !hunt.Stats.Stopped {
+			hunts = append(hunts, proto.Clone(hunt).(*api_proto.Hunt))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	running := make(map[string]bool)
+	for _, hunt := range hunts {
+		running[hunt.HuntId] = true
+
+		eligible, err := self.huntEligibleClients(ctx, config_obj, hunt, now)
+		if err != nil {
+			logger.Error("UpdateHuntProgress %v: %v", hunt.HuntId, err)
+			continue
+		}
+
+		_ = dispatcher.ModifyHunt(hunt.HuntId,
+			func(hunt_obj *api_proto.Hunt) error {
+				if hunt_obj.Stats != nil {
+					hunt_obj.Stats.TotalClientsEligible = eligible
+					updateHuntProgress(hunt_obj, flows.HuntTimeFromTime(now))
+				}
+				return nil
+			})
+	}
+
+	self.eligible.Prune(running)
+
+	return nil
+}
+
+// How many clients the hunt is expected to run on. Hunts with a
+// snapshot run on the clients in the snapshot, other hunts may run
+// on any of the currently known clients matching their condition.
+func (self *HuntManager) huntEligibleClients(ctx context.Context,
+	config_obj *config_proto.Config, hunt *api_proto.Hunt,
+	now time.Time) (uint64, error) {
+	eligible := hunt.SnapshotClientCount
+	if !hunt.SnapshotClients {
+		var err error
+		eligible, err = self.eligible.Get(hunt, now, func() (uint64, error) {
+			return flows.EstimateHunt(ctx, config_obj, hunt)
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if hunt.ClientLimit > 0 && eligible > hunt.ClientLimit {
+		eligible = hunt.ClientLimit
+	}
+
+	return eligible, nil
+}
+
+// Fill in the clients remaining and the ETA from the hunt's
+// stats. Called with the hunt locked.
+func updateHuntProgress(hunt *api_proto.Hunt, now uint64) {
+	stats := hunt.Stats
+
+	// Clients may have enrolled since the eligible clients were
+	// estimated.
+	known := stats.TotalClientsScheduled + stats.TotalClientsQueued
+	if stats.TotalClientsEligible < known {
+		stats.TotalClientsEligible = known
+	}
+
+	completed := stats.TotalClientsWithResults + stats.TotalClientsWithErrors
+	stats.TotalClientsRemaining = 0
+	if stats.TotalClientsEligible > completed {
+		stats.TotalClientsRemaining = stats.TotalClientsEligible - completed
+	}
+
+	stats.Eta = flows.EstimateCompletionTime(
+		hunt.StartTime, now, completed, stats.TotalClientsRemaining)
+}
+
+// Add the duration of a completed collection (in microseconds) to
+// the hunt's average.
+func recordHuntFlowDuration(stats *api_proto.HuntStats, duration uint64) {
+	stats.TotalFlowsTimed++
+	average := float64(stats.AverageFlowDuration)
+	average += (float64(duration) - average) / float64(stats.TotalFlowsTimed)
+	stats.AverageFlowDuration = uint64(average)
+}
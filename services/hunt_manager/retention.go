@@ -0,0 +1,211 @@
+package hunt_manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/flows"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	artifact_paths "www.velocidex.com/golang/velociraptor/paths/artifacts"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/vfilter"
+)
+
+var (
+	// How often we check hunts for expired files.
+	retention_sweep_period = time.Hour
+)
+
+// Periodically remove hunt files that are older than the hunt's
+// retention policy.
+func (self *HuntManager) StartRetentionSweeper(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	wg *sync.WaitGroup) error {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(retention_sweep_period):
+				err := SweepRetention(ctx, config_obj, time.Now())
+				if err != nil {
+					logger := logging.GetLogger(
+						config_obj, &logging.FrontendComponent)
+					logger.Error(fmt.Sprintf("SweepRetention: %v", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Apply the retention policy of all hunts relative to now. Prepared
+// download files are removed once they are older than the hunt's
+// RetentionDays, and if the hunt also specifies
+// RetentionIncludesResults, so are the results of flows that
+// completed before that time. Hunts that are still RUNNING are never
+// touched. Expired hunt idempotency keys are removed too.
+func SweepRetention(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	now time.Time) error {
+
+	err := flows.SweepHuntIdempotencyKeys(config_obj, now)
+	if err != nil {
+		return err
+	}
+
+	dispatcher := services.GetHuntDispatcher()
+	if dispatcher == nil {
+		return nil
+	}
+
+	// Take a copy of the hunts so we do not hold the dispatcher
+	// lock while we delete files.
+	hunts := []*api_proto.Hunt{}
+	err = dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		if hunt.RetentionDays <= 0 ||
+			hunt.State == api_proto.Hunt_RUNNING {
+			return nil
+		}
+		hunts = append(hunts, proto.Clone(hunt).(*api_proto.Hunt))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, hunt := range hunts {
+		cutoff := now.Add(-time.Duration(hunt.RetentionDays) * 24 * time.Hour)
+
+		err := sweepHuntDownloads(config_obj, hunt, cutoff)
+		if err != nil {
+			return err
+		}
+
+		if hunt.RetentionIncludesResults {
+			err = sweepHuntResults(ctx, config_obj, hunt, cutoff)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Remove prepared download files older than the cutoff. Downloads
+// which are still being written (have a lock file) are left alone.
+func sweepHuntDownloads(
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt,
+	cutoff time.Time) error {
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt.HuntId)
+	download_dir := path.Dir(hunt_path_manager.GetHuntDownloadsFile(false, ""))
+
+	file_store_factory := file_store.GetFileStore(config_obj)
+	children, err := file_store_factory.ListDirectory(download_dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	names := make(map[string]bool)
+	for _, child := range children {
+		names[child.Name()] = true
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	for _, child := range children {
+		name := child.Name()
+		if strings.HasSuffix(name, ".lock") ||
+			names[name+".lock"] || !child.ModTime().Before(cutoff) {
+			continue
+		}
+
+		filename := path.Join(download_dir, name)
+		logger.Info("Retention: Removing hunt download %v", filename)
+		err := file_store_factory.Delete(filename)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove the result sets of all the hunt's flows that finished
+// before the cutoff.
+func sweepHuntResults(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt,
+	cutoff time.Time) error {
+
+	hunt_path_manager := paths.NewHuntPathManager(hunt.HuntId).Clients()
+	row_chan, err := file_store.GetTimeRange(ctx, config_obj,
+		hunt_path_manager, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	cutoff_usec := flows.HuntTimeFromTime(cutoff)
+	file_store_factory := file_store.GetFileStore(config_obj)
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	scope := vfilter.NewScope()
+
+	for row := range row_chan {
+		participation_row := &ParticipationRecord{}
+		err := vfilter.ExtractArgs(scope, row, participation_row)
+		if err != nil || !participation_row.Participate ||
+			participation_row.FlowId == "" {
+			continue
+		}
+
+		collection_context, err := flows.LoadCollectionContext(
+			config_obj, participation_row.ClientId,
+			participation_row.FlowId)
+		if err != nil || collection_context.ActiveTime == 0 ||
+			collection_context.ActiveTime >= cutoff_usec {
+			continue
+		}
+
+		for _, artifact := range collection_context.ArtifactsWithResults {
+			path_manager := artifact_paths.NewArtifactPathManager(
+				config_obj, participation_row.ClientId,
+				participation_row.FlowId, artifact)
+			filename, err := path_manager.GetPathForWriting()
+			if err != nil {
+				continue
+			}
+
+			logger.Info("Retention: Removing hunt results %v", filename)
+			err = file_store_factory.Delete(filename)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
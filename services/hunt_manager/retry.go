@@ -0,0 +1,448 @@
+package hunt_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/golang/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store"
+	"www.velocidex.com/golang/velociraptor/flows"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
+)
+
+var (
+	// How often we check for retries which are due.
+	retry_sweep_period = 10 * time.Second
+)
+
+// Periodically schedule the retries of failed hunt collections.
+func (self *HuntManager) StartRetrySweeper(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	wg *sync.WaitGroup) error {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(retry_sweep_period):
+				err := SweepRetries(ctx, config_obj, time.Now())
+				if err != nil {
+					logger := logging.GetLogger(
+						config_obj, &logging.FrontendComponent)
+					logger.Error(fmt.Sprintf("SweepRetries: %v", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Schedule the retries which are due by now. Retries are only
+// scheduled while their hunt is running - the retries of paused
+// hunts wait until the hunt is running again. The retries of
+// stopped, archived or expired hunts are dropped and their clients
+// count as failed.
+func SweepRetries(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	now time.Time) error {
+
+	dispatcher := services.GetHuntDispatcher()
+	if dispatcher == nil {
+		return nil
+	}
+
+	hunt_now := flows.HuntTimeFromTime(now)
+
+	// Take a copy of the hunts so we do not hold the dispatcher
+	// lock while we schedule the flows.
+	hunts := []*api_proto.Hunt{}
+	stopped_hunt_ids := []string{}
+	err := dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		if hunt.RetryPolicy == nil {
+			return nil
+		}
+
+		if hunt.State == api_proto.Hunt_STOPPED ||
+			hunt.State == api_proto.Hunt_ARCHIVED ||
+			(hunt.Stats != nil && hunt.Stats.Stopped) ||
+			hunt_now > hunt.Expires {
+			if hunt.Stats != nil && hunt.Stats.TotalClientsRetrying > 0 {
+				stopped_hunt_ids = append(stopped_hunt_ids, hunt.HuntId)
+			}
+			return nil
+		}
+
+		if hunt.State == api_proto.Hunt_RUNNING {
+			hunts = append(hunts, proto.Clone(hunt).(*api_proto.Hunt))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	for _, hunt_id := range stopped_hunt_ids {
+		err := dropHuntRetries(config_obj, hunt_id)
+		if err != nil {
+			logger.Error("SweepRetries %v: %v", hunt_id, err)
+		}
+	}
+
+	for _, hunt := range hunts {
+		records, err := hunt_dispatcher.ListHuntRetries(config_obj, hunt.HuntId)
+		if err != nil {
+			logger.Error("SweepRetries %v: %v", hunt.HuntId, err)
+			continue
+		}
+
+		for _, record := range records {
+			if record.NotBefore > hunt_now {
+				continue
+			}
+
+			// Excluded clients are not retried at all.
+			if flows.HuntExcludesClient(hunt, record.ClientId) {
+				err := dropHuntRetry(config_obj, record)
+				if err != nil {
+					logger.Error("SweepRetries %v: %v", hunt.HuntId, err)
+				}
+				continue
+			}
+
+			err := scheduleRetry(ctx, config_obj, hunt, record)
+			if err != nil {
+				logger.Error("SweepRetries %v: retry on %v: %v",
+					hunt.HuntId, record.ClientId, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Drop the pending retries of a hunt which will not run any more.
+func dropHuntRetries(config_obj *config_proto.Config, hunt_id string) error {
+	records, err := hunt_dispatcher.ListHuntRetries(config_obj, hunt_id)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		err := dropHuntRetry(config_obj, record)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(records) > 0 {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Info("Hunt %v: dropped %v pending retries", hunt_id, len(records))
+	}
+
+	return nil
+}
+
+// Drop the pending retry. The client is no longer retrying - its
+// last collection failed.
+func dropHuntRetry(
+	config_obj *config_proto.Config, record *api_proto.HuntRetryRecord) error {
+	err := hunt_dispatcher.DeleteHuntRetry(config_obj, record)
+	if err != nil {
+		return err
+	}
+
+	return services.GetHuntDispatcher().ModifyHunt(record.HuntId,
+		func(hunt_obj *api_proto.Hunt) error {
+			if hunt_obj.Stats == nil {
+				return nil
+			}
+			if hunt_obj.Stats.TotalClientsRetrying > 0 {
+				hunt_obj.Stats.TotalClientsRetrying--
+			}
+			hunt_obj.Stats.TotalClientsWithErrors++
+			return nil
+		})
+}
+
+// Does the client still match the hunt's condition? The client may
+// have changed (e.g. it was relabeled or upgraded) since it was
+// first scheduled.
+func clientMatchesHunt(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt, client_id string) (bool, error) {
+	client_info_manager := services.GetClientInfoManager()
+	if client_info_manager == nil {
+		return false, errors.New("Client info manager not ready")
+	}
+
+	client_info, err := client_info_manager.Get(client_id)
+	if err != nil {
+		return false, err
+	}
+
+	if !huntMatchesOS(hunt, client_info) ||
+		!flows.ClientMatchesVersion(hunt, client_info) ||
+		!huntHasLabel(config_obj, hunt, client_id) {
+		return false, nil
+	}
+
+	// A condition which could not be evaluated is tried again on
+	// the next sweep.
+	return flows.EvaluateConditionVQL(ctx, config_obj, hunt, client_id)
+}
+
+// Schedule the hunt's collection on the client again. The retry is
+// only removed once the new flow is scheduled so it is tried again
+// on the next sweep if this fails. Clients which no longer match
+// the hunt's condition are not retried.
+func scheduleRetry(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt,
+	record *api_proto.HuntRetryRecord) error {
+
+	matches, err := clientMatchesHunt(ctx, config_obj, hunt, record.ClientId)
+	if err != nil {
+		return err
+	}
+
+	if !matches {
+		logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+		logger.Info("Hunt %v: %v no longer matches the hunt's condition, not retrying",
+			hunt.HuntId, record.ClientId)
+		return dropHuntRetry(config_obj, record)
+	}
+
+	flow_id, err := relaunchHuntFlow(ctx, config_obj, hunt,
+		record.ClientId, record.FlowId, record.Attempt)
+	if flow_id == "" {
+		return err
+	}
+
+	// The flow is already scheduled so the retry is done even if
+	// the client could not be notified.
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	if err != nil {
+		logger.Error("Hunt %v: retry on %v: %v",
+			hunt.HuntId, record.ClientId, err)
+	}
+
+	logger.Info("Hunt %v: retry %v on %v as %v after %v failed: %v",
+		hunt.HuntId, record.Attempt, record.ClientId, flow_id,
+		record.FlowId, record.Error)
+
+	return hunt_dispatcher.DeleteHuntRetry(config_obj, record)
+}
+
+// Launch the hunt's collection on the client again as the given
+// retry attempt. The new flow replaces the failed one in the hunt's
+// flow index. The new flow id is returned once the flow is scheduled,
+// even if the client could not be notified.
+func relaunchHuntFlow(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt *api_proto.Hunt,
+	client_id, failed_flow_id string, attempt uint64) (string, error) {
+
+	request := &flows_proto.ArtifactCollectorArgs{
+		ClientId: client_id,
+		Creator:  hunt.HuntId,
+	}
+	proto.Merge(request, hunt.StartRequest)
+	applyLabelParameters(config_obj, hunt, request)
+	request.ClientId = client_id
+	request.HuntRetry = attempt
+
+	row := ordereddict.NewDict().
+		Set("HuntId", hunt.HuntId).
+		Set("ClientId", client_id).
+		Set("Participate", true).
+		Set("Retry", attempt).
+		Set("RetriedFlowId", failed_flow_id)
+
+	err := launchHuntFlow(ctx, config_obj, hunt.HuntId, request, row)
+	flow_id, _ := row.GetString("FlowId")
+	return flow_id, err
+}
+
+// Schedule the hunt's collection again, under the same hunt, on each
+// client whose latest flow in the hunt failed (e.g. it errored or
+// timed out). The clients of a completed hunt may be retried, but
+// not once the hunt expired or was stopped automatically. When hunts
+// require approval the hunt must be running, so retrying a stopped
+// hunt needs it to be approved again first. Clients with a retry
+// already pending, clients excluded from the hunt, clients which no
+// longer match its condition and clients beyond the hunt's client
+// limit are skipped. Like automatic retries, the
+// clients count as retrying rather than failed until their new flow
+// completes.
+func RetryFailedClients(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string) (*api_proto.RetryFailedClientsResponse, error) {
+
+	hunt, err := flows.GetHunt(config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkHuntRetryable(config_obj, hunt, flows.HuntTimeNow())
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := hunt_dispatcher.ListHuntRetries(config_obj, hunt_id)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]bool)
+	for _, record := range pending {
+		skip[record.ClientId] = true
+	}
+	for _, client_id := range hunt.ExcludedClients {
+		skip[client_id] = true
+	}
+
+	// Find each client's latest flow - retried flows come after
+	// the flows they replace in the index.
+	row_chan, err := file_store.GetTimeRange(ctx, config_obj,
+		paths.NewHuntPathManager(hunt_id).Clients(), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	client_ids := []string{}
+	latest_flows := make(map[string]string)
+	for row := range row_chan {
+		client_id, _ := row.GetString("ClientId")
+		flow_id, _ := row.GetString("FlowId")
+		if client_id == "" || flow_id == "" {
+			continue
+		}
+
+		_, pres := latest_flows[client_id]
+		if !pres {
+			client_ids = append(client_ids, client_id)
+		}
+		latest_flows[client_id] = flow_id
+	}
+
+	// Retries never take the hunt past its client limit.
+	if hunt.ClientLimit > 0 && uint64(len(client_ids)) > hunt.ClientLimit {
+		client_ids = client_ids[:hunt.ClientLimit]
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	result := &api_proto.RetryFailedClientsResponse{HuntId: hunt_id}
+	for _, client_id := range client_ids {
+		if skip[client_id] {
+			continue
+		}
+
+		failed_flow_id := latest_flows[client_id]
+		collection_context, err := flows.LoadCollectionContext(
+			config_obj, client_id, failed_flow_id)
+		if err != nil ||
+			collection_context.State != flows_proto.ArtifactCollectorContext_ERROR {
+			continue
+		}
+
+		matches, err := clientMatchesHunt(ctx, config_obj, hunt, client_id)
+		if err != nil || !matches {
+			continue
+		}
+
+		attempt := uint64(1)
+		if collection_context.Request != nil {
+			attempt = collection_context.Request.HuntRetry + 1
+		}
+
+		flow_id, err := relaunchHuntFlow(ctx, config_obj, hunt,
+			client_id, failed_flow_id, attempt)
+		if flow_id == "" {
+			logger.Error("RetryFailedClients %v: %v: %v", hunt_id, client_id, err)
+			continue
+		}
+
+		if err != nil {
+			logger.Error("Hunt %v: retry on %v: %v", hunt_id, client_id, err)
+		}
+
+		err = services.GetHuntDispatcher().ModifyHunt(hunt_id,
+			func(hunt_obj *api_proto.Hunt) error {
+				if hunt_obj.Stats == nil {
+					return nil
+				}
+				if hunt_obj.Stats.TotalClientsWithErrors > 0 {
+					hunt_obj.Stats.TotalClientsWithErrors--
+				}
+				hunt_obj.Stats.TotalClientsRetrying++
+				return nil
+			})
+		if err != nil {
+			logger.Error("RetryFailedClients %v: %v", hunt_id, err)
+		}
+
+		result.Items = append(result.Items, &api_proto.HuntClientRetry{
+			ClientId:     client_id,
+			FailedFlowId: failed_flow_id,
+			Error:        collection_context.Status,
+			FlowId:       flow_id,
+		})
+	}
+
+	logger.Info("Hunt %v: retried %v failed clients",
+		hunt_id, len(result.Items))
+
+	return result, nil
+}
+
+// Can the hunt's failed clients be retried by hand?
+func checkHuntRetryable(
+	config_obj *config_proto.Config, hunt *api_proto.Hunt, now uint64) error {
+	switch hunt.State {
+	case api_proto.Hunt_ARCHIVED:
+		return errors.New("Can not retry the clients of an archived hunt.")
+
+	case api_proto.Hunt_PENDING_APPROVAL:
+		return errors.New("Can not retry the clients of a hunt pending approval.")
+	}
+
+	// Expired hunts and hunts stopped automatically (e.g. they
+	// reached their client limit or error rate) can not be started
+	// again so their clients are not retried either.
+	if (hunt.Stats != nil && hunt.Stats.Stopped) || now > hunt.Expires {
+		return errors.New("Can not retry the clients of an expired or stopped hunt.")
+	}
+
+	// Retrying a hunt which is not running amounts to starting it
+	// again, which must be approved by another user.
+	if hunt.State != api_proto.Hunt_RUNNING && flows.HuntApprovalRequired(config_obj) {
+		return errors.New(
+			"Hunts must be approved and running to retry their clients.")
+	}
+
+	return nil
+}
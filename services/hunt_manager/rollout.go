@@ -0,0 +1,397 @@
+package hunt_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/golang/protobuf/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/flows"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
+)
+
+var (
+	// How often we schedule queued clients of hunts with a
+	// rollout rate.
+	rollout_sweep_period = 5 * time.Second
+
+	// Queued clients which fail to be scheduled this many times
+	// are dropped from the queue.
+	max_queued_client_failures = uint64(3)
+
+	errRolloutRateReached = errors.New("hunt rollout rate reached")
+	errHuntNotRunning     = errors.New("hunt is not running")
+)
+
+// Tracks when clients were scheduled on each hunt with a rollout
+// rate, over a sliding window of a minute. This is only kept in
+// memory so after a restart a hunt may schedule up to its rate
+// straight away.
+type rolloutLimiter struct {
+	mu        sync.Mutex
+	scheduled map[string][]time.Time
+}
+
+// Take a slot for scheduling a client on the hunt if fewer than
+// limit clients were scheduled in the last minute. Returns whether
+// the client may be scheduled and how many clients were scheduled
+// in the last minute.
+func (self *rolloutLimiter) Allow(
+	hunt_id string, limit uint64, now time.Time) (bool, uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	times := self.prune(hunt_id, now)
+	if uint64(len(times)) >= limit {
+		return false, uint64(len(times))
+	}
+
+	self.scheduled[hunt_id] = append(times, now)
+	return true, uint64(len(times)) + 1
+}
+
+// Give back a slot taken by Allow at now, e.g. because scheduling
+// the client failed.
+func (self *rolloutLimiter) Release(hunt_id string, now time.Time) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	times := self.scheduled[hunt_id]
+	for i := len(times) - 1; i >= 0; i-- {
+		if times[i].Equal(now) {
+			times = append(times[:i:i], times[i+1:]...)
+			break
+		}
+	}
+
+	if len(times) == 0 {
+		delete(self.scheduled, hunt_id)
+	} else {
+		self.scheduled[hunt_id] = times
+	}
+}
+
+// How many clients were scheduled on the hunt in the last minute.
+func (self *rolloutLimiter) Rate(hunt_id string, now time.Time) uint64 {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return uint64(len(self.prune(hunt_id, now)))
+}
+
+// Drop the times which are older than a minute. Called with the
+// lock held.
+func (self *rolloutLimiter) prune(hunt_id string, now time.Time) []time.Time {
+	if self.scheduled == nil {
+		self.scheduled = make(map[string][]time.Time)
+	}
+
+	times := self.scheduled[hunt_id]
+	cutoff := now.Add(-time.Minute)
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+	times = times[i:]
+
+	if len(times) == 0 {
+		delete(self.scheduled, hunt_id)
+	} else {
+		self.scheduled[hunt_id] = times
+	}
+
+	return times
+}
+
+// Periodically schedule the queued clients of hunts with a rollout
+// rate.
+func (self *HuntManager) StartRolloutSweeper(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	wg *sync.WaitGroup) error {
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(rollout_sweep_period):
+				err := self.SweepHuntQueues(ctx, config_obj, time.Now())
+				if err != nil {
+					logger := logging.GetLogger(
+						config_obj, &logging.FrontendComponent)
+					logger.Error(fmt.Sprintf("SweepHuntQueues: %v", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Schedule as many queued clients as each hunt's rollout rate
+// allows, oldest first. Clients are only scheduled while their hunt
+// is running - the queues of paused hunts wait until the hunt is
+// running again. The queues of stopped or archived hunts are
+// dropped.
+func (self *HuntManager) SweepHuntQueues(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	now time.Time) error {
+
+	dispatcher := services.GetHuntDispatcher()
+	if dispatcher == nil {
+		return nil
+	}
+
+	hunt_ids := []string{}
+	stopped_hunt_ids := []string{}
+	err := dispatcher.ApplyFuncOnHunts(func(hunt *api_proto.Hunt) error {
+		if hunt.ClientsPerMinute == 0 {
+			return nil
+		}
+
+		if hunt.State == api_proto.Hunt_STOPPED ||
+			hunt.State == api_proto.Hunt_ARCHIVED ||
+			(hunt.Stats != nil && hunt.Stats.Stopped) {
+			if hunt.Stats != nil && hunt.Stats.TotalClientsQueued > 0 {
+				stopped_hunt_ids = append(stopped_hunt_ids, hunt.HuntId)
+			}
+			return nil
+		}
+
+		if hunt.State == api_proto.Hunt_RUNNING {
+			hunt_ids = append(hunt_ids, hunt.HuntId)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	for _, hunt_id := range stopped_hunt_ids {
+		err := dropHuntQueue(config_obj, hunt_id)
+		if err != nil {
+			logger.Error("SweepHuntQueues %v: %v", hunt_id, err)
+		}
+	}
+
+	for _, hunt_id := range hunt_ids {
+		records, err := hunt_dispatcher.ListHuntQueue(config_obj, hunt_id)
+		if err != nil {
+			logger.Error("SweepHuntQueues %v: %v", hunt_id, err)
+			continue
+		}
+
+		for _, record := range records {
+			err := self.scheduleQueuedClient(ctx, config_obj, record, now)
+			if err == errRolloutRateReached || err == errHuntNotRunning {
+				break
+			}
+
+			// The client failed but the clients behind it
+			// may still be scheduled.
+			if err != nil {
+				logger.Error("SweepHuntQueues %v: %v: %v",
+					hunt_id, record.ClientId, err)
+			}
+		}
+
+		// Keep the rate current even when no clients are
+		// scheduled.
+		rate := self.rollout.Rate(hunt_id, now)
+		_ = dispatcher.ModifyHunt(hunt_id, func(hunt *api_proto.Hunt) error {
+			if hunt.Stats != nil {
+				hunt.Stats.SchedulingRate = rate
+			}
+			return nil
+		})
+	}
+
+	return nil
+}
+
+// Drop the clients queued on a stopped hunt - they will never be
+// scheduled.
+func dropHuntQueue(config_obj *config_proto.Config, hunt_id string) error {
+	dropped, err := hunt_dispatcher.DeleteHuntQueue(config_obj, hunt_id)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Info("Hunt %v: dropped %v queued clients of the stopped hunt",
+		hunt_id, dropped)
+
+	return services.GetHuntDispatcher().ModifyHunt(hunt_id,
+		func(hunt_obj *api_proto.Hunt) error {
+			if hunt_obj.Stats != nil {
+				hunt_obj.Stats.TotalClientsQueued = 0
+			}
+			return nil
+		})
+}
+
+// Schedule the queued client if the hunt's rollout rate allows
+// it. The client only counts as scheduled, and is removed from the
+// queue, once its flow is scheduled. Clients which fail to be
+// scheduled too many times are dropped from the queue.
+func (self *HuntManager) scheduleQueuedClient(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	record *api_proto.HuntQueuedClient,
+	now time.Time) error {
+
+	request := &flows_proto.ArtifactCollectorArgs{
+		ClientId: record.ClientId,
+		Creator:  record.HuntId,
+	}
+
+	stopped_reason := ""
+	excluded := false
+	err := services.GetHuntDispatcher().ModifyHunt(record.HuntId,
+		func(hunt_obj *api_proto.Hunt) error {
+			if hunt_obj.Stats == nil {
+				hunt_obj.Stats = &api_proto.HuntStats{}
+			}
+
+			if hunt_obj.Stats.Stopped ||
+				hunt_obj.State != api_proto.Hunt_RUNNING {
+				return errHuntNotRunning
+			}
+
+			// Excluded clients leave the queue without being
+			// scheduled.
+			if flows.HuntExcludesClient(hunt_obj, record.ClientId) {
+				if hunt_obj.Stats.TotalClientsQueued > 0 {
+					hunt_obj.Stats.TotalClientsQueued--
+				}
+				excluded = true
+				return nil
+			}
+
+			stopped_reason = huntStoppedReason(
+				hunt_obj, flows.HuntTimeFromTime(now))
+			if stopped_reason != "" {
+				hunt_obj.Stats.Stopped = true
+				return errHuntNotRunning
+			}
+
+			allowed, rate := self.rollout.Allow(
+				hunt_obj.HuntId, hunt_obj.ClientsPerMinute, now)
+			hunt_obj.Stats.SchedulingRate = rate
+			if !allowed {
+				return errRolloutRateReached
+			}
+
+			proto.Merge(request, hunt_obj.StartRequest)
+			applyLabelParameters(config_obj, hunt_obj, request)
+
+			return nil
+		})
+
+	if stopped_reason != "" {
+		flows.EmitHuntStateChange(config_obj, record.HuntId,
+			api_proto.Hunt_RUNNING, api_proto.Hunt_STOPPED, "",
+			stopped_reason)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if excluded {
+		return hunt_dispatcher.DeleteHuntQueuedClient(config_obj, record)
+	}
+
+	request.ClientId = record.ClientId
+	row := ordereddict.NewDict().
+		Set("HuntId", record.HuntId).
+		Set("ClientId", record.ClientId).
+		Set("Participate", true)
+
+	err = launchHuntFlow(ctx, config_obj, record.HuntId, request, row)
+	flow_id, _ := row.GetString("FlowId")
+	if flow_id == "" {
+		// The slot was not used after all.
+		self.rollout.Release(record.HuntId, now)
+		return queuedClientFailed(config_obj, record, err)
+	}
+
+	// The flow is already scheduled so the client leaves the queue
+	// even if it could not be notified.
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	if err != nil {
+		logger.Error("Hunt %v: queued client %v: %v",
+			record.HuntId, record.ClientId, err)
+	}
+
+	err = services.GetHuntDispatcher().ModifyHunt(record.HuntId,
+		func(hunt_obj *api_proto.Hunt) error {
+			if hunt_obj.Stats == nil {
+				hunt_obj.Stats = &api_proto.HuntStats{}
+			}
+			hunt_obj.Stats.TotalClientsScheduled += 1
+			if hunt_obj.Stats.TotalClientsQueued > 0 {
+				hunt_obj.Stats.TotalClientsQueued--
+			}
+			return nil
+		})
+	if err != nil {
+		logger.Error("Hunt %v: queued client %v: %v",
+			record.HuntId, record.ClientId, err)
+	}
+
+	return hunt_dispatcher.DeleteHuntQueuedClient(config_obj, record)
+}
+
+// Record that scheduling the queued client failed. The client stays
+// in the queue to be tried again on the next sweep, unless it failed
+// too many times already.
+func queuedClientFailed(
+	config_obj *config_proto.Config,
+	record *api_proto.HuntQueuedClient, launch_err error) error {
+	if launch_err == nil {
+		launch_err = errors.New("no flow was scheduled")
+	}
+
+	record.Failures++
+	record.Error = launch_err.Error()
+	if record.Failures < max_queued_client_failures {
+		err := hunt_dispatcher.QueueHuntClient(config_obj, record)
+		if err != nil {
+			return err
+		}
+		return launch_err
+	}
+
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+	logger.Error("Hunt %v: dropping queued client %v after %v failures: %v",
+		record.HuntId, record.ClientId, record.Failures, launch_err)
+
+	err := hunt_dispatcher.DeleteHuntQueuedClient(config_obj, record)
+	if err != nil {
+		return err
+	}
+
+	return services.GetHuntDispatcher().ModifyHunt(record.HuntId,
+		func(hunt_obj *api_proto.Hunt) error {
+			if hunt_obj.Stats != nil && hunt_obj.Stats.TotalClientsQueued > 0 {
+				hunt_obj.Stats.TotalClientsQueued--
+			}
+			return nil
+		})
+}
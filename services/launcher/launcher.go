@@ -75,6 +75,14 @@ func (self *Launcher) CompileCollectorArgs(
 	collector_request *flows_proto.ArtifactCollectorArgs) (
 	[]*actions_proto.VQLCollectorArgs, error) {
 
+	if collector_request.CpuLimit < 0 || collector_request.CpuLimit > 100 {
+		return nil, errors.New("CPU limit must be between 0 and 100 percent.")
+	}
+
+	if collector_request.IopsLimit < 0 {
+		return nil, errors.New("IOPS limit must not be negative.")
+	}
+
 	result := []*actions_proto.VQLCollectorArgs{}
 
 	for _, spec := range getCollectorSpecs(collector_request) {
@@ -106,6 +114,8 @@ func (self *Launcher) CompileCollectorArgs(
 			}
 
 			vql_collector_args.OpsPerSecond = collector_request.OpsPerSecond
+			vql_collector_args.CpuLimit = collector_request.CpuLimit
+			vql_collector_args.IopsLimit = collector_request.IopsLimit
 			vql_collector_args.Timeout = collector_request.Timeout
 			vql_collector_args.MaxRow = 1000
 
@@ -16,6 +16,7 @@ package services
 
 import (
 	"sync"
+	"time"
 
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 )
@@ -62,7 +63,25 @@ type Notifier interface {
 	// that was registered above.
 	NotifyListener(config_obj *config_proto.Config, id string) error
 
+	// Like NotifyByRegex but the matching listeners are notified
+	// a batch at a time to avoid waking them all at once. Returns
+	// before all batches are sent - remaining batches are dropped
+	// if the notifier shuts down.
+	NotifyByRegexInBatches(config_obj *config_proto.Config, regex string,
+		options NotificationBatchOptions) error
+
+	// Notify the listeners a batch at a time in the background.
+	NotifyListenersInBatches(config_obj *config_proto.Config, ids []string,
+		options NotificationBatchOptions) error
+
 	// Check if there is someone listening for the specified
 	// id. Currently this only works on one frontend.
 	IsClientConnected(client_id string) bool
 }
+
+// How to spread out notifying many listeners. A BatchSize of 0
+// notifies all listeners at once.
+type NotificationBatchOptions struct {
+	BatchSize int
+	Delay     time.Duration
+}
@@ -18,17 +18,24 @@ import (
 	"context"
 	"regexp"
 	"sync"
+	"time"
 
 	"github.com/Velocidex/ordereddict"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/notifications"
 	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
 )
 
 type Notifier struct {
 	pool_mu           sync.Mutex
 	notification_pool *notifications.NotificationPool
+
+	// Batched notifications are sent in the background until the
+	// service shuts down.
+	ctx context.Context
+	wg  *sync.WaitGroup
 }
 
 // The notifier service watches for events from
@@ -41,7 +48,10 @@ func StartNotificationService(
 	wg *sync.WaitGroup,
 	config_obj *config_proto.Config) error {
 
-	self := &Notifier{}
+	self := &Notifier{
+		ctx: ctx,
+		wg:  wg,
+	}
 
 	self.pool_mu.Lock()
 	defer self.pool_mu.Unlock()
@@ -90,21 +100,13 @@ func StartNotificationService(
 					continue
 				}
 
-				self.pool_mu.Lock()
 				if target == "Regex" {
-					regex_str, ok := event.GetString("Regex")
-					if ok {
-						regex, err := regexp.Compile(regex_str)
-						if err != nil {
-							logger.Error("Notification service: "+
-								"Unable to compiler regex '%v': %v\n",
-								regex_str, err)
-							continue
-						}
-						self.notification_pool.NotifyByRegex(regex)
-					}
-
-				} else if target == "All" {
+					self.processRegexEvent(logger, event)
+					continue
+				}
+
+				self.pool_mu.Lock()
+				if target == "All" {
 					self.notification_pool.NotifyAll()
 				} else {
 					self.notification_pool.Notify(target)
@@ -119,6 +121,35 @@ func StartNotificationService(
 	return nil
 }
 
+func (self *Notifier) processRegexEvent(
+	logger *logging.LogContext, event *ordereddict.Dict) {
+	regex_str, ok := event.GetString("Regex")
+	if !ok {
+		return
+	}
+
+	regex, err := regexp.Compile(regex_str)
+	if err != nil {
+		logger.Error("Notification service: "+
+			"Unable to compiler regex '%v': %v\n",
+			regex_str, err)
+		return
+	}
+
+	options := batchOptionsFromEvent(event)
+
+	self.pool_mu.Lock()
+	if options.BatchSize <= 0 {
+		self.notification_pool.NotifyByRegex(regex)
+		self.pool_mu.Unlock()
+		return
+	}
+	ids := self.notification_pool.MatchingListeners(regex)
+	self.pool_mu.Unlock()
+
+	self.notifyInBatches(ids, options, self.notifyLocalListener)
+}
+
 func (self *Notifier) ListenForNotification(client_id string) (chan bool, func()) {
 	self.pool_mu.Lock()
 	defer self.pool_mu.Unlock()
@@ -155,6 +186,105 @@ func (self *Notifier) NotifyByRegex(config_obj *config_proto.Config, regex strin
 	)
 }
 
+func (self *Notifier) NotifyByRegexInBatches(
+	config_obj *config_proto.Config, regex string,
+	options services.NotificationBatchOptions) error {
+	journal, err := services.GetJournal()
+	if err != nil {
+		return err
+	}
+
+	// Each frontend notifies its own listeners in batches.
+	return journal.PushRowsToArtifact(config_obj,
+		[]*ordereddict.Dict{ordereddict.NewDict().Set("Target", "Regex").
+			Set("Regex", regex).
+			Set("BatchSize", options.BatchSize).
+			Set("BatchDelay", options.Delay.Milliseconds())},
+		"Server.Internal.Notifications", "server", "",
+	)
+}
+
+func (self *Notifier) NotifyListenersInBatches(
+	config_obj *config_proto.Config, ids []string,
+	options services.NotificationBatchOptions) error {
+	logger := logging.GetLogger(config_obj, &logging.FrontendComponent)
+
+	self.notifyInBatches(ids, options, func(id string) {
+		err := self.NotifyListener(config_obj, id)
+		if err != nil {
+			logger.Error("Notification service: %v: %v", id, err)
+		}
+	})
+	return nil
+}
+
+// Call notify for each id, options.BatchSize ids at a time with
+// options.Delay between batches. The first batch is sent right away
+// and the rest in the background.
+func (self *Notifier) notifyInBatches(ids []string,
+	options services.NotificationBatchOptions, notify func(id string)) {
+	if options.BatchSize <= 0 || options.BatchSize >= len(ids) {
+		for _, id := range ids {
+			notify(id)
+		}
+		return
+	}
+
+	for _, id := range ids[:options.BatchSize] {
+		notify(id)
+	}
+	ids = ids[options.BatchSize:]
+
+	self.wg.Add(1)
+	go func() {
+		defer self.wg.Done()
+
+		for len(ids) > 0 {
+			select {
+			case <-self.ctx.Done():
+				return
+			case <-time.After(options.Delay):
+			}
+
+			batch := ids
+			if len(batch) > options.BatchSize {
+				batch = batch[:options.BatchSize]
+			}
+			ids = ids[len(batch):]
+
+			for _, id := range batch {
+				notify(id)
+			}
+		}
+	}()
+}
+
+// Notify a listener connected to this frontend. Must be called
+// without the pool lock held.
+func (self *Notifier) notifyLocalListener(id string) {
+	self.pool_mu.Lock()
+	defer self.pool_mu.Unlock()
+
+	if self.notification_pool != nil {
+		self.notification_pool.Notify(id)
+	}
+}
+
+func batchOptionsFromEvent(
+	event *ordereddict.Dict) services.NotificationBatchOptions {
+	result := services.NotificationBatchOptions{}
+
+	batch_size, _ := event.Get("BatchSize")
+	size, _ := utils.ToInt64(batch_size)
+	result.BatchSize = int(size)
+
+	batch_delay, _ := event.Get("BatchDelay")
+	delay, _ := utils.ToInt64(batch_delay)
+	result.Delay = time.Duration(delay) * time.Millisecond
+
+	return result
+}
+
 func (self *Notifier) NotifyListener(config_obj *config_proto.Config, id string) error {
 	journal, err := services.GetJournal()
 	if err != nil {
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A parsed cron expression in the standard 5 field format:
+// minute hour day-of-month month day-of-week
+//
+// Each field may be *, a number, a range (1-5), a step (*/15 or
+// 1-30/5) or a comma separated list of these. Day of week 0 and 7
+// are both Sunday. As in cron, if both day of month and day of week
+// are restricted, a day matching either one fires.
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	dom_any, dow_any bool
+}
+
+type cronField struct {
+	min, max int
+}
+
+var cron_fields = []cronField{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week
+}
+
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cron_fields) {
+		return nil, fmt.Errorf(
+			"Cron schedule %q should have 5 fields", expr)
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		value, err := parseCronField(field, cron_fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("Cron schedule %q: %v", expr, err)
+		}
+		bits[i] = value
+	}
+
+	// Sunday may be given as 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &CronSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		dom_any: fields[2] == "*",
+		dow_any: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, limits cronField) (uint64, error) {
+	result := uint64(0)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			value, err := strconv.Atoi(part[idx+1:])
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = value
+			part = part[:idx]
+		}
+
+		start, end := limits.min, limits.max
+		if part != "*" {
+			var err error
+			bounds := strings.SplitN(part, "-", 2)
+			start, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			end = start
+			if len(bounds) == 2 {
+				end, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return 0, fmt.Errorf("invalid value %q", part)
+				}
+			}
+		}
+
+		if start < limits.min || end > limits.max || start > end {
+			return 0, fmt.Errorf("%q is out of range %d-%d",
+				part, limits.min, limits.max)
+		}
+
+		for i := start; i <= end; i += step {
+			result |= 1 << uint(i)
+		}
+	}
+
+	return result, nil
+}
+
+func (self *CronSchedule) matchesDay(t time.Time) bool {
+	dom_match := self.dom&(1<<uint(t.Day())) != 0
+	dow_match := self.dow&(1<<uint(t.Weekday())) != 0
+
+	if self.dom_any || self.dow_any {
+		return dom_match && dow_match
+	}
+	return dom_match || dow_match
+}
+
+// Next returns the first time after t which matches the schedule, or
+// the zero time if there is none (e.g. February 30).
+func (self *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Any valid schedule fires within a leap year cycle.
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if self.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !self.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if self.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+
+		if self.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
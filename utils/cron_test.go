@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cronTest struct {
+	schedule string
+	now      string
+	next     string
+}
+
+var cronTestCases = []cronTest{
+	{"* * * * *", "2021-03-01T10:15:30Z", "2021-03-01T10:16:00Z"},
+	{"0 * * * *", "2021-03-01T10:15:00Z", "2021-03-01T11:00:00Z"},
+	{"*/15 * * * *", "2021-03-01T10:15:00Z", "2021-03-01T10:30:00Z"},
+	{"30 2 * * *", "2021-03-01T10:15:00Z", "2021-03-02T02:30:00Z"},
+	{"0 0 1 * *", "2021-12-15T00:00:00Z", "2022-01-01T00:00:00Z"},
+	{"0 9 * * 1-5", "2021-03-05T10:00:00Z", "2021-03-08T09:00:00Z"},
+
+	// Sunday may be given as 7.
+	{"0 0 * * 7", "2021-03-01T00:00:00Z", "2021-03-07T00:00:00Z"},
+
+	// Both day fields restricted - either matches.
+	{"0 0 15 * 1", "2021-03-01T00:00:00Z", "2021-03-08T00:00:00Z"},
+
+	{"0 0 29 2 *", "2021-03-01T00:00:00Z", "2024-02-29T00:00:00Z"},
+}
+
+func TestCronSchedule(t *testing.T) {
+	for _, test_case := range cronTestCases {
+		schedule, err := ParseCronSchedule(test_case.schedule)
+		assert.NoError(t, err, test_case.schedule)
+
+		now, _ := time.Parse(time.RFC3339, test_case.now)
+		next, _ := time.Parse(time.RFC3339, test_case.next)
+		assert.Equal(t, next, schedule.Next(now), test_case.schedule)
+	}
+}
+
+func TestCronScheduleInvalid(t *testing.T) {
+	for _, schedule := range []string{
+		"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+		"*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := ParseCronSchedule(schedule)
+		assert.Error(t, err, schedule)
+	}
+
+	// February 30 never happens.
+	schedule, err := ParseCronSchedule("0 0 30 2 *")
+	assert.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
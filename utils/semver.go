@@ -16,3 +16,9 @@ func normalizeSemVer(version string) string {
 func CompareVersions(v, w string) int {
 	return semver.Compare(normalizeSemVer(v), normalizeSemVer(w))
 }
+
+// Is the version a valid semantic version (with or without the
+// leading v)?
+func IsValidVersion(version string) bool {
+	return semver.IsValid(normalizeSemVer(version))
+}
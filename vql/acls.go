@@ -126,7 +126,18 @@ func GetPrincipal(scope vfilter.Scope) string {
 		return ""
 	}
 
-	manager, ok := manager_any.(*ServerACLManager)
+	manager, ok := manager_any.(ACLManager)
+	if !ok {
+		return ""
+	}
+
+	return GetPrincipalFromACLManager(manager)
+}
+
+// Get the principal the ACL manager checks permissions for. Only
+// server ACL managers carry a principal.
+func GetPrincipalFromACLManager(acl_manager ACLManager) string {
+	manager, ok := acl_manager.(*ServerACLManager)
 	if !ok {
 		return ""
 	}
@@ -3,6 +3,8 @@ package downloads
 import (
 	"archive/zip"
 	"context"
+	"crypto/sha256"
+	"hash"
 	"io"
 	"io/ioutil"
 	"os"
@@ -14,12 +16,15 @@ import (
 	"github.com/Velocidex/ordereddict"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"github.com/ulikunitz/xz"
+	"golang.org/x/crypto/openpgp"
 	"www.velocidex.com/golang/velociraptor/acls"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
 	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/file_store"
 	"www.velocidex.com/golang/velociraptor/file_store/csv"
 	"www.velocidex.com/golang/velociraptor/flows"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
 	"www.velocidex.com/golang/velociraptor/json"
 	"www.velocidex.com/golang/velociraptor/logging"
 	"www.velocidex.com/golang/velociraptor/paths"
@@ -31,11 +36,13 @@ import (
 )
 
 type CreateFlowDownloadArgs struct {
-	ClientId string `vfilter:"required,field=client_id,doc=Client ID to export."`
-	FlowId   string `vfilter:"required,field=flow_id,doc=The flow id to export."`
-	Wait     bool   `vfilter:"optional,field=wait,doc=If set we wait for the download to complete before returning."`
-	Type     string `vfilter:"optional,field=type,doc=Type of download to create (e.g. 'report') default a full zip file."`
-	Template string `vfilter:"optional,field=template,doc=Report template to use (defaults to Reporting.Default)."`
+	ClientId    string `vfilter:"required,field=client_id,doc=Client ID to export."`
+	FlowId      string `vfilter:"required,field=flow_id,doc=The flow id to export."`
+	Wait        bool   `vfilter:"optional,field=wait,doc=If set we wait for the download to complete before returning."`
+	Type        string `vfilter:"optional,field=type,doc=Type of download to create (e.g. 'report') default a full zip file."`
+	Template    string `vfilter:"optional,field=template,doc=Report template to use (defaults to Reporting.Default)."`
+	Compression string `vfilter:"optional,field=compression,doc=Compression for the zip members: deflate (default), store or xz."`
+	PublicKey   string `vfilter:"optional,field=public_key,doc=Encrypt the download to these ASCII armored PGP public keys. Only the matching private keys can decrypt it."`
 }
 
 type CreateFlowDownload struct{}
@@ -79,7 +86,20 @@ func (self *CreateFlowDownload) Call(ctx context.Context,
 		return result
 
 	case "":
-		result, err := createDownloadFile(config_obj, arg.FlowId, arg.ClientId, arg.Wait)
+		method, err := flows.GetDownloadCompressionMethod(arg.Compression)
+		if err != nil {
+			scope.Log("create_flow_download: %s", err)
+			return vfilter.Null{}
+		}
+
+		recipients, err := getDownloadRecipients(arg.PublicKey)
+		if err != nil {
+			scope.Log("create_flow_download: %s", err)
+			return vfilter.Null{}
+		}
+
+		result, err := createDownloadFile(config_obj, arg.FlowId,
+			arg.ClientId, arg.Wait, method, recipients)
 		if err != nil {
 			scope.Log("create_flow_download: %s", err)
 			return vfilter.Null{}
@@ -104,9 +124,13 @@ func (self CreateFlowDownload) Info(scope vfilter.Scope, type_map *vfilter.TypeM
 type CreateHuntDownloadArgs struct {
 	HuntId       string `vfilter:"required,field=hunt_id,doc=Hunt ID to export."`
 	OnlyCombined bool   `vfilter:"optional,field=only_combined,doc=If set we only export combined results."`
-	Wait         bool   `vfilter:"optional,field=wait,doc=If set we wait for the download to complete before returning."`
+	Wait         bool   `vfilter:"optional,field=wait,doc=If set we wait for the download to complete before returning. Cancelling the query then cancels the download."`
 	Format       string `vfilter:"optional,field=format,doc=Format to export (csv,json) defaults to both."`
 	Filename     string `vfilter:"optional,field=base,doc=Base filename to write to."`
+	Since        uint64 `vfilter:"optional,field=since,doc=Only export flows completed after this time (seconds since epoch)."`
+	SinceLast    bool   `vfilter:"optional,field=since_last_download,doc=Only export flows completed since the last download was prepared."`
+	Compression  string `vfilter:"optional,field=compression,doc=Compression for the zip members: deflate (default), store or xz."`
+	PublicKey    string `vfilter:"optional,field=public_key,doc=Encrypt the download to these ASCII armored PGP public keys. Only the matching private keys can decrypt it."`
 }
 
 type CreateHuntDownload struct{}
@@ -152,10 +176,35 @@ func (self *CreateHuntDownload) Call(ctx context.Context,
 		return vfilter.Null{}
 	}
 
+	method, err := flows.GetDownloadCompressionMethod(arg.Compression)
+	if err != nil {
+		scope.Log("create_hunt_download: %s", err)
+		return vfilter.Null{}
+	}
+
+	recipients, err := getDownloadRecipients(arg.PublicKey)
+	if err != nil {
+		scope.Log("create_hunt_download: %s", err)
+		return vfilter.Null{}
+	}
+
+	// A zero time means to export all the flows.
+	since := time.Time{}
+	if arg.Since > 0 {
+		since = time.Unix(int64(arg.Since), 0)
+
+	} else if arg.SinceLast {
+		since, err = lastHuntDownloadTime(config_obj, arg.HuntId)
+		if err != nil {
+			scope.Log("create_hunt_download: %s", err)
+			return vfilter.Null{}
+		}
+	}
+
 	result, err := createHuntDownloadFile(
 		ctx, config_obj, scope, arg.HuntId,
 		write_json, write_csv,
-		arg.Wait, arg.OnlyCombined, arg.Filename)
+		arg.Wait, arg.OnlyCombined, arg.Filename, since, method, recipients)
 	if err != nil {
 		scope.Log("create_hunt_download: %s", err)
 		return vfilter.Null{}
@@ -172,10 +221,152 @@ func (self CreateHuntDownload) Info(scope vfilter.Scope, type_map *vfilter.TypeM
 	}
 }
 
+// A zip writer which compresses all its members with the same
+// method. The members and the zip file itself are hashed as they
+// are written, and a manifest of the member hashes is added when the
+// writer is closed.
+type downloadZipWriter struct {
+	*zip.Writer
+	method uint16
+
+	bundle_hash hash.Hash
+	members     []*hashingWriter
+
+	// Closed after the zip file to finish encrypting it.
+	closer io.Closer
+
+	// Called as members are written with the number of members
+	// and their total size before compression.
+	on_progress func(members, bytes uint64)
+	written     uint64
+}
+
+func newDownloadZipWriter(fd io.Writer, method uint16) *downloadZipWriter {
+	bundle_hash := sha256.New()
+	zip_writer := zip.NewWriter(io.MultiWriter(fd, bundle_hash))
+	zip_writer.RegisterCompressor(95, func(out io.Writer) (io.WriteCloser, error) {
+		return &lazyXzWriter{out: out}, nil
+	})
+
+	return &downloadZipWriter{
+		Writer:      zip_writer,
+		method:      method,
+		bundle_hash: bundle_hash,
+	}
+}
+
+// The xz writer writes its stream header as soon as it is created
+// but the zip writer creates the compressor before it writes the
+// member's header, so we only create the xz writer when it is first
+// used.
+type lazyXzWriter struct {
+	out    io.Writer
+	writer *xz.Writer
+}
+
+func (self *lazyXzWriter) getWriter() (*xz.Writer, error) {
+	if self.writer == nil {
+		writer, err := xz.NewWriter(self.out)
+		if err != nil {
+			return nil, err
+		}
+		self.writer = writer
+	}
+	return self.writer, nil
+}
+
+func (self *lazyXzWriter) Write(buf []byte) (int, error) {
+	writer, err := self.getWriter()
+	if err != nil {
+		return 0, err
+	}
+	return writer.Write(buf)
+}
+
+func (self *lazyXzWriter) Close() error {
+	writer, err := self.getWriter()
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+func (self *downloadZipWriter) Create(name string) (io.Writer, error) {
+	f, err := self.CreateHeader(&zip.FileHeader{
+		Name:   name,
+		Method: self.method,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	member := &hashingWriter{
+		Writer:   f,
+		name:     name,
+		hash:     sha256.New(),
+		on_write: self.addProgress,
+	}
+	self.members = append(self.members, member)
+	self.addProgress(0)
+	return member, nil
+}
+
+func (self *downloadZipWriter) addProgress(n int) {
+	self.written += uint64(n)
+	if self.on_progress != nil {
+		self.on_progress(uint64(len(self.members)), self.written)
+	}
+}
+
+func (self *downloadZipWriter) Close() error {
+	err := self.writeManifest()
+	if err != nil {
+		return err
+	}
+
+	err = self.Writer.Close()
+	if err != nil {
+		return err
+	}
+
+	if self.closer != nil {
+		return self.closer.Close()
+	}
+	return nil
+}
+
+// Downloads are encrypted when the user gives public keys.
+func getDownloadRecipients(public_key string) (openpgp.EntityList, error) {
+	if public_key == "" {
+		return nil, nil
+	}
+	return flows.ParseDownloadPublicKeys(public_key)
+}
+
+// Open the zip writer for a download, encrypting it to the
+// recipients if there are any. The bundle hash is then the hash of
+// the zip file inside the encrypted message.
+func openDownloadZipWriter(fd io.Writer, download_file string,
+	method uint16, recipients openpgp.EntityList) (*downloadZipWriter, error) {
+	if len(recipients) == 0 {
+		return newDownloadZipWriter(fd, method), nil
+	}
+
+	encrypted, err := flows.EncryptDownload(fd, download_file, recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	zip_writer := newDownloadZipWriter(encrypted, method)
+	zip_writer.closer = encrypted
+	return zip_writer, nil
+}
+
 func createDownloadFile(
 	config_obj *config_proto.Config,
 	flow_id, client_id string,
-	wait bool) (string, error) {
+	wait bool, method uint16,
+	recipients openpgp.EntityList) (string, error) {
 	if client_id == "" || flow_id == "" {
 		return "", errors.New("Client Id and Flow Id should be specified.")
 	}
@@ -183,6 +374,9 @@ func createDownloadFile(
 	hostname := services.GetHostname(client_id)
 	flow_path_manager := paths.NewFlowPathManager(client_id, flow_id)
 	download_file := flow_path_manager.GetDownloadsFile(hostname).Path()
+	if len(recipients) > 0 {
+		download_file += flows.PGP_DOWNLOAD_EXT
+	}
 
 	logger := logging.GetLogger(config_obj, &logging.GUIComponent)
 	logger.WithFields(logrus.Fields{
@@ -192,33 +386,49 @@ func createDownloadFile(
 	}).Error("CreateDownload")
 
 	file_store_factory := file_store.GetFileStore(config_obj)
-	fd, err := file_store_factory.WriteFile(download_file)
+
+	// Do not truncate a download which is still being written.
+	preparation, err := startDownloadPreparation(
+		file_store_factory, download_file, time.Second*600)
 	if err != nil {
 		return "", err
 	}
 
-	err = fd.Truncate()
+	fd, err := file_store_factory.WriteFile(download_file)
 	if err != nil {
+		_ = preparation.Done()
 		return "", err
 	}
 
-	lock_file, err := file_store_factory.WriteFile(download_file + ".lock")
+	err = fd.Truncate()
 	if err != nil {
+		fd.Close()
+		_ = preparation.Done()
 		return "", err
 	}
-	lock_file.Close()
 
 	flow_details, err := flows.GetFlowDetails(config_obj, client_id, flow_id)
 	if err != nil {
+		fd.Close()
+		_ = preparation.Done()
 		return "", err
 	}
 
 	// Do these first to ensure errors are returned if the zip file
 	// is not writable.
-	zip_writer := zip.NewWriter(fd)
+	zip_writer, err := openDownloadZipWriter(
+		fd, download_file, method, recipients)
+	if err != nil {
+		fd.Close()
+		_ = preparation.Done()
+		return "", err
+	}
+	zip_writer.on_progress = preparation.Update
+
 	f, err := zip_writer.Create("FlowDetails")
 	if err != nil {
 		fd.Close()
+		_ = preparation.Done()
 		return "", err
 	}
 
@@ -227,6 +437,7 @@ func createDownloadFile(
 	if err != nil {
 		zip_writer.Close()
 		fd.Close()
+		_ = preparation.Done()
 		return "", err
 	}
 
@@ -236,9 +447,15 @@ func createDownloadFile(
 	// Write the bulk of the data asyncronously.
 	go func() {
 		defer wg.Done()
-		defer func() { _ = file_store_factory.Delete(download_file + ".lock") }()
+		defer func() { _ = preparation.Done() }()
 		defer fd.Close()
-		defer zip_writer.Close()
+		defer func() {
+			err := closeAndWriteBundleHash(
+				file_store_factory, download_file, zip_writer)
+			if err != nil {
+				logger.Error("CreateDownload %v: %v", download_file, err)
+			}
+		}()
 
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*600)
 		defer cancel()
@@ -261,7 +478,7 @@ func downloadFlowToZip(
 	client_id string,
 	hostname string,
 	flow_id string,
-	zip_writer *zip.Writer) error {
+	zip_writer *downloadZipWriter) error {
 
 	flow_details, err := flows.GetFlowDetails(config_obj, client_id, flow_id)
 	if err != nil {
@@ -372,14 +589,28 @@ func createHuntDownloadFile(
 	hunt_id string,
 	write_json, write_csv bool,
 	wait, only_combined bool,
-	base_filename string) (string, error) {
+	base_filename string,
+	since time.Time, method uint16,
+	recipients openpgp.EntityList) (string, error) {
 	if hunt_id == "" {
 		return "", errors.New("Hunt Id should be specified.")
 	}
 
+	// Delta downloads only contain the flows completed in the
+	// time range.
+	is_delta := !since.IsZero()
+	end := time.Now()
+
 	hunt_path_manager := paths.NewHuntPathManager(hunt_id)
 	download_file := hunt_path_manager.GetHuntDownloadsFile(
 		only_combined, base_filename)
+	if is_delta {
+		download_file = hunt_path_manager.GetHuntDeltaDownloadsFile(
+			since, end, base_filename)
+	}
+	if len(recipients) > 0 {
+		download_file += flows.PGP_DOWNLOAD_EXT
+	}
 
 	logger := logging.GetLogger(config_obj, &logging.GUIComponent)
 	logger.WithFields(logrus.Fields{
@@ -389,20 +620,23 @@ func createHuntDownloadFile(
 
 	file_store_factory := file_store.GetFileStore(config_obj)
 
-	lock_file, err := file_store_factory.WriteFile(download_file + ".lock")
+	// Refuse to start preparing the same download twice.
+	preparation, err := startDownloadPreparation(
+		file_store_factory, download_file, time.Hour)
 	if err != nil {
 		return "", err
 	}
-	lock_file.Close()
 
 	fd, err := file_store_factory.WriteFile(download_file)
 	if err != nil {
+		_ = preparation.Done()
 		return "", err
 	}
 
 	err = fd.Truncate()
 	if err != nil {
 		fd.Close()
+		_ = preparation.Done()
 		return "", err
 	}
 
@@ -410,16 +644,26 @@ func createHuntDownloadFile(
 		&api_proto.GetHuntRequest{HuntId: hunt_id})
 	if err != nil {
 		fd.Close()
+		_ = preparation.Done()
 		return "", err
 	}
 
 	// Do these first to ensure errors are returned if the zip file
 	// is not writable.
-	zip_writer := zip.NewWriter(fd)
+	zip_writer, err := openDownloadZipWriter(
+		fd, download_file, method, recipients)
+	if err != nil {
+		fd.Close()
+		_ = preparation.Done()
+		return "", err
+	}
+	zip_writer.on_progress = preparation.Update
+
 	f, err := zip_writer.Create("HuntDetails")
 	if err != nil {
 		zip_writer.Close()
 		fd.Close()
+		_ = preparation.Done()
 		return "", err
 	}
 
@@ -429,32 +673,79 @@ func createHuntDownloadFile(
 	if err != nil {
 		zip_writer.Close()
 		fd.Close()
+		_ = preparation.Done()
 		return "", err
 	}
 
 	wg := sync.WaitGroup{}
 	wg.Add(1)
 
+	// When the caller waits for the download, cancelling the
+	// caller's query also cancels the preparation. Otherwise the
+	// caller returns straight away so only the timeout applies.
+	parent_ctx := context.Background()
+	if wait {
+		parent_ctx = ctx
+	}
+
 	// Write the bulk of the data asyncronously.
 	go func() {
 		defer wg.Done()
 		defer func() {
-			err := file_store_factory.Delete(download_file + ".lock")
+			err := preparation.Done()
 			if err != nil {
 				logger.Error("Failed to bind to remove lock file for %v: %v",
 					download_file, err)
 			}
 
 		}()
-		defer fd.Close()
-		defer zip_writer.Close()
 
 		// Allow one hour to write the zip
-		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		ctx, cancel := context.WithTimeout(parent_ctx, time.Hour)
 		defer cancel()
 
+		// A cancelled preparation leaves an incomplete zip which
+		// must not be offered for download.
+		defer func() {
+			if ctx.Err() != nil {
+				zip_writer.Close()
+				fd.Close()
+
+				logger.Error("CreateHuntDownload %v: preparation cancelled: %v",
+					download_file, ctx.Err())
+				err := preparation.Abort()
+				if err != nil {
+					logger.Error("CreateHuntDownload %v: %v", download_file, err)
+				}
+				return
+			}
+
+			err := closeAndWriteBundleHash(
+				file_store_factory, download_file, zip_writer)
+			if err != nil {
+				logger.Error("CreateHuntDownload %v: %v", download_file, err)
+			}
+			fd.Close()
+		}()
+
+		// Work out which flows belong in the delta bundle.
+		var delta_flows *ordereddict.Dict
+		if is_delta {
+			var err error
+			delta_flows, err = getHuntDeltaFlows(
+				ctx, config_obj, scope, hunt_id, since, end)
+			if err != nil {
+				logger.Error("DownloadHuntResults: %v", err)
+				return
+			}
+		}
+
 		// Export aggregate CSV and JSON files for all clients.
 		for _, artifact_source := range hunt_details.ArtifactSources {
+			if ctx.Err() != nil {
+				return
+			}
+
 			artifact, source := paths.SplitFullSourceName(
 				artifact_source)
 
@@ -477,6 +768,12 @@ func createHuntDownloadFile(
 				"hunt_id=HuntId, artifact=Artifact, " +
 				"source=Source)"
 
+			if is_delta {
+				subscope.AppendVars(ordereddict.NewDict().
+					Set("DeltaFlows", delta_flows))
+				query += " WHERE get(item=DeltaFlows, field=FlowId)"
+			}
+
 			// Write all results to a tmpfile then just
 			// copy the tmpfile into the zip.
 			json_tmpfile, err := ioutil.TempFile("", "tmp*.json")
@@ -553,6 +850,13 @@ func createHuntDownloadFile(
 			Set("HuntId", hunt_id))
 		defer subscope.Close()
 
+		// Find the flows to export first so the progress can show
+		// how many there are.
+		type huntFlow struct {
+			client_id, flow_id string
+		}
+		hunt_flows := []huntFlow{}
+
 		vql, _ := vfilter.Parse(
 			"SELECT Flow.session_id AS FlowId, ClientId " +
 				"FROM hunt_flows(hunt_id=HuntId)")
@@ -563,9 +867,30 @@ func createHuntDownloadFile(
 				continue
 			}
 
+			if is_delta {
+				_, pres := delta_flows.Get(flow_id)
+				if !pres {
+					continue
+				}
+			}
+
+			hunt_flows = append(hunt_flows, huntFlow{
+				client_id: client_id, flow_id: flow_id})
+		}
+
+		preparation.SetTotalClients(uint64(len(hunt_flows)))
+
+		for _, hunt_flow := range hunt_flows {
+			if ctx.Err() != nil {
+				return
+			}
+
+			client_id := hunt_flow.client_id
 			hostname := services.GetHostname(client_id)
 			err := downloadFlowToZip(
-				ctx, config_obj, client_id, hostname, flow_id, zip_writer)
+				ctx, config_obj, client_id, hostname, hunt_flow.flow_id,
+				zip_writer)
+			preparation.ClientDone()
 			if err != nil {
 				logging.GetLogger(config_obj, &logging.FrontendComponent).
 					WithFields(logrus.Fields{
@@ -580,11 +905,92 @@ func createHuntDownloadFile(
 
 	if wait {
 		wg.Wait()
+
+		// The partial download was removed.
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
 	}
 
 	return download_file, nil
 }
 
+// Find the flows in the hunt which completed in the time range. The
+// flow ids are returned as the keys of the dict.
+func getHuntDeltaFlows(
+	ctx context.Context,
+	config_obj *config_proto.Config,
+	scope vfilter.Scope,
+	hunt_id string, since, end time.Time) (*ordereddict.Dict, error) {
+	result := ordereddict.NewDict()
+
+	subscope := scope.Copy()
+	subscope.AppendVars(ordereddict.NewDict().
+		Set("HuntId", hunt_id))
+	defer subscope.Close()
+
+	vql, err := vfilter.Parse(
+		"SELECT Flow.session_id AS FlowId, ClientId " +
+			"FROM hunt_flows(hunt_id=HuntId)")
+	if err != nil {
+		return nil, err
+	}
+
+	since_usec := flows.HuntTimeFromTime(since)
+	end_usec := flows.HuntTimeFromTime(end)
+
+	for row := range vql.Eval(ctx, subscope) {
+		flow_id := vql_subsystem.GetStringFromRow(scope, row, "FlowId")
+		client_id := vql_subsystem.GetStringFromRow(scope, row, "ClientId")
+		if flow_id == "" || client_id == "" {
+			continue
+		}
+
+		collection_context, err := flows.LoadCollectionContext(
+			config_obj, client_id, flow_id)
+		if err != nil ||
+			collection_context.State == flows_proto.ArtifactCollectorContext_RUNNING {
+			continue
+		}
+
+		if collection_context.ActiveTime > since_usec &&
+			collection_context.ActiveTime <= end_usec {
+			result.Set(flow_id, true)
+		}
+	}
+
+	return result, nil
+}
+
+// Find when the most recent download of the hunt was prepared. If
+// there are no downloads yet we use the hunt's creation time.
+func lastHuntDownloadTime(
+	config_obj *config_proto.Config, hunt_id string) (time.Time, error) {
+	hunt_obj, err := flows.GetHunt(config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	result := flows.HuntTimeToTime(hunt_obj.CreateTime)
+	if hunt_obj.Stats.AvailableDownloads == nil {
+		return result, nil
+	}
+
+	for _, item := range hunt_obj.Stats.AvailableDownloads.Files {
+		if !item.Complete {
+			continue
+		}
+
+		date, err := time.Parse(time.RFC3339, item.Date)
+		if err == nil && date.After(result) {
+			result = date
+		}
+	}
+
+	return result, nil
+}
+
 func StoreVQLAsCSVAndJsonFile(
 	ctx context.Context,
 	config_obj *config_proto.Config,
@@ -0,0 +1,277 @@
+// +build server_vql
+
+package downloads
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulikunitz/xz"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+	"www.velocidex.com/golang/velociraptor/file_store/memory"
+	"www.velocidex.com/golang/velociraptor/flows"
+)
+
+func TestDownloadZipCompression(t *testing.T) {
+	data := bytes.Repeat([]byte("hello world "), 1000)
+
+	for _, compression := range []string{"deflate", "store", "xz"} {
+		method, err := flows.GetDownloadCompressionMethod(compression)
+		assert.NoError(t, err)
+
+		buf := &bytes.Buffer{}
+		zip_writer := newDownloadZipWriter(buf, method)
+		for _, name := range []string{"HuntDetails", "results.json"} {
+			fd, err := zip_writer.Create(name)
+			assert.NoError(t, err)
+			_, err = fd.Write(data)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, zip_writer.Close())
+
+		zip_reader, err := zip.NewReader(
+			bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.NoError(t, err, compression)
+
+		zip_reader.RegisterDecompressor(95, func(in io.Reader) io.ReadCloser {
+			reader, err := xz.NewReader(in)
+			assert.NoError(t, err)
+			return ioutil.NopCloser(reader)
+		})
+
+		// The members and the manifest.
+		assert.Equal(t, 3, len(zip_reader.File))
+		for _, member := range zip_reader.File {
+			assert.Equal(t, method, member.Method, compression)
+			if member.Name == MANIFEST_MEMBER {
+				continue
+			}
+
+			fd, err := member.Open()
+			assert.NoError(t, err, compression)
+
+			member_data, err := ioutil.ReadAll(fd)
+			assert.NoError(t, err, compression)
+			assert.Equal(t, data, member_data, compression)
+		}
+	}
+}
+
+func TestDownloadZipManifest(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zip_writer := newDownloadZipWriter(buf, zip.Deflate)
+
+	members := map[string][]byte{
+		"HuntDetails":  []byte("{}"),
+		"results.json": bytes.Repeat([]byte("hello world "), 1000),
+	}
+	for _, name := range []string{"HuntDetails", "results.json"} {
+		fd, err := zip_writer.Create(name)
+		assert.NoError(t, err)
+
+		// Write in several chunks to check the hash is streamed.
+		data := members[name]
+		for len(data) > 0 {
+			n := 100
+			if n > len(data) {
+				n = len(data)
+			}
+			_, err = fd.Write(data[:n])
+			assert.NoError(t, err)
+			data = data[n:]
+		}
+	}
+	assert.NoError(t, zip_writer.Close())
+
+	// The bundle hash covers the whole zip file.
+	bundle_hash := sha256.Sum256(buf.Bytes())
+	assert.Equal(t, hex.EncodeToString(bundle_hash[:]), zip_writer.BundleHash())
+
+	zip_reader, err := zip.NewReader(
+		bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	var manifest *zip.File
+	for _, member := range zip_reader.File {
+		if member.Name == MANIFEST_MEMBER {
+			manifest = member
+		}
+	}
+	assert.NotNil(t, manifest)
+
+	fd, err := manifest.Open()
+	assert.NoError(t, err)
+
+	manifest_data, err := ioutil.ReadAll(fd)
+	assert.NoError(t, err)
+
+	parsed := struct {
+		Algorithm string
+		Files     []struct {
+			Name   string
+			Size   uint64
+			SHA256 string
+		}
+	}{}
+	assert.NoError(t, json.Unmarshal(manifest_data, &parsed))
+	assert.Equal(t, "sha256", parsed.Algorithm)
+	assert.Equal(t, 2, len(parsed.Files))
+
+	for _, file := range parsed.Files {
+		hash := sha256.Sum256(members[file.Name])
+		assert.Equal(t, hex.EncodeToString(hash[:]), file.SHA256, file.Name)
+		assert.Equal(t, uint64(len(members[file.Name])), file.Size, file.Name)
+	}
+}
+
+func TestDownloadPreparation(t *testing.T) {
+	file_store_factory := &memory.MemoryFileStore{
+		Data: make(map[string][]byte),
+	}
+	download_file := "/downloads/H.1234/H.1234.zip"
+
+	preparation, err := startDownloadPreparation(
+		file_store_factory, download_file, time.Hour)
+	assert.NoError(t, err)
+
+	// The download can not be prepared again while it is being
+	// prepared.
+	_, err = startDownloadPreparation(
+		file_store_factory, download_file, time.Hour)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already being prepared")
+
+	// Progress is recorded in the lock file.
+	preparation.progress.Updated = 0
+	preparation.Update(3, 100)
+
+	progress, err := flows.GetDownloadProgress(file_store_factory, download_file)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), progress.Members)
+	assert.Equal(t, uint64(100), progress.Bytes)
+
+	// Once done it may be prepared again.
+	assert.NoError(t, preparation.Done())
+	_, err = flows.GetDownloadProgress(file_store_factory, download_file)
+	assert.Error(t, err)
+
+	preparation, err = startDownloadPreparation(
+		file_store_factory, download_file, time.Hour)
+	assert.NoError(t, err)
+
+	// Stale preparations do not block new ones.
+	preparation.progress.Started = time.Now().Add(-2 * time.Hour).Unix()
+	assert.NoError(t, preparation.writeLockFile())
+
+	_, err = startDownloadPreparation(
+		file_store_factory, download_file, time.Hour)
+	assert.NoError(t, err)
+}
+
+func TestDownloadPreparationCancelled(t *testing.T) {
+	file_store_factory := &memory.MemoryFileStore{
+		Data: make(map[string][]byte),
+	}
+	download_file := "/downloads/H.1234/H.1234.zip"
+
+	preparation, err := startDownloadPreparation(
+		file_store_factory, download_file, time.Hour)
+	assert.NoError(t, err)
+
+	file_store_factory.Data[download_file] = []byte("partial zip")
+
+	// The total is recorded as soon as it is known, the clients
+	// exported so far as they complete.
+	preparation.SetTotalClients(3)
+
+	progress, err := flows.GetDownloadProgress(file_store_factory, download_file)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), progress.TotalClients)
+	assert.Equal(t, uint64(0), progress.Clients)
+
+	preparation.progress.Updated = 0
+	preparation.ClientDone()
+	preparation.progress.Updated = 0
+	preparation.ClientDone()
+
+	progress, err = flows.GetDownloadProgress(file_store_factory, download_file)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), progress.Clients)
+
+	// Cancelling removes the partial download as well as the lock.
+	assert.NoError(t, preparation.Abort())
+	assert.NoError(t, preparation.Done())
+
+	_, pres := file_store_factory.Data[download_file]
+	assert.False(t, pres)
+
+	_, err = flows.GetDownloadProgress(file_store_factory, download_file)
+	assert.Error(t, err)
+}
+
+func TestDownloadZipEncryption(t *testing.T) {
+	entity, err := openpgp.NewEntity("Analyst", "", "analyst@example.com",
+		&packet.Config{RSABits: 1024})
+	assert.NoError(t, err)
+
+	armored := &bytes.Buffer{}
+	armor_writer, err := armor.Encode(armored, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.Serialize(armor_writer))
+	assert.NoError(t, armor_writer.Close())
+
+	recipients, err := getDownloadRecipients(armored.String())
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	zip_writer, err := openDownloadZipWriter(
+		buf, "H.1234.zip.gpg", zip.Deflate, recipients)
+	assert.NoError(t, err)
+
+	fd, err := zip_writer.Create("HuntDetails")
+	assert.NoError(t, err)
+	_, err = fd.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.NoError(t, zip_writer.Close())
+
+	// The download is not a zip file until it is decrypted with
+	// the private key.
+	_, err = zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Error(t, err)
+
+	message, err := openpgp.ReadMessage(buf, openpgp.EntityList{entity}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "H.1234.zip", message.LiteralData.FileName)
+
+	decrypted, err := ioutil.ReadAll(message.UnverifiedBody)
+	assert.NoError(t, err)
+
+	zip_reader, err := zip.NewReader(
+		bytes.NewReader(decrypted), int64(len(decrypted)))
+	assert.NoError(t, err)
+	assert.Equal(t, "HuntDetails", zip_reader.File[0].Name)
+
+	// The bundle hash is the hash of the decrypted zip.
+	hash := sha256.Sum256(decrypted)
+	assert.Equal(t, hex.EncodeToString(hash[:]), zip_writer.BundleHash())
+
+	// Private keys are refused.
+	armored.Reset()
+	armor_writer, err = armor.Encode(armored, openpgp.PrivateKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.SerializePrivate(armor_writer, nil))
+	assert.NoError(t, armor_writer.Close())
+
+	_, err = getDownloadRecipients(armored.String())
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package downloads
+
+import (
+	"archive/zip"
+	"encoding/hex"
+	"hash"
+	"io"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+// Downloads carry a Manifest member listing the SHA-256 hash of
+// every other member. The hash of the whole zip file, which covers
+// the manifest too, is stored next to the download in a file with
+// this extension so it can be verified independently.
+const (
+	MANIFEST_MEMBER = "Manifest"
+	BUNDLE_HASH_EXT = ".sha256"
+)
+
+// Hashes a zip member as it is written so the data is only read
+// once.
+type hashingWriter struct {
+	io.Writer
+	name string
+	hash hash.Hash
+	size uint64
+
+	on_write func(n int)
+}
+
+func (self *hashingWriter) Write(buf []byte) (int, error) {
+	n, err := self.Writer.Write(buf)
+	_, _ = self.hash.Write(buf[:n])
+	self.size += uint64(n)
+	if self.on_write != nil {
+		self.on_write(n)
+	}
+	return n, err
+}
+
+func (self *downloadZipWriter) writeManifest() error {
+	files := []*ordereddict.Dict{}
+	for _, member := range self.members {
+		files = append(files, ordereddict.NewDict().
+			Set("Name", member.name).
+			Set("Size", member.size).
+			Set("SHA256", hex.EncodeToString(member.hash.Sum(nil))))
+	}
+
+	manifest, err := json.MarshalIndent(ordereddict.NewDict().
+		Set("Algorithm", "sha256").
+		Set("Files", files))
+	if err != nil {
+		return err
+	}
+
+	// The manifest does not list itself.
+	f, err := self.Writer.CreateHeader(&zip.FileHeader{
+		Name:   MANIFEST_MEMBER,
+		Method: self.method,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(manifest)
+	return err
+}
+
+// The hex encoded SHA-256 hash of the entire zip file. Only complete
+// once the writer is closed.
+func (self *downloadZipWriter) BundleHash() string {
+	return hex.EncodeToString(self.bundle_hash.Sum(nil))
+}
+
+// Close the zip writer and record the bundle hash next to the
+// download file.
+func closeAndWriteBundleHash(
+	file_store_factory api.FileStore,
+	download_file string,
+	zip_writer *downloadZipWriter) error {
+	err := zip_writer.Close()
+	if err != nil {
+		return err
+	}
+
+	fd, err := file_store_factory.WriteFile(download_file + BUNDLE_HASH_EXT)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	err = fd.Truncate()
+	if err != nil {
+		return err
+	}
+
+	_, err = fd.Write([]byte(zip_writer.BundleHash()))
+	return err
+}
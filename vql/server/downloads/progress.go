@@ -0,0 +1,152 @@
+package downloads
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"www.velocidex.com/golang/velociraptor/file_store/api"
+	"www.velocidex.com/golang/velociraptor/flows"
+	"www.velocidex.com/golang/velociraptor/json"
+)
+
+// Downloads are prepared in the background. While a download is
+// being prepared its lock file records when preparation started and
+// how far it got so the GUI can show its progress, and a second
+// request for the same download is refused rather than starting
+// another expensive zip job.
+var (
+	preparation_mu sync.Mutex
+
+	// How often the lock file is rewritten with new progress.
+	progress_update_interval = time.Second
+)
+
+type downloadPreparation struct {
+	mu sync.Mutex
+
+	file_store_factory api.FileStore
+	download_file      string
+	lock_file          string
+	progress           flows.DownloadProgress
+}
+
+// Start preparing the download file. Fails if the download is
+// already being prepared. Lock files older than max_age are left
+// over from preparations which never finished (e.g. the server
+// restarted) so they do not block a new one.
+func startDownloadPreparation(
+	file_store_factory api.FileStore,
+	download_file string,
+	max_age time.Duration) (*downloadPreparation, error) {
+	preparation_mu.Lock()
+	defer preparation_mu.Unlock()
+
+	now := time.Now()
+	existing, err := flows.GetDownloadProgress(file_store_factory, download_file)
+	if err == nil && existing.Started > 0 &&
+		now.Sub(time.Unix(existing.Started, 0)) < max_age {
+		return nil, errors.Errorf(
+			"Download %v is already being prepared (started %v)",
+			download_file, time.Unix(existing.Started, 0).UTC().Format(time.RFC3339))
+	}
+
+	// The download's cached info will be stale once it is
+	// prepared again. There is usually no info to forget.
+	_ = flows.ForgetDownloadInfo(file_store_factory, download_file)
+
+	self := &downloadPreparation{
+		file_store_factory: file_store_factory,
+		download_file:      download_file,
+		lock_file:          download_file + ".lock",
+		progress: flows.DownloadProgress{
+			Started: now.Unix(),
+			Updated: now.Unix(),
+		},
+	}
+
+	return self, self.writeLockFile()
+}
+
+// Record the number of members and bytes written so far. The lock
+// file is only rewritten once per progress_update_interval.
+func (self *downloadPreparation) Update(members, bytes uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.progress.Members = members
+	self.progress.Bytes = bytes
+
+	self.maybeWriteLockFile()
+}
+
+// Record how many clients will be exported. The lock file is
+// rewritten straight away so the total is shown as soon as it is
+// known.
+func (self *downloadPreparation) SetTotalClients(total uint64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.progress.TotalClients = total
+	self.progress.Updated = time.Now().Unix()
+
+	_ = self.writeLockFile()
+}
+
+// Another client was exported.
+func (self *downloadPreparation) ClientDone() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.progress.Clients++
+
+	self.maybeWriteLockFile()
+}
+
+func (self *downloadPreparation) maybeWriteLockFile() {
+	now := time.Now()
+	if now.Sub(time.Unix(self.progress.Updated, 0)) < progress_update_interval {
+		return
+	}
+	self.progress.Updated = now.Unix()
+
+	_ = self.writeLockFile()
+}
+
+// The download is complete (or failed) so remove the lock file.
+func (self *downloadPreparation) Done() error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.file_store_factory.Delete(self.lock_file)
+}
+
+// The preparation was cancelled so remove the partial download
+// file. The lock file is removed by Done() as usual.
+func (self *downloadPreparation) Abort() error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.file_store_factory.Delete(self.download_file)
+}
+
+func (self *downloadPreparation) writeLockFile() error {
+	serialized, err := json.Marshal(&self.progress)
+	if err != nil {
+		return err
+	}
+
+	fd, err := self.file_store_factory.WriteFile(self.lock_file)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	err = fd.Truncate()
+	if err != nil {
+		return err
+	}
+
+	_, err = fd.Write(serialized)
+	return err
+}
@@ -0,0 +1,147 @@
+// +build server_vql
+
+package hunts
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/flows"
+	"www.velocidex.com/golang/velociraptor/json"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type CreateCampaignFunctionArgs struct {
+	Name        string `vfilter:"required,field=name,doc=The name of the campaign."`
+	Description string `vfilter:"optional,field=description,doc=A description of the campaign."`
+}
+
+type CreateCampaignFunction struct{}
+
+func (self *CreateCampaignFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+	if err != nil {
+		scope.Log("campaign: %s", err)
+		return vfilter.Null{}
+	}
+
+	arg := &CreateCampaignFunctionArgs{}
+	err = vfilter.ExtractArgs(scope, args, arg)
+	if err != nil {
+		scope.Log("campaign: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	campaign := &api_proto.Campaign{
+		Name:        arg.Name,
+		Description: arg.Description,
+		Creator:     vql_subsystem.GetPrincipal(scope),
+	}
+	campaign_id, err := flows.CreateCampaign(config_obj, campaign)
+	if err != nil {
+		scope.Log("campaign: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	return ordereddict.NewDict().
+		Set("CampaignId", campaign_id).
+		Set("Campaign", json.ConvertProtoToOrderedDict(campaign))
+}
+
+func (self CreateCampaignFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "campaign",
+		Doc:     "Create a campaign to group related hunts.",
+		ArgType: type_map.AddType(scope, &CreateCampaignFunctionArgs{}),
+	}
+}
+
+type CampaignsPluginArgs struct {
+	CampaignId string `vfilter:"optional,field=campaign_id,doc=If specified only return this campaign."`
+}
+
+type CampaignsPlugin struct{}
+
+func (self CampaignsPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("campaigns: %s", err)
+			return
+		}
+
+		arg := &CampaignsPluginArgs{}
+		err = vfilter.ExtractArgs(scope, args, arg)
+		if err != nil {
+			scope.Log("campaigns: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		campaigns := []*api_proto.Campaign{}
+		if arg.CampaignId != "" {
+			campaign, err := flows.GetCampaign(config_obj, arg.CampaignId)
+			if err != nil {
+				scope.Log("campaigns: %v", err)
+				return
+			}
+			campaigns = append(campaigns, campaign)
+
+		} else {
+			result, err := flows.ListCampaigns(config_obj)
+			if err != nil {
+				scope.Log("campaigns: %v", err)
+				return
+			}
+			campaigns = result.Items
+		}
+
+		for _, campaign := range campaigns {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- json.ConvertProtoToOrderedDict(campaign):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self CampaignsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name: "campaigns",
+		Doc: "Retrieve the list of campaigns with the combined stats of " +
+			"their hunts, most recent first.",
+		ArgType: type_map.AddType(scope, &CampaignsPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&CreateCampaignFunction{})
+	vql_subsystem.RegisterPlugin(&CampaignsPlugin{})
+}
@@ -0,0 +1,69 @@
+// +build server_vql
+
+package hunts
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/flows"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type CancelHuntFlowsFunctionArg struct {
+	HuntId string `vfilter:"required,field=hunt_id,doc=The hunt whose flows to cancel"`
+}
+
+type CancelHuntFlowsFunction struct{}
+
+func (self *CancelHuntFlowsFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	// Same permission required by the GUI to cancel flows.
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+	if err != nil {
+		scope.Log("cancel_hunt_flows: %s", err)
+		return vfilter.Null{}
+	}
+
+	arg := &CancelHuntFlowsFunctionArg{}
+	err = vfilter.ExtractArgs(scope, args, arg)
+	if err != nil {
+		scope.Log("cancel_hunt_flows: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	result, err := flows.CancelHuntFlows(ctx, config_obj, arg.HuntId,
+		vql_subsystem.GetPrincipal(scope))
+	if err != nil {
+		scope.Log("cancel_hunt_flows: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	return ordereddict.NewDict().
+		Set("HuntId", arg.HuntId).
+		Set("Cancelled", result.Cancelled).
+		Set("Offline", result.Offline)
+}
+
+func (self CancelHuntFlowsFunction) Info(scope vfilter.Scope,
+	type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "cancel_hunt_flows",
+		Doc:     "Cancel the flows of a hunt which are still running on clients.",
+		ArgType: type_map.AddType(scope, &CancelHuntFlowsFunctionArg{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&CancelHuntFlowsFunction{})
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/Velocidex/ordereddict"
 	"www.velocidex.com/golang/velociraptor/acls"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
 	"www.velocidex.com/golang/velociraptor/flows"
 	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
 	"www.velocidex.com/golang/velociraptor/services"
@@ -43,6 +44,20 @@ type ScheduleHuntFunctionArg struct {
 	OpsPerSecond float64     `vfilter:"optional,field=ops_per_sec,doc=Set query ops_per_sec value"`
 	MaxRows      uint64      `vfilter:"optional,field=max_rows,doc=Max number of rows to fetch"`
 	MaxBytes     uint64      `vfilter:"optional,field=max_bytes,doc=Max number of bytes to upload"`
+
+	CpuLimit           float64  `vfilter:"optional,field=cpu_limit,doc=Throttle the query on clients using more than this percentage of their total CPU"`
+	IopsLimit          float64  `vfilter:"optional,field=iops_limit,doc=Throttle the query on clients performing more than this many IO operations per second"`
+	IdempotencyKey     string   `vfilter:"optional,field=idempotency_key,doc=If a hunt was already created with this key return it rather than creating another."`
+	CampaignId         string   `vfilter:"optional,field=campaign_id,doc=Add the hunt to this campaign."`
+	Tags               []string `vfilter:"optional,field=tags,doc=Tags to find the hunt by, e.g. an incident number."`
+	DisableObfuscation bool     `vfilter:"optional,field=disable_obfuscation,doc=Send the VQL to clients without obfuscating it. Only use this in trusted environments."`
+	SnapshotClients    bool     `vfilter:"optional,field=snapshot_clients,doc=Record which clients matched the hunt when it started. This searches all clients so can be slow on large deployments."`
+	MaxRetries         uint64   `vfilter:"optional,field=max_retries,doc=Retry the collection this many times on clients where it fails."`
+	RetryBackoff       uint64   `vfilter:"optional,field=retry_backoff,doc=Seconds to wait before the first retry (doubled for each further retry)."`
+	AutoStopErrorRate  float64  `vfilter:"optional,field=auto_stop_error_rate,doc=Stop the hunt when more than this percentage of the completed clients failed."`
+	AutoStopMinClients uint64   `vfilter:"optional,field=auto_stop_min_clients,doc=Only check the error rate once this many clients completed the hunt."`
+	AutoStopMaxErrors  uint64   `vfilter:"optional,field=auto_stop_max_errors,doc=Stop the hunt once this many clients failed."`
+	ClientsPerMinute   uint64   `vfilter:"optional,field=clients_per_minute,doc=Schedule at most this many clients each minute, queueing the rest."`
 }
 
 type ScheduleHuntFunction struct{}
@@ -70,21 +85,46 @@ func (self *ScheduleHuntFunction) Call(ctx context.Context,
 		return vfilter.Null{}
 	}
 
-	manager, err := services.GetRepositoryManager()
+	hunt_id, hunt_request, err := createHunt(ctx, scope, config_obj, arg)
 	if err != nil {
-		scope.Log("Command can only run on the server")
+		scope.Log("hunt: %s", err.Error())
 		return vfilter.Null{}
 	}
+
+	return ordereddict.NewDict().
+		Set("HuntId", hunt_id).
+		Set("Request", hunt_request)
+}
+
+func (self ScheduleHuntFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "hunt",
+		Doc:     "Launch an artifact collection against a client.",
+		ArgType: type_map.AddType(scope, &ScheduleHuntFunctionArg{}),
+	}
+}
+
+// Create a running hunt from the hunt() arguments. Shared with
+// create_and_monitor_hunt().
+func createHunt(ctx context.Context,
+	scope vfilter.Scope,
+	config_obj *config_proto.Config,
+	arg *ScheduleHuntFunctionArg) (string, *api_proto.Hunt, error) {
+	manager, err := services.GetRepositoryManager()
+	if err != nil {
+		return "", nil, err
+	}
 	repository, err := manager.GetGlobalRepository(config_obj)
 	if err != nil {
-		scope.Log("Command can only run on the server")
-		return vfilter.Null{}
+		return "", nil, err
 	}
 
 	request := &flows_proto.ArtifactCollectorArgs{
 		Creator:        vql_subsystem.GetPrincipal(scope),
 		Artifacts:      arg.Artifacts,
 		OpsPerSecond:   float32(arg.OpsPerSecond),
+		CpuLimit:       float32(arg.CpuLimit),
+		IopsLimit:      float32(arg.IopsLimit),
 		Timeout:        arg.Timeout,
 		MaxRows:        arg.MaxRows,
 		MaxUploadBytes: arg.MaxBytes,
@@ -93,16 +133,36 @@ func (self *ScheduleHuntFunction) Call(ctx context.Context,
 	err = tools.AddSpecProtobuf(config_obj, repository, scope,
 		arg.Spec, request)
 	if err != nil {
-		scope.Log("Command can only run on the server")
-		return vfilter.Null{}
+		return "", nil, err
 	}
 
 	hunt_request := &api_proto.Hunt{
-		HuntDescription: arg.Description,
-		Creator:         vql_subsystem.GetPrincipal(scope),
-		StartRequest:    request,
-		Expires:         arg.Expires,
-		State:           api_proto.Hunt_RUNNING,
+		HuntDescription:    arg.Description,
+		Creator:            vql_subsystem.GetPrincipal(scope),
+		StartRequest:       request,
+		Expires:            arg.Expires,
+		State:              api_proto.Hunt_RUNNING,
+		IdempotencyKey:     arg.IdempotencyKey,
+		CampaignId:         arg.CampaignId,
+		Tags:               arg.Tags,
+		DisableObfuscation: arg.DisableObfuscation,
+		SnapshotClients:    arg.SnapshotClients,
+		ClientsPerMinute:   arg.ClientsPerMinute,
+	}
+
+	if arg.MaxRetries > 0 {
+		hunt_request.RetryPolicy = &api_proto.HuntRetryPolicy{
+			MaxRetries: arg.MaxRetries,
+			Backoff:    arg.RetryBackoff,
+		}
+	}
+
+	if arg.AutoStopErrorRate > 0 || arg.AutoStopMaxErrors > 0 {
+		hunt_request.AutoStopErrorRate = &api_proto.HuntAutoStopErrorRate{
+			MaxErrorPercent: arg.AutoStopErrorRate,
+			MinClients:      arg.AutoStopMinClients,
+			MaxErrors:       arg.AutoStopMaxErrors,
+		}
 	}
 
 	// Run the hunt in the ACL context of the caller.
@@ -110,21 +170,10 @@ func (self *ScheduleHuntFunction) Call(ctx context.Context,
 		config_obj, vql_subsystem.GetPrincipal(scope))
 	hunt_id, err := flows.CreateHunt(ctx, config_obj, acl_manager, hunt_request)
 	if err != nil {
-		scope.Log("hunt: %s", err.Error())
-		return vfilter.Null{}
+		return "", nil, err
 	}
 
-	return ordereddict.NewDict().
-		Set("HuntId", hunt_id).
-		Set("Request", hunt_request)
-}
-
-func (self ScheduleHuntFunction) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
-	return &vfilter.FunctionInfo{
-		Name:    "hunt",
-		Doc:     "Launch an artifact collection against a client.",
-		ArgType: type_map.AddType(scope, &ScheduleHuntFunctionArg{}),
-	}
+	return hunt_id, hunt_request, nil
 }
 
 type AddToHuntFunctionArg struct {
@@ -186,7 +235,62 @@ func (self AddToHuntFunction) Info(scope vfilter.Scope,
 	}
 }
 
+type TestHuntFunctionArg struct {
+	HuntId   string `vfilter:"required,field=hunt_id,doc=The hunt to test."`
+	ClientId string `vfilter:"required,field=client_id,doc=The client to run the hunt's request on."`
+}
+
+type TestHuntFunction struct{}
+
+func (self *TestHuntFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+	if err != nil {
+		scope.Log("hunt_test: %s", err)
+		return vfilter.Null{}
+	}
+
+	arg := &TestHuntFunctionArg{}
+	err = vfilter.ExtractArgs(scope, args, arg)
+	if err != nil {
+		scope.Log("hunt_test: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	acl_manager := vql_subsystem.NewServerACLManager(
+		config_obj, vql_subsystem.GetPrincipal(scope))
+	flow_id, err := flows.RunHuntOnClient(ctx, config_obj, acl_manager,
+		arg.HuntId, arg.ClientId)
+	if err != nil {
+		scope.Log("hunt_test: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	return ordereddict.NewDict().
+		Set("HuntId", arg.HuntId).
+		Set("ClientId", arg.ClientId).
+		Set("FlowId", flow_id)
+}
+
+func (self TestHuntFunction) Info(scope vfilter.Scope,
+	type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "hunt_test",
+		Doc:     "Run a hunt's compiled request on a single client without adding it to the hunt.",
+		ArgType: type_map.AddType(scope, &TestHuntFunctionArg{}),
+	}
+}
+
 func init() {
 	vql_subsystem.RegisterFunction(&ScheduleHuntFunction{})
 	vql_subsystem.RegisterFunction(&AddToHuntFunction{})
+	vql_subsystem.RegisterFunction(&TestHuntFunction{})
 }
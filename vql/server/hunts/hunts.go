@@ -23,13 +23,13 @@ package hunts
 
 import (
 	"context"
-	"path"
+	"math"
+	"strings"
 
 	"github.com/Velocidex/ordereddict"
 	"www.velocidex.com/golang/velociraptor/acls"
 	"www.velocidex.com/golang/velociraptor/api"
 	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
-	"www.velocidex.com/golang/velociraptor/constants"
 	"www.velocidex.com/golang/velociraptor/datastore"
 	"www.velocidex.com/golang/velociraptor/file_store"
 	"www.velocidex.com/golang/velociraptor/file_store/result_sets"
@@ -43,7 +43,10 @@ import (
 	"www.velocidex.com/golang/vfilter"
 )
 
-type HuntsPluginArgs struct{}
+type HuntsPluginArgs struct {
+	HuntId string `vfilter:"optional,field=hunt_id,doc=If specified only return this hunt."`
+	State  string `vfilter:"optional,field=state,doc=Only show hunts in this state (e.g. RUNNING, PAUSED, STOPPED, ARCHIVED)."`
+}
 
 type HuntsPlugin struct{}
 
@@ -74,45 +77,53 @@ func (self HuntsPlugin) Call(
 			return
 		}
 
-		db, err := datastore.GetDB(config_obj)
-		if err != nil {
-			scope.Log("Error: %v", err)
+		state := api_proto.Hunt_UNSET
+		if arg.State != "" {
+			state_value, pres := api_proto.Hunt_State_value[strings.ToUpper(arg.State)]
+			if !pres {
+				scope.Log("hunts: unknown hunt state %v", arg.State)
+				return
+			}
+			state = api_proto.Hunt_State(state_value)
+		}
+
+		// Just fetch the one hunt. An unknown hunt simply
+		// produces no rows.
+		if arg.HuntId != "" {
+			hunt_obj, err := flows.GetHunt(config_obj,
+				&api_proto.GetHuntRequest{HuntId: arg.HuntId})
+			if err != nil ||
+				(state != api_proto.Hunt_UNSET && hunt_obj.State != state) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+			case output_chan <- hunt_obj:
+			}
 			return
 		}
 
-		hunt_path_manager := paths.NewHuntPathManager("")
-		hunts, err := db.ListChildren(config_obj,
-			hunt_path_manager.HuntDirectory().Path(), 0, 100)
+		// Take one snapshot of the hunts so hunts created while
+		// we iterate do not shift them around.
+		result, err := flows.ListHunts(config_obj,
+			&api_proto.ListHuntsRequest{
+				Count:           math.MaxUint64,
+				IncludeArchived: true,
+				State:           state,
+			})
 		if err != nil {
-			scope.Log("Error: %v", err)
+			scope.Log("hunts: %v", err)
 			return
 		}
 
-		for _, hunt_urn := range hunts {
-			hunt_id := path.Base(hunt_urn)
-			if !constants.HuntIdRegex.MatchString(hunt_id) {
-				continue
-			}
-
-			hunt_obj := &api_proto.Hunt{}
-			err = db.GetSubject(config_obj, hunt_urn, hunt_obj)
+		for _, item := range result.Items {
+			hunt_obj, err := flows.GetHunt(config_obj,
+				&api_proto.GetHuntRequest{HuntId: item.HuntId})
 			if err != nil {
 				continue
 			}
 
-			// Re-read the stats into the hunt object.
-			hunt_path_manager := paths.NewHuntPathManager(hunt_obj.HuntId)
-			hunt_stats := &api_proto.HuntStats{}
-			err := db.GetSubject(config_obj,
-				hunt_path_manager.Stats().Path(), hunt_stats)
-			if err == nil {
-				hunt_obj.Stats = hunt_stats
-			}
-
-			if hunt_obj.HuntId != hunt_id {
-				continue
-			}
-
 			select {
 			case <-ctx.Done():
 				return
@@ -127,7 +138,7 @@ func (self HuntsPlugin) Call(
 func (self HuntsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
 	return &vfilter.PluginInfo{
 		Name:    "hunts",
-		Doc:     "Retrieve the list of hunts.",
+		Doc:     "Retrieve the list of hunts, most recent first.",
 		ArgType: type_map.AddType(scope, &HuntsPluginArgs{}),
 	}
 }
@@ -137,6 +148,7 @@ type HuntResultsPluginArgs struct {
 	Source   string `vfilter:"optional,field=source,doc=An optional source within the artifact."`
 	HuntId   string `vfilter:"required,field=hunt_id,doc=The hunt id to read."`
 	Brief    bool   `vfilter:"optional,field=brief,doc=If set we return less columns."`
+	Limit    int64  `vfilter:"optional,field=limit,doc=The maximum number of rows to return (default all)."`
 }
 
 type HuntResultsPlugin struct{}
@@ -169,19 +181,21 @@ func (self HuntResultsPlugin) Call(
 			return
 		}
 
+		db, err := datastore.GetDB(config_obj)
+		if err != nil {
+			scope.Log("hunt_results: %v", err)
+			return
+		}
+
+		// The hunt is only needed for its tags when the
+		// artifact is given.
+		hunt_obj := &api_proto.Hunt{}
+		err = db.GetSubject(config_obj,
+			paths.NewHuntPathManager(arg.HuntId).Path(), hunt_obj)
+
 		// If no artifact is specified, get the first one from
 		// the hunt.
 		if arg.Artifact == "" {
-			db, err := datastore.GetDB(config_obj)
-			if err != nil {
-				scope.Log("hunt_results: %v", err)
-				return
-			}
-
-			hunt_path_manager := paths.NewHuntPathManager(arg.HuntId)
-			hunt_obj := &api_proto.Hunt{}
-			err = db.GetSubject(config_obj,
-				hunt_path_manager.Path(), hunt_obj)
 			if err != nil {
 				scope.Log("hunt_results: %v", err)
 				return
@@ -193,9 +207,11 @@ func (self HuntResultsPlugin) Call(
 				return
 			}
 
+			artifact, source := paths.SplitFullSourceName(
+				hunt_obj.Artifacts[0])
+			arg.Artifact = artifact
 			if arg.Source == "" {
-				arg.Artifact, arg.Source = paths.SplitFullSourceName(
-					hunt_obj.Artifacts[0])
+				arg.Source = source
 			}
 
 			// If the source is not specified find the
@@ -221,18 +237,25 @@ func (self HuntResultsPlugin) Call(
 				}
 			}
 
-		} else if arg.Source != "" {
+		}
+
+		if arg.Source != "" {
 			arg.Artifact += "/" + arg.Source
 		}
 
-		// Backwards compatibility.
+		// Only read the flows of the clients that took part in
+		// the hunt.
 		hunt_path_manager := paths.NewHuntPathManager(arg.HuntId).Clients()
 		row_chan, err := file_store.GetTimeRange(ctx, config_obj,
 			hunt_path_manager, 0, 0)
 		if err != nil {
+			scope.Log("hunt_results: %v", err)
 			return
 		}
 
+		count := int64(0)
+		seen := make(map[string]bool)
+
 		// Read each file and emit it with some extra columns
 		// for context.
 		for row := range row_chan {
@@ -242,7 +265,10 @@ func (self HuntResultsPlugin) Call(
 				continue
 			}
 
-			if participation_row.Participate {
+			key := participation_row.ClientId + participation_row.FlowId
+			if participation_row.Participate && !seen[key] {
+				seen[key] = true
+
 				api_client, err := api.GetApiClient(
 					config_obj, nil, participation_row.ClientId, false)
 				if err != nil {
@@ -271,11 +297,22 @@ func (self HuntResultsPlugin) Call(
 					if api_client.OsInfo != nil {
 						row.Set("Fqdn", api_client.OsInfo.Fqdn)
 					}
+
+					// So exported results can be
+					// correlated with the hunt's tags.
+					if len(hunt_obj.Tags) > 0 {
+						row.Set("HuntTags", hunt_obj.Tags)
+					}
 					select {
 					case <-ctx.Done():
 						return
 					case output_chan <- row:
 					}
+
+					count++
+					if arg.Limit > 0 && count >= arg.Limit {
+						return
+					}
 				}
 			}
 		}
@@ -292,6 +329,76 @@ func (self HuntResultsPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMa
 	}
 }
 
+type HuntSummaryPluginArgs struct {
+	HuntId   string `vfilter:"required,field=hunt_id,doc=The hunt id to summarize."`
+	Artifact string `vfilter:"required,field=artifact,doc=The artifact source to summarize (e.g. Artifact/Source)."`
+	Column   string `vfilter:"required,field=column,doc=The column to count the values of."`
+	Top      int64  `vfilter:"optional,field=top,doc=Only return this many of the most common values (default 20)."`
+}
+
+type HuntSummaryPlugin struct{}
+
+func (self HuntSummaryPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		arg := &HuntSummaryPluginArgs{}
+		err := vfilter.ExtractArgs(scope, args, arg)
+		if err != nil {
+			scope.Log("hunt_summary: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		if arg.Top == 0 {
+			arg.Top = 20
+		}
+
+		acl_manager := vql_subsystem.NewServerACLManager(
+			config_obj, vql_subsystem.GetPrincipal(scope))
+		summary, err := flows.SummarizeHunt(ctx, config_obj, acl_manager,
+			arg.HuntId, arg.Artifact, arg.Column, int(arg.Top))
+		if err != nil {
+			scope.Log("hunt_summary: %v", err)
+			return
+		}
+
+		scope.Log("hunt_summary: %v rows, %v without %v, %v distinct values",
+			summary.TotalRows, summary.MissingRows, summary.Column,
+			summary.DistinctValues)
+
+		for _, item := range summary.Top {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- ordereddict.NewDict().
+				Set("Value", item.Value).
+				Set("Count", item.Count):
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self HuntSummaryPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "hunt_summary",
+		Doc:     "Count the most common values of a column across a hunt's results.",
+		ArgType: type_map.AddType(scope, &HuntSummaryPluginArgs{}),
+	}
+}
+
 type HuntFlowsPluginArgs struct {
 	HuntId   string `vfilter:"required,field=hunt_id,doc=The hunt id to inspect."`
 	StartRow int64  `vfilter:"optional,field=start_row,doc=The first row to show (used for paging)."`
@@ -388,4 +495,5 @@ func init() {
 	vql_subsystem.RegisterPlugin(&HuntsPlugin{})
 	vql_subsystem.RegisterPlugin(&HuntResultsPlugin{})
 	vql_subsystem.RegisterPlugin(&HuntFlowsPlugin{})
+	vql_subsystem.RegisterPlugin(&HuntSummaryPlugin{})
 }
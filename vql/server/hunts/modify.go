@@ -0,0 +1,118 @@
+// +build server_vql
+
+package hunts
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/flows"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type ModifyHuntFunctionArg struct {
+	HuntId         string   `vfilter:"required,field=hunt_id,doc=The hunt to modify"`
+	State          string   `vfilter:"optional,field=state,doc=New state of the hunt (RUNNING, STOPPED or ARCHIVED)"`
+	Description    string   `vfilter:"optional,field=description,doc=New description of the hunt"`
+	Expires        uint64   `vfilter:"optional,field=expires,doc=New expiry time in seconds since epoch"`
+	Force          bool     `vfilter:"optional,field=force,doc=Start the hunt even if its condition matches no clients"`
+	CancelFlows    bool     `vfilter:"optional,field=cancel_flows,doc=When stopping the hunt also cancel its flows still running on clients"`
+	Tags           []string `vfilter:"optional,field=tags,doc=Replace the hunt's tags with these"`
+	ClearTags      bool     `vfilter:"optional,field=clear_tags,doc=Remove all the hunt's tags"`
+	ExcludeClients []string `vfilter:"optional,field=exclude_clients,doc=Stop scheduling these clients and cancel their running flows, while the hunt keeps running on the others"`
+	IncludeClients []string `vfilter:"optional,field=include_clients,doc=Stop excluding these clients"`
+}
+
+type ModifyHuntFunction struct{}
+
+func (self *ModifyHuntFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	// Same permission required by the GUI to modify hunts.
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+	if err != nil {
+		scope.Log("modify_hunt: %s", err)
+		return vfilter.Null{}
+	}
+
+	arg := &ModifyHuntFunctionArg{}
+	err = vfilter.ExtractArgs(scope, args, arg)
+	if err != nil {
+		scope.Log("modify_hunt: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	// All the requested changes are applied at once so the hunt
+	// is only saved once.
+	modification := &api_proto.Hunt{
+		HuntId:          arg.HuntId,
+		HuntDescription: arg.Description,
+		Expires:         arg.Expires * 1000000,
+		Tags:            arg.Tags,
+		ClearTags:       arg.ClearTags,
+		ExcludedClients: arg.ExcludeClients,
+		IncludedClients: arg.IncludeClients,
+		Force:           arg.Force,
+		CancelFlows:     arg.CancelFlows,
+	}
+
+	if arg.State != "" {
+		state, pres := api_proto.Hunt_State_value[strings.ToUpper(arg.State)]
+		if !pres || state == int32(api_proto.Hunt_UNSET) {
+			scope.Log("modify_hunt: unknown hunt state %v", arg.State)
+			return vfilter.Null{}
+		}
+		modification.State = api_proto.Hunt_State(state)
+	}
+
+	// A modification without any changes would stop the hunt.
+	if arg.State != "" || arg.Description != "" || arg.Expires != 0 ||
+		len(arg.Tags) > 0 || arg.ClearTags ||
+		len(arg.ExcludeClients) > 0 || len(arg.IncludeClients) > 0 {
+		err = flows.ModifyHunt(ctx, config_obj, modification,
+			vql_subsystem.GetPrincipal(scope))
+		if err != nil {
+			scope.Log("modify_hunt: %s", err.Error())
+			return vfilter.Null{}
+		}
+	}
+
+	hunt_obj, err := flows.GetHunt(config_obj,
+		&api_proto.GetHuntRequest{HuntId: arg.HuntId})
+	if err != nil {
+		scope.Log("modify_hunt: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	return ordereddict.NewDict().
+		Set("HuntId", hunt_obj.HuntId).
+		Set("State", hunt_obj.State.String()).
+		Set("Expires", hunt_obj.Expires).
+		Set("Description", hunt_obj.HuntDescription).
+		Set("Tags", hunt_obj.Tags).
+		Set("ExcludedClients", hunt_obj.ExcludedClients)
+}
+
+func (self ModifyHuntFunction) Info(scope vfilter.Scope,
+	type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "modify_hunt",
+		Doc:     "Modify a hunt's state, description, expiry, tags or excluded clients.",
+		ArgType: type_map.AddType(scope, &ModifyHuntFunctionArg{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&ModifyHuntFunction{})
+}
@@ -0,0 +1,149 @@
+// +build server_vql
+
+package hunts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/flows"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/services/launcher"
+	"www.velocidex.com/golang/velociraptor/services/notifications"
+	"www.velocidex.com/golang/velociraptor/services/repository"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type ModifyHuntTestSuite struct {
+	suite.Suite
+	config_obj *config_proto.Config
+	sm         *services.Service
+	cancel     func()
+	hunt_id    string
+}
+
+func (self *ModifyHuntTestSuite) SetupTest() {
+	self.config_obj = config.GetDefaultConfig()
+	self.config_obj.Datastore.Implementation = "Test"
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*60)
+	self.cancel = cancel
+	self.sm = services.NewServiceManager(ctx, self.config_obj)
+
+	require.NoError(self.T(), self.sm.Start(journal.StartJournalService))
+	require.NoError(self.T(), self.sm.Start(notifications.StartNotificationService))
+	require.NoError(self.T(), self.sm.Start(launcher.StartLauncherService))
+	require.NoError(self.T(), self.sm.Start(hunt_dispatcher.StartHuntDispatcher))
+	require.NoError(self.T(), self.sm.Start(repository.StartRepositoryManager))
+
+	var err error
+	self.hunt_id, err = flows.CreateHunt(ctx, self.config_obj,
+		vql_subsystem.NullACLManager{}, &api_proto.Hunt{
+			HuntDescription: "Original",
+			State:           api_proto.Hunt_PAUSED,
+			StartRequest: &flows_proto.ArtifactCollectorArgs{
+				Artifacts: []string{"Generic.Client.Info"},
+			},
+		})
+	require.NoError(self.T(), err)
+}
+
+func (self *ModifyHuntTestSuite) TearDownTest() {
+	self.sm.Close()
+	self.cancel()
+	test_utils.GetMemoryFileStore(self.T(), self.config_obj).Clear()
+	test_utils.GetMemoryDataStore(self.T(), self.config_obj).Clear()
+}
+
+func (self *ModifyHuntTestSuite) call(args *ordereddict.Dict) vfilter.Any {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     self.config_obj,
+		ACLManager: vql_subsystem.NullACLManager{},
+		Logger:     logging.NewPlainLogger(self.config_obj, &logging.FrontendComponent),
+	})
+	defer scope.Close()
+
+	return (&ModifyHuntFunction{}).Call(context.Background(), scope,
+		args.Set("hunt_id", self.hunt_id))
+}
+
+func (self *ModifyHuntTestSuite) getHunt() *api_proto.Hunt {
+	hunt_obj, err := flows.GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: self.hunt_id})
+	require.NoError(self.T(), err)
+	return hunt_obj
+}
+
+func (self *ModifyHuntTestSuite) TestModifyHunt() {
+	assert.Equal(self.T(), api_proto.Hunt_PAUSED, self.getHunt().State)
+
+	// All the changes are applied together.
+	expires := uint64(time.Now().Add(time.Hour).Unix())
+	result, ok := self.call(ordereddict.NewDict().
+		Set("description", "Modified").
+		Set("expires", expires).
+		Set("tags", []string{"Foo"}).
+		Set("state", "running")).(*ordereddict.Dict)
+	require.True(self.T(), ok)
+
+	state, _ := result.GetString("State")
+	assert.Equal(self.T(), "RUNNING", state)
+
+	hunt_obj := self.getHunt()
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, hunt_obj.State)
+	assert.Equal(self.T(), "Modified", hunt_obj.HuntDescription)
+	assert.Equal(self.T(), expires*1000000, hunt_obj.Expires)
+	assert.Equal(self.T(), []string{"Foo"}, hunt_obj.Tags)
+
+	// A call without changes leaves the hunt alone.
+	_, ok = self.call(ordereddict.NewDict()).(*ordereddict.Dict)
+	require.True(self.T(), ok)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, self.getHunt().State)
+
+	// If any change is invalid none of them are applied.
+	_, ok = self.call(ordereddict.NewDict().
+		Set("description", "Not applied").
+		Set("expires", uint64(time.Now().Add(-time.Hour).Unix())).
+		Set("state", "stopped")).(vfilter.Null)
+	assert.True(self.T(), ok)
+
+	hunt_obj = self.getHunt()
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, hunt_obj.State)
+	assert.Equal(self.T(), "Modified", hunt_obj.HuntDescription)
+
+	// Unknown states are rejected.
+	_, ok = self.call(ordereddict.NewDict().
+		Set("state", "bogus")).(vfilter.Null)
+	assert.True(self.T(), ok)
+
+	// Clearing the tags and stopping the hunt.
+	_, ok = self.call(ordereddict.NewDict().
+		Set("clear_tags", true).
+		Set("state", "stopped")).(*ordereddict.Dict)
+	require.True(self.T(), ok)
+
+	hunt_obj = self.getHunt()
+	assert.Equal(self.T(), api_proto.Hunt_STOPPED, hunt_obj.State)
+	assert.Empty(self.T(), hunt_obj.Tags)
+}
+
+func TestModifyHunt(t *testing.T) {
+	suite.Run(t, &ModifyHuntTestSuite{})
+}
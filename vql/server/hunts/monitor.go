@@ -0,0 +1,271 @@
+// +build server_vql
+
+package hunts
+
+import (
+	"context"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/flows"
+	"www.velocidex.com/golang/velociraptor/services"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// How often the hunt is checked for having finished between
+// completions.
+var monitor_hunt_poll_interval = 5 * time.Second
+
+type CreateAndMonitorHuntPluginArgs struct {
+	Description     string      `vfilter:"required,field=description,doc=Description of the hunt"`
+	Artifacts       []string    `vfilter:"required,field=artifacts,doc=A list of artifacts to collect"`
+	Expires         uint64      `vfilter:"optional,field=expires,doc=Number of seconds since epoch for expiry"`
+	Spec            vfilter.Any `vfilter:"optional,field=spec,doc=Parameters to apply to the artifacts"`
+	Timeout         uint64      `vfilter:"optional,field=timeout,doc=Set query timeout (default 10 min)"`
+	OpsPerSecond    float64     `vfilter:"optional,field=ops_per_sec,doc=Set query ops_per_sec value"`
+	CpuLimit        float64     `vfilter:"optional,field=cpu_limit,doc=Throttle the query on clients using more than this percentage of their total CPU"`
+	IopsLimit       float64     `vfilter:"optional,field=iops_limit,doc=Throttle the query on clients performing more than this many IO operations per second"`
+	MaxRows         uint64      `vfilter:"optional,field=max_rows,doc=Max number of rows to fetch"`
+	MaxBytes        uint64      `vfilter:"optional,field=max_bytes,doc=Max number of bytes to upload"`
+	SnapshotClients bool        `vfilter:"optional,field=snapshot_clients,doc=Record which clients matched the hunt when it started, so the hunt finishes once they all completed."`
+	Wait            int64       `vfilter:"optional,field=wait,doc=Stop monitoring after this many seconds (default until the hunt finishes)."`
+	StopOnCancel    bool        `vfilter:"optional,field=stop_on_cancel,doc=Stop the hunt if the query is cancelled or the wait time elapses before the hunt finished."`
+}
+
+type CreateAndMonitorHuntPlugin struct{}
+
+func (self CreateAndMonitorHuntPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope,
+			acls.COLLECT_CLIENT, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("create_and_monitor_hunt: %s", err)
+			return
+		}
+
+		arg := &CreateAndMonitorHuntPluginArgs{}
+		err = vfilter.ExtractArgs(scope, args, arg)
+		if err != nil {
+			scope.Log("create_and_monitor_hunt: %v", err)
+			return
+		}
+
+		config_obj, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		journal, err := services.GetJournal()
+		if err != nil {
+			scope.Log("create_and_monitor_hunt: %v", err)
+			return
+		}
+
+		// Watch before creating the hunt so no completion is
+		// missed.
+		qm_chan, cancel := journal.Watch("System.Flow.Completion")
+		defer cancel()
+
+		hunt_id, hunt_request, err := createHunt(ctx, scope, config_obj,
+			&ScheduleHuntFunctionArg{
+				Description:     arg.Description,
+				Artifacts:       arg.Artifacts,
+				Expires:         arg.Expires,
+				Spec:            arg.Spec,
+				Timeout:         arg.Timeout,
+				OpsPerSecond:    arg.OpsPerSecond,
+				CpuLimit:        arg.CpuLimit,
+				IopsLimit:       arg.IopsLimit,
+				MaxRows:         arg.MaxRows,
+				MaxBytes:        arg.MaxBytes,
+				SnapshotClients: arg.SnapshotClients,
+			})
+		if err != nil {
+			scope.Log("create_and_monitor_hunt: %v", err)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case output_chan <- ordereddict.NewDict().
+			Set("Event", "Created").
+			Set("HuntId", hunt_id).
+			Set("Request", hunt_request):
+		}
+
+		sub_ctx := ctx
+		if arg.Wait > 0 {
+			var sub_cancel func()
+			sub_ctx, sub_cancel = context.WithTimeout(
+				ctx, time.Duration(arg.Wait)*time.Second)
+			defer sub_cancel()
+		}
+
+		reason := monitorHunt(ctx, sub_ctx, config_obj, hunt_id,
+			qm_chan, output_chan)
+		scope.Log("create_and_monitor_hunt: Stopped monitoring %v: %v",
+			hunt_id, reason)
+
+		if arg.StopOnCancel && (reason == "Cancelled" || reason == "Timeout") {
+			// The query may already be cancelled.
+			err = flows.ModifyHunt(context.Background(), config_obj,
+				&api_proto.Hunt{
+					HuntId: hunt_id,
+					State:  api_proto.Hunt_STOPPED,
+				}, vql_subsystem.GetPrincipal(scope))
+			if err != nil {
+				scope.Log("create_and_monitor_hunt: Stopping %v: %v",
+					hunt_id, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case output_chan <- ordereddict.NewDict().
+			Set("Event", "Finished").
+			Set("HuntId", hunt_id).
+			Set("Reason", reason):
+		}
+	}()
+
+	return output_chan
+}
+
+// Emit a row for each client completing the hunt until the hunt
+// finishes. Returns why monitoring stopped: Completed, Stopped,
+// Expired, Timeout or Cancelled.
+func monitorHunt(
+	ctx, sub_ctx context.Context,
+	config_obj *config_proto.Config,
+	hunt_id string,
+	qm_chan <-chan *ordereddict.Dict,
+	output_chan chan vfilter.Row) string {
+
+	// The snapshot and expiry are only in the full hunt, while
+	// polling only needs its state and stats.
+	hunt_obj, err := flows.GetHunt(config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	if err != nil {
+		return "Stopped"
+	}
+
+	// Only count each client once even if its flow completes
+	// more than once.
+	seen := make(map[string]bool)
+	stats := hunt_obj
+
+	for {
+		reason := huntFinishedReason(hunt_obj, stats, uint64(len(seen)))
+		if reason != "" {
+			return reason
+		}
+
+		select {
+		case <-sub_ctx.Done():
+			if ctx.Err() != nil {
+				return "Cancelled"
+			}
+			return "Timeout"
+
+		case <-time.After(monitor_hunt_poll_interval):
+
+		case row, ok := <-qm_chan:
+			if !ok {
+				return "Cancelled"
+			}
+
+			completion, client_id := huntCompletion(row, hunt_id)
+			if completion == nil || seen[client_id] {
+				continue
+			}
+			seen[client_id] = true
+
+			completion.Set("Event", "Completion").
+				Set("Completed", len(seen))
+
+			latest, err := flows.GetHunt(config_obj,
+				&api_proto.GetHuntRequest{HuntId: hunt_id, StatsOnly: true})
+			if err == nil {
+				stats = latest
+				completion.Set("Scheduled", stats.Stats.TotalClientsScheduled).
+					Set("EstimatedCompletion", stats.Stats.EstimatedCompletion)
+			}
+
+			select {
+			case <-ctx.Done():
+				return "Cancelled"
+			case output_chan <- completion:
+			}
+			continue
+		}
+
+		latest, err := flows.GetHunt(config_obj,
+			&api_proto.GetHuntRequest{HuntId: hunt_id, StatsOnly: true})
+		if err == nil {
+			stats = latest
+		}
+	}
+}
+
+// Why the hunt is finished, or "" if it is still running. stats is
+// the latest state and stats of the hunt. The hunt has a fixed set
+// of clients only when it took a client snapshot, otherwise it runs
+// until it is stopped or expires. completed is the number of
+// completions seen, which may be ahead of the stats.
+func huntFinishedReason(
+	hunt, stats *api_proto.Hunt, completed uint64) string {
+	switch stats.State {
+	case api_proto.Hunt_STOPPED, api_proto.Hunt_ARCHIVED:
+		return "Stopped"
+	}
+
+	if stats.Stats == nil {
+		return ""
+	}
+
+	if stats.Stats.Stopped {
+		return "Stopped"
+	}
+
+	if hunt.Expires > 0 && hunt.Expires < flows.HuntTimeNow() {
+		return "Expired"
+	}
+
+	stats_completed := stats.Stats.TotalClientsWithResults +
+		stats.Stats.TotalClientsWithErrors
+	if stats_completed > completed {
+		completed = stats_completed
+	}
+
+	if hunt.SnapshotClients && completed >= hunt.SnapshotClientCount {
+		return "Completed"
+	}
+
+	return ""
+}
+
+func (self CreateAndMonitorHuntPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name: "create_and_monitor_hunt",
+		Doc: "Create a running hunt and emit a row as each client completes " +
+			"until the hunt finishes.",
+		ArgType: type_map.AddType(scope, &CreateAndMonitorHuntPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&CreateAndMonitorHuntPlugin{})
+}
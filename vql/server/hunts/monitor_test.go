@@ -0,0 +1,197 @@
+// +build server_vql
+
+package hunts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	actions_proto "www.velocidex.com/golang/velociraptor/actions/proto"
+	api_proto "www.velocidex.com/golang/velociraptor/api/proto"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/constants"
+	"www.velocidex.com/golang/velociraptor/datastore"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/flows"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/paths"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/client_info"
+	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/services/labels"
+	"www.velocidex.com/golang/velociraptor/services/launcher"
+	"www.velocidex.com/golang/velociraptor/services/notifications"
+	"www.velocidex.com/golang/velociraptor/services/repository"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type MonitorHuntTestSuite struct {
+	suite.Suite
+	config_obj *config_proto.Config
+	sm         *services.Service
+	cancel     func()
+}
+
+func (self *MonitorHuntTestSuite) SetupTest() {
+	self.config_obj = config.GetDefaultConfig()
+	self.config_obj.Datastore.Implementation = "Test"
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*60)
+	self.cancel = cancel
+	self.sm = services.NewServiceManager(ctx, self.config_obj)
+
+	require.NoError(self.T(), self.sm.Start(journal.StartJournalService))
+	require.NoError(self.T(), self.sm.Start(notifications.StartNotificationService))
+	require.NoError(self.T(), self.sm.Start(launcher.StartLauncherService))
+	require.NoError(self.T(), self.sm.Start(hunt_dispatcher.StartHuntDispatcher))
+	require.NoError(self.T(), self.sm.Start(repository.StartRepositoryManager))
+	require.NoError(self.T(), self.sm.Start(labels.StartLabelService))
+	require.NoError(self.T(), self.sm.Start(client_info.StartClientInfoService))
+}
+
+func (self *MonitorHuntTestSuite) TearDownTest() {
+	self.sm.Close()
+	self.cancel()
+	test_utils.GetMemoryFileStore(self.T(), self.config_obj).Clear()
+	test_utils.GetMemoryDataStore(self.T(), self.config_obj).Clear()
+}
+
+func (self *MonitorHuntTestSuite) call(
+	ctx context.Context, args *ordereddict.Dict) <-chan vfilter.Row {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     self.config_obj,
+		ACLManager: vql_subsystem.NullACLManager{},
+		Logger:     logging.NewPlainLogger(self.config_obj, &logging.FrontendComponent),
+	})
+
+	output_chan := CreateAndMonitorHuntPlugin{}.Call(ctx, scope, args)
+
+	// Close the scope once the plugin is done.
+	result := make(chan vfilter.Row)
+	go func() {
+		defer close(result)
+		defer scope.Close()
+
+		for row := range output_chan {
+			result <- row
+		}
+	}()
+
+	return result
+}
+
+func (self *MonitorHuntTestSuite) next(output_chan <-chan vfilter.Row) *ordereddict.Dict {
+	select {
+	case row, ok := <-output_chan:
+		require.True(self.T(), ok, "create_and_monitor_hunt exited early")
+		return row.(*ordereddict.Dict)
+
+	case <-time.After(10 * time.Second):
+		self.T().Fatalf("Timed out waiting for create_and_monitor_hunt")
+	}
+	return nil
+}
+
+func (self *MonitorHuntTestSuite) TestCompletesWithSnapshot() {
+	db, err := datastore.GetDB(self.config_obj)
+	assert.NoError(self.T(), err)
+
+	err = db.SetSubject(self.config_obj,
+		paths.NewClientPathManager("C.1").Path(),
+		&actions_proto.ClientInfo{ClientId: "C.1"})
+	assert.NoError(self.T(), err)
+
+	err = db.SetIndex(self.config_obj, constants.CLIENT_INDEX_URN,
+		"C.1", []string{"all"})
+	assert.NoError(self.T(), err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	output_chan := self.call(ctx, ordereddict.NewDict().
+		Set("description", "Monitored").
+		Set("artifacts", []string{"Generic.Client.Info"}).
+		Set("snapshot_clients", true))
+
+	row := self.next(output_chan)
+	event, _ := row.GetString("Event")
+	assert.Equal(self.T(), "Created", event)
+	hunt_id, _ := row.GetString("HuntId")
+
+	journal, err := services.GetJournal()
+	assert.NoError(self.T(), err)
+
+	err = journal.PushRowsToArtifact(self.config_obj,
+		[]*ordereddict.Dict{ordereddict.NewDict().
+			Set("Timestamp", flows.HuntTimeNow()).
+			Set("Flow", &flows_proto.ArtifactCollectorContext{
+				ClientId:  "C.1",
+				SessionId: "F.1",
+				State:     flows_proto.ArtifactCollectorContext_FINISHED,
+				Request: &flows_proto.ArtifactCollectorArgs{
+					Creator: hunt_id,
+				},
+			}).
+			Set("FlowId", "F.1").
+			Set("ClientId", "C.1")},
+		"System.Flow.Completion", "C.1", "F.1")
+	assert.NoError(self.T(), err)
+
+	row = self.next(output_chan)
+	event, _ = row.GetString("Event")
+	assert.Equal(self.T(), "Completion", event)
+	client_id, _ := row.GetString("ClientId")
+	assert.Equal(self.T(), "C.1", client_id)
+
+	// The only client in the snapshot completed so the hunt is
+	// finished.
+	row = self.next(output_chan)
+	reason, _ := row.GetString("Reason")
+	assert.Equal(self.T(), "Completed", reason)
+
+	hunt_obj, err := flows.GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), api_proto.Hunt_RUNNING, hunt_obj.State)
+}
+
+func (self *MonitorHuntTestSuite) TestStopOnTimeout() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	output_chan := self.call(ctx, ordereddict.NewDict().
+		Set("description", "Monitored").
+		Set("artifacts", []string{"Generic.Client.Info"}).
+		Set("wait", 1).
+		Set("stop_on_cancel", true))
+
+	row := self.next(output_chan)
+	hunt_id, _ := row.GetString("HuntId")
+
+	// Without a snapshot the hunt only finishes when it is
+	// stopped, so the wait time elapses first.
+	row = self.next(output_chan)
+	reason, _ := row.GetString("Reason")
+	assert.Equal(self.T(), "Timeout", reason)
+
+	hunt_obj, err := flows.GetHunt(self.config_obj,
+		&api_proto.GetHuntRequest{HuntId: hunt_id})
+	assert.NoError(self.T(), err)
+	assert.Equal(self.T(), api_proto.Hunt_STOPPED, hunt_obj.State)
+}
+
+func TestMonitorHunt(t *testing.T) {
+	suite.Run(t, &MonitorHuntTestSuite{})
+}
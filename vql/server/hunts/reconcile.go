@@ -0,0 +1,81 @@
+// +build server_vql
+
+package hunts
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/hunt_dispatcher"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type ReconcileHuntStatsFunctionArg struct {
+	HuntId string `vfilter:"required,field=hunt_id,doc=The hunt to reconcile"`
+}
+
+type ReconcileHuntStatsFunction struct{}
+
+func (self *ReconcileHuntStatsFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+	if err != nil {
+		scope.Log("reconcile_hunt_stats: %s", err)
+		return vfilter.Null{}
+	}
+
+	arg := &ReconcileHuntStatsFunctionArg{}
+	err = vfilter.ExtractArgs(scope, args, arg)
+	if err != nil {
+		scope.Log("reconcile_hunt_stats: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	before, after, err := hunt_dispatcher.ReconcileHuntStats(
+		ctx, config_obj, arg.HuntId)
+	if err != nil {
+		scope.Log("reconcile_hunt_stats: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	return ordereddict.NewDict().
+		Set("HuntId", arg.HuntId).
+		Set("Before", before).
+		Set("After", after).
+		Set("Delta", ordereddict.NewDict().
+			Set("TotalClientsScheduled",
+				int64(after.TotalClientsScheduled)-
+					int64(before.TotalClientsScheduled)).
+			Set("TotalClientsWithResults",
+				int64(after.TotalClientsWithResults)-
+					int64(before.TotalClientsWithResults)).
+			Set("TotalClientsWithErrors",
+				int64(after.TotalClientsWithErrors)-
+					int64(before.TotalClientsWithErrors)).
+			Set("TotalClientsRetrying",
+				int64(after.TotalClientsRetrying)-
+					int64(before.TotalClientsRetrying)))
+}
+
+func (self ReconcileHuntStatsFunction) Info(scope vfilter.Scope,
+	type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "reconcile_hunt_stats",
+		Doc:     "Recompute a hunt's stats from its flows.",
+		ArgType: type_map.AddType(scope, &ReconcileHuntStatsFunctionArg{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&ReconcileHuntStatsFunction{})
+}
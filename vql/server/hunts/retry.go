@@ -0,0 +1,76 @@
+// +build server_vql
+
+package hunts
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	"www.velocidex.com/golang/velociraptor/services/hunt_manager"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type RetryFailedHuntClientsFunctionArg struct {
+	HuntId string `vfilter:"required,field=hunt_id,doc=The hunt whose failed clients to retry"`
+}
+
+type RetryFailedHuntClientsFunction struct{}
+
+func (self *RetryFailedHuntClientsFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	// Same permission required by the GUI to modify hunts.
+	err := vql_subsystem.CheckAccess(scope, acls.COLLECT_CLIENT)
+	if err != nil {
+		scope.Log("retry_failed_hunt_clients: %s", err)
+		return vfilter.Null{}
+	}
+
+	arg := &RetryFailedHuntClientsFunctionArg{}
+	err = vfilter.ExtractArgs(scope, args, arg)
+	if err != nil {
+		scope.Log("retry_failed_hunt_clients: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	config_obj, ok := vql_subsystem.GetServerConfig(scope)
+	if !ok {
+		scope.Log("Command can only run on the server")
+		return vfilter.Null{}
+	}
+
+	result, err := hunt_manager.RetryFailedClients(ctx, config_obj, arg.HuntId)
+	if err != nil {
+		scope.Log("retry_failed_hunt_clients: %s", err.Error())
+		return vfilter.Null{}
+	}
+
+	retried := []*ordereddict.Dict{}
+	for _, item := range result.Items {
+		retried = append(retried, ordereddict.NewDict().
+			Set("ClientId", item.ClientId).
+			Set("FailedFlowId", item.FailedFlowId).
+			Set("Error", item.Error).
+			Set("FlowId", item.FlowId))
+	}
+
+	return ordereddict.NewDict().
+		Set("HuntId", arg.HuntId).
+		Set("Retried", retried)
+}
+
+func (self RetryFailedHuntClientsFunction) Info(scope vfilter.Scope,
+	type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "retry_failed_hunt_clients",
+		Doc:     "Schedule a hunt again on the clients whose collection failed.",
+		ArgType: type_map.AddType(scope, &RetryFailedHuntClientsFunctionArg{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterFunction(&RetryFailedHuntClientsFunction{})
+}
@@ -0,0 +1,126 @@
+// +build server_vql
+
+package hunts
+
+import (
+	"context"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/json"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/utils"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type WatchHuntPluginArgs struct {
+	HuntId string `vfilter:"required,field=hunt_id,doc=The hunt id to watch."`
+}
+
+type WatchHuntPlugin struct{}
+
+func (self WatchHuntPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.READ_RESULTS)
+		if err != nil {
+			scope.Log("watch_hunt: %s", err)
+			return
+		}
+
+		arg := &WatchHuntPluginArgs{}
+		err = vfilter.ExtractArgs(scope, args, arg)
+		if err != nil {
+			scope.Log("watch_hunt: %v", err)
+			return
+		}
+
+		_, ok := vql_subsystem.GetServerConfig(scope)
+		if !ok {
+			scope.Log("Command can only run on the server")
+			return
+		}
+
+		journal, err := services.GetJournal()
+		if err != nil {
+			scope.Log("watch_hunt: %v", err)
+			return
+		}
+
+		qm_chan, cancel := journal.Watch("System.Flow.Completion")
+		defer cancel()
+
+		// Only emit each client once even if its flow completes
+		// more than once.
+		seen := make(map[string]bool)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case row, ok := <-qm_chan:
+				if !ok {
+					return
+				}
+
+				completion, client_id := huntCompletion(row, arg.HuntId)
+				if completion == nil || seen[client_id] {
+					continue
+				}
+				seen[client_id] = true
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- completion:
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+// Decode a System.Flow.Completion row into the row to emit if it
+// completes a flow of the hunt, or return nil. Flows scheduled by a
+// hunt carry the hunt id as their creator.
+func huntCompletion(
+	row *ordereddict.Dict, hunt_id string) (*ordereddict.Dict, string) {
+	flow := &flows_proto.ArtifactCollectorContext{}
+	flow_any, _ := row.Get("Flow")
+	err := utils.ParseIntoProtobuf(flow_any, flow)
+	if err != nil || flow.Request == nil || flow.Request.Creator != hunt_id {
+		return nil, ""
+	}
+
+	timestamp, _ := row.Get("Timestamp")
+	return ordereddict.NewDict().
+		Set("Timestamp", timestamp).
+		Set("HuntId", hunt_id).
+		Set("ClientId", flow.ClientId).
+		Set("FlowId", flow.SessionId).
+		Set("State", flow.State.String()).
+		Set("Flow", json.ConvertProtoToOrderedDict(flow)), flow.ClientId
+}
+
+func (self WatchHuntPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name: "watch_hunt",
+		Doc: "Watch a hunt for clients completing their collection. This " +
+			"is an event plugin which emits a row as each client completes.",
+		ArgType: type_map.AddType(scope, &WatchHuntPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&WatchHuntPlugin{})
+}
@@ -0,0 +1,150 @@
+// +build server_vql
+
+package hunts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"www.velocidex.com/golang/velociraptor/config"
+	config_proto "www.velocidex.com/golang/velociraptor/config/proto"
+	"www.velocidex.com/golang/velociraptor/file_store/test_utils"
+	"www.velocidex.com/golang/velociraptor/flows"
+	flows_proto "www.velocidex.com/golang/velociraptor/flows/proto"
+	"www.velocidex.com/golang/velociraptor/logging"
+	"www.velocidex.com/golang/velociraptor/services"
+	"www.velocidex.com/golang/velociraptor/services/journal"
+	"www.velocidex.com/golang/velociraptor/services/repository"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+)
+
+type WatchHuntTestSuite struct {
+	suite.Suite
+	config_obj *config_proto.Config
+	sm         *services.Service
+	cancel     func()
+}
+
+func (self *WatchHuntTestSuite) SetupTest() {
+	self.config_obj = config.GetDefaultConfig()
+	self.config_obj.Datastore.Implementation = "Test"
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*60)
+	self.cancel = cancel
+	self.sm = services.NewServiceManager(ctx, self.config_obj)
+
+	require.NoError(self.T(), self.sm.Start(journal.StartJournalService))
+	require.NoError(self.T(), self.sm.Start(repository.StartRepositoryManager))
+}
+
+func (self *WatchHuntTestSuite) TearDownTest() {
+	self.sm.Close()
+	self.cancel()
+	test_utils.GetMemoryFileStore(self.T(), self.config_obj).Clear()
+	test_utils.GetMemoryDataStore(self.T(), self.config_obj).Clear()
+}
+
+func (self *WatchHuntTestSuite) TestWatchHunt() {
+	manager, err := services.GetRepositoryManager()
+	assert.NoError(self.T(), err)
+
+	scope := manager.BuildScope(services.ScopeBuilder{
+		Config:     self.config_obj,
+		ACLManager: vql_subsystem.NullACLManager{},
+		Logger:     logging.NewPlainLogger(self.config_obj, &logging.FrontendComponent),
+	})
+	defer scope.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	output_chan := WatchHuntPlugin{}.Call(ctx, scope,
+		ordereddict.NewDict().Set("hunt_id", "H.1"))
+
+	journal, err := services.GetJournal()
+	assert.NoError(self.T(), err)
+
+	completion := func(hunt_id, client_id, flow_id string) *ordereddict.Dict {
+		return ordereddict.NewDict().
+			Set("Timestamp", flows.HuntTimeNow()).
+			Set("Flow", &flows_proto.ArtifactCollectorContext{
+				ClientId:  client_id,
+				SessionId: flow_id,
+				State:     flows_proto.ArtifactCollectorContext_FINISHED,
+				Request: &flows_proto.ArtifactCollectorArgs{
+					Creator: hunt_id,
+				},
+			}).
+			Set("FlowId", flow_id).
+			Set("ClientId", client_id)
+	}
+
+	// The plugin subscribes asynchronously so keep pushing the
+	// completions until it has seen them.
+	done := make(chan bool)
+	defer close(done)
+	go func() {
+		for {
+			for _, row := range []*ordereddict.Dict{
+				completion("H.2", "C.1", "F.2"),
+				completion("H.1", "C.1", "F.1"),
+				completion("H.1", "C.2", "F.3"),
+			} {
+				client_id, _ := row.GetString("ClientId")
+				flow_id, _ := row.GetString("FlowId")
+				_ = journal.PushRowsToArtifact(self.config_obj,
+					[]*ordereddict.Dict{row}, "System.Flow.Completion",
+					client_id, flow_id)
+			}
+
+			select {
+			case <-done:
+				return
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+	}()
+
+	clients := []string{}
+	for len(clients) < 2 {
+		select {
+		case row := <-output_chan:
+			dict := row.(*ordereddict.Dict)
+			hunt_id, _ := dict.GetString("HuntId")
+			assert.Equal(self.T(), "H.1", hunt_id)
+
+			client_id, _ := dict.GetString("ClientId")
+			clients = append(clients, client_id)
+
+		case <-time.After(10 * time.Second):
+			self.T().Fatalf("Timed out waiting for hunt completions")
+		}
+	}
+	assert.ElementsMatch(self.T(), []string{"C.1", "C.2"}, clients)
+
+	// Each client is only reported once, however many times its
+	// flow completes.
+	select {
+	case row := <-output_chan:
+		self.T().Fatalf("Unexpected row %v", row)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	// Cancelling the query stops the plugin.
+	cancel()
+	select {
+	case _, ok := <-output_chan:
+		assert.False(self.T(), ok)
+	case <-time.After(10 * time.Second):
+		self.T().Fatalf("watch_hunt did not exit on cancellation")
+	}
+}
+
+func TestWatchHunt(t *testing.T) {
+	suite.Run(t, &WatchHuntTestSuite{})
+}
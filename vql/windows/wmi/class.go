@@ -0,0 +1,240 @@
+package wmi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Velocidex/ordereddict"
+	ole "github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	wmi_parse "www.velocidex.com/golang/velociraptor/vql/windows/wmi/parse"
+	vfilter "www.velocidex.com/golang/vfilter"
+)
+
+const (
+	// Ask for the localized qualifiers (e.g. Description) too.
+	wbemFlagUseAmendedQualifiers = 0x20000
+
+	WBEM_E_NOT_FOUND     = 0x80041002
+	WBEM_E_INVALID_CLASS = 0x80041010
+)
+
+type WmiClassPluginArgs struct {
+	Namespace string `vfilter:"optional,field=namespace,doc=The WMI namespace to use (ROOT/CIMV2)"`
+	Class     string `vfilter:"required,field=class,doc=The name of the class to describe."`
+}
+
+type WmiClassPlugin struct{}
+
+func (self WmiClassPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("wmi_class: %s", err)
+			return
+		}
+
+		arg := &WmiClassPluginArgs{}
+		err = vfilter.ExtractArgs(scope, args, arg)
+		if err != nil {
+			scope.Log("wmi_class: %s", err.Error())
+			return
+		}
+
+		if arg.Namespace == "" {
+			arg.Namespace = "ROOT/CIMV2"
+		}
+
+		rows, err := GetClass(arg.Class, arg.Namespace)
+		if err != nil {
+			scope.Log("wmi_class: %v", err)
+			return
+		}
+
+		for _, row := range rows {
+			select {
+			case <-ctx.Done():
+				return
+			case output_chan <- row:
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self WmiClassPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "wmi_class",
+		Doc:     "Describe the properties of a WMI class.",
+		ArgType: type_map.AddType(scope, &WmiClassPluginArgs{}),
+	}
+}
+
+// Fetch the definition of a class and return a row for each of its
+// properties.
+func GetClass(class string, namespace string) ([]*ordereddict.Dict, error) {
+	result := []*ordereddict.Dict{}
+	err := withService(namespace, func(service *ole.IDispatch) error {
+		class_raw, err := oleutil.CallMethod(service, "Get",
+			class, wbemFlagUseAmendedQualifiers)
+		if err != nil {
+			if isClassNotFound(err) {
+				return fmt.Errorf("Class %v not found in namespace %v",
+					class, namespace)
+			}
+			return err
+		}
+
+		class_obj := class_raw.ToIDispatch()
+		defer class_obj.Release()
+
+		properties_raw, err := class_obj.GetProperty("Properties_")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = properties_raw.Clear()
+		}()
+
+		properties := properties_raw.ToIDispatch()
+		defer properties.Release()
+
+		return oleutil.ForEach(properties,
+			func(v *ole.VARIANT) error {
+				property := v.ToIDispatch()
+				defer property.Release()
+
+				row, err := describeProperty(property)
+				if err != nil {
+					return err
+				}
+				result = append(result, row.Set("Class", class))
+				return nil
+			})
+	})
+	return result, err
+}
+
+func describeProperty(property *ole.IDispatch) (*ordereddict.Dict, error) {
+	name, err := getValue(property, "Name")
+	if err != nil {
+		return nil, err
+	}
+
+	cim_type, err := getValue(property, "CIMType")
+	if err != nil {
+		return nil, err
+	}
+
+	cim_type_int, _ := cim_type.(int32)
+
+	is_array, err := getValue(property, "IsArray")
+	if err != nil {
+		return nil, err
+	}
+
+	origin, err := getValue(property, "Origin")
+	if err != nil {
+		return nil, err
+	}
+
+	qualifiers, err := getQualifiers(property)
+	if err != nil {
+		return nil, err
+	}
+
+	return ordereddict.NewDict().
+		Set("Name", name).
+		Set("Type", wmi_parse.CIMTypeName(int64(cim_type_int))).
+		Set("IsArray", is_array).
+		Set("Origin", origin).
+		Set("Qualifiers", qualifiers), nil
+}
+
+// Collect the qualifiers of a property into a dict of name to value.
+func getQualifiers(property *ole.IDispatch) (*ordereddict.Dict, error) {
+	result := ordereddict.NewDict()
+
+	qualifiers_raw, err := property.GetProperty("Qualifiers_")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = qualifiers_raw.Clear()
+	}()
+
+	qualifiers := qualifiers_raw.ToIDispatch()
+	defer qualifiers.Release()
+
+	err = oleutil.ForEach(qualifiers,
+		func(v *ole.VARIANT) error {
+			qualifier := v.ToIDispatch()
+			defer qualifier.Release()
+
+			name, err := getValue(qualifier, "Name")
+			if err != nil {
+				return err
+			}
+
+			name_str, ok := name.(string)
+			if !ok {
+				return nil
+			}
+
+			value, err := getValue(qualifier, "Value")
+			if err != nil {
+				return err
+			}
+			result.Set(name_str, value)
+			return nil
+		})
+	return result, err
+}
+
+func getValue(item *ole.IDispatch, name string) (interface{}, error) {
+	value_raw, err := item.GetProperty(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = value_raw.Clear()
+	}()
+
+	value, _ := variantValue(value_raw)
+	return value, nil
+}
+
+// WMI reports a missing class through the exception info of the
+// failed call.
+func isClassNotFound(err error) bool {
+	ole_err, ok := err.(*ole.OleError)
+	if !ok {
+		return false
+	}
+
+	code := uint32(ole_err.Code())
+	excep_info, ok := ole_err.SubError().(ole.EXCEPINFO)
+	if ok {
+		code = excep_info.SCODE()
+	}
+
+	switch code {
+	case WBEM_E_NOT_FOUND, WBEM_E_INVALID_CLASS:
+		return true
+	}
+	return false
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&WmiClassPlugin{})
+}
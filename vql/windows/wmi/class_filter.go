@@ -0,0 +1,40 @@
+package wmi
+
+import (
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+// Is the parsed event's class in the class filter (of upper cased
+// class names)? The class of the event's TargetInstance is checked,
+// or the class of the event itself for events without one (e.g.
+// extrinsic events). Events without a class are always emitted.
+func eventMatchesClassFilter(
+	parsed *ordereddict.Dict, class_filter map[string]bool) bool {
+	if len(class_filter) == 0 {
+		return true
+	}
+
+	target, pres := parsed.Get("TargetInstance")
+	if pres {
+		target_dict, ok := target.(*ordereddict.Dict)
+		if ok {
+			parsed = target_dict
+		}
+	}
+
+	class, pres := parsed.Get("__Type")
+	if !pres {
+		return true
+	}
+
+	switch t := class.(type) {
+	case string:
+		return class_filter[strings.ToUpper(t)]
+	case *string:
+		return t == nil || class_filter[strings.ToUpper(*t)]
+	}
+
+	return true
+}
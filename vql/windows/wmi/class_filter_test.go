@@ -0,0 +1,57 @@
+package wmi
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventMatchesClassFilter(t *testing.T) {
+	process := "Win32_Process"
+	class_filter := map[string]bool{"WIN32_PROCESS": true}
+
+	for _, test := range []struct {
+		name     string
+		parsed   *ordereddict.Dict
+		filter   map[string]bool
+		expected bool
+	}{
+		{"No filter",
+			ordereddict.NewDict().Set("__Type", "Win32_Service"),
+			nil, true},
+
+		// Intrinsic events are filtered on their TargetInstance.
+		{"Target instance matches",
+			ordereddict.NewDict().
+				Set("__Type", "__InstanceCreationEvent").
+				Set("TargetInstance", ordereddict.NewDict().
+					Set("__Type", "win32_process")),
+			class_filter, true},
+		{"Target instance does not match",
+			ordereddict.NewDict().
+				Set("__Type", "__InstanceCreationEvent").
+				Set("TargetInstance", ordereddict.NewDict().
+					Set("__Type", "Win32_Service")),
+			class_filter, false},
+
+		// Extrinsic events are filtered on their own class.
+		{"Event class matches",
+			ordereddict.NewDict().Set("__Type", &process),
+			class_filter, true},
+		{"Event class does not match",
+			ordereddict.NewDict().Set("__Type", "Win32_ProcessStartTrace"),
+			class_filter, false},
+
+		// Events without a class are emitted.
+		{"No class",
+			ordereddict.NewDict().Set("Name", "foo"),
+			class_filter, true},
+		{"Nil class",
+			ordereddict.NewDict().Set("__Type", (*string)(nil)),
+			class_filter, true},
+	} {
+		assert.Equal(t, test.expected,
+			eventMatchesClassFilter(test.parsed, test.filter), test.name)
+	}
+}
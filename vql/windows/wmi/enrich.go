@@ -0,0 +1,55 @@
+// +build windows
+
+package wmi
+
+import (
+	"fmt"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/shirou/gopsutil/process"
+	"golang.org/x/sys/windows"
+)
+
+// Resolving an account may need to talk to a domain controller so
+// this can be slow.
+func lookupAccount(sid_string string) string {
+	sid, err := windows.StringToSid(sid_string)
+	if err != nil {
+		return ""
+	}
+
+	account, domain, _, err := sid.LookupAccount("")
+	if err != nil || account == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s\\%s", domain, account)
+}
+
+// The process may already have exited, in which case the columns
+// are left empty.
+func lookupProcess(info *processEventInfo) *ordereddict.Dict {
+	result := newEnrichment()
+
+	proc, err := process.NewProcess(int32(info.Pid))
+	if err == nil {
+		exe, _ := proc.Exe()
+		cmdline, _ := proc.Cmdline()
+		result.Set("Exe", exe).Set("CommandLine", cmdline)
+
+		// Instance events do not carry the SID.
+		if info.Sid == "" {
+			username, _ := proc.Username()
+			result.Set("Username", username)
+		}
+	}
+
+	if info.ParentPid > 0 {
+		parent, err := process.NewProcess(int32(info.ParentPid))
+		if err == nil {
+			name, _ := parent.Name()
+			result.Set("ParentName", name)
+		}
+	}
+
+	return result
+}
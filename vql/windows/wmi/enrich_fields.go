@@ -0,0 +1,114 @@
+package wmi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+)
+
+// The fields of a process start event that can be enriched.
+type processEventInfo struct {
+	Pid       int64
+	ParentPid int64
+
+	// The string form of the SID the process runs as, if the
+	// event has one.
+	Sid string
+}
+
+// Recognize process start events: Win32_ProcessStartTrace events,
+// and instance events whose TargetInstance is a Win32_Process.
+func processEventFields(parsed *ordereddict.Dict) (*processEventInfo, bool) {
+	switch strings.ToUpper(wmiTypeName(parsed)) {
+	case "WIN32_PROCESSSTARTTRACE":
+		pid, ok := wmiInt(parsed, "ProcessID")
+		if !ok {
+			return nil, false
+		}
+		ppid, _ := wmiInt(parsed, "ParentProcessID")
+
+		result := &processEventInfo{Pid: pid, ParentPid: ppid}
+		sid_any, _ := parsed.Get("Sid")
+		sid_bytes, ok := sid_any.([]interface{})
+		if ok {
+			result.Sid, _ = sidToString(sid_bytes)
+		}
+		return result, true
+
+	case "__INSTANCECREATIONEVENT", "__INSTANCEMODIFICATIONEVENT",
+		"__INSTANCEDELETIONEVENT":
+		target_any, _ := parsed.Get("TargetInstance")
+		target, ok := target_any.(*ordereddict.Dict)
+		if !ok || strings.ToUpper(wmiTypeName(target)) != "WIN32_PROCESS" {
+			return nil, false
+		}
+
+		pid, ok := wmiInt(target, "ProcessId")
+		if !ok {
+			return nil, false
+		}
+		ppid, _ := wmiInt(target, "ParentProcessId")
+		return &processEventInfo{Pid: pid, ParentPid: ppid}, true
+	}
+
+	return nil, false
+}
+
+func wmiTypeName(parsed *ordereddict.Dict) string {
+	value, _ := parsed.Get("__Type")
+	switch t := value.(type) {
+	case string:
+		return t
+	case *string:
+		if t != nil {
+			return *t
+		}
+	}
+	return ""
+}
+
+// MOF encodes 64 bit integers as strings.
+func wmiInt(parsed *ordereddict.Dict, field string) (int64, bool) {
+	value, _ := parsed.Get(field)
+	switch t := value.(type) {
+	case int64:
+		return t, true
+	case string:
+		result, err := strconv.ParseInt(t, 10, 64)
+		return result, err == nil
+	}
+	return 0, false
+}
+
+// Format a binary SID given as an array of bytes, e.g. S-1-5-18.
+func sidToString(sid []interface{}) (string, bool) {
+	data := make([]byte, 0, len(sid))
+	for _, item := range sid {
+		value, ok := item.(int64)
+		if !ok || value < 0 || value > 255 {
+			return "", false
+		}
+		data = append(data, byte(value))
+	}
+
+	// Revision, sub authority count and a 48 bit big endian
+	// authority followed by the little endian sub authorities.
+	if len(data) < 8 || len(data) != 8+4*int(data[1]) {
+		return "", false
+	}
+
+	authority := uint64(0)
+	for _, b := range data[2:8] {
+		authority = authority<<8 | uint64(b)
+	}
+
+	result := fmt.Sprintf("S-%d-%d", data[0], authority)
+	for i := 8; i < len(data); i += 4 {
+		result += fmt.Sprintf("-%d", binary.LittleEndian.Uint32(data[i:]))
+	}
+
+	return result, true
+}
@@ -0,0 +1,226 @@
+package wmi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// We never wait longer than this after reading an event for its
+// enrichment.
+const default_enrich_timeout = time.Second
+
+// Abandoned lookups keep running in the background. While this many
+// are still running new events are not enriched, so a wedged domain
+// controller can not pile up goroutines.
+const max_enrich_in_flight = 10
+
+// Events which can carry the enriched columns.
+type enrichableEvent interface {
+	Parse() (*ordereddict.Dict, error)
+	SetEnrichment(enrichment *ordereddict.Dict)
+}
+
+// Adds the user name and process details to process start events.
+// Enrichment is best effort - the columns are left empty if the
+// lookups fail (e.g. the process already exited) or take too long.
+type eventEnricher struct {
+	mu        sync.Mutex
+	usernames map[string]string
+	timeout   time.Duration
+
+	// Closed when the SID's lookup completes, so events for the
+	// same SID share a single lookup.
+	pending map[string]chan bool
+
+	// Each running enrichment holds a slot.
+	slots chan bool
+
+	lookup_account func(sid string) string
+	lookup_process func(info *processEventInfo) *ordereddict.Dict
+}
+
+func newEventEnricher(
+	lookup_account func(sid string) string,
+	lookup_process func(info *processEventInfo) *ordereddict.Dict) *eventEnricher {
+	return &eventEnricher{
+		usernames:      make(map[string]string),
+		timeout:        default_enrich_timeout,
+		pending:        make(map[string]chan bool),
+		slots:          make(chan bool, max_enrich_in_flight),
+		lookup_account: lookup_account,
+		lookup_process: lookup_process,
+	}
+}
+
+// An event read from the queue whose lookups may still be running.
+type pendingEnrichment struct {
+	item     vfilter.Row
+	deadline time.Time
+
+	// nil if the event is not enriched.
+	event  enrichableEvent
+	result chan *ordereddict.Dict
+}
+
+// Enriches the events read from input. The lookups run while the
+// queue keeps being read, so slow lookups never hold up the WMI
+// delivery. Events are emitted in order, each as soon as its
+// enrichment is ready or its deadline passes. At most buffer_size
+// events wait for their enrichment.
+func (self *eventEnricher) Pipeline(
+	ctx context.Context, input <-chan vfilter.Row,
+	buffer_size int64) <-chan vfilter.Row {
+	output := make(chan vfilter.Row)
+	pending := make(chan *pendingEnrichment, buffer_size)
+
+	go func() {
+		defer close(pending)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case item, ok := <-input:
+				if !ok {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case pending <- self.start(item):
+				}
+			}
+		}
+	}()
+
+	go func() {
+		defer close(output)
+
+		for next := range pending {
+			if next.event != nil {
+				next.event.SetEnrichment(next.wait(ctx))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case output <- next.item:
+			}
+		}
+	}()
+
+	return output
+}
+
+// Starts the lookups for the event in the background.
+func (self *eventEnricher) start(item vfilter.Row) *pendingEnrichment {
+	result := &pendingEnrichment{
+		item:     item,
+		deadline: time.Now().Add(self.timeout),
+	}
+
+	event, ok := item.(enrichableEvent)
+	if !ok {
+		return result
+	}
+
+	parsed, err := event.Parse()
+	if err != nil {
+		return result
+	}
+
+	info, ok := processEventFields(parsed)
+	if !ok {
+		return result
+	}
+
+	result.event = event
+	result.result = make(chan *ordereddict.Dict, 1)
+
+	select {
+	case self.slots <- true:
+	default:
+		result.result <- newEnrichment()
+		return result
+	}
+
+	go func() {
+		defer func() { <-self.slots }()
+		result.result <- self.enrich(info)
+	}()
+
+	return result
+}
+
+func (self *pendingEnrichment) wait(ctx context.Context) *ordereddict.Dict {
+	// Lookups which take too long are abandoned.
+	timer := time.NewTimer(time.Until(self.deadline))
+	defer timer.Stop()
+
+	select {
+	case enriched := <-self.result:
+		return enriched
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return newEnrichment()
+}
+
+func newEnrichment() *ordereddict.Dict {
+	return ordereddict.NewDict().
+		Set("Username", "").
+		Set("Exe", "").
+		Set("CommandLine", "").
+		Set("ParentName", "")
+}
+
+// The process is looked up first as it may exit at any time.
+func (self *eventEnricher) enrich(info *processEventInfo) *ordereddict.Dict {
+	result := self.lookup_process(info)
+	if info.Sid != "" {
+		result.Set("Username", self.lookupUsername(info.Sid))
+	}
+	return result
+}
+
+// Usernames are cached for the life of the query. Failed lookups
+// are cached too so we do not keep retrying them. Only one lookup
+// runs for each SID, the other callers wait for its result.
+func (self *eventEnricher) lookupUsername(sid_string string) string {
+	self.mu.Lock()
+	username, pres := self.usernames[sid_string]
+	if pres {
+		self.mu.Unlock()
+		return username
+	}
+
+	done, pres := self.pending[sid_string]
+	if pres {
+		self.mu.Unlock()
+		<-done
+
+		self.mu.Lock()
+		defer self.mu.Unlock()
+		return self.usernames[sid_string]
+	}
+
+	done = make(chan bool)
+	self.pending[sid_string] = done
+	self.mu.Unlock()
+
+	username = self.lookup_account(sid_string)
+
+	self.mu.Lock()
+	self.usernames[sid_string] = username
+	delete(self.pending, sid_string)
+	self.mu.Unlock()
+	close(done)
+
+	return username
+}
@@ -0,0 +1,113 @@
+package wmi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"www.velocidex.com/golang/vfilter"
+)
+
+type testEvent struct {
+	pid        int64
+	enrichment *ordereddict.Dict
+}
+
+func (self *testEvent) Parse() (*ordereddict.Dict, error) {
+	trace_type := "Win32_ProcessStartTrace"
+	return ordereddict.NewDict().
+		Set("__Type", &trace_type).
+		Set("ProcessID", self.pid).
+		// S-1-5-18
+		Set("Sid", []interface{}{int64(1), int64(1), int64(0), int64(0),
+			int64(0), int64(0), int64(0), int64(5),
+			int64(18), int64(0), int64(0), int64(0)}), nil
+}
+
+func (self *testEvent) SetEnrichment(enrichment *ordereddict.Dict) {
+	self.enrichment = enrichment
+}
+
+func testLookupProcess(info *processEventInfo) *ordereddict.Dict {
+	return newEnrichment().Set("Exe", fmt.Sprintf("%d.exe", info.Pid))
+}
+
+// Runs the events through the pipeline and returns them in the
+// order they were emitted.
+func runEnrichPipeline(t *testing.T, enricher *eventEnricher,
+	events []vfilter.Row) []vfilter.Row {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	input := make(chan vfilter.Row, len(events))
+	for _, event := range events {
+		input <- event
+	}
+	close(input)
+
+	result := []vfilter.Row{}
+	for item := range enricher.Pipeline(ctx, input, 100) {
+		result = append(result, item)
+	}
+	require.NoError(t, ctx.Err())
+
+	return result
+}
+
+func TestEnrichPipeline(t *testing.T) {
+	enricher := newEventEnricher(
+		func(sid string) string { return "NT AUTHORITY\\SYSTEM" },
+		testLookupProcess)
+
+	// Rows which can not be enriched pass through.
+	error_row := ordereddict.NewDict().Set("Error", "Failed")
+	events := []vfilter.Row{&testEvent{pid: 1}, error_row, &testEvent{pid: 2}}
+	result := runEnrichPipeline(t, enricher, events)
+	assert.Equal(t, events, result)
+
+	for _, item := range []vfilter.Row{result[0], result[2]} {
+		event := item.(*testEvent)
+		require.NotNil(t, event.enrichment)
+
+		username, _ := event.enrichment.GetString("Username")
+		assert.Equal(t, "NT AUTHORITY\\SYSTEM", username)
+
+		exe, _ := event.enrichment.GetString("Exe")
+		assert.Equal(t, fmt.Sprintf("%d.exe", event.pid), exe)
+	}
+}
+
+func TestEnrichPipelineSlowLookup(t *testing.T) {
+	// The account lookup never completes.
+	stalled := make(chan bool)
+	defer close(stalled)
+
+	enricher := newEventEnricher(
+		func(sid string) string {
+			<-stalled
+			return "Never"
+		}, testLookupProcess)
+	enricher.timeout = 200 * time.Millisecond
+
+	events := []vfilter.Row{}
+	for i := 0; i < 50; i++ {
+		events = append(events, &testEvent{pid: int64(i)})
+	}
+
+	// Waiting for each lookup in turn would take 10 seconds,
+	// instead the events all wait for their lookups together.
+	start := time.Now()
+	result := runEnrichPipeline(t, enricher, events)
+	assert.Less(t, int64(time.Since(start)), int64(2*time.Second))
+
+	// The events are emitted in order without the enrichment.
+	assert.Equal(t, events, result)
+	for _, item := range result {
+		username, _ := item.(*testEvent).enrichment.GetString("Username")
+		assert.Equal(t, "", username)
+	}
+}
@@ -0,0 +1,47 @@
+package wmi
+
+import (
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessEventFields(t *testing.T) {
+	// S-1-5-21-1-2-3-1001
+	sid := []interface{}{}
+	for _, b := range []int64{1, 5, 0, 0, 0, 0, 0, 5, 21, 0, 0, 0,
+		1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0, 0xe9, 3, 0, 0} {
+		sid = append(sid, b)
+	}
+
+	trace_type := "Win32_ProcessStartTrace"
+	info, ok := processEventFields(ordereddict.NewDict().
+		Set("__Type", &trace_type).
+		Set("ProcessID", int64(1234)).
+		Set("ParentProcessID", int64(4)).
+		Set("Sid", sid))
+	assert.True(t, ok)
+	assert.Equal(t, &processEventInfo{
+		Pid: 1234, ParentPid: 4, Sid: "S-1-5-21-1-2-3-1001"}, info)
+
+	info, ok = processEventFields(ordereddict.NewDict().
+		Set("__Type", "__InstanceCreationEvent").
+		Set("TargetInstance", ordereddict.NewDict().
+			Set("__Type", "Win32_Process").
+			Set("ProcessId", "1234").
+			Set("ParentProcessId", int64(4))))
+	assert.True(t, ok)
+	assert.Equal(t, &processEventInfo{Pid: 1234, ParentPid: 4}, info)
+
+	// Other classes are not enriched.
+	_, ok = processEventFields(ordereddict.NewDict().
+		Set("__Type", "__InstanceCreationEvent").
+		Set("TargetInstance", ordereddict.NewDict().
+			Set("__Type", "Win32_Service")))
+	assert.False(t, ok)
+
+	// A truncated SID is ignored.
+	_, ok = sidToString(sid[:10])
+	assert.False(t, ok)
+}
@@ -96,6 +96,13 @@ HRESULT SetStatus(
     /* [in] */ HRESULT hResult,
     /* [in] */ BSTR strParam,
     /* [in] */ IWbemClassObject __RPC_FAR *pObjParam) {
+    EventSink *self = (EventSink *)This;
+
+    // Errors in the query may only be reported once the async call
+    // completes.
+    if (lFlags == WBEM_STATUS_COMPLETE && FAILED(hResult)) {
+        Error(self->ctx, "ExecNotificationQueryAsync", hResult);
+    }
     return WBEM_S_NO_ERROR;
 }
 
@@ -130,28 +137,29 @@ typedef struct  {
 // These functions are called from Go to create and destroy an event
 // watcher context.
 void *watchEvents(void *go_ctx, char *query, char* namespace);
-void destroyEvent(void *c_ctx);
+long destroyEvent(void *c_ctx);
 void log_error(void *go_ctx, char *message);
 
 // Allocate and initialize an event watcher context.  Returns the
-// context of NULL on error. The go_ctx is an opaque Go pointer which
-// will be passed into the go callback. NOTE: This must be allocated
-// using the pointer package's pointer.Save().
+// context of NULL on error. The reason for the error is always
+// reported through log_error() before returning NULL. The go_ctx is
+// an opaque Go pointer which will be passed into the go
+// callback. NOTE: This must be allocated using the pointer package's
+// pointer.Save(). COM must already be initialized on the calling
+// thread and destroyEvent() must be called on the same thread.
 void *watchEvents(void *go_ctx, char *query, char *namespace) {
     HRESULT hres;
     watcher_context *ctx = (watcher_context *)calloc(sizeof(watcher_context), 1);
 
     if (ctx == NULL) {
+        log_error(go_ctx, "Failed to allocate the watcher context.");
         return NULL;
     }
 
     // Step 1: --------------------------------------------------
-    // Initialize COM. ------------------------------------------
-    hres =  CoInitializeEx(0, COINIT_MULTITHREADED);
-    if (FAILED(hres)) {
-        log_error(go_ctx, "Failed to initialize COM library - CoInitializeEx.");
-        return NULL;
-    }
+    // COM is initialized for the multithreaded apartment by the
+    // caller on this thread. The caller uninitializes it after
+    // destroyEvent() so the C layer never initializes it twice.
 
     // Step 2: --------------------------------------------------
     // Set general COM security levels --------------------------
@@ -233,7 +241,7 @@ void *watchEvents(void *go_ctx, char *query, char *namespace) {
                             CLSCTX_LOCAL_SERVER, &IID_IUnsecuredApartment,
                             (void**)&ctx->apartment);
     if (FAILED(hres)) {
-        Error(go_ctx, "Failed to create IWbemLocator.", hres);
+        Error(go_ctx, "Failed to create the unsecured apartment - CoCreateInstance.", hres);
         goto error;
     }
 
@@ -245,7 +253,7 @@ void *watchEvents(void *go_ctx, char *query, char *namespace) {
     hres = ctx->apartment->lpVtbl->CreateObjectStub(
         ctx->apartment, (IUnknown *)ctx->sink, &ctx->unknown_stub);
     if (FAILED(hres)) {
-        Error(go_ctx, "Failed to create IWbemLocator.", hres);
+        Error(go_ctx, "Failed to create the event sink - CreateObjectStub.", hres);
         goto error;
     }
 
@@ -283,12 +291,16 @@ void *watchEvents(void *go_ctx, char *query, char *namespace) {
     return NULL;
 }
 
-// Destroy the C context.
-void destroyEvent(void *c_ctx) {
+// Destroy the C context. Returns the result of cancelling the
+// subscription so the caller can report a failed teardown.
+long destroyEvent(void *c_ctx) {
     watcher_context *ctx = (watcher_context *)c_ctx;
+    HRESULT hres = S_OK;
 
     if (ctx->service) {
-        ctx->service->lpVtbl->CancelAsyncCall(ctx->service, ctx->stub_sink);
+        if (ctx->stub_sink) {
+            hres = ctx->service->lpVtbl->CancelAsyncCall(ctx->service, ctx->stub_sink);
+        }
         ctx->service->lpVtbl->Release(ctx->service);
     }
 
@@ -312,8 +324,9 @@ void destroyEvent(void *c_ctx) {
         ctx->stub_sink->lpVtbl->Release(ctx->stub_sink);
     }
 
-    CoUninitialize();
     free(ctx);
+
+    return hres;
 }
 
 
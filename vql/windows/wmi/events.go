@@ -27,29 +27,62 @@ package wmi
 //
 // void *watchEvents(void *go_ctx, char *query, char *namespace);
 //
-// void destroyEvent(void *c_ctx);
+// long destroyEvent(void *c_ctx);
 import "C"
 
 import (
 	"context"
+	"fmt"
 	"runtime"
+	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
 	"github.com/Velocidex/ordereddict"
 	ole "github.com/go-ole/go-ole"
 	pointer "github.com/mattn/go-pointer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"www.velocidex.com/golang/velociraptor/acls"
 	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
 	wmi_parse "www.velocidex.com/golang/velociraptor/vql/windows/wmi/parse"
 	vfilter "www.velocidex.com/golang/vfilter"
 )
 
+// What to do with a new event when the queue is full.
+const (
+	overflow_drop_newest = "drop_newest"
+	overflow_drop_oldest = "drop_oldest"
+	overflow_block       = "block"
+
+	default_event_buffer_size = 100
+)
+
+var (
+	wmiOverflowCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "wmi_events_overflow",
+			Help: "Number of WMI events affected by a full queue or suppressed by sampling, by action.",
+		},
+		[]string{"action"},
+	)
+)
+
 type WMIObject struct {
-	Raw    string
+	Raw string
+
+	// Extra columns about process start events, only set when
+	// wmi_events() is called with enrich.
+	Enrichment *ordereddict.Dict
+
 	parsed *ordereddict.Dict
 }
 
+func (self *WMIObject) SetEnrichment(enrichment *ordereddict.Dict) {
+	self.Enrichment = enrichment
+}
+
 func (self *WMIObject) Parse() (*ordereddict.Dict, error) {
 	if self.parsed != nil {
 		return self.parsed, nil
@@ -63,24 +96,242 @@ func (self *WMIObject) Parse() (*ordereddict.Dict, error) {
 	return self.parsed, nil
 }
 
+// A stable JSON serialization of the parsed object, suitable for
+// hashing or deduplicating events.
+func (self *WMIObject) CanonicalJSON() ([]byte, error) {
+	parsed, err := self.Parse()
+	if err != nil {
+		return nil, err
+	}
+	return wmi_parse.CanonicalJSON(parsed)
+}
+
 type eventQueryContext struct {
 	output chan vfilter.Row
 	scope  vfilter.Scope
+
+	// If set, the first error reported by the C layer cancels the
+	// subscription.
+	stop_on_error bool
+	cancel        func()
+
+	mu         sync.Mutex
+	last_error string
+
+	// Errors logged before watchEvents returns explain why the
+	// subscription could not be set up.
+	subscribed  bool
+	setup_error string
+
+	// One of the overflow_* policies. In block mode a full
+	// queue blocks the WMI delivery rather than dropping the
+	// event, for at most timeout.
+	overflow_policy string
+	timeout         time.Duration
+
+	// How many times each overflow action was taken, keyed by
+	// action.
+	overflow_counts map[string]uint64
+
+	// Closed before the subscription is torn down.
+	done chan bool
+
+	// If set, only events of these (upper cased) classes are
+	// emitted.
+	class_filter map[string]bool
+
+	// Suppresses events according to sample_rate and
+	// max_events_per_second. nil keeps all events.
+	sampler *eventSampler
+}
+
+// Should the event be emitted according to the class filter?
+// Events which can not be parsed are always emitted.
+func (self *eventQueryContext) matchesClassFilter(event *WMIObject) bool {
+	if len(self.class_filter) == 0 {
+		return true
+	}
+
+	parsed, err := event.Parse()
+	if err != nil {
+		return true
+	}
+
+	return eventMatchesClassFilter(parsed, self.class_filter)
+}
+
+// Record an overflow action in the metrics and the per query
+// counts logged when the subscription ends.
+func (self *eventQueryContext) recordOverflow(action string) {
+	wmiOverflowCounter.WithLabelValues(action).Inc()
+
+	self.mu.Lock()
+	self.overflow_counts[action]++
+	self.mu.Unlock()
+}
+
+// A summary of the overflow actions taken, e.g.
+// "drop_oldest=3 blocked=10".
+func (self *eventQueryContext) overflowSummary() string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	result := []string{}
+	for _, action := range []string{
+		"drop_newest", "drop_oldest", "blocked", "block_timeout",
+		"sampled_out", "rate_limited"} {
+		if count := self.overflow_counts[action]; count > 0 {
+			result = append(result, fmt.Sprintf("%v=%v", action, count))
+		}
+	}
+	return strings.Join(result, " ")
 }
 
 // This is called to handle the serialized event string. We just send
 // it down the channel.
-func (self *eventQueryContext) ProcessEvent(event string) {
+//
+// When the queue is full the overflow policy decides what happens.
+// By default (drop_newest) the new event is dropped, so a slow query
+// never holds up WMI. With drop_oldest the oldest queued event is
+// discarded to make room, so the query sees the most recent events.
+//
+// With block we instead block the WMI delivery thread until the
+// query catches up, which pushes back on WMI so it queues the events
+// itself. This means a slow query delays all further events and WMI
+// may eventually give up on the subscription, so we only block for
+// a bounded time before dropping the event anyway.
+func (self *eventQueryContext) ProcessEvent(raw string) {
+	// Filter the events here so they do not take up space in the
+	// queue. The parsed event is kept so it is not parsed again.
+	event := &WMIObject{Raw: raw}
+	if !self.matchesClassFilter(event) {
+		return
+	}
+
+	// Sampled out events are counted just like dropped ones.
+	keep, action := self.sampler.Keep(time.Now())
+	if !keep {
+		self.recordOverflow(action)
+		return
+	}
+
 	select {
-	case self.output <- &WMIObject{Raw: event}:
+	case self.output <- event:
+		return
+	default:
+	}
+
+	switch self.overflow_policy {
+	case overflow_drop_oldest:
+		// The reader may empty the queue at the same time, so we
+		// only try to make room once.
+		select {
+		case <-self.output:
+			self.recordOverflow("drop_oldest")
+		default:
+		}
+
+		select {
+		case self.output <- event:
+		default:
+			self.recordOverflow("drop_newest")
+		}
+
+	case overflow_block:
+		self.recordOverflow("blocked")
+
+		select {
+		case self.output <- event:
+
+		// destroyEvent() waits for any deliveries in flight to
+		// return, so we must never block once the subscription
+		// is being torn down.
+		case <-self.done:
+
+		case <-time.After(self.timeout):
+			self.recordOverflow("block_timeout")
+			self.scope.Log("wmi_events: Dropping event after blocking for %v",
+				self.timeout)
+		}
+
 	default:
-		// We can not send the message because the queue is
-		// too full. We have no choice but to drop it.
+		// We can not send the message because the queue is too
+		// full. We have no choice but to drop it.
+		self.recordOverflow("drop_newest")
 	}
 }
 
 func (self *eventQueryContext) Log(message string) {
 	self.scope.Log(message)
+
+	self.mu.Lock()
+	if !self.subscribed && self.setup_error == "" {
+		self.setup_error = message
+	}
+	self.mu.Unlock()
+
+	if !self.stop_on_error {
+		return
+	}
+
+	// Only the first error is recorded - it is the one that
+	// tears down the subscription.
+	self.mu.Lock()
+	if self.last_error == "" {
+		self.last_error = message
+	}
+	self.mu.Unlock()
+
+	self.cancel()
+}
+
+// Called once watchEvents returns. If the subscription could not be
+// set up, the reason the C layer logged becomes the error emitted
+// as the final row whether or not stop_on_error is set.
+func (self *eventQueryContext) setSubscribed(ok bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.subscribed = true
+	if ok {
+		return
+	}
+
+	if self.last_error == "" {
+		self.last_error = self.setup_error
+	}
+
+	if self.last_error == "" {
+		self.last_error = "Unable to set up the subscription"
+	}
+}
+
+// Why the subscription ended once sub_ctx is done. The parent
+// context is done when the query is cancelled, while sub_ctx alone
+// expires after the requested wait time.
+func (self *eventQueryContext) teardownReason(
+	ctx, sub_ctx context.Context) string {
+	if self.Error() != "" {
+		return "Error"
+	}
+
+	if ctx.Err() != nil {
+		return "Cancelled"
+	}
+
+	if sub_ctx.Err() == context.DeadlineExceeded {
+		return "Timeout"
+	}
+
+	return "Cancelled"
+}
+
+// The error that caused the subscription to be torn down (if any).
+func (self *eventQueryContext) Error() string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	return self.last_error
 }
 
 //export process_event
@@ -99,11 +350,34 @@ func log_error(ctx *C.int, message *C.char) {
 }
 
 type WmiEventPluginArgs struct {
-	Query     string `vfilter:"required,field=query,doc=WMI query to run."`
+	Query         string      `vfilter:"optional,field=query,doc=WMI query to run."`
+	QueryTemplate string      `vfilter:"optional,field=query_template,doc=The name of a built in query to run if query is not given."`
+	TemplateArgs  vfilter.Any `vfilter:"optional,field=template_args,doc=A dict of parameters for the query_template."`
+
 	Namespace string `vfilter:"required,field=namespace,doc=WMI namespace"`
 
 	// How long to wait for events.
 	Wait int64 `vfilter:"required,field=wait,doc=Wait this many seconds for events and then quit."`
+
+	StopOnError bool `vfilter:"optional,field=stop_on_error,doc=If set, the first error terminates the subscription and is emitted as the final row."`
+
+	BufferSize     int64  `vfilter:"optional,field=buffer_size,doc=How many events to queue for the query (default 100)."`
+	OverflowPolicy string `vfilter:"optional,field=overflow_policy,doc=What to do when the queue is full: drop_newest (default), drop_oldest or block."`
+
+	BlockTimeout int64 `vfilter:"optional,field=block_timeout,doc=In block mode, drop an event after blocking this many seconds (default 10)."`
+
+	Lossless        bool  `vfilter:"optional,field=lossless,doc=Same as overflow_policy='block'."`
+	LosslessTimeout int64 `vfilter:"optional,field=lossless_timeout,doc=Same as block_timeout."`
+
+	ClassFilter []string `vfilter:"optional,field=class_filter,doc=Only emit events whose TargetInstance is one of these classes (case insensitive)."`
+
+	Enrich bool `vfilter:"optional,field=enrich,doc=If set, add the user name and process details to process start events."`
+	Typed  bool `vfilter:"optional,field=typed,doc=If set, emit the parsed event with well known classes (e.g. Win32_Process) decoded into typed columns."`
+
+	SampleRate         int64 `vfilter:"optional,field=sample_rate,doc=Only emit one in this many events."`
+	MaxEventsPerSecond int64 `vfilter:"optional,field=max_events_per_second,doc=Emit at most this many events each second."`
+
+	Within int64 `vfilter:"optional,field=within,doc=Add a WITHIN clause polling every this many seconds to intrinsic event queries which lack one."`
 }
 
 type WmiEventPlugin struct{}
@@ -133,22 +407,151 @@ func (self WmiEventPlugin) Call(
 			return
 		}
 
+		if arg.QueryTemplate != "" && arg.Query != "" {
+			scope.Log("wmi_events: query overrides query_template %v",
+				arg.QueryTemplate)
+
+		} else if arg.QueryTemplate != "" {
+			template_args := make(map[string]string)
+			if arg.TemplateArgs != nil {
+				for _, name := range scope.GetMembers(arg.TemplateArgs) {
+					value, _ := scope.Associative(arg.TemplateArgs, name)
+					template_args[name] = fmt.Sprintf("%v", value)
+				}
+			}
+
+			arg.Query, err = expandQueryTemplate(
+				arg.QueryTemplate, template_args)
+			if err != nil {
+				scope.Log("wmi_events: %v", err)
+				return
+			}
+			scope.Log("wmi_events: Expanded query_template %v to %v",
+				arg.QueryTemplate, arg.Query)
+		}
+
+		if arg.Query == "" {
+			scope.Log("wmi_events: One of query or query_template is required")
+			return
+		}
+
+		query, err := normalizeEventQuery(arg.Query, arg.Within)
+		if err != nil {
+			scope.Log("wmi_events: %v", err)
+			return
+		}
+		if query != arg.Query {
+			scope.Log("wmi_events: Added a WITHIN clause: %v", query)
+			arg.Query = query
+		}
+
 		if arg.Namespace == "" {
 			arg.Namespace = "ROOT/CIMV2"
 		}
 
+		if arg.BlockTimeout <= 0 {
+			arg.BlockTimeout = arg.LosslessTimeout
+		}
+
+		if arg.BlockTimeout <= 0 {
+			arg.BlockTimeout = 10
+		}
+
+		if arg.BufferSize <= 0 {
+			arg.BufferSize = default_event_buffer_size
+		}
+
+		// Typed rows replace the event object which carries the
+		// enrichment.
+		if arg.Typed && arg.Enrich {
+			scope.Log("wmi_events: enrich can not be combined with typed")
+			return
+		}
+
+		arg.OverflowPolicy = strings.ToLower(arg.OverflowPolicy)
+		switch arg.OverflowPolicy {
+		case "":
+			arg.OverflowPolicy = overflow_drop_newest
+			if arg.Lossless {
+				arg.OverflowPolicy = overflow_block
+			}
+		case overflow_drop_newest, overflow_drop_oldest:
+			if arg.Lossless {
+				scope.Log("wmi_events: lossless conflicts with overflow_policy %v",
+					arg.OverflowPolicy)
+				return
+			}
+		case overflow_block:
+		default:
+			scope.Log("wmi_events: Unknown overflow_policy %v", arg.OverflowPolicy)
+			return
+		}
+
 		sub_ctx, cancel := context.WithTimeout(
 			ctx, time.Duration(arg.Wait)*time.Second)
 		defer cancel()
 
-		event_context := eventQueryContext{
-			// Queue up to 100 messages
-			output: make(chan vfilter.Row, 100),
-			scope:  scope,
+		event_context := &eventQueryContext{
+			output:          make(chan vfilter.Row, arg.BufferSize),
+			scope:           scope,
+			stop_on_error:   arg.StopOnError,
+			cancel:          cancel,
+			overflow_policy: arg.OverflowPolicy,
+			timeout:         time.Duration(arg.BlockTimeout) * time.Second,
+			overflow_counts: make(map[string]uint64),
+			done:            make(chan bool),
+			class_filter:    make(map[string]bool),
+			sampler: newEventSampler(
+				arg.SampleRate, arg.MaxEventsPerSecond),
+		}
+		for _, class := range arg.ClassFilter {
+			event_context.class_filter[strings.ToUpper(class)] = true
 		}
 		defer close(event_context.output)
 
-		ptr := pointer.Save(&event_context)
+		// Why the subscription ended: one of Timeout, Cancelled,
+		// Error or SubscriptionFailed.
+		reason := ""
+		teardown_error := ""
+
+		// Log why the subscription ended and emit the error which
+		// caused the teardown as the final row.
+		defer func() {
+			message := event_context.Error()
+			scope.Log("wmi_events: Subscription ended: reason=%v error=%q teardown_error=%q",
+				reason, message, teardown_error)
+
+			summary := event_context.overflowSummary()
+			if summary != "" {
+				scope.Log("wmi_events: Events dropped or suppressed (queue of %v, policy %v): %v",
+					arg.BufferSize, arg.OverflowPolicy, summary)
+			}
+
+			if message == "" {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+			case output_chan <- ordereddict.NewDict().
+				Set("Error", message).
+				Set("Reason", reason):
+			}
+		}()
+
+		// The C layer expects COM to be initialized on this thread
+		// and all its COM objects are released by destroyEvent()
+		// before COM is uninitialized here.
+		err = coInitialize()
+		if err != nil {
+			event_context.Log(err.Error())
+			event_context.setSubscribed(false)
+			reason = "SubscriptionFailed"
+			return
+		}
+		defer ole.CoUninitialize()
+
+		ptr := pointer.Save(event_context)
 		defer pointer.Unref(ptr)
 
 		c_query := C.CString(arg.Query)
@@ -158,21 +561,72 @@ func (self WmiEventPlugin) Call(
 		defer C.free(unsafe.Pointer(c_nsp))
 
 		c_ctx := C.watchEvents(ptr, c_query, c_nsp)
+		event_context.setSubscribed(c_ctx != nil)
 		if c_ctx == nil {
+			reason = "SubscriptionFailed"
 			return
 		}
 
-		for item := range event_context.output {
+		// Destroy the C context when we are done here.
+		defer func() {
+			hres := C.destroyEvent(c_ctx)
+			if hres != 0 {
+				teardown_error = fmt.Sprintf(
+					"CancelAsyncCall: Error code %#x", uint32(hres))
+			}
+		}()
+
+		// Release any blocked deliveries first.
+		defer close(event_context.done)
+
+		// The enrichment lookups run while the queue keeps being
+		// read so slow lookups never hold up the WMI delivery.
+		var events <-chan vfilter.Row = event_context.output
+		if arg.Enrich {
+			events = newEventEnricher(lookupAccount, lookupProcess).
+				Pipeline(sub_ctx, event_context.output, arg.BufferSize)
+		}
+
+		for {
 			select {
 			case <-sub_ctx.Done():
-				// Destroy the C context - we are done here.
-				C.destroyEvent(c_ctx)
+				reason = event_context.teardownReason(ctx, sub_ctx)
 				return
 
 				// Read the next item from the event
 				// queue and send it to the VQL
 				// subsystem.
-			case output_chan <- item:
+			case item, ok := <-events:
+				// The pipeline only closes when the
+				// subscription ends.
+				if !ok {
+					<-sub_ctx.Done()
+					reason = event_context.teardownReason(ctx, sub_ctx)
+					return
+				}
+
+				event, ok := item.(*WMIObject)
+
+				// Events which do not match their
+				// class's schema are emitted untyped.
+				if ok && arg.Typed {
+					parsed, err := event.Parse()
+					if err == nil {
+						typed, err := wmi_parse.DecodeTyped(parsed)
+						if err != nil {
+							scope.Log("wmi_events: %v", err)
+						} else {
+							item = typed
+						}
+					}
+				}
+
+				select {
+				case <-sub_ctx.Done():
+					reason = event_context.teardownReason(ctx, sub_ctx)
+					return
+				case output_chan <- item:
+				}
 			}
 		}
 	}()
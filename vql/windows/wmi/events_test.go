@@ -0,0 +1,75 @@
+// +build windows
+
+package wmi
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+)
+
+// Several subscriptions and WMI queries run at the same time each
+// on their own locked thread, so none of them should see COM
+// initialized for the wrong apartment or already uninitialized.
+func TestConcurrentEventQueries(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(plugin vfilter.PluginGeneratorInterface,
+		args *ordereddict.Dict) string {
+		buffer := &bytes.Buffer{}
+		scope := vql_subsystem.MakeScope().AppendVars(ordereddict.NewDict().
+			Set(vql_subsystem.ACL_MANAGER_VAR, vql_subsystem.NullACLManager{}))
+		scope.SetLogger(log.New(buffer, "", 0))
+		defer scope.Close()
+
+		for row := range plugin.Call(ctx, scope, args) {
+			row_dict, ok := row.(*ordereddict.Dict)
+			if ok {
+				message, pres := row_dict.Get("Error")
+				assert.False(t, pres, "Error row: %v", message)
+			}
+		}
+		return buffer.String()
+	}
+
+	wg := &sync.WaitGroup{}
+	mu := &sync.Mutex{}
+	event_logs := []string{}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			logs := run(WmiEventPlugin{}, ordereddict.NewDict().
+				Set("query", "SELECT * FROM __InstanceCreationEvent WITHIN 1 "+
+					"WHERE TargetInstance ISA 'Win32_Process'").
+				Set("namespace", "ROOT/CIMV2").
+				Set("wait", 3))
+
+			mu.Lock()
+			event_logs = append(event_logs, logs)
+			mu.Unlock()
+		}()
+
+		go func() {
+			defer wg.Done()
+			logs := run(WMIQueryPlugin{}, ordereddict.NewDict().
+				Set("query", "SELECT Name FROM Win32_OperatingSystem"))
+			assert.NotContains(t, logs, "CoInitialize")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 4, len(event_logs))
+	for _, logs := range event_logs {
+		assert.NotContains(t, logs, "CoInitialize")
+		assert.Contains(t, logs, "reason=Timeout")
+	}
+}
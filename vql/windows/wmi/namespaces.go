@@ -0,0 +1,121 @@
+package wmi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+)
+
+// Namespaces are not expected to nest anywhere near this deep - this
+// just bounds the recursion.
+const max_namespace_depth = 32
+
+type WmiNamespacesPluginArgs struct {
+	Root string `vfilter:"optional,field=root,doc=The namespace to start enumerating from (default ROOT)."`
+}
+
+type WmiNamespacesPlugin struct{}
+
+func (self WmiNamespacesPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("wmi_namespaces: %s", err)
+			return
+		}
+
+		arg := &WmiNamespacesPluginArgs{}
+		err = vfilter.ExtractArgs(scope, args, arg)
+		if err != nil {
+			scope.Log("wmi_namespaces: %s", err.Error())
+			return
+		}
+
+		if arg.Root == "" {
+			arg.Root = "ROOT"
+		}
+
+		type pending struct {
+			namespace string
+			depth     int
+		}
+
+		// Walk the namespaces breadth first. WMI should not
+		// report cycles but we keep track of the namespaces we
+		// have seen in case it does.
+		queue := []pending{{namespace: arg.Root}}
+		seen := map[string]bool{strings.ToUpper(arg.Root): true}
+
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			if current.depth >= max_namespace_depth {
+				scope.Log("wmi_namespaces: %v is too deep - skipping",
+					current.namespace)
+				continue
+			}
+
+			// Access to some namespaces may be denied - we
+			// just skip them.
+			children, err := Query("SELECT Name FROM __NAMESPACE",
+				current.namespace)
+			if err != nil {
+				scope.Log("wmi_namespaces: %v: %v", current.namespace, err)
+				continue
+			}
+
+			for _, child := range children {
+				name, ok := child.GetString("Name")
+				if !ok || name == "" {
+					continue
+				}
+
+				namespace := current.namespace + "/" + name
+				key := strings.ToUpper(namespace)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- ordereddict.NewDict().
+					Set("Namespace", namespace).
+					Set("Name", name):
+				}
+
+				queue = append(queue, pending{
+					namespace: namespace,
+					depth:     current.depth + 1,
+				})
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self WmiNamespacesPlugin) Info(scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "wmi_namespaces",
+		Doc:     "Recursively enumerate the WMI namespaces under a root namespace.",
+		ArgType: type_map.AddType(scope, &WmiNamespacesPluginArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&WmiNamespacesPlugin{})
+}
@@ -0,0 +1,41 @@
+package wmi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The class of a WMI object path, e.g.
+// \\HOST\ROOT\subscription:__EventFilter.Name="foo" -> __EventFilter
+func classFromObjectPath(path string) string {
+	// Key values may contain anything so only look before the
+	// first key.
+	if idx := strings.Index(path, "="); idx >= 0 {
+		path = path[:idx]
+	}
+
+	if idx := strings.LastIndex(path, "."); idx >= 0 {
+		path = path[:idx]
+	}
+
+	if idx := strings.LastIndex(path, ":"); idx >= 0 {
+		path = path[idx+1:]
+	}
+
+	return path
+}
+
+// Only the objects making up a subscription may be deleted. Bindings
+// must be deleted before the filters and consumers they refer to.
+func subscriptionDeleteOrder(path string) (int, error) {
+	class := strings.ToLower(classFromObjectPath(path))
+	switch {
+	case class == "__filtertoconsumerbinding":
+		return 0, nil
+	case strings.HasSuffix(class, "eventconsumer"):
+		return 1, nil
+	case class == "__eventfilter":
+		return 2, nil
+	}
+	return 0, fmt.Errorf("%v is not part of a WMI subscription", path)
+}
@@ -0,0 +1,48 @@
+package wmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassFromObjectPath(t *testing.T) {
+	for _, test := range []struct {
+		path, expected string
+	}{
+		{`\\HOST\ROOT\subscription:__EventFilter.Name="foo"`, "__EventFilter"},
+		{`__EventFilter.Name="foo"`, "__EventFilter"},
+		{`\\HOST\ROOT\subscription:CommandLineEventConsumer.Name="a.b:c"`,
+			"CommandLineEventConsumer"},
+		{`__FilterToConsumerBinding.Consumer="CommandLineEventConsumer.Name=\"foo\"",` +
+			`Filter="__EventFilter.Name=\"foo\""`, "__FilterToConsumerBinding"},
+		{`Win32_Process`, "Win32_Process"},
+	} {
+		assert.Equal(t, test.expected, classFromObjectPath(test.path), test.path)
+	}
+}
+
+func TestSubscriptionDeleteOrder(t *testing.T) {
+	for _, test := range []struct {
+		path     string
+		expected int
+		err      bool
+	}{
+		{`__FilterToConsumerBinding.Consumer="x",Filter="y"`, 0, false},
+		{`\\HOST\ROOT\subscription:CommandLineEventConsumer.Name="foo"`, 1, false},
+		{`ActiveScriptEventConsumer.Name="foo"`, 1, false},
+		{`__EventFilter.Name="foo"`, 2, false},
+
+		// Other objects are not part of a subscription.
+		{`Win32_Process.Handle="4"`, 0, true},
+		{`__EventFilter2.Name="foo"`, 0, true},
+	} {
+		order, err := subscriptionDeleteOrder(test.path)
+		if test.err {
+			assert.Error(t, err, test.path)
+			continue
+		}
+		assert.NoError(t, err, test.path)
+		assert.Equal(t, test.expected, order, test.path)
+	}
+}
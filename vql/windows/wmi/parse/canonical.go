@@ -0,0 +1,62 @@
+package wmi
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// Serialize a parsed WMI object to JSON in a canonical form: keys
+// are sorted at every level, nulls are always encoded the same way
+// and there is no insignificant whitespace. Two objects with the same
+// fields and values always serialize to the same bytes, regardless
+// of the order GetObjectText emitted the fields in, so the result
+// can be hashed or used to deduplicate events.
+func CanonicalJSON(dict *ordereddict.Dict) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+
+	// encoding/json always sorts map keys.
+	err := encoder.Encode(canonicalValue(dict))
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func canonicalValue(value interface{}) interface{} {
+	switch t := value.(type) {
+	case *ordereddict.Dict:
+		if t == nil {
+			return nil
+		}
+		result := make(map[string]interface{})
+		for _, key := range t.Keys() {
+			item, _ := t.Get(key)
+			result[key] = canonicalValue(item)
+		}
+		return result
+
+	case []interface{}:
+		result := make([]interface{}, 0, len(t))
+		for _, item := range t {
+			result = append(result, canonicalValue(item))
+		}
+		return result
+
+	case *string:
+		if t == nil {
+			return nil
+		}
+		return *t
+
+	case vfilter.Null, *vfilter.Null:
+		return nil
+	}
+
+	return value
+}
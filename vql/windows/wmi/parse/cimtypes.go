@@ -0,0 +1,53 @@
+package wmi
+
+import "fmt"
+
+// WbemCimtypeEnum values as reported by SWbemProperty.CIMType.
+const (
+	CIM_SINT16    = 2
+	CIM_SINT32    = 3
+	CIM_REAL32    = 4
+	CIM_REAL64    = 5
+	CIM_STRING    = 8
+	CIM_BOOLEAN   = 11
+	CIM_OBJECT    = 13
+	CIM_SINT8     = 16
+	CIM_UINT8     = 17
+	CIM_UINT16    = 18
+	CIM_UINT32    = 19
+	CIM_SINT64    = 20
+	CIM_UINT64    = 21
+	CIM_DATETIME  = 101
+	CIM_REFERENCE = 102
+	CIM_CHAR16    = 103
+)
+
+// The names are spelled the way MOF declares the types.
+var cim_type_names = map[int64]string{
+	CIM_SINT16:    "sint16",
+	CIM_SINT32:    "sint32",
+	CIM_REAL32:    "real32",
+	CIM_REAL64:    "real64",
+	CIM_STRING:    "string",
+	CIM_BOOLEAN:   "boolean",
+	CIM_OBJECT:    "object",
+	CIM_SINT8:     "sint8",
+	CIM_UINT8:     "uint8",
+	CIM_UINT16:    "uint16",
+	CIM_UINT32:    "uint32",
+	CIM_SINT64:    "sint64",
+	CIM_UINT64:    "uint64",
+	CIM_DATETIME:  "datetime",
+	CIM_REFERENCE: "ref",
+	CIM_CHAR16:    "char16",
+}
+
+// Name a CIM type. Unknown types are reported by number rather than
+// dropped.
+func CIMTypeName(cim_type int64) string {
+	name, pres := cim_type_names[cim_type]
+	if pres {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", cim_type)
+}
@@ -0,0 +1,49 @@
+package wmi
+
+import "time"
+
+// Typed versions of commonly collected classes. Only the properties
+// most queries use are decoded - use the generic dict for the rest.
+
+type Win32_Process struct {
+	Class           string `wmi:"__Type"`
+	Name            string
+	ProcessId       uint32
+	ParentProcessId uint32
+	SessionId       uint32
+	CommandLine     string
+	ExecutablePath  string
+	CreationDate    time.Time
+	HandleCount     uint32
+	ThreadCount     uint32
+	Priority        uint32
+	KernelModeTime  uint64
+	UserModeTime    uint64
+	WorkingSetSize  uint64
+	VirtualSize     uint64
+	WindowsVersion  string
+}
+
+type Win32_Service struct {
+	Class                   string `wmi:"__Type"`
+	Name                    string
+	DisplayName             string
+	Description             string
+	PathName                string
+	ServiceType             string
+	StartMode               string
+	StartName               string
+	State                   string
+	Status                  string
+	Started                 bool
+	AcceptPause             bool
+	AcceptStop              bool
+	ProcessId               uint32
+	ExitCode                uint32
+	ServiceSpecificExitCode uint32
+}
+
+func init() {
+	RegisterTypedClass("Win32_Process", &Win32_Process{})
+	RegisterTypedClass("Win32_Service", &Win32_Service{})
+}
@@ -43,3 +43,55 @@ func TestParseMOF(t *testing.T) {
 
 	goldie.Assert(t, "sample", encoded)
 }
+
+func TestCanonicalJSON(t *testing.T) {
+	data, err := ioutil.ReadFile("fixtures/sample.txt")
+	assert.NoError(t, err, "ReadFile")
+
+	mof, err := wmi_parse.Parse(string(data))
+	assert.NoError(t, err, "Parse")
+
+	encoded, err := wmi_parse.CanonicalJSON(mof.ToDict())
+	assert.NoError(t, err, "CanonicalJSON")
+
+	// Repeated serializations are byte for byte identical.
+	for i := 0; i < 100; i++ {
+		mof, err := wmi_parse.Parse(string(data))
+		assert.NoError(t, err, "Parse")
+
+		again, err := wmi_parse.CanonicalJSON(mof.ToDict())
+		assert.NoError(t, err, "CanonicalJSON")
+		assert.Equal(t, string(encoded), string(again))
+	}
+
+	// The order of the fields in the MOF text does not matter.
+	first, err := wmi_parse.Parse(`instance of Win32_Process
+{
+	Name = "notepad.exe";
+	ProcessId = 984;
+	Foo = {1, 2};
+	Description = NULL;
+};`)
+	assert.NoError(t, err, "Parse")
+
+	second, err := wmi_parse.Parse(`instance of Win32_Process
+{
+	Description = NULL;
+	Foo = {1, 2};
+	ProcessId = 984;
+	Name = "notepad.exe";
+};`)
+	assert.NoError(t, err, "Parse")
+
+	first_encoded, err := wmi_parse.CanonicalJSON(first.ToDict())
+	assert.NoError(t, err, "CanonicalJSON")
+
+	second_encoded, err := wmi_parse.CanonicalJSON(second.ToDict())
+	assert.NoError(t, err, "CanonicalJSON")
+
+	assert.Equal(t, `{"Description":null,"Foo":[1,2],"Name":"notepad.exe",`+
+		`"ProcessId":984,"__Type":"Win32_Process"}`, string(first_encoded))
+	assert.Equal(t, string(first_encoded), string(second_encoded))
+
+	goldie.Assert(t, "sample_canonical", encoded)
+}
@@ -0,0 +1,237 @@
+package wmi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"www.velocidex.com/golang/vfilter"
+)
+
+// Objects of well known classes can be decoded into Go structs so
+// their columns are always present and have consistent types. Each
+// exported field is filled from the property of the same name (or
+// the name in its wmi tag). A property which can not be converted to
+// the field's type is an error so changes in the class schema are
+// noticed rather than silently producing empty columns.
+var (
+	typed_mu      sync.Mutex
+	typed_classes = make(map[string]reflect.Type)
+)
+
+// Decode objects of the class into the prototype's struct type.
+func RegisterTypedClass(class string, prototype interface{}) {
+	typ := reflect.TypeOf(prototype)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	typed_mu.Lock()
+	defer typed_mu.Unlock()
+
+	typed_classes[strings.ToUpper(class)] = typ
+}
+
+func getTypedClass(class string) (reflect.Type, bool) {
+	typed_mu.Lock()
+	defer typed_mu.Unlock()
+
+	typ, pres := typed_classes[strings.ToUpper(class)]
+	return typ, pres
+}
+
+// Decode a parsed object into a pointer to its registered struct.
+// Objects of other classes are returned as a dict, with any nested
+// objects of registered classes (e.g. an event's TargetInstance)
+// decoded.
+func DecodeTyped(parsed *ordereddict.Dict) (interface{}, error) {
+	class := typeName(parsed)
+	typ, pres := getTypedClass(class)
+	if !pres {
+		result := ordereddict.NewDict()
+		for _, key := range parsed.Keys() {
+			value, _ := parsed.Get(key)
+			nested, ok := value.(*ordereddict.Dict)
+			if ok {
+				decoded, err := DecodeTyped(nested)
+				if err != nil {
+					return nil, err
+				}
+				value = decoded
+			}
+			result.Set(key, value)
+		}
+		return result, nil
+	}
+
+	result := reflect.New(typ)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+		tag := field.Tag.Get("wmi")
+		if tag != "" {
+			name = tag
+		}
+
+		value, pres := parsed.Get(name)
+		if !pres {
+			continue
+		}
+
+		err := setTypedField(result.Elem().Field(i), value)
+		if err != nil {
+			return nil, fmt.Errorf("%v.%v: %v", class, name, err)
+		}
+	}
+
+	return result.Interface(), nil
+}
+
+var time_type = reflect.TypeOf(time.Time{})
+
+func setTypedField(field reflect.Value, value interface{}) error {
+	switch t := value.(type) {
+	case nil, vfilter.Null, *vfilter.Null:
+		return nil
+	case *string:
+		if t == nil {
+			return nil
+		}
+		value = *t
+	}
+
+	if field.Type() == time_type {
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a datetime, got %T", value)
+		}
+		parsed, err := ParseCIMDatetime(str)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		switch t := value.(type) {
+		case string:
+			field.SetString(t)
+		case int64, bool:
+			field.SetString(fmt.Sprintf("%v", t))
+		default:
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+
+	case reflect.Bool:
+		t, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected a bool, got %T", value)
+		}
+		field.SetBool(t)
+
+	// MOF encodes 64 bit integers as strings.
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var number int64
+		switch t := value.(type) {
+		case int64:
+			number = t
+		case string:
+			parsed, err := strconv.ParseInt(t, 10, 64)
+			if err != nil {
+				return err
+			}
+			number = parsed
+		default:
+			return fmt.Errorf("expected an integer, got %T", value)
+		}
+		if field.OverflowInt(number) {
+			return fmt.Errorf("%v overflows %v", number, field.Type())
+		}
+		field.SetInt(number)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var number uint64
+		switch t := value.(type) {
+		case int64:
+			if t < 0 {
+				return fmt.Errorf("%v is negative", t)
+			}
+			number = uint64(t)
+		case string:
+			parsed, err := strconv.ParseUint(t, 10, 64)
+			if err != nil {
+				return err
+			}
+			number = parsed
+		default:
+			return fmt.Errorf("expected an integer, got %T", value)
+		}
+		if field.OverflowUint(number) {
+			return fmt.Errorf("%v overflows %v", number, field.Type())
+		}
+		field.SetUint(number)
+
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected an array, got %T", value)
+		}
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			err := setTypedField(slice.Index(i), item)
+			if err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+
+	default:
+		return fmt.Errorf("unsupported field type %v", field.Type())
+	}
+
+	return nil
+}
+
+// Parse a CIM DATETIME, e.g. 20181007201847.788310-420 where the
+// suffix is the offset from UTC in minutes.
+func ParseCIMDatetime(value string) (time.Time, error) {
+	if len(value) != 25 {
+		return time.Time{}, fmt.Errorf("invalid datetime %q", value)
+	}
+
+	offset, err := strconv.Atoi(value[21:])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid datetime %q", value)
+	}
+
+	result, err := time.ParseInLocation("20060102150405.000000",
+		value[:21], time.FixedZone("", offset*60))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid datetime %q", value)
+	}
+
+	return result.UTC(), nil
+}
+
+func typeName(parsed *ordereddict.Dict) string {
+	value, _ := parsed.Get("__Type")
+	switch t := value.(type) {
+	case string:
+		return t
+	case *string:
+		if t != nil {
+			return *t
+		}
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package wmi_test
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/Velocidex/ordereddict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	wmi_parse "www.velocidex.com/golang/velociraptor/vql/windows/wmi/parse"
+)
+
+func TestDecodeTyped(t *testing.T) {
+	data, err := ioutil.ReadFile("fixtures/sample.txt")
+	require.NoError(t, err, "ReadFile")
+
+	mof, err := wmi_parse.Parse(string(data))
+	require.NoError(t, err, "Parse")
+
+	// The event class is not registered so it stays a dict but its
+	// TargetInstance is decoded.
+	decoded, err := wmi_parse.DecodeTyped(mof.ToDict())
+	require.NoError(t, err, "DecodeTyped")
+
+	event, ok := decoded.(*ordereddict.Dict)
+	require.True(t, ok)
+
+	time_created, _ := event.Get("TIME_CREATED")
+	assert.Equal(t, "131834423287753198", time_created)
+
+	target, _ := event.Get("TargetInstance")
+	process, ok := target.(*wmi_parse.Win32_Process)
+	require.True(t, ok)
+
+	assert.Equal(t, "Win32_Process", process.Class)
+	assert.Equal(t, "notepad.exe", process.Name)
+	assert.Equal(t, uint32(984), process.ProcessId)
+	assert.Equal(t, uint32(2424), process.ParentProcessId)
+	assert.Equal(t, `C:\Windows\system32\notepad.exe`, process.ExecutablePath)
+	assert.Equal(t, uint64(15560704), process.WorkingSetSize)
+	assert.Equal(t, time.Date(2018, 10, 8, 3, 18, 47, 788310000, time.UTC),
+		process.CreationDate)
+
+	// Missing properties are left empty.
+	service_mof, err := wmi_parse.Parse(`instance of Win32_Service
+{
+	Name = "Spooler";
+	State = "Running";
+	Started = TRUE;
+	ProcessId = 2212;
+	Description = NULL;
+};`)
+	require.NoError(t, err, "Parse")
+
+	decoded, err = wmi_parse.DecodeTyped(service_mof.ToDict())
+	require.NoError(t, err, "DecodeTyped")
+	assert.Equal(t, &wmi_parse.Win32_Service{
+		Class:     "Win32_Service",
+		Name:      "Spooler",
+		State:     "Running",
+		Started:   true,
+		ProcessId: 2212,
+	}, decoded)
+
+	// Properties of the wrong type are an error.
+	drifted, err := wmi_parse.Parse(`instance of Win32_Process
+{
+	ProcessId = "not a pid";
+};`)
+	require.NoError(t, err, "Parse")
+
+	_, err = wmi_parse.DecodeTyped(drifted.ToDict())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Win32_Process.ProcessId")
+}
+
+func TestParseCIMDatetime(t *testing.T) {
+	parsed, err := wmi_parse.ParseCIMDatetime("20210301120000.000000+060")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 3, 1, 11, 0, 0, 0, time.UTC), parsed)
+
+	_, err = wmi_parse.ParseCIMDatetime("2021030112")
+	assert.Error(t, err)
+}
+
+func TestCIMTypeName(t *testing.T) {
+	assert.Equal(t, "string", wmi_parse.CIMTypeName(wmi_parse.CIM_STRING))
+	assert.Equal(t, "uint32", wmi_parse.CIMTypeName(wmi_parse.CIM_UINT32))
+	assert.Equal(t, "datetime", wmi_parse.CIMTypeName(wmi_parse.CIM_DATETIME))
+	assert.Equal(t, "ref", wmi_parse.CIMTypeName(wmi_parse.CIM_REFERENCE))
+	assert.Equal(t, "unknown(99)", wmi_parse.CIMTypeName(99))
+}
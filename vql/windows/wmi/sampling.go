@@ -0,0 +1,68 @@
+package wmi
+
+import (
+	"sync"
+	"time"
+)
+
+// Thins out a chatty event stream before it is queued. With a
+// sample_rate of N only every Nth event is kept (starting with the
+// first one), and with max_events_per_second no more than that many
+// events are kept in each one second window. Events are never
+// delayed, only suppressed, so the delivery thread is not held up.
+type eventSampler struct {
+	mu sync.Mutex
+
+	sample_rate           uint64
+	max_events_per_second uint64
+
+	seen uint64
+
+	window_start time.Time
+	window_count uint64
+}
+
+func newEventSampler(sample_rate, max_events_per_second int64) *eventSampler {
+	if sample_rate <= 1 && max_events_per_second <= 0 {
+		return nil
+	}
+
+	result := &eventSampler{}
+	if sample_rate > 1 {
+		result.sample_rate = uint64(sample_rate)
+	}
+	if max_events_per_second > 0 {
+		result.max_events_per_second = uint64(max_events_per_second)
+	}
+	return result
+}
+
+// Should the event arriving at now be kept? If not, the reason it
+// was suppressed is returned as the overflow action to record.
+func (self *eventSampler) Keep(now time.Time) (bool, string) {
+	if self == nil {
+		return true, ""
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.seen++
+	if self.sample_rate > 1 && (self.seen-1)%self.sample_rate != 0 {
+		return false, "sampled_out"
+	}
+
+	if self.max_events_per_second > 0 {
+		if now.Sub(self.window_start) >= time.Second {
+			self.window_start = now
+			self.window_count = 0
+		}
+
+		if self.window_count >= self.max_events_per_second {
+			return false, "rate_limited"
+		}
+		self.window_count++
+	}
+
+	return true, ""
+}
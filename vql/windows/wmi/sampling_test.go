@@ -0,0 +1,62 @@
+package wmi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func keptEvents(sampler *eventSampler, times []time.Time) (int, map[string]int) {
+	kept := 0
+	suppressed := make(map[string]int)
+	for _, now := range times {
+		keep, action := sampler.Keep(now)
+		if keep {
+			kept++
+		} else {
+			suppressed[action]++
+		}
+	}
+	return kept, suppressed
+}
+
+func TestEventSampler(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	same_time := make([]time.Time, 10)
+	for i := range same_time {
+		same_time[i] = start
+	}
+
+	// No sampling at all.
+	assert.Nil(t, newEventSampler(0, 0))
+	assert.Nil(t, newEventSampler(1, 0))
+	kept, _ := keptEvents(nil, same_time)
+	assert.Equal(t, 10, kept)
+
+	// 1 in 3 keeps events 1, 4, 7 and 10.
+	kept, suppressed := keptEvents(newEventSampler(3, 0), same_time)
+	assert.Equal(t, 4, kept)
+	assert.Equal(t, map[string]int{"sampled_out": 6}, suppressed)
+
+	// Only 4 events are kept in the same second.
+	kept, suppressed = keptEvents(newEventSampler(0, 4), same_time)
+	assert.Equal(t, 4, kept)
+	assert.Equal(t, map[string]int{"rate_limited": 6}, suppressed)
+
+	// The limit applies to each second separately: 10 events over
+	// 2.5 seconds with at most 2 per second.
+	spread := []time.Time{}
+	for i := 0; i < 10; i++ {
+		spread = append(spread, start.Add(time.Duration(i)*250*time.Millisecond))
+	}
+	kept, suppressed = keptEvents(newEventSampler(0, 2), spread)
+	assert.Equal(t, 6, kept)
+	assert.Equal(t, map[string]int{"rate_limited": 4}, suppressed)
+
+	// Sampling applies before the rate limit.
+	kept, suppressed = keptEvents(newEventSampler(2, 3), same_time)
+	assert.Equal(t, 3, kept)
+	assert.Equal(t, map[string]int{
+		"sampled_out": 5, "rate_limited": 2}, suppressed)
+}
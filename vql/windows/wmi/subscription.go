@@ -0,0 +1,331 @@
+package wmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Velocidex/ordereddict"
+	ole "github.com/go-ole/go-ole"
+	"github.com/go-ole/go-ole/oleutil"
+	"www.velocidex.com/golang/velociraptor/acls"
+	vql_subsystem "www.velocidex.com/golang/velociraptor/vql"
+	vfilter "www.velocidex.com/golang/vfilter"
+)
+
+// Permanent subscriptions live in this namespace by default.
+const default_subscription_namespace = "ROOT/subscription"
+
+var (
+	subscription_name_regex = regexp.MustCompile(`^[a-zA-Z0-9_ .-]+$`)
+)
+
+type WmiCreateSubscriptionArgs struct {
+	Name           string `vfilter:"required,field=name,doc=The name of the filter and consumer to create."`
+	Query          string `vfilter:"required,field=query,doc=The WQL event query the filter triggers on."`
+	EventNamespace string `vfilter:"optional,field=event_namespace,doc=The namespace the event query runs in (default ROOT/CIMV2)."`
+	Namespace      string `vfilter:"optional,field=namespace,doc=The namespace to create the subscription in (default ROOT/subscription)."`
+	CommandLine    string `vfilter:"optional,field=command_line,doc=Create a CommandLineEventConsumer running this command."`
+	Script         string `vfilter:"optional,field=script,doc=Create an ActiveScriptEventConsumer running this script."`
+	ScriptEngine   string `vfilter:"optional,field=script_engine,doc=The script's language - VBScript (default) or JScript."`
+	ReallyDoIt     bool   `vfilter:"optional,field=really_do_it,doc=Must be set to actually create the subscription."`
+}
+
+func (self *WmiCreateSubscriptionArgs) validate() error {
+	if !subscription_name_regex.MatchString(self.Name) {
+		return fmt.Errorf("invalid name %q", self.Name)
+	}
+
+	if !strings.HasPrefix(strings.ToUpper(
+		strings.TrimSpace(self.Query)), "SELECT ") {
+		return errors.New("query must be a WQL SELECT statement")
+	}
+
+	if (self.CommandLine == "") == (self.Script == "") {
+		return errors.New("exactly one of command_line or script is required")
+	}
+
+	if self.Script != "" {
+		switch strings.ToLower(self.ScriptEngine) {
+		case "":
+			self.ScriptEngine = "VBScript"
+		case "vbscript", "jscript":
+		default:
+			return fmt.Errorf("unsupported script_engine %q",
+				self.ScriptEngine)
+		}
+	}
+
+	if self.EventNamespace == "" {
+		self.EventNamespace = "ROOT/CIMV2"
+	}
+
+	if self.Namespace == "" {
+		self.Namespace = default_subscription_namespace
+	}
+
+	return nil
+}
+
+type WmiCreateSubscriptionPlugin struct{}
+
+func (self WmiCreateSubscriptionPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		// The consumer runs arbitrary commands on the endpoint.
+		err := vql_subsystem.CheckAccess(scope, acls.EXECVE)
+		if err != nil {
+			scope.Log("wmi_create_subscription: %s", err)
+			return
+		}
+
+		arg := &WmiCreateSubscriptionArgs{}
+		err = vfilter.ExtractArgs(scope, args, arg)
+		if err != nil {
+			scope.Log("wmi_create_subscription: %s", err.Error())
+			return
+		}
+
+		err = arg.validate()
+		if err != nil {
+			scope.Log("wmi_create_subscription: %v", err)
+			return
+		}
+
+		if !arg.ReallyDoIt {
+			scope.Log("wmi_create_subscription: Not creating subscription %v "+
+				"in %v - set really_do_it to confirm.", arg.Name, arg.Namespace)
+			return
+		}
+
+		scope.Log("wmi_create_subscription: Creating permanent subscription "+
+			"%v in %v: query=%q command_line=%q script=%q",
+			arg.Name, arg.Namespace, arg.Query, arg.CommandLine, arg.Script)
+
+		row, err := createSubscription(arg)
+		if err != nil {
+			scope.Log("wmi_create_subscription: %v", err)
+			return
+		}
+
+		scope.Log("wmi_create_subscription: Created %v, %v and %v",
+			row.Filter, row.Consumer, row.Binding)
+
+		select {
+		case <-ctx.Done():
+		case output_chan <- row:
+		}
+	}()
+
+	return output_chan
+}
+
+func (self WmiCreateSubscriptionPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "wmi_create_subscription",
+		Doc:     "Create a permanent WMI event subscription for detection testing.",
+		ArgType: type_map.AddType(scope, &WmiCreateSubscriptionArgs{}),
+	}
+}
+
+type subscriptionPaths struct {
+	Name      string
+	Namespace string
+	Filter    string
+	Consumer  string
+	Binding   string
+}
+
+func createSubscription(
+	arg *WmiCreateSubscriptionArgs) (*subscriptionPaths, error) {
+	result := &subscriptionPaths{
+		Name:      arg.Name,
+		Namespace: arg.Namespace,
+	}
+
+	err := withService(arg.Namespace, func(service *ole.IDispatch) (err error) {
+		// Do not leave a partial subscription behind if we fail
+		// half way.
+		defer func() {
+			if err != nil {
+				for _, path := range []string{
+					result.Binding, result.Consumer, result.Filter} {
+					if path != "" {
+						_ = deleteInstance(service, path)
+					}
+				}
+			}
+		}()
+
+		result.Filter, err = putInstance(service, "__EventFilter",
+			ordereddict.NewDict().
+				Set("Name", arg.Name).
+				Set("EventNamespace", arg.EventNamespace).
+				Set("QueryLanguage", "WQL").
+				Set("Query", arg.Query))
+		if err != nil {
+			return err
+		}
+
+		if arg.CommandLine != "" {
+			result.Consumer, err = putInstance(service,
+				"CommandLineEventConsumer", ordereddict.NewDict().
+					Set("Name", arg.Name).
+					Set("CommandLineTemplate", arg.CommandLine))
+		} else {
+			result.Consumer, err = putInstance(service,
+				"ActiveScriptEventConsumer", ordereddict.NewDict().
+					Set("Name", arg.Name).
+					Set("ScriptingEngine", arg.ScriptEngine).
+					Set("ScriptText", arg.Script))
+		}
+		if err != nil {
+			return err
+		}
+
+		result.Binding, err = putInstance(service,
+			"__FilterToConsumerBinding", ordereddict.NewDict().
+				Set("Filter", result.Filter).
+				Set("Consumer", result.Consumer))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Create a new instance of the class and return its object path.
+func putInstance(service *ole.IDispatch,
+	class string, properties *ordereddict.Dict) (string, error) {
+	class_raw, err := oleutil.CallMethod(service, "Get", class)
+	if err != nil {
+		return "", fmt.Errorf("%v: %v", class, err)
+	}
+	class_obj := class_raw.ToIDispatch()
+	defer class_obj.Release()
+
+	instance_raw, err := oleutil.CallMethod(class_obj, "SpawnInstance_")
+	if err != nil {
+		return "", fmt.Errorf("%v: %v", class, err)
+	}
+	instance := instance_raw.ToIDispatch()
+	defer instance.Release()
+
+	for _, key := range properties.Keys() {
+		value, _ := properties.Get(key)
+		_, err := oleutil.PutProperty(instance, key, value)
+		if err != nil {
+			return "", fmt.Errorf("%v.%v: %v", class, key, err)
+		}
+	}
+
+	path_raw, err := oleutil.CallMethod(instance, "Put_")
+	if err != nil {
+		return "", fmt.Errorf("%v: %v", class, err)
+	}
+	path_obj := path_raw.ToIDispatch()
+	defer path_obj.Release()
+
+	path, err := oleutil.GetProperty(path_obj, "Path")
+	if err != nil {
+		return "", fmt.Errorf("%v: %v", class, err)
+	}
+	defer func() {
+		_ = path.Clear()
+	}()
+
+	return path.ToString(), nil
+}
+
+func deleteInstance(service *ole.IDispatch, path string) error {
+	_, err := oleutil.CallMethod(service, "Delete", path)
+	return err
+}
+
+type WmiDeleteSubscriptionArgs struct {
+	Paths     []string `vfilter:"required,field=paths,doc=The object paths returned by wmi_create_subscription()."`
+	Namespace string   `vfilter:"optional,field=namespace,doc=The namespace of the subscription (default ROOT/subscription)."`
+}
+
+type WmiDeleteSubscriptionFunction struct{}
+
+func (self *WmiDeleteSubscriptionFunction) Call(ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) vfilter.Any {
+
+	err := vql_subsystem.CheckAccess(scope, acls.EXECVE)
+	if err != nil {
+		scope.Log("wmi_delete_subscription: %s", err)
+		return false
+	}
+
+	arg := &WmiDeleteSubscriptionArgs{}
+	err = vfilter.ExtractArgs(scope, args, arg)
+	if err != nil {
+		scope.Log("wmi_delete_subscription: %s", err.Error())
+		return false
+	}
+
+	if arg.Namespace == "" {
+		arg.Namespace = default_subscription_namespace
+	}
+
+	order := make(map[string]int)
+	for _, path := range arg.Paths {
+		order[path], err = subscriptionDeleteOrder(path)
+		if err != nil {
+			scope.Log("wmi_delete_subscription: %v", err)
+			return false
+		}
+	}
+
+	paths := append([]string{}, arg.Paths...)
+	sort.SliceStable(paths, func(i, j int) bool {
+		return order[paths[i]] < order[paths[j]]
+	})
+
+	deleted := []string{}
+	err = withService(arg.Namespace, func(service *ole.IDispatch) error {
+		for _, path := range paths {
+			err := deleteInstance(service, path)
+			if err != nil {
+				scope.Log("wmi_delete_subscription: %v: %v", path, err)
+				continue
+			}
+			scope.Log("wmi_delete_subscription: Deleted %v", path)
+			deleted = append(deleted, path)
+		}
+		return nil
+	})
+	if err != nil {
+		scope.Log("wmi_delete_subscription: %v", err)
+	}
+
+	return deleted
+}
+
+func (self *WmiDeleteSubscriptionFunction) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.FunctionInfo {
+	return &vfilter.FunctionInfo{
+		Name:    "wmi_delete_subscription",
+		Doc:     "Delete the objects of a WMI subscription by path.",
+		ArgType: type_map.AddType(scope, &WmiDeleteSubscriptionArgs{}),
+	}
+}
+
+func init() {
+	vql_subsystem.RegisterPlugin(&WmiCreateSubscriptionPlugin{})
+	vql_subsystem.RegisterFunction(&WmiDeleteSubscriptionFunction{})
+}
@@ -0,0 +1,126 @@
+package wmi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Built in WQL event queries for common detections. Parameters are
+// written as {{name}} in the query and are substituted with the
+// template_args given to wmi_events(), or with the template's
+// defaults. Parameters without a default must be given.
+type eventQueryTemplate struct {
+	Description string
+	Query       string
+	Defaults    map[string]string
+}
+
+var (
+	event_query_templates = map[string]eventQueryTemplate{
+		"process_creation": {
+			Description: "A process is started.",
+			Query: "SELECT * FROM __InstanceCreationEvent WITHIN {{within}} " +
+				"WHERE TargetInstance ISA 'Win32_Process'",
+			Defaults: map[string]string{"within": "1"},
+		},
+		"process_start_trace": {
+			Description: "A process is started (reported by the kernel trace provider without polling).",
+			Query:       "SELECT * FROM Win32_ProcessStartTrace",
+		},
+		"service_install": {
+			Description: "A service is installed.",
+			Query: "SELECT * FROM __InstanceCreationEvent WITHIN {{within}} " +
+				"WHERE TargetInstance ISA 'Win32_Service'",
+			Defaults: map[string]string{"within": "5"},
+		},
+		"volume_change": {
+			Description: "A volume is mounted or removed (e.g. a USB drive).",
+			Query:       "SELECT * FROM Win32_VolumeChangeEvent",
+		},
+		"instance_creation": {
+			Description: "An instance of the class is created.",
+			Query: "SELECT * FROM __InstanceCreationEvent WITHIN {{within}} " +
+				"WHERE TargetInstance ISA '{{class}}'",
+			Defaults: map[string]string{"within": "5"},
+		},
+		"instance_modification": {
+			Description: "An instance of the class is modified.",
+			Query: "SELECT * FROM __InstanceModificationEvent WITHIN {{within}} " +
+				"WHERE TargetInstance ISA '{{class}}'",
+			Defaults: map[string]string{"within": "5"},
+		},
+		"instance_deletion": {
+			Description: "An instance of the class is deleted.",
+			Query: "SELECT * FROM __InstanceDeletionEvent WITHIN {{within}} " +
+				"WHERE TargetInstance ISA '{{class}}'",
+			Defaults: map[string]string{"within": "5"},
+		},
+	}
+
+	template_parameter_regex = regexp.MustCompile(`{{([a-z_]+)}}`)
+
+	// Parameters are substituted into the WQL as is, so they may
+	// not contain quotes or anything else that could change the
+	// meaning of the query.
+	template_value_regex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+)
+
+// Expand the named template into a WQL query.
+func expandQueryTemplate(name string, args map[string]string) (string, error) {
+	template, pres := event_query_templates[strings.ToLower(name)]
+	if !pres {
+		names := make([]string, 0, len(event_query_templates))
+		for k := range event_query_templates {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("unknown query_template %q (one of %v)",
+			name, strings.Join(names, ", "))
+	}
+
+	used := make(map[string]bool)
+	var expand_err error
+	query := template_parameter_regex.ReplaceAllStringFunc(template.Query,
+		func(match string) string {
+			parameter := template_parameter_regex.FindStringSubmatch(match)[1]
+			used[parameter] = true
+
+			value, pres := args[parameter]
+			if !pres {
+				value, pres = template.Defaults[parameter]
+			}
+
+			if !pres {
+				if expand_err == nil {
+					expand_err = fmt.Errorf(
+						"query_template %v requires parameter %v",
+						name, parameter)
+				}
+				return match
+			}
+
+			if !template_value_regex.MatchString(value) {
+				if expand_err == nil {
+					expand_err = fmt.Errorf(
+						"invalid value %q for parameter %v", value, parameter)
+				}
+				return match
+			}
+
+			return value
+		})
+	if expand_err != nil {
+		return "", expand_err
+	}
+
+	for parameter := range args {
+		if !used[parameter] {
+			return "", fmt.Errorf(
+				"query_template %v has no parameter %v", name, parameter)
+		}
+	}
+
+	return query, nil
+}
@@ -0,0 +1,37 @@
+package wmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandQueryTemplate(t *testing.T) {
+	// Defaults are used for missing parameters.
+	query, err := expandQueryTemplate("process_creation", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM __InstanceCreationEvent WITHIN 1 "+
+		"WHERE TargetInstance ISA 'Win32_Process'", query)
+
+	query, err = expandQueryTemplate("Instance_Deletion", map[string]string{
+		"class": "Win32_Service", "within": "10"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM __InstanceDeletionEvent WITHIN 10 "+
+		"WHERE TargetInstance ISA 'Win32_Service'", query)
+
+	// The class has no default.
+	_, err = expandQueryTemplate("instance_creation", nil)
+	assert.Error(t, err)
+
+	// Values can not break out of the query.
+	_, err = expandQueryTemplate("instance_creation", map[string]string{
+		"class": "Win32_Process' OR 'a'='a"})
+	assert.Error(t, err)
+
+	_, err = expandQueryTemplate("volume_change", map[string]string{
+		"within": "1"})
+	assert.Error(t, err)
+
+	_, err = expandQueryTemplate("no_such_template", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package wmi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Intrinsic events are generated by WMI itself polling for
+	// changes, so queries for them must say how often to poll.
+	intrinsic_event_classes = map[string]bool{
+		"__INSTANCECREATIONEVENT":      true,
+		"__INSTANCEMODIFICATIONEVENT":  true,
+		"__INSTANCEDELETIONEVENT":      true,
+		"__INSTANCEOPERATIONEVENT":     true,
+		"__CLASSCREATIONEVENT":         true,
+		"__CLASSMODIFICATIONEVENT":     true,
+		"__CLASSDELETIONEVENT":         true,
+		"__CLASSOPERATIONEVENT":        true,
+		"__NAMESPACECREATIONEVENT":     true,
+		"__NAMESPACEMODIFICATIONEVENT": true,
+		"__NAMESPACEDELETIONEVENT":     true,
+		"__NAMESPACEOPERATIONEVENT":    true,
+	}
+
+	// WITHIN must directly follow the event class.
+	event_class_regex = regexp.MustCompile(
+		`(?i)\bFROM\s+(\w+)(\s+WITHIN\s+[0-9.]+\b)?`)
+
+	quoted_string_regex = regexp.MustCompile(`'[^']*'|"[^"]*"`)
+)
+
+// Make sure a query for an intrinsic event class has a WITHIN
+// clause, otherwise WMI rejects it with an opaque error. If within
+// is set, a WITHIN clause polling every within seconds is added to
+// queries lacking one, otherwise a query lacking one is an
+// error. Other queries are returned untouched.
+func normalizeEventQuery(query string, within int64) (string, error) {
+	// Ignore anything in string literals (e.g. in the WHERE
+	// clause). They are blanked out so the offsets still match
+	// the query.
+	stripped := quoted_string_regex.ReplaceAllStringFunc(query,
+		func(literal string) string {
+			return strings.Repeat(" ", len(literal))
+		})
+
+	match := event_class_regex.FindStringSubmatchIndex(stripped)
+	if match == nil {
+		return query, nil
+	}
+
+	class := query[match[2]:match[3]]
+	if !intrinsic_event_classes[strings.ToUpper(class)] || match[4] >= 0 {
+		return query, nil
+	}
+
+	if within <= 0 {
+		return "", fmt.Errorf(
+			"Queries for the intrinsic event class %v must say how often "+
+				"to poll with a WITHIN clause, e.g. SELECT * FROM %v "+
+				"WITHIN 5 WHERE ... Add one to the query or set the "+
+				"within argument", class, class)
+	}
+
+	return fmt.Sprintf("%v WITHIN %v%v",
+		query[:match[3]], within, query[match[3]:]), nil
+}
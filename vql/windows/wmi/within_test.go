@@ -0,0 +1,44 @@
+package wmi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEventQuery(t *testing.T) {
+	// Intrinsic event queries with a WITHIN clause are untouched.
+	query := "SELECT * FROM __InstanceCreationEvent WITHIN 1 " +
+		"WHERE TargetInstance ISA 'Win32_Process'"
+	result, err := normalizeEventQuery(query, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, query, result)
+
+	// So are extrinsic event queries.
+	query = "SELECT * FROM Win32_ProcessStartTrace"
+	result, err = normalizeEventQuery(query, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, query, result)
+
+	// A missing WITHIN clause is added after the class.
+	result, err = normalizeEventQuery(
+		"select * from __instancedeletionevent "+
+			"where TargetInstance ISA 'Win32_Service'", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "select * from __instancedeletionevent WITHIN 10 "+
+		"where TargetInstance ISA 'Win32_Service'", result)
+
+	// Without a polling interval it is an error.
+	_, err = normalizeEventQuery(
+		"SELECT * FROM __InstanceModificationEvent", 0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "WITHIN")
+
+	// WITHIN in a string literal does not count.
+	result, err = normalizeEventQuery(
+		"SELECT * FROM __InstanceCreationEvent "+
+			"WHERE TargetInstance.Name = 'FROM x WITHIN 1'", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM __InstanceCreationEvent WITHIN 2 "+
+		"WHERE TargetInstance.Name = 'FROM x WITHIN 1'", result)
+}
@@ -26,9 +26,11 @@ ordereddict.Dict().
 package wmi
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"runtime"
-	"sync"
+	"time"
 
 	"github.com/Velocidex/ordereddict"
 	ole "github.com/go-ole/go-ole"
@@ -42,15 +44,46 @@ var (
 	// ErrNilCreateObject is the error returned if CreateObject returns nil even
 	// if the error was nil.
 	ErrNilCreateObject = errors.New("wmi: create object returned nil")
-	lock               sync.Mutex
 )
 
 // S_FALSE is returned by CoInitializeEx if it was already called on this thread.
 const S_FALSE = 0x00000001
 
-func Query(query string, namespace string) ([]*ordereddict.Dict, error) {
-	lock.Lock()
-	defer lock.Unlock()
+// RPC_E_CHANGED_MODE is returned by CoInitializeEx if the thread was
+// already initialized for a different apartment.
+const RPC_E_CHANGED_MODE = 0x80010106
+
+// Initialize COM for the multithreaded apartment on the current
+// thread, which must be locked with runtime.LockOSThread(). Every
+// successful call (including when COM was already initialized on
+// this thread) must be balanced by ole.CoUninitialize() on the same
+// thread.
+func coInitialize() error {
+	err := ole.CoInitializeEx(0, ole.COINIT_MULTITHREADED)
+	if err == nil {
+		return nil
+	}
+
+	ole_err, ok := err.(*ole.OleError)
+	if !ok {
+		return err
+	}
+
+	switch uint32(ole_err.Code()) {
+	case ole.S_OK, S_FALSE:
+		return nil
+	case RPC_E_CHANGED_MODE:
+		return fmt.Errorf(
+			"CoInitializeEx: Thread is already in a single threaded apartment")
+	}
+	return err
+}
+
+// Connect to the WMI namespace and call cb with the service. Each
+// call initializes COM on its own locked OS thread, so calls do not
+// need to wait for each other and a query which never returns only
+// ties up its own thread.
+func withService(namespace string, cb func(service *ole.IDispatch) error) error {
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 
@@ -58,48 +91,70 @@ func Query(query string, namespace string) ([]*ordereddict.Dict, error) {
 		namespace = "ROOT/CIMV2"
 	}
 
-	err := ole.CoInitializeEx(0, ole.COINIT_MULTITHREADED)
+	err := coInitialize()
 	if err != nil {
-		oleCode := err.(*ole.OleError).Code()
-		if oleCode != ole.S_OK && oleCode != S_FALSE {
-			return nil, err
-		}
+		return err
 	}
 	defer ole.CoUninitialize()
 
 	unknown, err := oleutil.CreateObject("WbemScripting.SWbemLocator")
 	if err != nil {
-		return nil, err
+		return err
 	} else if unknown == nil {
-		return nil, ErrNilCreateObject
+		return ErrNilCreateObject
 	}
 	defer unknown.Release()
 
 	wmi, err := unknown.QueryInterface(ole.IID_IDispatch)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer wmi.Release()
 
 	serviceRaw, err := oleutil.CallMethod(wmi, "ConnectServer", nil, namespace)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	service := serviceRaw.ToIDispatch()
 	defer service.Release()
 
+	return cb(service)
+}
+
+func Query(query string, namespace string) ([]*ordereddict.Dict, error) {
+	var result []*ordereddict.Dict
+	err := withService(namespace, func(service *ole.IDispatch) error {
+		var err error
+		result, err = queryService(service, query)
+		return err
+	})
+	return result, err
+}
+
+func queryService(service *ole.IDispatch, query string) ([]*ordereddict.Dict, error) {
+	result := []*ordereddict.Dict{}
 	resultRaw, err := oleutil.CallMethod(service, "ExecQuery", query)
 	if err != nil {
 		return nil, err
 	}
+
+	err = forEachRow(resultRaw, func(row *ordereddict.Dict) error {
+		result = append(result, row)
+		return nil
+	})
+	return result, err
+}
+
+// Convert each object in the result of ExecQuery to a row and pass
+// it to cb. Enumeration stops at the first error cb returns.
+func forEachRow(resultRaw *ole.VARIANT, cb func(row *ordereddict.Dict) error) error {
 	wmi_result := resultRaw.ToIDispatch()
 	defer wmi_result.Release()
 
-	result := []*ordereddict.Dict{}
 	properties := []string{}
 
-	err = oleutil.ForEach(wmi_result,
+	return oleutil.ForEach(wmi_result,
 		func(v *ole.VARIANT) error {
 			item := v.ToIDispatch()
 			defer item.Release()
@@ -123,26 +178,29 @@ func Query(query string, namespace string) ([]*ordereddict.Dict, error) {
 					_ = property_raw.Clear()
 				}()
 
-				// If it is an array we convert it here.
-				if property_raw.VT&ole.VT_ARRAY > 0 {
-					row.Set(property, property_raw.ToArray().ToValueArray())
-					continue
-				}
-
-				switch property_raw.VT {
-				case ole.VT_UNKNOWN, ole.VT_DISPATCH:
-					// Do not set these because we
-					// cant do anything with them.
-
-				default:
-					row.Set(property, property_raw.Value())
+				value, ok := variantValue(property_raw)
+				if ok {
+					row.Set(property, value)
 				}
 			}
 
-			result = append(result, row)
-			return nil
+			return cb(row)
 		})
-	return result, err
+}
+
+// Convert a variant to a Go value. Objects are not converted because
+// we cant do anything with them.
+func variantValue(v *ole.VARIANT) (interface{}, bool) {
+	// If it is an array we convert it here.
+	if v.VT&ole.VT_ARRAY > 0 {
+		return v.ToArray().ToValueArray(), true
+	}
+
+	switch v.VT {
+	case ole.VT_UNKNOWN, ole.VT_DISPATCH:
+		return nil, false
+	}
+	return v.Value(), true
 }
 
 func getProperties(item *ole.IDispatch) ([]string, error) {
@@ -181,46 +239,137 @@ func getProperties(item *ole.IDispatch) ([]string, error) {
 	return result, err
 }
 
+const (
+	// Semisynchronous flags for ExecQuery so rows are returned as
+	// WMI produces them.
+	wbemFlagReturnImmediately = 0x10
+	wbemFlagForwardOnly       = 0x20
+
+	default_wmi_query_timeout = 600
+)
+
 // The VQL WMI plugin.
 type WMIQueryArgs struct {
 	Query     string `vfilter:"required,field=query,doc=The WMI query to issue."`
 	Namespace string `vfilter:"optional,field=namespace,doc=The WMI namespace to use (ROOT/CIMV2)"`
+	Timeout   uint64 `vfilter:"optional,field=timeout,doc=Abandon the query after this many seconds (default 600)."`
 }
 
-func runWMIQuery(scope vfilter.Scope,
-	args *ordereddict.Dict) []vfilter.Row {
-	var result []vfilter.Row
+type WMIQueryPlugin struct{}
+
+// A wedged WMI service may never return from a query. The query
+// runs on its own locked OS thread and streams its rows back, so
+// once the timeout passes we emit a final row saying the query timed
+// out and return with the rows we already have. The abandoned query
+// stops and releases its COM objects on its own thread as soon as
+// WMI returns control to it.
+func (self WMIQueryPlugin) Call(
+	ctx context.Context,
+	scope vfilter.Scope,
+	args *ordereddict.Dict) <-chan vfilter.Row {
+	output_chan := make(chan vfilter.Row)
+
+	go func() {
+		defer close(output_chan)
+
+		err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
+		if err != nil {
+			scope.Log("wmi: %s", err)
+			return
+		}
 
-	err := vql_subsystem.CheckAccess(scope, acls.MACHINE_STATE)
-	if err != nil {
-		scope.Log("wmi: %s", err)
-		return result
-	}
+		arg := &WMIQueryArgs{}
+		err = vfilter.ExtractArgs(scope, args, arg)
+		if err != nil {
+			scope.Log("wmi: %s", err.Error())
+			return
+		}
 
-	arg := &WMIQueryArgs{}
-	err = vfilter.ExtractArgs(scope, args, arg)
-	if err != nil {
-		scope.Log("wmi: %s", err.Error())
-		return result
-	}
+		if arg.Timeout == 0 {
+			arg.Timeout = default_wmi_query_timeout
+		}
+		timeout := time.Duration(arg.Timeout) * time.Second
 
-	query_result, err := Query(arg.Query, arg.Namespace)
-	if err != nil {
-		scope.Log("wmi: %s", err.Error())
-		return result
-	}
-	for _, item := range query_result {
-		result = append(result, item)
-	}
+		sub_ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		row_chan := make(chan *ordereddict.Dict)
+		err_chan := make(chan error, 1)
 
-	return result
+		go func() {
+			defer close(row_chan)
+
+			err_chan <- withService(arg.Namespace, func(service *ole.IDispatch) error {
+				resultRaw, err := oleutil.CallMethod(service, "ExecQuery",
+					arg.Query, "WQL",
+					wbemFlagReturnImmediately|wbemFlagForwardOnly)
+				if err != nil {
+					return err
+				}
+
+				return forEachRow(resultRaw, func(row *ordereddict.Dict) error {
+					select {
+					case <-sub_ctx.Done():
+						return sub_ctx.Err()
+					case row_chan <- row:
+						return nil
+					}
+				})
+			})
+		}()
+
+		count := 0
+		for {
+			select {
+			case <-sub_ctx.Done():
+				// The query was cancelled.
+				if ctx.Err() != nil {
+					return
+				}
+
+				message := fmt.Sprintf(
+					"Query timed out after %v with %v rows", timeout, count)
+				scope.Log("wmi: %v", message)
+
+				select {
+				case <-ctx.Done():
+				case output_chan <- ordereddict.NewDict().
+					Set("Error", message).
+					Set("Reason", "Timeout"):
+				}
+				return
+
+			case row, ok := <-row_chan:
+				if !ok {
+					err := <-err_chan
+					if err != nil {
+						scope.Log("wmi: %s", err.Error())
+					}
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case output_chan <- row:
+					count++
+				}
+			}
+		}
+	}()
+
+	return output_chan
+}
+
+func (self WMIQueryPlugin) Info(
+	scope vfilter.Scope, type_map *vfilter.TypeMap) *vfilter.PluginInfo {
+	return &vfilter.PluginInfo{
+		Name:    "wmi",
+		Doc:     "Execute simple WMI queries synchronously.",
+		ArgType: type_map.AddType(scope, &WMIQueryArgs{}),
+	}
 }
 
 func init() {
-	vql_subsystem.RegisterPlugin(&vfilter.GenericListPlugin{
-		PluginName: "wmi",
-		Doc:        "Execute simple WMI queries synchronously.",
-		Function:   runWMIQuery,
-		ArgType:    &WMIQueryArgs{},
-	})
+	vql_subsystem.RegisterPlugin(&WMIQueryPlugin{})
 }